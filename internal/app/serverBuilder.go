@@ -1,14 +1,21 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/VladSnap/shortener/internal/auth"
 	"github.com/VladSnap/shortener/internal/config"
+	"github.com/VladSnap/shortener/internal/constants"
 	"github.com/VladSnap/shortener/internal/data"
 	"github.com/VladSnap/shortener/internal/data/repos"
 	"github.com/VladSnap/shortener/internal/handlers"
+	"github.com/VladSnap/shortener/internal/helpers"
 	"github.com/VladSnap/shortener/internal/services"
+	"github.com/VladSnap/shortener/internal/tracing"
+	"github.com/redis/go-redis/v9"
 )
 
 // ServerBuilder предоставляет fluent interface для создания сервера.
@@ -26,6 +33,7 @@ func NewServerBuilder(cfg *config.Options, resMng *services.ResourceManager) *Se
 // CreateServer - Создает структуру интерфейса ShortenerServer используя Options pattern.
 func CreateServer(opts *config.Options, resMng *services.ResourceManager) (ShortenerServer, error) {
 	return NewServerBuilder(opts, resMng).
+		WithTracing().
 		WithRepository().
 		WithServices().
 		WithHandlers().
@@ -42,39 +50,133 @@ func (sb *ServerBuilder) Apply(options ...ServerOption) *ServerBuilder {
 	return sb
 }
 
+// WithTracing настраивает трассировку OpenTelemetry на основе конфигурации.
+// Провайдер регистрируется первым, чтобы при завершении остановиться последним и отправить все span.
+func (sb *ServerBuilder) WithTracing() *ServerBuilder {
+	shutdown, err := tracing.Setup(context.Background(), sb.options.GetConfig())
+	if err != nil {
+		panic(fmt.Errorf("failed setup tracing: %w", err))
+	}
+	sb.options.GetResourceManager().Register(shutdown)
+	return sb
+}
+
 // WithRepository настраивает репозиторий на основе конфигурации.
 func (sb *ServerBuilder) WithRepository() *ServerBuilder {
 	cfg := sb.options.GetConfig()
 	resMng := sb.options.GetResourceManager()
+	healthService := sb.options.GetHealthService()
 
 	var shortLinkRepo services.ShortLinkRepo
+	var clickRepo services.ClickRepo
+	var shortIDSequence services.ShortIDSequence
+	var apiKeyRepo services.APIKeyRepo
+	var deleteQueue services.DeleteQueue
+	var auditRepo services.AuditRepo
+	var fileRepo *repos.FileShortLinkRepo
 
 	switch {
-	case cfg.DataBaseConnString != "":
-		database, err := data.NewDatabaseShortener(cfg.DataBaseConnString)
-		if err != nil {
-			// В реальном приложении лучше возвращать ошибку,
-			// но для совместимости с существующим кодом используем panic
-			panic(fmt.Errorf("failed create DatabaseShortener: %w", err))
+	case cfg.RedisAddr != "":
+		client := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr})
+		resMng.Register(client.Close)
+		if err := client.Ping(context.Background()).Err(); err != nil {
+			panic(fmt.Errorf("failed connect to Redis: %w", err))
 		}
-		resMng.Register(database.Close)
-		err = database.InitDatabase()
-		if err != nil {
-			panic(fmt.Errorf("failed init Database: %w", err))
+		redisRepo := repos.NewRedisShortLinkRepo(client)
+		healthService.AddReadinessCheck("storage", redisRepo)
+		shortLinkRepo = redisRepo
+		shortIDSequence = repos.NewRedisShortIDSequence(client)
+		// Переходы по ссылкам пишутся пачками и не нужны для редиректа, поэтому остаются в БД, если она задана.
+		if cfg.DataBaseConnString != "" {
+			database := openDatabase(cfg, resMng)
+			healthService.AddReadinessCheck("database", database)
+			clickRepo = repos.NewDatabaseClickRepo(database)
+			apiKeyRepo = repos.NewDatabaseAPIKeyRepo(database)
+			deleteQueue = repos.NewDatabaseDeleteQueue(database)
+			auditRepo = repos.NewDatabaseAuditRepo(database)
+		} else {
+			clickRepo = repos.NewInMemoryClickRepo()
 		}
+	case cfg.DataBaseConnString != "":
+		database := openDatabase(cfg, resMng)
+		healthService.AddReadinessCheck("storage", database)
 		shortLinkRepo = repos.NewDatabaseShortLinkRepo(database)
+		clickRepo = repos.NewDatabaseClickRepo(database)
+		shortIDSequence = repos.NewDatabaseShortIDSequence(database)
+		apiKeyRepo = repos.NewDatabaseAPIKeyRepo(database)
+		deleteQueue = repos.NewDatabaseDeleteQueue(database)
+		auditRepo = repos.NewDatabaseAuditRepo(database)
 	case cfg.FileStoragePath != "":
-		fileRepo, err := repos.NewFileShortLinkRepo(cfg.FileStoragePath)
+		var err error
+		fileRepo, err = repos.NewFileShortLinkRepo(cfg.FileStoragePath,
+			repos.WithFileSyncPolicy(cfg.FileSyncPolicy), repos.WithFileCompactThreshold(cfg.FileCompactThreshold))
 		if err != nil {
 			panic(fmt.Errorf("failed create FileShortLinkRepo: %w", err))
 		}
 		resMng.Register(fileRepo.Close)
+		healthService.AddReadinessCheck("storage", fileRepo)
 		shortLinkRepo = fileRepo
+		fileClickRepo, err := repos.NewFileClickRepo(cfg.FileStoragePath + ".clicks")
+		if err != nil {
+			panic(fmt.Errorf("failed create FileClickRepo: %w", err))
+		}
+		resMng.Register(fileClickRepo.Close)
+		clickRepo = fileClickRepo
+		fileDeleteQueue, err := repos.NewFileDeleteQueue(cfg.FileStoragePath + ".delete-queue")
+		if err != nil {
+			panic(fmt.Errorf("failed create FileDeleteQueue: %w", err))
+		}
+		deleteQueue = fileDeleteQueue
+		fileAuditRepo, err := repos.NewFileAuditRepo(cfg.FileStoragePath + ".audit")
+		if err != nil {
+			panic(fmt.Errorf("failed create FileAuditRepo: %w", err))
+		}
+		resMng.Register(fileAuditRepo.Close)
+		auditRepo = fileAuditRepo
 	default:
 		shortLinkRepo = repos.NewShortLinkRepo()
+		clickRepo = repos.NewInMemoryClickRepo()
+	}
+
+	if apiKeyRepo == nil {
+		// Без БД ключи API живут до перезапуска сервера.
+		apiKeyRepo = repos.NewInMemoryAPIKeyRepo()
+	}
+	if deleteQueue == nil {
+		// Без БД и файла запросы на удаление, не выполненные до остановки сервера, теряются.
+		deleteQueue = repos.NewInMemoryDeleteQueue()
+	}
+	if auditRepo == nil {
+		// Без БД и файла журнал аудита живет до перезапуска сервера.
+		auditRepo = repos.NewInMemoryAuditRepo()
+	}
+
+	appMetrics := sb.options.GetMetrics()
+	// Замеряется только обращение к хранилищу, попадания в кеш учитываются метриками кеша.
+	shortLinkRepo = services.NewInstrumentedShortLinkRepo(shortLinkRepo, appMetrics)
+
+	if cfg.CacheSize > 0 {
+		cachedRepo := services.NewCachedShortLinkRepo(shortLinkRepo, cfg.CacheSize,
+			services.WithCacheTTL(time.Duration(cfg.CacheTTLSec)*time.Second),
+			services.WithCacheNegativeTTL(time.Duration(cfg.CacheNegativeTTLSec)*time.Second))
+		resMng.Register(cachedRepo.Close)
+		appMetrics.RegisterCache(cachedRepo.Stats)
+		shortLinkRepo = cachedRepo
+	}
+
+	if shortIDSequence == nil && cfg.ShortIDStrategy == constants.ShortIDStrategySequence {
+		// Локальный счетчик продолжается после наибольшего уже выданного идентификатора,
+		// поэтому после физического удаления ссылок и перезапуска идентификаторы не повторяются.
+		var start uint64
+		if fileRepo != nil {
+			start = services.MaxSequenceValue(fileRepo.GetShortIDs(), cfg.ShortIDAlphabet)
+		}
+		shortIDSequence = services.NewCounterSequence(start)
 	}
 
-	err := sb.options.Apply(WithShortLinkRepo(shortLinkRepo))
+	err := sb.options.Apply(WithShortLinkRepo(shortLinkRepo), WithClickRepo(clickRepo),
+		WithShortIDSequence(shortIDSequence), WithAPIKeyRepo(apiKeyRepo),
+		WithDeleteQueue(deleteQueue), WithAuditRepo(auditRepo))
 	if err != nil {
 		panic(fmt.Errorf("failed Apply ShortLinkRepo: %w", err))
 	}
@@ -82,17 +184,78 @@ func (sb *ServerBuilder) WithRepository() *ServerBuilder {
 	return sb
 }
 
+// openDatabase - Подключается к БД и применяет миграции.
+func openDatabase(cfg *config.Options, resMng *services.ResourceManager) *data.DatabaseShortener {
+	database, err := data.NewDatabaseShortener(cfg.DataBaseConnString)
+	if err != nil {
+		// В реальном приложении лучше возвращать ошибку,
+		// но для совместимости с существующим кодом используем panic
+		panic(fmt.Errorf("failed create DatabaseShortener: %w", err))
+	}
+	resMng.Register(database.Close)
+	err = database.InitDatabase()
+	if err != nil {
+		panic(fmt.Errorf("failed init Database: %w", err))
+	}
+	return database
+}
+
 // WithServices создает и настраивает все необходимые сервисы.
 func (sb *ServerBuilder) WithServices() *ServerBuilder {
-	shorterService := services.NewNaiveShorterService(sb.options.GetShortLinkRepo())
-	deleteWorker := handlers.NewDeleteWorker(shorterService)
+	cfg := sb.options.GetConfig()
+	appMetrics := sb.options.GetMetrics()
+	auditLog := services.NewAuditLog(sb.options.GetAuditRepo())
+	serviceOptions := []services.ShorterServiceOption{
+		services.WithClickRepo(sb.options.GetClickRepo()),
+		services.WithMetrics(appMetrics),
+		services.WithAuditLog(auditLog),
+	}
+	if cfg.RestoreWindowSec > 0 {
+		serviceOptions = append(serviceOptions,
+			services.WithRestoreWindow(time.Duration(cfg.RestoreWindowSec)*time.Second))
+	}
+	aliasRules := services.NewAliasRules(constants.AliasCharset, constants.AliasMinLength, constants.AliasMaxLength,
+		reservedPaths(cfg))
+	if cfg.AliasCharset != "" {
+		aliasRules = services.NewAliasRules(cfg.AliasCharset, cfg.AliasMinLength, cfg.AliasMaxLength,
+			reservedPaths(cfg))
+	}
+	serviceOptions = append(serviceOptions, services.WithAliasRules(aliasRules))
+	if cfg.ShortIDStrategy != "" {
+		idGenerator, err := services.NewShortIDGenerator(cfg.ShortIDStrategy, cfg.ShortIDAlphabet, cfg.ShortIDLength,
+			sb.options.GetShortIDSequence())
+		if err != nil {
+			panic(fmt.Errorf("failed create ShortIDGenerator: %w", err))
+		}
+		serviceOptions = append(serviceOptions, services.WithShortIDGenerator(idGenerator))
+	}
+	shorterService := services.NewNaiveShorterService(sb.options.GetShortLinkRepo(), serviceOptions...)
+	deleteWorker := handlers.NewDeleteWorker(shorterService, sb.options.GetDeleteQueue(),
+		handlers.WithDeleteWorkerMetrics(appMetrics),
+		handlers.WithDeleteWorkerAudit(auditLog),
+		handlers.WithDeleteRetryPolicy(cfg.DeleteMaxAttempts, time.Duration(cfg.DeleteRetryBackoffSec)*time.Second))
 
 	sb.options.GetResourceManager().Register(deleteWorker.Close)
 	deleteWorker.RunWork()
+	sb.options.GetHealthService().AddLivenessCheck("delete_worker", deleteWorker)
+
+	clickTracker := services.NewClickTracker(sb.options.GetClickRepo())
+	sb.options.GetResourceManager().Register(clickTracker.Close)
+	clickTracker.RunWork()
+
+	if cfg.PurgeIntervalSec > 0 {
+		purgeWorker := services.NewPurgeWorker(sb.options.GetShortLinkRepo(),
+			time.Duration(cfg.PurgeIntervalSec)*time.Second, time.Duration(cfg.PurgeRetentionSec)*time.Second)
+		sb.options.GetResourceManager().Register(purgeWorker.Close)
+		purgeWorker.RunWork()
+	}
 
 	err := sb.options.Apply(
 		WithShorterService(shorterService),
 		WithDeleteWorker(deleteWorker),
+		WithClickTracker(clickTracker),
+		WithAPIKeyService(services.NewAPIKeyService(sb.options.GetAPIKeyRepo())),
+		WithAuditLog(auditLog),
 	)
 	if err != nil {
 		panic(fmt.Errorf("failed Apply Services: %w", err))
@@ -106,16 +269,33 @@ func (sb *ServerBuilder) WithHandlers() *ServerBuilder {
 	shorterService := sb.options.GetShorterService()
 	deleteWorker := sb.options.GetDeleteWorker()
 
+	trustedProxies, err := helpers.ParseTrustedProxies(cfg.TrustedSubnet, cfg.TrustedProxies)
+	if err != nil {
+		panic(fmt.Errorf("failed parse trusted proxies: %w", err))
+	}
+
 	postHandler := handlers.NewPostHandler(shorterService, cfg.BaseURL)
-	getHandler := handlers.NewGetHandler(shorterService)
+	getHandler := handlers.NewGetHandler(shorterService, sb.options.GetClickTracker(),
+		handlers.WithGetHandlerMetrics(sb.options.GetMetrics()), handlers.WithGetHandlerTrustedProxies(trustedProxies))
 	shortenHandler := handlers.NewShortenHandler(shorterService, cfg.BaseURL)
-	pingHandler := handlers.NewGetPingHandler(cfg)
+	healthService := sb.options.GetHealthService()
+	pingHandler := handlers.NewGetPingHandler(healthService)
 	batchHandler := handlers.NewBatchHandler(shorterService, cfg.BaseURL)
 	urlsHandler := handlers.NewUrlsHandler(shorterService, cfg.BaseURL)
 	deleteHandler := handlers.NewDeleteHandler(deleteWorker)
+	deleteJobHandler := handlers.NewDeleteJobHandler(deleteWorker)
+	restoreHandler := handlers.NewRestoreHandler(shorterService)
+	updateLinkHandler := handlers.NewUpdateLinkHandler(shorterService, cfg.BaseURL)
 	getStatsHandler := handlers.NewGetStatsHandler(cfg, shorterService)
+	linkStatsHandler := handlers.NewLinkStatsHandler(shorterService, cfg.BaseURL)
+	userStatsHandler := handlers.NewUserStatsHandler(shorterService, cfg.BaseURL)
+	tokenHandler := handlers.NewTokenHandler(auth.NewJWTManager(cfg))
+	livenessHandler := handlers.NewLivenessHandler(healthService)
+	readinessHandler := handlers.NewReadinessHandler(healthService)
+	apiKeysHandler := handlers.NewAPIKeysHandler(sb.options.GetAPIKeyService())
+	auditHandler := handlers.NewAuditHandler(sb.options.GetAuditLog())
 
-	err := sb.options.Apply(
+	err = sb.options.Apply(
 		WithPostHandler(postHandler),
 		WithGetHandler(getHandler),
 		WithShortenHandler(shortenHandler),
@@ -123,7 +303,17 @@ func (sb *ServerBuilder) WithHandlers() *ServerBuilder {
 		WithBatchHandler(batchHandler),
 		WithUrlsHandler(urlsHandler),
 		WithDeleteHandler(deleteHandler),
+		WithDeleteJobHandler(deleteJobHandler),
+		WithRestoreHandler(restoreHandler),
+		WithUpdateLinkHandler(updateLinkHandler),
 		WithGetStatsHandler(getStatsHandler),
+		WithLinkStatsHandler(linkStatsHandler),
+		WithUserStatsHandler(userStatsHandler),
+		WithTokenHandler(tokenHandler),
+		WithLivenessHandler(livenessHandler),
+		WithReadinessHandler(readinessHandler),
+		WithAPIKeysHandler(apiKeysHandler),
+		WithAuditHandler(auditHandler),
 	)
 	if err != nil {
 		panic(fmt.Errorf("failed Apply Handlers: %w", err))
@@ -144,11 +334,23 @@ func (sb *ServerBuilder) Build() (ShortenerServer, error) {
 	if sb.options.deleteWorker == nil {
 		return nil, errors.New("deleteWorker is not configured")
 	}
+	if sb.options.clickTracker == nil {
+		return nil, errors.New("clickTracker is not configured")
+	}
+	if sb.options.apiKeyService == nil {
+		return nil, errors.New("apiKeyService is not configured")
+	}
+	if sb.options.auditLog == nil {
+		return nil, errors.New("auditLog is not configured")
+	}
 
 	// Проверяем, что все обработчики установлены
 	if sb.options.postHandler == nil || sb.options.getHandler == nil || sb.options.shortenHandler == nil ||
 		sb.options.pingHandler == nil || sb.options.batchHandler == nil || sb.options.urlsHandler == nil ||
-		sb.options.deleteHandler == nil || sb.options.getStatsHandler == nil {
+		sb.options.deleteHandler == nil || sb.options.getStatsHandler == nil || sb.options.linkStatsHandler == nil ||
+		sb.options.userStatsHandler == nil || sb.options.tokenHandler == nil || sb.options.livenessHandler == nil ||
+		sb.options.readinessHandler == nil || sb.options.apiKeysHandler == nil || sb.options.deleteJobHandler == nil ||
+		sb.options.restoreHandler == nil || sb.options.updateLinkHandler == nil || sb.options.auditHandler == nil {
 		return nil, errors.New("not all handlers are configured")
 	}
 
@@ -162,10 +364,24 @@ func (sb *ServerBuilder) Build() (ShortenerServer, error) {
 		WithUnifiedBatchHandler(sb.options.batchHandler),
 		WithUnifiedUrlsHandler(sb.options.urlsHandler),
 		WithUnifiedDeleteHandler(sb.options.deleteHandler),
+		WithUnifiedDeleteJobHandler(sb.options.deleteJobHandler),
+		WithUnifiedRestoreHandler(sb.options.restoreHandler),
+		WithUnifiedUpdateLinkHandler(sb.options.updateLinkHandler),
 		WithUnifiedGetStatsHandler(sb.options.getStatsHandler),
+		WithUnifiedLinkStatsHandler(sb.options.linkStatsHandler),
+		WithUnifiedUserStatsHandler(sb.options.userStatsHandler),
+		WithUnifiedTokenHandler(sb.options.tokenHandler),
+		WithUnifiedLivenessHandler(sb.options.livenessHandler),
+		WithUnifiedReadinessHandler(sb.options.readinessHandler),
+		WithUnifiedMetrics(sb.options.GetMetrics()),
+		WithUnifiedHealthService(sb.options.GetHealthService()),
+		WithUnifiedAPIKeyService(sb.options.GetAPIKeyService()),
+		WithUnifiedAPIKeysHandler(sb.options.apiKeysHandler),
+		WithUnifiedAuditHandler(sb.options.auditHandler),
 		WithGRPCHandler(
 			sb.options.GetShorterService(),
 			sb.options.GetDeleteWorker(),
+			sb.options.GetHealthService(),
 			sb.options.GetConfig().BaseURL,
 			sb.options.GetConfig(),
 		),
@@ -3,6 +3,7 @@ package app
 import (
 	"github.com/VladSnap/shortener/internal/config"
 	"github.com/VladSnap/shortener/internal/handlers"
+	"github.com/VladSnap/shortener/internal/metrics"
 	"github.com/VladSnap/shortener/internal/services"
 )
 
@@ -10,23 +11,43 @@ import (
 type ServerOptions struct {
 	config          *config.Options
 	resourceManager *services.ResourceManager
+	metrics         *metrics.Metrics
+	healthService   *services.HealthService
 
 	// Repositories
-	shortLinkRepo services.ShortLinkRepo
+	shortLinkRepo   services.ShortLinkRepo
+	clickRepo       services.ClickRepo
+	shortIDSequence services.ShortIDSequence
+	apiKeyRepo      services.APIKeyRepo
+	deleteQueue     services.DeleteQueue
+	auditRepo       services.AuditRepo
 
 	// Services
 	shorterService handlers.ShorterService
 	deleteWorker   handlers.DeleterWorker
+	clickTracker   handlers.ClickTracker
+	apiKeyService  *services.APIKeyService
+	auditLog       *services.AuditLog
 
 	// Handlers
-	postHandler     Handler
-	getHandler      Handler
-	shortenHandler  Handler
-	pingHandler     Handler
-	batchHandler    Handler
-	urlsHandler     Handler
-	deleteHandler   Handler
-	getStatsHandler Handler
+	postHandler       Handler
+	getHandler        Handler
+	shortenHandler    Handler
+	pingHandler       Handler
+	batchHandler      Handler
+	urlsHandler       Handler
+	deleteHandler     Handler
+	deleteJobHandler  Handler
+	restoreHandler    Handler
+	updateLinkHandler Handler
+	getStatsHandler   Handler
+	linkStatsHandler  Handler
+	userStatsHandler  Handler
+	tokenHandler      Handler
+	livenessHandler   Handler
+	readinessHandler  Handler
+	apiKeysHandler    *handlers.APIKeysHandler
+	auditHandler      Handler
 }
 
 // ServerOption представляет функцию для настройки ServerOptions.
@@ -37,6 +58,8 @@ func NewServerOptions(cfg *config.Options, resMng *services.ResourceManager) *Se
 	return &ServerOptions{
 		config:          cfg,
 		resourceManager: resMng,
+		metrics:         metrics.New(),
+		healthService:   services.NewHealthService(),
 	}
 }
 
@@ -48,6 +71,54 @@ func WithShortLinkRepo(repo services.ShortLinkRepo) ServerOption {
 	}
 }
 
+// WithClickRepo устанавливает репозиторий для переходов по ссылкам.
+func WithClickRepo(repo services.ClickRepo) ServerOption {
+	return func(opts *ServerOptions) error {
+		opts.clickRepo = repo
+		return nil
+	}
+}
+
+// WithShortIDSequence устанавливает последовательность для генерации идентификаторов сокращенных ссылок.
+func WithShortIDSequence(sequence services.ShortIDSequence) ServerOption {
+	return func(opts *ServerOptions) error {
+		opts.shortIDSequence = sequence
+		return nil
+	}
+}
+
+// WithAPIKeyRepo устанавливает репозиторий ключей API.
+func WithAPIKeyRepo(repo services.APIKeyRepo) ServerOption {
+	return func(opts *ServerOptions) error {
+		opts.apiKeyRepo = repo
+		return nil
+	}
+}
+
+// WithDeleteQueue устанавливает очередь удаления сокращенных ссылок.
+func WithDeleteQueue(queue services.DeleteQueue) ServerOption {
+	return func(opts *ServerOptions) error {
+		opts.deleteQueue = queue
+		return nil
+	}
+}
+
+// WithAuditRepo устанавливает репозиторий журнала аудита.
+func WithAuditRepo(repo services.AuditRepo) ServerOption {
+	return func(opts *ServerOptions) error {
+		opts.auditRepo = repo
+		return nil
+	}
+}
+
+// WithMetrics устанавливает метрики Prometheus.
+func WithMetrics(m *metrics.Metrics) ServerOption {
+	return func(opts *ServerOptions) error {
+		opts.metrics = m
+		return nil
+	}
+}
+
 // WithShorterService устанавливает сервис для сокращения ссылок.
 func WithShorterService(service handlers.ShorterService) ServerOption {
 	return func(opts *ServerOptions) error {
@@ -64,6 +135,22 @@ func WithDeleteWorker(worker handlers.DeleterWorker) ServerOption {
 	}
 }
 
+// WithClickTracker устанавливает воркер для сохранения переходов по ссылкам.
+func WithClickTracker(tracker handlers.ClickTracker) ServerOption {
+	return func(opts *ServerOptions) error {
+		opts.clickTracker = tracker
+		return nil
+	}
+}
+
+// WithAPIKeyService устанавливает сервис ключей API.
+func WithAPIKeyService(service *services.APIKeyService) ServerOption {
+	return func(opts *ServerOptions) error {
+		opts.apiKeyService = service
+		return nil
+	}
+}
+
 // WithPostHandler устанавливает обработчик POST запросов.
 func WithPostHandler(handler Handler) ServerOption {
 	return func(opts *ServerOptions) error {
@@ -128,6 +215,94 @@ func WithGetStatsHandler(handler Handler) ServerOption {
 	}
 }
 
+// WithDeleteJobHandler устанавливает обработчик статуса запроса на удаление.
+func WithDeleteJobHandler(handler Handler) ServerOption {
+	return func(opts *ServerOptions) error {
+		opts.deleteJobHandler = handler
+		return nil
+	}
+}
+
+// WithRestoreHandler устанавливает обработчик восстановления удаленных ссылок.
+func WithRestoreHandler(handler Handler) ServerOption {
+	return func(opts *ServerOptions) error {
+		opts.restoreHandler = handler
+		return nil
+	}
+}
+
+// WithUpdateLinkHandler устанавливает обработчик изменения оригинального URL ссылки.
+func WithUpdateLinkHandler(handler Handler) ServerOption {
+	return func(opts *ServerOptions) error {
+		opts.updateLinkHandler = handler
+		return nil
+	}
+}
+
+// WithLinkStatsHandler устанавливает обработчик статистики переходов по ссылке.
+func WithLinkStatsHandler(handler Handler) ServerOption {
+	return func(opts *ServerOptions) error {
+		opts.linkStatsHandler = handler
+		return nil
+	}
+}
+
+// WithUserStatsHandler устанавливает обработчик статистики пользователя.
+func WithUserStatsHandler(handler Handler) ServerOption {
+	return func(opts *ServerOptions) error {
+		opts.userStatsHandler = handler
+		return nil
+	}
+}
+
+// WithTokenHandler устанавливает обработчик выпуска JWT токенов.
+func WithTokenHandler(handler Handler) ServerOption {
+	return func(opts *ServerOptions) error {
+		opts.tokenHandler = handler
+		return nil
+	}
+}
+
+// WithLivenessHandler устанавливает обработчик проверки живости сервиса.
+func WithLivenessHandler(handler Handler) ServerOption {
+	return func(opts *ServerOptions) error {
+		opts.livenessHandler = handler
+		return nil
+	}
+}
+
+// WithReadinessHandler устанавливает обработчик проверки готовности сервиса.
+func WithReadinessHandler(handler Handler) ServerOption {
+	return func(opts *ServerOptions) error {
+		opts.readinessHandler = handler
+		return nil
+	}
+}
+
+// WithAuditLog устанавливает журнал аудита действий со ссылками.
+func WithAuditLog(audit *services.AuditLog) ServerOption {
+	return func(opts *ServerOptions) error {
+		opts.auditLog = audit
+		return nil
+	}
+}
+
+// WithAuditHandler устанавливает обработчик чтения журнала аудита.
+func WithAuditHandler(handler Handler) ServerOption {
+	return func(opts *ServerOptions) error {
+		opts.auditHandler = handler
+		return nil
+	}
+}
+
+// WithAPIKeysHandler устанавливает обработчик управления ключами API.
+func WithAPIKeysHandler(handler *handlers.APIKeysHandler) ServerOption {
+	return func(opts *ServerOptions) error {
+		opts.apiKeysHandler = handler
+		return nil
+	}
+}
+
 // Apply применяет все переданные опции к ServerOptions.
 func (so *ServerOptions) Apply(options ...ServerOption) error {
 	for _, option := range options {
@@ -148,11 +323,46 @@ func (so *ServerOptions) GetResourceManager() *services.ResourceManager {
 	return so.resourceManager
 }
 
+// GetMetrics возвращает метрики Prometheus.
+func (so *ServerOptions) GetMetrics() *metrics.Metrics {
+	return so.metrics
+}
+
+// GetHealthService возвращает сервис проверок живости и готовности.
+func (so *ServerOptions) GetHealthService() *services.HealthService {
+	return so.healthService
+}
+
 // GetShortLinkRepo возвращает репозиторий коротких ссылок.
 func (so *ServerOptions) GetShortLinkRepo() services.ShortLinkRepo {
 	return so.shortLinkRepo
 }
 
+// GetClickRepo возвращает репозиторий переходов по ссылкам.
+func (so *ServerOptions) GetClickRepo() services.ClickRepo {
+	return so.clickRepo
+}
+
+// GetShortIDSequence возвращает последовательность для генерации идентификаторов сокращенных ссылок.
+func (so *ServerOptions) GetShortIDSequence() services.ShortIDSequence {
+	return so.shortIDSequence
+}
+
+// GetAPIKeyRepo возвращает репозиторий ключей API.
+func (so *ServerOptions) GetAPIKeyRepo() services.APIKeyRepo {
+	return so.apiKeyRepo
+}
+
+// GetDeleteQueue возвращает очередь удаления сокращенных ссылок.
+func (so *ServerOptions) GetDeleteQueue() services.DeleteQueue {
+	return so.deleteQueue
+}
+
+// GetAuditRepo возвращает репозиторий журнала аудита.
+func (so *ServerOptions) GetAuditRepo() services.AuditRepo {
+	return so.auditRepo
+}
+
 // GetShorterService возвращает сервис сокращения ссылок.
 func (so *ServerOptions) GetShorterService() handlers.ShorterService {
 	return so.shorterService
@@ -162,3 +372,18 @@ func (so *ServerOptions) GetShorterService() handlers.ShorterService {
 func (so *ServerOptions) GetDeleteWorker() handlers.DeleterWorker {
 	return so.deleteWorker
 }
+
+// GetClickTracker возвращает воркер сохранения переходов.
+func (so *ServerOptions) GetClickTracker() handlers.ClickTracker {
+	return so.clickTracker
+}
+
+// GetAPIKeyService возвращает сервис ключей API.
+func (so *ServerOptions) GetAPIKeyService() *services.APIKeyService {
+	return so.apiKeyService
+}
+
+// GetAuditLog возвращает журнал аудита действий со ссылками.
+func (so *ServerOptions) GetAuditLog() *services.AuditLog {
+	return so.auditLog
+}
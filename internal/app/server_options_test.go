@@ -1,11 +1,15 @@
 package app
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/VladSnap/shortener/internal/config"
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/data"
 	"github.com/VladSnap/shortener/internal/data/repos"
 	"github.com/VladSnap/shortener/internal/services"
+	"github.com/alicebob/miniredis/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -85,6 +89,51 @@ func TestServerBuilder(t *testing.T) {
 		assert.NotNil(t, unifiedServer)
 	})
 
+	t.Run("Builder uses Redis repository when REDIS_ADDR is set", func(t *testing.T) {
+		redisCfg := *cfg
+		redisCfg.RedisAddr = miniredis.RunT(t).Addr()
+		builder := NewServerBuilder(&redisCfg, resMng)
+
+		builder.WithRepository()
+		options := builder.GetOptions()
+		require.IsType(t, &services.InstrumentedShortLinkRepo{}, options.GetShortLinkRepo())
+		instrumented := options.GetShortLinkRepo().(*services.InstrumentedShortLinkRepo) //nolint:forcetypeassert // checked
+		assert.IsType(t, &repos.RedisShortLinkRepo{}, instrumented.Unwrap())
+		assert.IsType(t, &repos.RedisShortIDSequence{}, options.GetShortIDSequence())
+	})
+
+	t.Run("Builder continues local sequence after stored short ids", func(t *testing.T) {
+		fileCfg := *cfg
+		fileCfg.FileStoragePath = filepath.Join(t.TempDir(), "storage.json")
+		fileCfg.ShortIDStrategy = constants.ShortIDStrategySequence
+		fileCfg.ShortIDAlphabet = constants.ShortIDAlphabet
+		fileRepo, err := repos.NewFileShortLinkRepo(fileCfg.FileStoragePath)
+		require.NoError(t, err)
+		_, err = fileRepo.AddBatch(t.Context(), []*data.ShortLinkData{
+			data.NewShortLinkData("uuid-00000010", "00000010", "http://a.url", "user1"),
+			data.NewShortLinkData("uuid-0000000z", "0000000z", "http://b.url", "user1"),
+			data.NewShortLinkData("uuid-my-alias", "my-alias", "http://c.url", "user1"),
+		})
+		require.NoError(t, err)
+		require.NoError(t, fileRepo.Close())
+
+		builder := NewServerBuilder(&fileCfg, resMng)
+		builder.WithRepository()
+		next, err := builder.GetOptions().GetShortIDSequence().Next(t.Context())
+
+		require.NoError(t, err)
+		assert.Equal(t, uint64(63), next)
+	})
+
+	t.Run("Builder creates local sequence only for sequence strategy", func(t *testing.T) {
+		randomCfg := *cfg
+		randomCfg.ShortIDStrategy = constants.ShortIDStrategyRandom
+		builder := NewServerBuilder(&randomCfg, resMng)
+
+		builder.WithRepository()
+		assert.Nil(t, builder.GetOptions().GetShortIDSequence())
+	})
+
 	t.Run("Builder fails without required dependencies", func(t *testing.T) {
 		builder := NewServerBuilder(cfg, resMng)
 
@@ -8,22 +8,31 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/VladSnap/shortener/internal/auth"
 	"github.com/VladSnap/shortener/internal/config"
+	"github.com/VladSnap/shortener/internal/constants"
 	grpchandlers "github.com/VladSnap/shortener/internal/grpc/handlers"
 	"github.com/VladSnap/shortener/internal/grpc/interceptors"
 	"github.com/VladSnap/shortener/internal/handlers"
+	"github.com/VladSnap/shortener/internal/helpers"
 	"github.com/VladSnap/shortener/internal/log"
+	"github.com/VladSnap/shortener/internal/metrics"
 	"github.com/VladSnap/shortener/internal/middlewares"
+	"github.com/VladSnap/shortener/internal/ratelimit"
+	"github.com/VladSnap/shortener/internal/services"
 	pb "github.com/VladSnap/shortener/proto"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/acme/autocert"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 const (
@@ -33,6 +42,8 @@ const (
 	errorChannelSize  = 2
 	httpServerCount   = 1
 	grpcServerCount   = 1
+	// healthUpdatePeriod is how often the gRPC health status is refreshed from readiness checks.
+	healthUpdatePeriod = 5 * time.Second
 )
 
 // Handler - Интерфейс обработчика http запросов.
@@ -49,16 +60,32 @@ type ShortenerServer interface {
 
 // UnifiedShortenerServer implements ShortenerServer interface and supports both HTTP and gRPC.
 type UnifiedShortenerServer struct {
-	opts            *config.Options
-	postHandler     Handler
-	getHandler      Handler
-	shortenHandler  Handler
-	pingHandler     Handler
-	batchHandler    Handler
-	urlsHandler     Handler
-	deleteHandler   Handler
-	getStatsHandler Handler
-	grpcHandler     *grpchandlers.ShortenerGRPCHandler
+	opts              *config.Options
+	postHandler       Handler
+	getHandler        Handler
+	shortenHandler    Handler
+	pingHandler       Handler
+	batchHandler      Handler
+	urlsHandler       Handler
+	deleteHandler     Handler
+	deleteJobHandler  Handler
+	restoreHandler    Handler
+	updateLinkHandler Handler
+	getStatsHandler   Handler
+	linkStatsHandler  Handler
+	userStatsHandler  Handler
+	tokenHandler      Handler
+	livenessHandler   Handler
+	readinessHandler  Handler
+	grpcHandler       *grpchandlers.ShortenerGRPCHandler
+	metrics           *metrics.Metrics
+	healthService     *services.HealthService
+	apiKeys           middlewares.APIKeyAuthenticator
+	cookieRing        *auth.CookieKeyRing
+	trustedProxies    []*net.IPNet
+	apiKeysHandler    *handlers.APIKeysHandler
+	auditHandler      Handler
+	rateLimits        *ratelimit.Limits
 }
 
 // UnifiedServerOption представляет функцию для настройки UnifiedShortenerServer.
@@ -66,8 +93,19 @@ type UnifiedServerOption func(*UnifiedShortenerServer) error
 
 // NewUnifiedShortenerServer создает сервер используя Options pattern.
 func NewUnifiedShortenerServer(opts *config.Options, options ...UnifiedServerOption) (*UnifiedShortenerServer, error) {
+	cookieRing, err := auth.NewCookieKeyRing(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed create CookieKeyRing: %w", err)
+	}
+	trustedProxies, err := helpers.ParseTrustedProxies(opts.TrustedSubnet, opts.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("failed parse trusted proxies: %w", err)
+	}
 	server := &UnifiedShortenerServer{
-		opts: opts,
+		opts:           opts,
+		rateLimits:     ratelimit.NewLimits(opts),
+		cookieRing:     cookieRing,
+		trustedProxies: trustedProxies,
 	}
 
 	for _, option := range options {
@@ -135,6 +173,30 @@ func WithUnifiedDeleteHandler(handler Handler) UnifiedServerOption {
 	}
 }
 
+// WithUnifiedDeleteJobHandler устанавливает обработчик статуса запроса на удаление.
+func WithUnifiedDeleteJobHandler(handler Handler) UnifiedServerOption {
+	return func(server *UnifiedShortenerServer) error {
+		server.deleteJobHandler = handler
+		return nil
+	}
+}
+
+// WithUnifiedRestoreHandler устанавливает обработчик восстановления удаленных ссылок.
+func WithUnifiedRestoreHandler(handler Handler) UnifiedServerOption {
+	return func(server *UnifiedShortenerServer) error {
+		server.restoreHandler = handler
+		return nil
+	}
+}
+
+// WithUnifiedUpdateLinkHandler устанавливает обработчик изменения оригинального URL ссылки.
+func WithUnifiedUpdateLinkHandler(handler Handler) UnifiedServerOption {
+	return func(server *UnifiedShortenerServer) error {
+		server.updateLinkHandler = handler
+		return nil
+	}
+}
+
 // WithUnifiedGetStatsHandler устанавливает обработчик статистики.
 func WithUnifiedGetStatsHandler(handler Handler) UnifiedServerOption {
 	return func(server *UnifiedShortenerServer) error {
@@ -143,11 +205,93 @@ func WithUnifiedGetStatsHandler(handler Handler) UnifiedServerOption {
 	}
 }
 
+// WithUnifiedLinkStatsHandler устанавливает обработчик статистики переходов по ссылке.
+func WithUnifiedLinkStatsHandler(handler Handler) UnifiedServerOption {
+	return func(server *UnifiedShortenerServer) error {
+		server.linkStatsHandler = handler
+		return nil
+	}
+}
+
+// WithUnifiedUserStatsHandler устанавливает обработчик статистики пользователя.
+func WithUnifiedUserStatsHandler(handler Handler) UnifiedServerOption {
+	return func(server *UnifiedShortenerServer) error {
+		server.userStatsHandler = handler
+		return nil
+	}
+}
+
+// WithUnifiedTokenHandler устанавливает обработчик выпуска JWT токенов.
+func WithUnifiedTokenHandler(handler Handler) UnifiedServerOption {
+	return func(server *UnifiedShortenerServer) error {
+		server.tokenHandler = handler
+		return nil
+	}
+}
+
+// WithUnifiedLivenessHandler устанавливает обработчик проверки живости сервиса.
+func WithUnifiedLivenessHandler(handler Handler) UnifiedServerOption {
+	return func(server *UnifiedShortenerServer) error {
+		server.livenessHandler = handler
+		return nil
+	}
+}
+
+// WithUnifiedReadinessHandler устанавливает обработчик проверки готовности сервиса.
+func WithUnifiedReadinessHandler(handler Handler) UnifiedServerOption {
+	return func(server *UnifiedShortenerServer) error {
+		server.readinessHandler = handler
+		return nil
+	}
+}
+
+// WithUnifiedHealthService устанавливает сервис проверок, без него gRPC сервис grpc.health.v1 не регистрируется.
+func WithUnifiedHealthService(healthService *services.HealthService) UnifiedServerOption {
+	return func(server *UnifiedShortenerServer) error {
+		server.healthService = healthService
+		return nil
+	}
+}
+
+// WithUnifiedAPIKeyService устанавливает сервис ключей API, без него ключи API не принимаются.
+func WithUnifiedAPIKeyService(service *services.APIKeyService) UnifiedServerOption {
+	return func(server *UnifiedShortenerServer) error {
+		if service != nil {
+			server.apiKeys = service
+		}
+		return nil
+	}
+}
+
+// WithUnifiedAPIKeysHandler устанавливает обработчик управления ключами API.
+func WithUnifiedAPIKeysHandler(handler *handlers.APIKeysHandler) UnifiedServerOption {
+	return func(server *UnifiedShortenerServer) error {
+		server.apiKeysHandler = handler
+		return nil
+	}
+}
+
+// WithUnifiedAuditHandler устанавливает обработчик чтения журнала аудита.
+func WithUnifiedAuditHandler(handler Handler) UnifiedServerOption {
+	return func(server *UnifiedShortenerServer) error {
+		server.auditHandler = handler
+		return nil
+	}
+}
+
+// WithUnifiedMetrics устанавливает метрики Prometheus, без них эндпоинт метрик не регистрируется.
+func WithUnifiedMetrics(m *metrics.Metrics) UnifiedServerOption {
+	return func(server *UnifiedShortenerServer) error {
+		server.metrics = m
+		return nil
+	}
+}
+
 // WithGRPCHandler устанавливает gRPC обработчик.
 func WithGRPCHandler(service handlers.ShorterService, deleteWorker handlers.DeleterWorker,
-	baseURL string, opts *config.Options) UnifiedServerOption {
+	healthService *services.HealthService, baseURL string, opts *config.Options) UnifiedServerOption {
 	return func(server *UnifiedShortenerServer) error {
-		server.grpcHandler = grpchandlers.NewShortenerGRPCHandler(service, deleteWorker, baseURL, opts)
+		server.grpcHandler = grpchandlers.NewShortenerGRPCHandler(service, deleteWorker, healthService, baseURL, opts)
 		return nil
 	}
 }
@@ -258,31 +402,37 @@ func (server *UnifiedShortenerServer) runGRPCServer(ctx context.Context) error {
 	}
 
 	// Create gRPC server with interceptors
-	var grpcServer *grpc.Server
+	unaryInterceptors := []grpc.UnaryServerInterceptor{interceptors.TracingInterceptor()}
+	if server.metrics != nil {
+		unaryInterceptors = append(unaryInterceptors, interceptors.MetricsInterceptor(server.metrics))
+	}
+	unaryInterceptors = append(unaryInterceptors,
+		interceptors.LoggingInterceptor(),
+		interceptors.AuditInterceptor(),
+		interceptors.AuthInterceptor(server.opts, server.cookieRing, server.apiKeys),
+		interceptors.RateLimitInterceptor(map[string]*ratelimit.Limiter{
+			"CreateShortLink":      server.rateLimits.Create,
+			"CreateShortLinkBatch": server.rateLimits.Batch,
+			"GetURL":               server.rateLimits.Redirect,
+		}, server.metrics),
+	)
 	if server.opts.TrustedSubnet != "" {
 		// Add trusted subnet interceptor for stats endpoint
 		trustedSubnetConfig := interceptors.NewTrustedSubnetConfigWithSuffix(
 			server.opts.TrustedSubnet,
 			"GetStats",
 		)
-		grpcServer = grpc.NewServer(
-			grpc.ChainUnaryInterceptor(
-				interceptors.LoggingInterceptor(),
-				interceptors.AuthInterceptor(server.opts),
-				interceptors.TrustedSubnetInterceptor(trustedSubnetConfig),
-			),
-		)
-	} else {
-		// No trusted subnet configured
-		grpcServer = grpc.NewServer(
-			grpc.ChainUnaryInterceptor(
-				interceptors.LoggingInterceptor(),
-				interceptors.AuthInterceptor(server.opts),
-			),
-		)
+		unaryInterceptors = append(unaryInterceptors, interceptors.TrustedSubnetInterceptor(trustedSubnetConfig))
 	}
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(unaryInterceptors...))
 
 	pb.RegisterShortenerServiceServer(grpcServer, server.grpcHandler)
+	var healthServer *health.Server
+	if server.healthService != nil {
+		healthServer = health.NewServer()
+		healthpb.RegisterHealthServer(grpcServer, healthServer)
+		go server.runGRPCHealthUpdates(ctx, healthServer)
+	}
 
 	// Start gRPC server in a goroutine
 	go func() {
@@ -295,6 +445,10 @@ func (server *UnifiedShortenerServer) runGRPCServer(ctx context.Context) error {
 	// Wait for context cancellation, then shut down
 	<-ctx.Done()
 	log.Zap.Info("Shutting down gRPC server...")
+	if healthServer != nil {
+		// Report NOT_SERVING so that clients stop sending new requests while in-flight ones finish.
+		healthServer.Shutdown()
+	}
 
 	// Graceful stop with timeout
 	stopped := make(chan struct{})
@@ -315,37 +469,132 @@ func (server *UnifiedShortenerServer) runGRPCServer(ctx context.Context) error {
 	return nil
 }
 
+// runGRPCHealthUpdates periodically sets the gRPC health status of the server and the shortener service
+// from the readiness checks until ctx is cancelled.
+func (server *UnifiedShortenerServer) runGRPCHealthUpdates(ctx context.Context, healthServer *health.Server) {
+	ticker := time.NewTicker(healthUpdatePeriod)
+	defer ticker.Stop()
+	for {
+		status := healthpb.HealthCheckResponse_SERVING
+		if err := server.healthService.Readiness(ctx).Err(); err != nil {
+			log.Zap.Warn("Readiness check failed", zap.Error(err))
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		healthServer.SetServingStatus("", status)
+		healthServer.SetServingStatus(pb.ShortenerService_ServiceDesc.ServiceName, status)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// routePrefixes lists the top-level paths registered by initRouter besides the configurable metrics path.
+// Their first segments cannot be taken by short links, which are served on /{id}.
+var routePrefixes = []string{"/api/", "/debug/pprof/", "/healthz", "/ping", "/readyz"}
+
+// reservedPaths returns the top-level paths of the HTTP API, including the metrics path, for alias rules.
+func reservedPaths(opts *config.Options) []string {
+	return append(slices.Clone(routePrefixes), metricsPath(opts))
+}
+
+// metricsPath returns the configured path of the Prometheus metrics endpoint or the default one.
+func metricsPath(opts *config.Options) string {
+	if opts.MetricsPath == "" {
+		return constants.MetricsPath
+	}
+	return opts.MetricsPath
+}
+
 // initRouter initializes the HTTP router (same as ChiShortenerServer).
+// A new top-level route should be added to routePrefixes.
 func (server *UnifiedShortenerServer) initRouter() *chi.Mux {
 	r := chi.NewRouter()
+	r.Use(middlewares.TracingMiddleware)
+	if server.metrics != nil {
+		r.Use(middlewares.MetricsMiddleware(server.metrics))
+	}
 	r.Use(middlewares.LogMiddleware)
 	r.Use(middlewares.GzipMiddleware)
 	r.Use(middleware.Recoverer)
+	r.Use(middlewares.AuditMiddleware(server.trustedProxies))
 
-	r.Get("/{id}", server.getHandler.Handle)
+	r.With(middlewares.RateLimitMiddleware(server.rateLimits.Redirect, server.metrics, server.trustedProxies)).
+		Get("/{id}", server.getHandler.Handle)
 	r.Get("/ping", server.pingHandler.Handle)
+	if server.livenessHandler != nil {
+		r.Get("/healthz", server.livenessHandler.Handle)
+	}
+	if server.readinessHandler != nil {
+		r.Get("/readyz", server.readinessHandler.Handle)
+	}
 
 	// Routes with authentication
 	r.Group(func(r chi.Router) {
-		r.Use(middlewares.AuthMiddleware(server.opts))
-		r.Post("/", server.postHandler.Handle)
-		r.Post("/api/shorten", server.shortenHandler.Handle)
-		r.Post("/api/shorten/batch", server.batchHandler.Handle)
-		r.Get("/api/user/urls", server.urlsHandler.Handle)
-		r.Delete("/api/user/urls", server.deleteHandler.Handle)
+		r.Use(middlewares.AuthMiddleware(server.opts, server.cookieRing, server.apiKeys))
+		create := r.With(middlewares.RequireScope(services.ScopeCreate))
+		createOne := create.With(middlewares.RateLimitMiddleware(server.rateLimits.Create, server.metrics,
+			server.trustedProxies))
+		createOne.Post("/", server.postHandler.Handle)
+		createOne.Post("/api/shorten", server.shortenHandler.Handle)
+		create.With(middlewares.RateLimitMiddleware(server.rateLimits.Batch, server.metrics, server.trustedProxies)).
+			Post("/api/shorten/batch", server.batchHandler.Handle)
+		if server.updateLinkHandler != nil {
+			create.Patch("/api/user/urls/{id}", server.updateLinkHandler.Handle)
+		}
+		r.With(middlewares.RequireScope(services.ScopeRead)).Get("/api/user/urls", server.urlsHandler.Handle)
+		deletion := r.With(middlewares.RequireScope(services.ScopeDelete))
+		deletion.Delete("/api/user/urls", server.deleteHandler.Handle)
+		if server.deleteJobHandler != nil {
+			deletion.Get(handlers.DeleteJobPath+"{id}", server.deleteJobHandler.Handle)
+		}
+		if server.restoreHandler != nil {
+			deletion.Post("/api/user/urls/restore", server.restoreHandler.Handle)
+		}
+		stats := r.With(middlewares.RequireScope(services.ScopeStats))
+		stats.Get("/api/user/urls/{id}/stats", server.linkStatsHandler.Handle)
+		stats.Get("/api/user/stats", server.userStatsHandler.Handle)
+		if server.tokenHandler != nil {
+			r.With(middlewares.DenyAPIKey).Post("/api/auth/token", server.tokenHandler.Handle)
+		}
 	})
 
 	r.Group(func(r chi.Router) {
 		r.Use(middlewares.TrustedSubnetMiddleware(server.opts.TrustedSubnet))
 		r.Get("/api/internal/stats", server.getStatsHandler.Handle)
+		if server.apiKeysHandler != nil {
+			r.Post("/api/internal/keys", server.apiKeysHandler.HandleCreate)
+			r.Get("/api/internal/keys", server.apiKeysHandler.HandleList)
+			r.Delete("/api/internal/keys/{id}", server.apiKeysHandler.HandleRevoke)
+		}
+		if server.auditHandler != nil {
+			r.Get("/api/internal/audit", server.auditHandler.Handle)
+		}
 	})
 
+	if server.metrics != nil {
+		server.mountMetrics(r)
+	}
+
 	if server.opts.Performance != nil && *server.opts.Performance {
 		r.Handle("/debug/pprof/*", http.DefaultServeMux)
 	}
 	return r
 }
 
+// mountMetrics registers the Prometheus metrics endpoint, optionally restricted to the trusted subnet.
+func (server *UnifiedShortenerServer) mountMetrics(r chi.Router) {
+	if server.opts.MetricsTrustedOnly != nil && *server.opts.MetricsTrustedOnly {
+		r = r.With(middlewares.TrustedSubnetMiddleware(server.opts.TrustedSubnet))
+	}
+	r.Method(http.MethodGet, metricsPath(server.opts), server.metrics.Handler())
+}
+
 // listenTLS starts HTTPS server with automatic certificate management.
 func (server *UnifiedShortenerServer) listenTLS(serv *http.Server) error {
 	// Configure TLS certificate manager
@@ -1,14 +1,32 @@
 package app
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/VladSnap/shortener/internal/config"
+	"github.com/VladSnap/shortener/internal/data"
 	"github.com/VladSnap/shortener/internal/data/repos"
 	"github.com/VladSnap/shortener/internal/handlers"
 	"github.com/VladSnap/shortener/internal/services"
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 // TestNewUnifiedShortenerServerWithOptions tests the creation of UnifiedShortenerServer with various options.
@@ -27,7 +45,7 @@ func TestNewUnifiedShortenerServerWithOptions(t *testing.T) {
 		// Создаем необходимые зависимости
 		repo := repos.NewShortLinkRepo()
 		shorterService := services.NewNaiveShorterService(repo)
-		deleteWorker := handlers.NewDeleteWorker(shorterService)
+		deleteWorker := handlers.NewDeleteWorker(shorterService, repos.NewInMemoryDeleteQueue())
 		deleteWorker.RunWork()
 		defer func() {
 			if err := deleteWorker.Close(); err != nil {
@@ -37,9 +55,9 @@ func TestNewUnifiedShortenerServerWithOptions(t *testing.T) {
 
 		// Создаем обработчики
 		postHandler := handlers.NewPostHandler(shorterService, cfg.BaseURL)
-		getHandler := handlers.NewGetHandler(shorterService)
+		getHandler := handlers.NewGetHandler(shorterService, services.NewClickTracker(repos.NewInMemoryClickRepo()))
 		shortenHandler := handlers.NewShortenHandler(shorterService, cfg.BaseURL)
-		pingHandler := handlers.NewGetPingHandler(cfg)
+		pingHandler := handlers.NewGetPingHandler(services.NewHealthService())
 		batchHandler := handlers.NewBatchHandler(shorterService, cfg.BaseURL)
 		urlsHandler := handlers.NewUrlsHandler(shorterService, cfg.BaseURL)
 		deleteHandler := handlers.NewDeleteHandler(deleteWorker)
@@ -56,7 +74,7 @@ func TestNewUnifiedShortenerServerWithOptions(t *testing.T) {
 			WithUnifiedUrlsHandler(urlsHandler),
 			WithUnifiedDeleteHandler(deleteHandler),
 			WithUnifiedGetStatsHandler(getStatsHandler),
-			WithGRPCHandler(shorterService, deleteWorker, cfg.BaseURL, cfg),
+			WithGRPCHandler(shorterService, deleteWorker, services.NewHealthService(), cfg.BaseURL, cfg),
 		)
 
 		require.NoError(t, err)
@@ -86,7 +104,7 @@ func TestUnifiedServerOptionsValidation(t *testing.T) {
 	t.Run("Individual options set fields correctly", func(t *testing.T) {
 		repo := repos.NewShortLinkRepo()
 		shorterService := services.NewNaiveShorterService(repo)
-		deleteWorker := handlers.NewDeleteWorker(shorterService)
+		deleteWorker := handlers.NewDeleteWorker(shorterService, repos.NewInMemoryDeleteQueue())
 		deleteWorker.RunWork()
 		defer func() {
 			if err := deleteWorker.Close(); err != nil {
@@ -95,14 +113,14 @@ func TestUnifiedServerOptionsValidation(t *testing.T) {
 		}()
 
 		postHandler := handlers.NewPostHandler(shorterService, cfg.BaseURL)
-		getHandler := handlers.NewGetHandler(shorterService)
+		getHandler := handlers.NewGetHandler(shorterService, services.NewClickTracker(repos.NewInMemoryClickRepo()))
 
 		server := &UnifiedShortenerServer{opts: cfg}
 
 		// Применяем отдельные опции
 		postOption := WithUnifiedPostHandler(postHandler)
 		getOption := WithUnifiedGetHandler(getHandler)
-		grpcOption := WithGRPCHandler(shorterService, deleteWorker, cfg.BaseURL, cfg)
+		grpcOption := WithGRPCHandler(shorterService, deleteWorker, services.NewHealthService(), cfg.BaseURL, cfg)
 
 		err := postOption(server)
 		require.NoError(t, err)
@@ -116,3 +134,554 @@ func TestUnifiedServerOptionsValidation(t *testing.T) {
 		assert.NotNil(t, server.grpcHandler)
 	})
 }
+
+// TestUnifiedServerMetrics tests that requests are counted and exposed on the metrics endpoint.
+func TestUnifiedServerMetrics(t *testing.T) {
+	resMng := services.NewResourceManager()
+	defer func() {
+		if err := resMng.Cleanup(); err != nil {
+			t.Fatalf("failed to cleanup resources: %v", err)
+		}
+	}()
+	newRouter := func(cfg *config.Options) http.Handler {
+		server, err := NewServerBuilder(cfg, resMng).WithRepository().WithServices().WithHandlers().Build()
+		require.NoError(t, err)
+		return server.(*UnifiedShortenerServer).initRouter() //nolint:forcetypeassert // builder returns unified server
+	}
+
+	t.Run("Metrics are exposed on configured path", func(t *testing.T) {
+		router := newRouter(&config.Options{BaseURL: "http://localhost:8080", MetricsPath: "/internal/metrics"})
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown1", http.NoBody))
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/internal/metrics", http.NoBody))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `shortener_http_requests_total{method="GET",route="/{id}",status="404"} 1`)
+		assert.Contains(t, rec.Body.String(), `shortener_redirects_total{result="not_found"} 1`)
+	})
+
+	t.Run("Metrics are restricted to trusted subnet", func(t *testing.T) {
+		trustedOnly := true
+		router := newRouter(&config.Options{BaseURL: "http://localhost:8080", MetricsPath: "/metrics",
+			MetricsTrustedOnly: &trustedOnly, TrustedSubnet: "10.0.0.0/8"})
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+
+		req := httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody)
+		req.Header.Set("X-Real-IP", "10.1.2.3")
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+// TestUnifiedServerTracing tests that HTTP requests continue the caller trace and are named by route.
+func TestUnifiedServerTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	resMng := services.NewResourceManager()
+	defer func() {
+		if err := resMng.Cleanup(); err != nil {
+			t.Fatalf("failed to cleanup resources: %v", err)
+		}
+	}()
+	server, err := NewServerBuilder(&config.Options{BaseURL: "http://localhost:8080"}, resMng).
+		WithRepository().WithServices().WithHandlers().Build()
+	require.NoError(t, err)
+	router := server.(*UnifiedShortenerServer).initRouter() //nolint:forcetypeassert // builder returns unified server
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/unknown1", http.NoBody)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var httpSpan sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.SpanKind() == trace.SpanKindServer {
+			httpSpan = span
+		}
+	}
+	require.NotNil(t, httpSpan)
+	assert.Equal(t, "GET /{id}", httpSpan.Name())
+	assert.Equal(t, traceID, httpSpan.SpanContext().TraceID().String())
+	assert.Contains(t, httpSpan.Attributes(), semconv.HTTPResponseStatusCode(http.StatusNotFound))
+}
+
+// TestUnifiedServerHealth tests liveness and readiness probes over HTTP and gRPC.
+func TestUnifiedServerHealth(t *testing.T) {
+	resMng := services.NewResourceManager()
+	defer func() {
+		if err := resMng.Cleanup(); err != nil {
+			t.Fatalf("failed to cleanup resources: %v", err)
+		}
+	}()
+	cfg := &config.Options{BaseURL: "http://localhost:8080", FileStoragePath: filepath.Join(t.TempDir(), "storage.json")}
+	builder := NewServerBuilder(cfg, resMng).WithRepository().WithServices().WithHandlers()
+	server, err := builder.Build()
+	require.NoError(t, err)
+	unified := server.(*UnifiedShortenerServer) //nolint:forcetypeassert // builder returns unified server
+	router := unified.initRouter()
+
+	get := func(path string) (int, handlers.HealthResponse) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, http.NoBody))
+		var resp handlers.HealthResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return rec.Code, resp
+	}
+
+	t.Run("Healthy service", func(t *testing.T) {
+		code, resp := get("/healthz")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, map[string]string{"delete_worker": handlers.HealthStatusOK}, resp.Checks)
+
+		code, resp = get("/readyz")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, handlers.HealthStatusOK, resp.Checks["storage"])
+	})
+
+	builder.GetOptions().GetHealthService().AddReadinessCheck("cache",
+		services.HealthCheckFunc(func(ctx context.Context) error { return errors.New("connection refused") }))
+
+	t.Run("Failed readiness check", func(t *testing.T) {
+		code, _ := get("/healthz")
+		assert.Equal(t, http.StatusOK, code)
+
+		code, resp := get("/readyz")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.Equal(t, handlers.HealthStatusFail, resp.Status)
+		assert.Equal(t, "connection refused", resp.Checks["cache"])
+	})
+
+	t.Run("gRPC health reflects readiness", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+		healthServer := health.NewServer()
+		go unified.runGRPCHealthUpdates(ctx, healthServer)
+
+		require.Eventually(t, func() bool {
+			resp, err := healthServer.Check(ctx, &healthpb.HealthCheckRequest{Service: "shortener.ShortenerService"})
+			return err == nil && resp.GetStatus() == healthpb.HealthCheckResponse_NOT_SERVING
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+// TestUnifiedServerBearerAuth tests exchanging the Auth cookie for a JWT and using it instead of the cookie.
+func TestUnifiedServerBearerAuth(t *testing.T) {
+	resMng := services.NewResourceManager()
+	defer func() {
+		if err := resMng.Cleanup(); err != nil {
+			t.Fatalf("failed to cleanup resources: %v", err)
+		}
+	}()
+	server, err := NewServerBuilder(&config.Options{BaseURL: "http://localhost:8080", AuthCookieKey: "secret",
+		JWTSecret: "jwt-secret"}, resMng).WithRepository().WithServices().WithHandlers().Build()
+	require.NoError(t, err)
+	router := server.(*UnifiedShortenerServer).initRouter() //nolint:forcetypeassert // builder returns unified server
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("http://example.com"))
+	req.Header.Set("Content-Type", "text/plain")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+	cookies := rec.Result().Cookies()
+	require.NoError(t, rec.Result().Body.Close())
+	require.Len(t, cookies, 1)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/auth/token", http.NoBody)
+	req.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var token handlers.TokenResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &token))
+
+	req = httptest.NewRequest(http.MethodGet, "/api/user/urls", http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "http://example.com")
+	assert.Empty(t, rec.Result().Cookies(), "bearer requests should not get a new cookie")
+	require.NoError(t, rec.Result().Body.Close())
+
+	req = httptest.NewRequest(http.MethodGet, "/api/user/urls", http.NoBody)
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken+"x")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestUnifiedServerAPIKeys(t *testing.T) {
+	resMng := services.NewResourceManager()
+	defer func() {
+		if err := resMng.Cleanup(); err != nil {
+			t.Fatalf("failed to cleanup resources: %v", err)
+		}
+	}()
+	server, err := NewServerBuilder(&config.Options{BaseURL: "http://localhost:8080", AuthCookieKey: "secret",
+		TrustedSubnet: "10.0.0.0/8"}, resMng).WithRepository().WithServices().WithHandlers().Build()
+	require.NoError(t, err)
+	router := server.(*UnifiedShortenerServer).initRouter() //nolint:forcetypeassert // builder returns unified server
+	serve := func(method, target, body, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "text/plain")
+		req.Header.Set("X-Real-IP", "10.0.0.1")
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(http.MethodPost, "/api/internal/keys", `{"name":"importer","scopes":["create"]}`, "")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var key handlers.APIKeyResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &key))
+	require.NotEmpty(t, key.Key)
+
+	rec = serve(http.MethodPost, "/", "http://example.com", key.Key)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Empty(t, rec.Result().Cookies(), "api key requests should not get a cookie")
+	require.NoError(t, rec.Result().Body.Close())
+
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodGet, "/api/user/urls", "", key.Key).Code)
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodPost, "/api/auth/token", "", key.Key).Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodGet, "/api/user/urls", "", key.Key+"x").Code)
+
+	rec = serve(http.MethodGet, "/api/internal/keys", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), key.Key, "listing must not reveal keys")
+
+	assert.Equal(t, http.StatusNoContent, serve(http.MethodDelete, "/api/internal/keys/"+key.ID, "", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(http.MethodPost, "/", "http://example.com", key.Key).Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodDelete, "/api/internal/keys/missing", "", "").Code)
+}
+
+func TestUnifiedServerRateLimit(t *testing.T) {
+	resMng := services.NewResourceManager()
+	defer func() {
+		if err := resMng.Cleanup(); err != nil {
+			t.Fatalf("failed to cleanup resources: %v", err)
+		}
+	}()
+	newRouter := func(trustedProxies ...string) http.Handler {
+		server, err := NewServerBuilder(&config.Options{BaseURL: "http://localhost:8080", AuthCookieKey: "secret",
+			RateLimitCreatePerMin: 1, RateLimitRedirectPerMin: 60, RateLimitRedirectBurst: 1,
+			TrustedProxies: trustedProxies}, resMng).
+			WithRepository().WithServices().WithHandlers().Build()
+		require.NoError(t, err)
+		return server.(*UnifiedShortenerServer).initRouter() //nolint:forcetypeassert // builder returns unified server
+	}
+	// Запросы httptest приходят с адреса 192.0.2.1, которому доверяется заголовок X-Real-IP.
+	router := newRouter("192.0.2.1")
+	createOn := func(router http.Handler, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("http://example.com/"+ip))
+		req.Header.Set("Content-Type", "text/plain")
+		req.Header.Set("X-Real-IP", ip)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	create := func(ip string) *httptest.ResponseRecorder { return createOn(router, ip) }
+
+	rec := create("10.0.0.1")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	shortURL := strings.TrimPrefix(rec.Body.String(), "http://localhost:8080")
+
+	rec = create("10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code, "requests without cookie are limited by IP")
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusCreated, create("10.0.0.2").Code)
+
+	redirect := func() int {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, shortURL, http.NoBody))
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusTemporaryRedirect, redirect())
+	assert.Equal(t, http.StatusTooManyRequests, redirect())
+
+	untrusted := newRouter()
+	require.Equal(t, http.StatusCreated, createOn(untrusted, "10.0.0.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, createOn(untrusted, "10.0.0.2").Code,
+		"spoofed X-Real-IP from an untrusted address does not get a new limit")
+}
+
+func TestUnifiedServerDeleteJobs(t *testing.T) {
+	resMng := services.NewResourceManager()
+	defer func() {
+		if err := resMng.Cleanup(); err != nil {
+			t.Fatalf("failed to cleanup resources: %v", err)
+		}
+	}()
+	builder := NewServerBuilder(&config.Options{BaseURL: "http://localhost:8080", AuthCookieKey: "secret"}, resMng).
+		WithRepository().WithServices().WithHandlers()
+	server, err := builder.Build()
+	require.NoError(t, err)
+	router := server.(*UnifiedShortenerServer).initRouter() //nolint:forcetypeassert // builder returns unified server
+	serve := func(method, target, contentType, body string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(http.MethodPost, "/", "text/plain", "http://example.com")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	owner := rec.Result().Cookies()
+	require.NoError(t, rec.Result().Body.Close())
+	shortID := strings.TrimPrefix(rec.Body.String(), "http://localhost:8080/")
+
+	rec = serve(http.MethodPost, "/", "text/plain", "http://example.org")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	other := rec.Result().Cookies()
+	require.NoError(t, rec.Result().Body.Close())
+	foreignID := strings.TrimPrefix(rec.Body.String(), "http://localhost:8080/")
+
+	rec = serve(http.MethodDelete, "/api/user/urls", "application/json",
+		`["`+shortID+`","`+foreignID+`","missing1"]`, owner...)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	var deleted handlers.DeleteResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &deleted))
+	require.NotEmpty(t, deleted.JobID)
+	jobPath := handlers.DeleteJobPath + deleted.JobID
+	assert.Equal(t, jobPath, rec.Header().Get("Location"))
+
+	rec = serve(http.MethodGet, jobPath, "", "", owner...)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"pending"`)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, jobPath, "", "", other...).Code,
+		"job of another user should not be visible")
+
+	// Остановка воркера выполняет накопленные задачи.
+	require.NoError(t, builder.options.GetDeleteWorker().Close())
+
+	rec = serve(http.MethodGet, jobPath, "", "", owner...)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var job handlers.DeleteJobResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &job))
+	assert.Equal(t, "completed", job.Status)
+	assert.Equal(t, []handlers.DeleteJobItemResponse{
+		{ShortURL: shortID, Outcome: "deleted"},
+		{ShortURL: foreignID, Outcome: "not_owned"},
+		{ShortURL: "missing1", Outcome: "not_found"},
+	}, job.Items)
+}
+
+func TestUnifiedServerRestore(t *testing.T) {
+	resMng := services.NewResourceManager()
+	defer func() {
+		if err := resMng.Cleanup(); err != nil {
+			t.Fatalf("failed to cleanup resources: %v", err)
+		}
+	}()
+	builder := NewServerBuilder(&config.Options{BaseURL: "http://localhost:8080", AuthCookieKey: "secret"}, resMng).
+		WithRepository().WithServices().WithHandlers()
+	server, err := builder.Build()
+	require.NoError(t, err)
+	router := server.(*UnifiedShortenerServer).initRouter() //nolint:forcetypeassert // builder returns unified server
+	serve := func(method, target, contentType, body string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(http.MethodPost, "/", "text/plain", "http://example.com")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	owner := rec.Result().Cookies()
+	require.NoError(t, rec.Result().Body.Close())
+	shortID := strings.TrimPrefix(rec.Body.String(), "http://localhost:8080/")
+
+	rec = serve(http.MethodDelete, "/api/user/urls", "application/json", `["`+shortID+`"]`, owner...)
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	require.NoError(t, builder.options.GetDeleteWorker().Close())
+	require.Equal(t, http.StatusGone, serve(http.MethodGet, "/"+shortID, "", "").Code)
+
+	rec = serve(http.MethodPost, "/api/user/urls/restore", "application/json", `["`+shortID+`","missing1"]`, owner...)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var restored []handlers.RestoreItemResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &restored))
+	assert.Equal(t, []handlers.RestoreItemResponse{
+		{ShortURL: shortID, Outcome: "restored"},
+		{ShortURL: "missing1", Outcome: "not_found"},
+	}, restored)
+	assert.Equal(t, http.StatusTemporaryRedirect, serve(http.MethodGet, "/"+shortID, "", "").Code)
+}
+
+func TestUnifiedServerUpdateLink(t *testing.T) {
+	resMng := services.NewResourceManager()
+	defer func() {
+		if err := resMng.Cleanup(); err != nil {
+			t.Fatalf("failed to cleanup resources: %v", err)
+		}
+	}()
+	server, err := NewServerBuilder(&config.Options{BaseURL: "http://localhost:8080", AuthCookieKey: "secret",
+		CacheSize: 10}, resMng).WithRepository().WithServices().WithHandlers().Build()
+	require.NoError(t, err)
+	router := server.(*UnifiedShortenerServer).initRouter() //nolint:forcetypeassert // builder returns unified server
+	serve := func(method, target, body string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if method == http.MethodPost {
+			req.Header.Set("Content-Type", "text/plain")
+		}
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(http.MethodPost, "/", "http://example.com")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	owner := rec.Result().Cookies()
+	require.NoError(t, rec.Result().Body.Close())
+	shortID := strings.TrimPrefix(rec.Body.String(), "http://localhost:8080/")
+	// Ссылка попадает в кеш переходов до изменения.
+	assert.Equal(t, "http://example.com", serve(http.MethodGet, "/"+shortID, "").Header().Get("Location"))
+
+	rec = serve(http.MethodPatch, "/api/user/urls/"+shortID, `{"url":"http://example.org"}`, owner...)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var updated handlers.UpdateLinkResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &updated))
+	assert.Equal(t, "http://example.org", updated.OriginalURL)
+	require.Len(t, updated.History, 1)
+	assert.Equal(t, "http://example.com", updated.History[0].OriginalURL)
+	assert.Equal(t, "http://example.org", serve(http.MethodGet, "/"+shortID, "").Header().Get("Location"))
+
+	rec = serve(http.MethodPost, "/", "http://example.net")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	other := rec.Result().Cookies()
+	require.NoError(t, rec.Result().Body.Close())
+	assert.Equal(t, http.StatusForbidden,
+		serve(http.MethodPatch, "/api/user/urls/"+shortID, `{"url":"http://example.io"}`, other...).Code)
+	assert.Equal(t, http.StatusConflict,
+		serve(http.MethodPatch, "/api/user/urls/"+shortID, `{"url":"http://example.net"}`, owner...).Code,
+		"URL shortened by another link")
+}
+
+func TestUnifiedServerAudit(t *testing.T) {
+	resMng := services.NewResourceManager()
+	defer func() {
+		if err := resMng.Cleanup(); err != nil {
+			t.Fatalf("failed to cleanup resources: %v", err)
+		}
+	}()
+	storagePath := filepath.Join(t.TempDir(), "storage.json")
+	builder := NewServerBuilder(&config.Options{BaseURL: "http://localhost:8080", AuthCookieKey: "secret",
+		TrustedSubnet: "10.0.0.0/8", TrustedProxies: []string{"192.0.2.1"}, FileStoragePath: storagePath}, resMng).
+		WithRepository().WithServices().WithHandlers()
+	server, err := builder.Build()
+	require.NoError(t, err)
+	router := server.(*UnifiedShortenerServer).initRouter() //nolint:forcetypeassert // builder returns unified server
+	serve := func(method, target, body, realIP string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if method == http.MethodPost && target == "/" {
+			req.Header.Set("Content-Type", "text/plain")
+		}
+		req.Header.Set("X-Real-IP", realIP)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(http.MethodPost, "/", "http://example.com", "203.0.113.7")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	owner := rec.Result().Cookies()
+	require.NoError(t, rec.Result().Body.Close())
+	shortID := strings.TrimPrefix(rec.Body.String(), "http://localhost:8080/")
+	require.Equal(t, http.StatusAccepted,
+		serve(http.MethodDelete, "/api/user/urls", `["`+shortID+`"]`, "203.0.113.8", owner...).Code)
+	require.NoError(t, builder.options.GetDeleteWorker().Close())
+
+	assert.Equal(t, http.StatusForbidden, serve(http.MethodGet, "/api/internal/audit", "", "203.0.113.7").Code)
+	rec = serve(http.MethodGet, "/api/internal/audit", "", "10.0.0.1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var events []handlers.AuditEventResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &events))
+	require.Len(t, events, 2)
+	assert.Equal(t, services.AuditActionDelete, events[0].Action)
+	assert.Equal(t, "203.0.113.8", events[0].ClientIP)
+	assert.Equal(t, services.AuditActionCreate, events[1].Action)
+	assert.Equal(t, shortID, events[1].ShortURL)
+	assert.Equal(t, services.AuditSourceHTTP, events[1].Source)
+	assert.Equal(t, "203.0.113.7", events[1].ClientIP)
+	assert.Equal(t, "http://example.com", events[1].OriginalURL)
+
+	rec = serve(http.MethodGet, "/api/internal/audit?user_id=someone", "", "10.0.0.1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, "[]", rec.Body.String())
+
+	// Журнал файлового хранилища сохраняется в отдельном файле в формате JSON Lines.
+	fileRepo, err := repos.NewFileAuditRepo(storagePath + ".audit")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, fileRepo.Close()) }()
+	stored, err := fileRepo.GetEvents(t.Context(), &data.AuditQueryData{UserID: events[1].UserID})
+	require.NoError(t, err)
+	assert.Len(t, stored, 2)
+}
+
+// TestUnifiedServerReservedAliases tests that aliases matching the registered routes are rejected.
+func TestUnifiedServerReservedAliases(t *testing.T) {
+	resMng := services.NewResourceManager()
+	defer func() {
+		if err := resMng.Cleanup(); err != nil {
+			t.Fatalf("failed to cleanup resources: %v", err)
+		}
+	}()
+	performance := true
+	cfg := &config.Options{BaseURL: "http://localhost:8080", AuthCookieKey: "secret",
+		MetricsPath: "/internal/prometheus", Performance: &performance}
+	server, err := NewServerBuilder(cfg, resMng).WithRepository().WithServices().WithHandlers().Build()
+	require.NoError(t, err)
+	router := server.(*UnifiedShortenerServer).initRouter() //nolint:forcetypeassert // builder returns unified server
+	shorten := func(alias string) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/shorten",
+			strings.NewReader(`{"url":"http://example.com/`+alias+`","alias":"`+alias+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	segments := make(map[string]bool)
+	require.NoError(t, chi.Walk(router, func(_ string, route string, _ http.Handler,
+		_ ...func(http.Handler) http.Handler) error {
+		segment, _, _ := strings.Cut(strings.TrimPrefix(route, "/"), "/")
+		if segment != "" && segment != "{id}" {
+			segments[segment] = true
+		}
+		return nil
+	}))
+	require.Contains(t, segments, "internal", "metrics are registered on the configured path")
+	reserved := make(map[string]bool)
+	for _, path := range reservedPaths(cfg) {
+		segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+		reserved[segment] = true
+	}
+	for segment := range segments {
+		assert.True(t, reserved[segment], "route segment %q should be added to routePrefixes", segment)
+		assert.Equal(t, http.StatusBadRequest, shorten(segment), "alias %q matches a registered route", segment)
+	}
+	assert.Equal(t, http.StatusCreated, shorten("metrics"), "default metrics path is not registered")
+}
@@ -14,14 +14,18 @@ import (
 
 // CookieAuthData - Структура для хранения информации о пользователе в cookie в формате JSON.
 type CookieAuthData struct {
+	// UserID - Идентификатор пользователя.
 	UserID string `json:"user_id"`
+	// IssuedAt - Время выпуска куки в секундах Unix, отсутствует в куках старого формата.
+	IssuedAt int64 `json:"iat,omitempty"`
+	// ExpiresAt - Время истечения куки в секундах Unix, отсутствует в куках старого формата.
+	ExpiresAt int64 `json:"exp,omitempty"`
 }
 
 const cookieValidSegmentCount int = 2
 
 // CreateSignedCookie - Создает безопасную (подписаную) куки.
-func CreateSignedCookie(userID, authKey string) (string, error) {
-	data := CookieAuthData{userID}
+func CreateSignedCookie(data *CookieAuthData, authKey string) (string, error) {
 	var jsonBuf bytes.Buffer
 	err := json.NewEncoder(&jsonBuf).Encode(data)
 	if err != nil {
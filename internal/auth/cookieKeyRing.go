@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/config"
+	"github.com/VladSnap/shortener/internal/constants"
+)
+
+// ErrCookieExpired - Ошибка, которая возвращается, если срок действия куки истек.
+var ErrCookieExpired = errors.New("the cookie has expired")
+
+// CookieKeyRing - Набор ключей подписи куки Auth. Новые куки подписываются текущим ключом,
+// а предыдущими ключами куки только проверяются, чтобы ключ можно было сменить, не теряя пользователей.
+type CookieKeyRing struct {
+	keys   []string
+	maxAge time.Duration
+	// legacyUntil - Момент, до которого принимаются куки старого формата без срока действия.
+	legacyUntil time.Time
+	now         func() time.Time
+}
+
+// NewCookieKeyRing - Создает новую структуру CookieKeyRing с указателем по настройкам конфига.
+// Если AuthCookieLegacyUntil не задан, куки старого формата принимаются в течение времени жизни куки
+// с момента создания, чтобы пользователи успели получить куку нового формата.
+func NewCookieKeyRing(opts *config.Options) (*CookieKeyRing, error) {
+	maxAge := time.Duration(opts.AuthCookieMaxAgeSec) * time.Second
+	if maxAge <= 0 {
+		maxAge = constants.AuthCookieMaxAge
+	}
+	keys := make([]string, 0, 1+len(opts.AuthCookiePreviousKeys))
+	keys = append(keys, opts.AuthCookieKey)
+	for _, key := range opts.AuthCookiePreviousKeys {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	ring := &CookieKeyRing{keys: keys, maxAge: maxAge, now: time.Now}
+	ring.legacyUntil = ring.now().Add(maxAge)
+	if opts.AuthCookieLegacyUntil != "" {
+		legacyUntil, err := time.Parse(time.RFC3339, opts.AuthCookieLegacyUntil)
+		if err != nil {
+			return nil, fmt.Errorf("failed parse AuthCookieLegacyUntil: %w", err)
+		}
+		ring.legacyUntil = legacyUntil
+	}
+	return ring, nil
+}
+
+// MaxAge - Возвращает время жизни куки.
+func (ring *CookieKeyRing) MaxAge() time.Duration {
+	return ring.maxAge
+}
+
+// Issue - Создает куки для пользователя, подписанную текущим ключом.
+func (ring *CookieKeyRing) Issue(userID string) (string, error) {
+	now := ring.now()
+	data := &CookieAuthData{
+		UserID:    userID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ring.maxAge).Unix(),
+	}
+	return CreateSignedCookie(data, ring.keys[0])
+}
+
+// Verify - Проверяет подпись и срок действия куки и возвращает данные пользователя.
+// needRotate равен true, если куку нужно перевыпустить: она подписана предыдущим ключом,
+// создана в старом формате без срока действия или прожила больше половины срока.
+// Куки старого формата принимаются только до момента AuthCookieLegacyUntil из конфига или до истечения
+// времени жизни куки после запуска сервера.
+func (ring *CookieKeyRing) Verify(cookieValue string) (authData *CookieAuthData, needRotate bool, err error) {
+	keyIndex := -1
+	var verifyErr error
+	for i, key := range ring.keys {
+		if _, verifyErr = VerifySignCookie(cookieValue, key); verifyErr == nil {
+			keyIndex = i
+			break
+		}
+	}
+	if keyIndex < 0 {
+		return nil, false, verifyErr
+	}
+
+	authData, err = DecodeCookie(cookieValue)
+	if err != nil {
+		return nil, false, err
+	}
+	now := ring.now()
+	if authData.ExpiresAt == 0 {
+		if !now.Before(ring.legacyUntil) {
+			return nil, false, fmt.Errorf("%w: legacy cookie without expiry is no longer accepted", ErrCookieExpired)
+		}
+		return authData, true, nil
+	}
+
+	expiresAt := time.Unix(authData.ExpiresAt, 0)
+	if !now.Before(expiresAt) {
+		return nil, false, fmt.Errorf("%w at %s", ErrCookieExpired, expiresAt.UTC().Format(time.RFC3339))
+	}
+	issuedAt := time.Unix(authData.IssuedAt, 0)
+	halfLife := issuedAt.Add(expiresAt.Sub(issuedAt) / 2)
+	return authData, keyIndex > 0 || !now.Before(halfLife), nil
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCookieKeyRing(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	newRing := func(key string, previous ...string) *CookieKeyRing {
+		ring, err := NewCookieKeyRing(&config.Options{AuthCookieKey: key, AuthCookiePreviousKeys: previous,
+			AuthCookieMaxAgeSec: int((10 * time.Hour).Seconds())})
+		require.NoError(t, err)
+		ring.now = func() time.Time { return now }
+		return ring
+	}
+	ring := newRing("new", "old")
+
+	t.Run("fresh cookie", func(t *testing.T) {
+		cookie, err := ring.Issue("user1")
+		require.NoError(t, err)
+		authData, needRotate, err := ring.Verify(cookie)
+		require.NoError(t, err)
+		assert.Equal(t, "user1", authData.UserID)
+		assert.Equal(t, now.Add(10*time.Hour).Unix(), authData.ExpiresAt)
+		assert.False(t, needRotate)
+	})
+
+	t.Run("cookie signed with previous key", func(t *testing.T) {
+		cookie, err := newRing("old").Issue("user1")
+		require.NoError(t, err)
+		authData, needRotate, err := ring.Verify(cookie)
+		require.NoError(t, err)
+		assert.Equal(t, "user1", authData.UserID)
+		assert.True(t, needRotate)
+
+		_, _, err = newRing("new").Verify(cookie)
+		require.Error(t, err, "the old key is no longer accepted once removed from the ring")
+	})
+
+	t.Run("legacy cookie without expiry", func(t *testing.T) {
+		cookie, err := CreateSignedCookie(&CookieAuthData{UserID: "user1"}, "new")
+		require.NoError(t, err)
+		legacyRing, err := NewCookieKeyRing(&config.Options{AuthCookieKey: "new",
+			AuthCookieLegacyUntil: now.Add(time.Hour).Format(time.RFC3339)})
+		require.NoError(t, err)
+		legacyRing.now = func() time.Time { return now }
+		authData, needRotate, err := legacyRing.Verify(cookie)
+		require.NoError(t, err, "legacy cookie is accepted before the cutoff")
+		assert.Equal(t, "user1", authData.UserID)
+		assert.True(t, needRotate)
+
+		legacyRing.now = func() time.Time { return now.Add(time.Hour) }
+		_, _, err = legacyRing.Verify(cookie)
+		require.ErrorIs(t, err, ErrCookieExpired, "legacy cookie is rejected after the cutoff")
+	})
+
+	t.Run("legacy cookie without configured cutoff", func(t *testing.T) {
+		cookie, err := CreateSignedCookie(&CookieAuthData{UserID: "user1"}, "new")
+		require.NoError(t, err)
+		startedAt := time.Now()
+		legacyRing, err := NewCookieKeyRing(&config.Options{AuthCookieKey: "new",
+			AuthCookieMaxAgeSec: int((10 * time.Hour).Seconds())})
+		require.NoError(t, err)
+
+		legacyRing.now = func() time.Time { return startedAt.Add(9 * time.Hour) }
+		authData, needRotate, err := legacyRing.Verify(cookie)
+		require.NoError(t, err, "legacy cookie is accepted during the cookie max age after start")
+		assert.Equal(t, "user1", authData.UserID)
+		assert.True(t, needRotate)
+
+		legacyRing.now = func() time.Time { return startedAt.Add(11 * time.Hour) }
+		_, _, err = legacyRing.Verify(cookie)
+		require.ErrorIs(t, err, ErrCookieExpired, "legacy cookie is rejected after the grace period")
+	})
+
+	t.Run("malformed cutoff", func(t *testing.T) {
+		_, err := NewCookieKeyRing(&config.Options{AuthCookieKey: "new", AuthCookieLegacyUntil: "2025-03-01"})
+		require.Error(t, err)
+	})
+
+	t.Run("cookie past half of its lifetime", func(t *testing.T) {
+		cookie, err := ring.Issue("user1")
+		require.NoError(t, err)
+		later := newRing("new", "old")
+		later.now = func() time.Time { return now.Add(6 * time.Hour) }
+		_, needRotate, err := later.Verify(cookie)
+		require.NoError(t, err)
+		assert.True(t, needRotate)
+	})
+
+	t.Run("expired cookie", func(t *testing.T) {
+		cookie, err := ring.Issue("user1")
+		require.NoError(t, err)
+		later := newRing("new")
+		later.now = func() time.Time { return now.Add(10 * time.Hour) }
+		_, _, err = later.Verify(cookie)
+		require.ErrorIs(t, err, ErrCookieExpired)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		cookie, err := newRing("other").Issue("user1")
+		require.NoError(t, err)
+		_, _, err = ring.Verify(cookie)
+		require.Error(t, err)
+	})
+}
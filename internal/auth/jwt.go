@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/config"
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// bearerScheme - Схема авторизации в заголовке Authorization для JWT токена.
+const bearerScheme = "Bearer"
+
+// ErrInvalidToken - Ошибка, которая возвращается, если токен не прошел проверку.
+var ErrInvalidToken = errors.New("invalid token")
+
+// errSecretNotSet - Ошибка, которая возвращается, если ключ подписи JWT токенов не задан.
+var errSecretNotSet = errors.New("jwt secret is not set")
+
+// JWTManager - Выпускает и проверяет JWT токены доступа, подписанные HMAC SHA-256.
+// Идентификатор пользователя хранится в claim sub, как и в куке Auth.
+type JWTManager struct {
+	secret   []byte
+	issuer   string
+	audience string
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+// NewJWTManager - Создает новую структуру JWTManager с указателем по настройкам конфига.
+// Ключ JWT не заменяется ключом куки Auth: без него токены не выпускаются и не принимаются.
+// Издатель и аудитория всегда выпускаются и проверяются, если не заданы - используются значения по умолчанию.
+func NewJWTManager(opts *config.Options) *JWTManager {
+	ttl := time.Duration(opts.JWTTTLSec) * time.Second
+	if ttl <= 0 {
+		ttl = constants.JWTTTL
+	}
+	manager := &JWTManager{
+		secret:   []byte(opts.JWTSecret),
+		issuer:   opts.JWTIssuer,
+		audience: opts.JWTAudience,
+		ttl:      ttl,
+		now:      time.Now,
+	}
+	if manager.issuer == "" {
+		manager.issuer = constants.JWTIssuer
+	}
+	if manager.audience == "" {
+		manager.audience = constants.JWTAudience
+	}
+	return manager
+}
+
+// IssueToken - Выпускает токен для пользователя и возвращает его вместе со временем истечения.
+func (manager *JWTManager) IssueToken(userID string) (string, time.Time, error) {
+	if len(manager.secret) == 0 {
+		return "", time.Time{}, errSecretNotSet
+	}
+	now := manager.now()
+	expiresAt := now.Add(manager.ttl)
+	claims := jwt.RegisteredClaims{
+		Subject:   userID,
+		Issuer:    manager.issuer,
+		Audience:  jwt.ClaimStrings{manager.audience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(manager.secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed sign token: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// VerifyToken - Проверяет подпись, срок действия, издателя и аудиторию токена и возвращает идентификатор пользователя.
+func (manager *JWTManager) VerifyToken(tokenString string) (string, error) {
+	if len(manager.secret) == 0 {
+		return "", fmt.Errorf("%w: %w", ErrInvalidToken, errSecretNotSet)
+	}
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(manager.now),
+		jwt.WithIssuer(manager.issuer),
+		jwt.WithAudience(manager.audience),
+	}
+
+	var claims jwt.RegisteredClaims
+	_, err := jwt.ParseWithClaims(tokenString, &claims, func(*jwt.Token) (any, error) {
+		return manager.secret, nil
+	}, options...)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	if claims.Subject == "" {
+		return "", fmt.Errorf("%w: subject is empty", ErrInvalidToken)
+	}
+	return claims.Subject, nil
+}
+
+// ParseBearerToken - Извлекает токен из значения заголовка Authorization вида "Bearer <token>".
+// Схема сравнивается без учета регистра, как требует RFC 6750.
+func ParseBearerToken(authorization string) (string, bool) {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(authorization), " ")
+	if !ok || !strings.EqualFold(scheme, bearerScheme) {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
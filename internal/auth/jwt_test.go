@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestJWTManager(opts *config.Options) *JWTManager {
+	if opts.JWTSecret == "" {
+		opts.JWTSecret = "secret"
+	}
+	if opts.JWTIssuer == "" {
+		opts.JWTIssuer = "shortener"
+	}
+	if opts.JWTAudience == "" {
+		opts.JWTAudience = "shortener"
+	}
+	return NewJWTManager(opts)
+}
+
+func TestJWTManager(t *testing.T) {
+	manager := newTestJWTManager(&config.Options{JWTTTLSec: 60})
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	manager.now = func() time.Time { return now }
+
+	token, expiresAt, err := manager.IssueToken("user1")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Minute), expiresAt)
+
+	t.Run("valid token", func(t *testing.T) {
+		userID, err := manager.VerifyToken(token)
+		require.NoError(t, err)
+		assert.Equal(t, "user1", userID)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		expired := *manager
+		expired.now = func() time.Time { return now.Add(2 * time.Minute) }
+		_, err := expired.VerifyToken(token)
+		require.ErrorIs(t, err, ErrInvalidToken)
+		require.ErrorIs(t, err, jwt.ErrTokenExpired)
+	})
+
+	t.Run("other secret, issuer or audience", func(t *testing.T) {
+		for _, opts := range []*config.Options{
+			{JWTSecret: "other"},
+			{JWTIssuer: "other"},
+			{JWTAudience: "other"},
+		} {
+			other := newTestJWTManager(opts)
+			other.now = manager.now
+			_, err := other.VerifyToken(token)
+			require.ErrorIs(t, err, ErrInvalidToken)
+		}
+	})
+
+	t.Run("no secret", func(t *testing.T) {
+		// Без ключа JWT токены не выпускаются и не принимаются, ключ куки Auth не используется.
+		unset := NewJWTManager(&config.Options{AuthCookieKey: "secret"})
+		unset.now = manager.now
+		_, _, err := unset.IssueToken("user1")
+		require.Error(t, err)
+		_, err = unset.VerifyToken(token)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("default issuer and audience", func(t *testing.T) {
+		defaults := NewJWTManager(&config.Options{JWTSecret: "secret"})
+		defaults.now = manager.now
+		_, err := defaults.VerifyToken(token)
+		require.NoError(t, err)
+
+		noClaims, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+			Subject:   "user1",
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		}).SignedString([]byte("secret"))
+		require.NoError(t, err)
+		_, err = defaults.VerifyToken(noClaims)
+		require.ErrorIs(t, err, ErrInvalidToken, "issuer and audience are always checked")
+	})
+
+	t.Run("unsigned token", func(t *testing.T) {
+		unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{
+			Subject:   "user1",
+			Issuer:    "shortener",
+			Audience:  jwt.ClaimStrings{"shortener"},
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+		_, err = manager.VerifyToken(unsigned)
+		require.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestParseBearerToken(t *testing.T) {
+	tests := []struct {
+		authorization string
+		token         string
+		ok            bool
+	}{
+		{authorization: "Bearer abc.def.ghi", token: "abc.def.ghi", ok: true},
+		{authorization: "bearer  abc.def.ghi ", token: "abc.def.ghi", ok: true},
+		{authorization: "Basic dXNlcjpwYXNz"},
+		{authorization: "Bearer "},
+		{authorization: "abc.def.ghi"},
+	}
+	for _, tt := range tests {
+		token, ok := ParseBearerToken(tt.authorization)
+		assert.Equal(t, tt.ok, ok, tt.authorization)
+		assert.Equal(t, tt.token, token, tt.authorization)
+	}
+}
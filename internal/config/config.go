@@ -8,6 +8,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/VladSnap/shortener/internal/constants"
 	"github.com/VladSnap/shortener/internal/log"
 	"github.com/caarlos0/env/v6"
 	"go.uber.org/zap"
@@ -29,14 +30,92 @@ type Options struct {
 
 	// AuthCookieKey - Key for signing auth cookies
 	AuthCookieKey string `env:"AUTH_COOKIE_KEY" json:"-"`
+	// AuthCookiePreviousKeys - Previous keys that are still accepted for auth cookies, separated by commas
+	AuthCookiePreviousKeys []string `env:"AUTH_COOKIE_PREVIOUS_KEYS" envSeparator:"," json:"-"`
+	// AuthCookieMaxAgeSec - Lifetime in seconds of auth cookies
+	AuthCookieMaxAgeSec int `env:"AUTH_COOKIE_MAX_AGE_SEC" json:"auth_cookie_max_age_sec,omitempty"`
+	// AuthCookieSecure - Set the Secure attribute on auth cookies, defaults to EnableHTTPS
+	AuthCookieSecure *bool `env:"AUTH_COOKIE_SECURE" json:"auth_cookie_secure,omitempty"`
+	// AuthCookieSameSite - SameSite attribute of auth cookies: lax, strict or none
+	AuthCookieSameSite string `env:"AUTH_COOKIE_SAMESITE" json:"auth_cookie_samesite,omitempty"`
+	// AuthCookieLegacyUntil - Time in RFC 3339 until which legacy auth cookies without expiry are accepted,
+	// defaults to the auth cookie max age after the server start
+	AuthCookieLegacyUntil string `env:"AUTH_COOKIE_LEGACY_UNTIL" json:"auth_cookie_legacy_until,omitempty"`
 	// Performance - Enable pprof for performance testing
 	Performance *bool `json:"-"`
 	// ConfigPath path to config file
 	ConfigPath string `env:"CONFIG" json:"-"`
 	// TrustedSubnet - Trusted subnet for access to statistics
 	TrustedSubnet string `env:"TRUSTED_SUBNET" json:"trusted_subnet"`
+	// TrustedProxies - IPs or CIDR subnets of proxies whose X-Real-IP header is trusted besides TrustedSubnet,
+	// separated by commas
+	TrustedProxies []string `env:"TRUSTED_PROXIES" envSeparator:"," json:"trusted_proxies,omitempty"`
 	// GRPCAddress - gRPC server listen address
 	GRPCAddress string `env:"GRPC_ADDRESS" json:"grpc_address,omitempty"`
+	// AliasCharset - Allowed characters for custom short link aliases
+	AliasCharset string `env:"ALIAS_CHARSET" json:"alias_charset,omitempty"`
+	// AliasMinLength - Minimum length of custom short link aliases
+	AliasMinLength int `env:"ALIAS_MIN_LENGTH" json:"alias_min_length,omitempty"`
+	// AliasMaxLength - Maximum length of custom short link aliases
+	AliasMaxLength int `env:"ALIAS_MAX_LENGTH" json:"alias_max_length,omitempty"`
+	// PurgeIntervalSec - Interval in seconds between purges of deleted and expired links
+	PurgeIntervalSec int `env:"PURGE_INTERVAL_SEC" json:"purge_interval_sec,omitempty"`
+	// PurgeRetentionSec - Retention in seconds of deleted and expired links before they are purged
+	PurgeRetentionSec int `env:"PURGE_RETENTION_SEC" json:"purge_retention_sec,omitempty"`
+	// FileSyncPolicy - When the file storage log is synced to disk: always, interval or never
+	FileSyncPolicy string `env:"FILE_STORAGE_SYNC" json:"file_storage_sync,omitempty"`
+	// FileCompactThreshold - Number of file storage log records after which the log is compacted into a snapshot
+	FileCompactThreshold int `env:"FILE_STORAGE_COMPACT_THRESHOLD" json:"file_storage_compact_threshold,omitempty"`
+	// RedisAddr - Redis server address, when set short links are stored in Redis
+	RedisAddr string `env:"REDIS_ADDR" json:"redis_addr,omitempty"`
+	// CacheSize - Maximum number of short links in the redirect cache, a negative value disables the cache
+	CacheSize int `env:"CACHE_SIZE" json:"cache_size,omitempty"`
+	// CacheTTLSec - Lifetime in seconds of a cached short link
+	CacheTTLSec int `env:"CACHE_TTL_SEC" json:"cache_ttl_sec,omitempty"`
+	// CacheNegativeTTLSec - Lifetime in seconds of a cached unknown short ID
+	CacheNegativeTTLSec int `env:"CACHE_NEGATIVE_TTL_SEC" json:"cache_negative_ttl_sec,omitempty"`
+	// ShortIDStrategy - Strategy of short ID generation: random, sequence or hash
+	ShortIDStrategy string `env:"SHORT_ID_STRATEGY" json:"short_id_strategy,omitempty"`
+	// ShortIDAlphabet - Characters of generated short IDs
+	ShortIDAlphabet string `env:"SHORT_ID_ALPHABET" json:"short_id_alphabet,omitempty"`
+	// ShortIDLength - Length of generated short IDs, the minimum length for the sequence strategy
+	ShortIDLength int `env:"SHORT_ID_LENGTH" json:"short_id_length,omitempty"`
+	// MetricsPath - HTTP path of the Prometheus metrics endpoint
+	MetricsPath string `env:"METRICS_PATH" json:"metrics_path,omitempty"`
+	// MetricsTrustedOnly - Allow access to the metrics endpoint only from the trusted subnet
+	MetricsTrustedOnly *bool `env:"METRICS_TRUSTED_ONLY" json:"metrics_trusted_only,omitempty"`
+	// TracingExporter - OpenTelemetry span exporter: none, stdout or otlp
+	TracingExporter string `env:"TRACING_EXPORTER" json:"tracing_exporter,omitempty"`
+	// TracingEndpoint - OTLP/gRPC collector address for the otlp exporter
+	TracingEndpoint string `env:"TRACING_ENDPOINT" json:"tracing_endpoint,omitempty"`
+	// TracingInsecure - Connect to the OTLP collector without TLS
+	TracingInsecure *bool `env:"TRACING_INSECURE" json:"tracing_insecure,omitempty"`
+	// JWTSecret - Key for signing JWT access tokens, required
+	JWTSecret string `env:"JWT_SECRET" json:"-"`
+	// JWTIssuer - Issuer (iss claim) of JWT access tokens
+	JWTIssuer string `env:"JWT_ISSUER" json:"jwt_issuer,omitempty"`
+	// JWTAudience - Audience (aud claim) of JWT access tokens
+	JWTAudience string `env:"JWT_AUDIENCE" json:"jwt_audience,omitempty"`
+	// JWTTTLSec - Lifetime in seconds of issued JWT access tokens
+	JWTTTLSec int `env:"JWT_TTL_SEC" json:"jwt_ttl_sec,omitempty"`
+	// RateLimitCreatePerMin - Short links a client may create per minute, 0 disables the limit
+	RateLimitCreatePerMin int `env:"RATE_LIMIT_CREATE_PER_MIN" json:"rate_limit_create_per_min,omitempty"`
+	// RateLimitCreateBurst - Short links a client may create at once, defaults to RateLimitCreatePerMin
+	RateLimitCreateBurst int `env:"RATE_LIMIT_CREATE_BURST" json:"rate_limit_create_burst,omitempty"`
+	// RateLimitBatchPerMin - Batch requests a client may send per minute, 0 disables the limit
+	RateLimitBatchPerMin int `env:"RATE_LIMIT_BATCH_PER_MIN" json:"rate_limit_batch_per_min,omitempty"`
+	// RateLimitBatchBurst - Batch requests a client may send at once, defaults to RateLimitBatchPerMin
+	RateLimitBatchBurst int `env:"RATE_LIMIT_BATCH_BURST" json:"rate_limit_batch_burst,omitempty"`
+	// RateLimitRedirectPerMin - Redirects a client may follow per minute, 0 disables the limit
+	RateLimitRedirectPerMin int `env:"RATE_LIMIT_REDIRECT_PER_MIN" json:"rate_limit_redirect_per_min,omitempty"`
+	// RateLimitRedirectBurst - Redirects a client may follow at once, defaults to RateLimitRedirectPerMin
+	RateLimitRedirectBurst int `env:"RATE_LIMIT_REDIRECT_BURST" json:"rate_limit_redirect_burst,omitempty"`
+	// DeleteMaxAttempts - Attempts to delete a short link before the task is moved to the dead letters
+	DeleteMaxAttempts int `env:"DELETE_MAX_ATTEMPTS" json:"delete_max_attempts,omitempty"`
+	// DeleteRetryBackoffSec - Delay in seconds before the first retry of a failed deletion, doubled on each retry
+	DeleteRetryBackoffSec int `env:"DELETE_RETRY_BACKOFF_SEC" json:"delete_retry_backoff_sec,omitempty"`
+	// RestoreWindowSec - Time in seconds after deletion during which a link can be restored by its owner
+	RestoreWindowSec int `env:"RESTORE_WINDOW_SEC" json:"restore_window_sec,omitempty"`
 }
 
 // MarshalLogObject - Сериализует структуру конфига для эффективного логирования.
@@ -55,10 +134,61 @@ func (opts *Options) MarshalLogObject(enc zapcore.ObjectEncoder) error {
 	} else {
 		enc.AddBool("Performance", *opts.Performance)
 	}
-	enc.AddString("AuthCookieKey", opts.AuthCookieKey)
+	// Ключи подписи не пишутся в лог, чтобы по логу нельзя было подделать куки и токены.
+	enc.AddBool("AuthCookieKeySet", opts.AuthCookieKey != "")
+	enc.AddInt("AuthCookiePreviousKeys", len(opts.AuthCookiePreviousKeys))
+	enc.AddInt("AuthCookieMaxAgeSec", opts.AuthCookieMaxAgeSec)
+	if opts.AuthCookieSecure == nil {
+		enc.AddString("AuthCookieSecure", "nil")
+	} else {
+		enc.AddBool("AuthCookieSecure", *opts.AuthCookieSecure)
+	}
+	enc.AddString("AuthCookieSameSite", opts.AuthCookieSameSite)
+	enc.AddString("AuthCookieLegacyUntil", opts.AuthCookieLegacyUntil)
 	enc.AddString("ConfigPath", opts.ConfigPath)
 	enc.AddString("TrustedSubnet", opts.TrustedSubnet)
+	enc.AddString("TrustedProxies", strings.Join(opts.TrustedProxies, ","))
 	enc.AddString("GRPCAddress", opts.GRPCAddress)
+	enc.AddString("AliasCharset", opts.AliasCharset)
+	enc.AddInt("AliasMinLength", opts.AliasMinLength)
+	enc.AddInt("AliasMaxLength", opts.AliasMaxLength)
+	enc.AddInt("PurgeIntervalSec", opts.PurgeIntervalSec)
+	enc.AddInt("PurgeRetentionSec", opts.PurgeRetentionSec)
+	enc.AddString("FileSyncPolicy", opts.FileSyncPolicy)
+	enc.AddInt("FileCompactThreshold", opts.FileCompactThreshold)
+	enc.AddString("RedisAddr", opts.RedisAddr)
+	enc.AddInt("CacheSize", opts.CacheSize)
+	enc.AddInt("CacheTTLSec", opts.CacheTTLSec)
+	enc.AddInt("CacheNegativeTTLSec", opts.CacheNegativeTTLSec)
+	enc.AddString("ShortIDStrategy", opts.ShortIDStrategy)
+	enc.AddString("ShortIDAlphabet", opts.ShortIDAlphabet)
+	enc.AddInt("ShortIDLength", opts.ShortIDLength)
+	enc.AddString("MetricsPath", opts.MetricsPath)
+	if opts.MetricsTrustedOnly == nil {
+		enc.AddString("MetricsTrustedOnly", "nil")
+	} else {
+		enc.AddBool("MetricsTrustedOnly", *opts.MetricsTrustedOnly)
+	}
+	enc.AddString("TracingExporter", opts.TracingExporter)
+	enc.AddString("TracingEndpoint", opts.TracingEndpoint)
+	if opts.TracingInsecure == nil {
+		enc.AddString("TracingInsecure", "nil")
+	} else {
+		enc.AddBool("TracingInsecure", *opts.TracingInsecure)
+	}
+	enc.AddBool("JWTSecretSet", opts.JWTSecret != "")
+	enc.AddString("JWTIssuer", opts.JWTIssuer)
+	enc.AddString("JWTAudience", opts.JWTAudience)
+	enc.AddInt("JWTTTLSec", opts.JWTTTLSec)
+	enc.AddInt("RateLimitCreatePerMin", opts.RateLimitCreatePerMin)
+	enc.AddInt("RateLimitCreateBurst", opts.RateLimitCreateBurst)
+	enc.AddInt("RateLimitBatchPerMin", opts.RateLimitBatchPerMin)
+	enc.AddInt("RateLimitBatchBurst", opts.RateLimitBatchBurst)
+	enc.AddInt("RateLimitRedirectPerMin", opts.RateLimitRedirectPerMin)
+	enc.AddInt("RateLimitRedirectBurst", opts.RateLimitRedirectBurst)
+	enc.AddInt("DeleteMaxAttempts", opts.DeleteMaxAttempts)
+	enc.AddInt("DeleteRetryBackoffSec", opts.DeleteRetryBackoffSec)
+	enc.AddInt("RestoreWindowSec", opts.RestoreWindowSec)
 	return nil
 }
 
@@ -96,6 +226,9 @@ func InitConfig(validater ConfigValidater) (*Options, error) {
 		return nil, fmt.Errorf("config validating failed: %w", err)
 	}
 
+	if opts.AuthCookieKey == constants.DefaultAuthCookieKey {
+		log.Zap.Warn("AuthCookieKey is not set, the default development key is used to sign auth cookies")
+	}
 	log.Zap.Info("Config loaded", zap.Object("config", opts))
 	return opts, nil
 }
@@ -195,15 +328,126 @@ func mergeOptions(flagEnvOpts, fileOpts *Options) *Options {
 	if merged.AuthCookieKey == "" && fileOpts.AuthCookieKey != "" {
 		merged.AuthCookieKey = fileOpts.AuthCookieKey
 	}
+	if merged.AuthCookieMaxAgeSec == 0 && fileOpts.AuthCookieMaxAgeSec != 0 {
+		merged.AuthCookieMaxAgeSec = fileOpts.AuthCookieMaxAgeSec
+	}
+	if merged.AuthCookieSecure == nil && fileOpts.AuthCookieSecure != nil {
+		merged.AuthCookieSecure = fileOpts.AuthCookieSecure
+	}
+	if merged.AuthCookieSameSite == "" && fileOpts.AuthCookieSameSite != "" {
+		merged.AuthCookieSameSite = fileOpts.AuthCookieSameSite
+	}
+	if merged.AuthCookieLegacyUntil == "" && fileOpts.AuthCookieLegacyUntil != "" {
+		merged.AuthCookieLegacyUntil = fileOpts.AuthCookieLegacyUntil
+	}
 	if merged.ConfigPath == "" && fileOpts.ConfigPath != "" {
 		merged.ConfigPath = fileOpts.ConfigPath
 	}
 	if merged.TrustedSubnet == "" && fileOpts.TrustedSubnet != "" {
 		merged.TrustedSubnet = fileOpts.TrustedSubnet
 	}
+	if len(merged.TrustedProxies) == 0 && len(fileOpts.TrustedProxies) > 0 {
+		merged.TrustedProxies = fileOpts.TrustedProxies
+	}
 	if merged.GRPCAddress == "" && fileOpts.GRPCAddress != "" {
 		merged.GRPCAddress = fileOpts.GRPCAddress
 	}
+	if merged.AliasCharset == "" && fileOpts.AliasCharset != "" {
+		merged.AliasCharset = fileOpts.AliasCharset
+	}
+	if merged.AliasMinLength == 0 && fileOpts.AliasMinLength != 0 {
+		merged.AliasMinLength = fileOpts.AliasMinLength
+	}
+	if merged.AliasMaxLength == 0 && fileOpts.AliasMaxLength != 0 {
+		merged.AliasMaxLength = fileOpts.AliasMaxLength
+	}
+	if merged.PurgeIntervalSec == 0 && fileOpts.PurgeIntervalSec != 0 {
+		merged.PurgeIntervalSec = fileOpts.PurgeIntervalSec
+	}
+	if merged.PurgeRetentionSec == 0 && fileOpts.PurgeRetentionSec != 0 {
+		merged.PurgeRetentionSec = fileOpts.PurgeRetentionSec
+	}
+	if merged.FileSyncPolicy == "" && fileOpts.FileSyncPolicy != "" {
+		merged.FileSyncPolicy = fileOpts.FileSyncPolicy
+	}
+	if merged.FileCompactThreshold == 0 && fileOpts.FileCompactThreshold != 0 {
+		merged.FileCompactThreshold = fileOpts.FileCompactThreshold
+	}
+	if merged.RedisAddr == "" && fileOpts.RedisAddr != "" {
+		merged.RedisAddr = fileOpts.RedisAddr
+	}
+	if merged.CacheSize == 0 && fileOpts.CacheSize != 0 {
+		merged.CacheSize = fileOpts.CacheSize
+	}
+	if merged.CacheTTLSec == 0 && fileOpts.CacheTTLSec != 0 {
+		merged.CacheTTLSec = fileOpts.CacheTTLSec
+	}
+	if merged.CacheNegativeTTLSec == 0 && fileOpts.CacheNegativeTTLSec != 0 {
+		merged.CacheNegativeTTLSec = fileOpts.CacheNegativeTTLSec
+	}
+	if merged.ShortIDStrategy == "" && fileOpts.ShortIDStrategy != "" {
+		merged.ShortIDStrategy = fileOpts.ShortIDStrategy
+	}
+	if merged.ShortIDAlphabet == "" && fileOpts.ShortIDAlphabet != "" {
+		merged.ShortIDAlphabet = fileOpts.ShortIDAlphabet
+	}
+	if merged.ShortIDLength == 0 && fileOpts.ShortIDLength != 0 {
+		merged.ShortIDLength = fileOpts.ShortIDLength
+	}
+	if merged.MetricsPath == "" && fileOpts.MetricsPath != "" {
+		merged.MetricsPath = fileOpts.MetricsPath
+	}
+	if merged.MetricsTrustedOnly == nil && fileOpts.MetricsTrustedOnly != nil {
+		merged.MetricsTrustedOnly = fileOpts.MetricsTrustedOnly
+	}
+	if merged.TracingExporter == "" && fileOpts.TracingExporter != "" {
+		merged.TracingExporter = fileOpts.TracingExporter
+	}
+	if merged.TracingEndpoint == "" && fileOpts.TracingEndpoint != "" {
+		merged.TracingEndpoint = fileOpts.TracingEndpoint
+	}
+	if merged.TracingInsecure == nil && fileOpts.TracingInsecure != nil {
+		merged.TracingInsecure = fileOpts.TracingInsecure
+	}
+	if merged.JWTSecret == "" && fileOpts.JWTSecret != "" {
+		merged.JWTSecret = fileOpts.JWTSecret
+	}
+	if merged.JWTIssuer == "" && fileOpts.JWTIssuer != "" {
+		merged.JWTIssuer = fileOpts.JWTIssuer
+	}
+	if merged.JWTAudience == "" && fileOpts.JWTAudience != "" {
+		merged.JWTAudience = fileOpts.JWTAudience
+	}
+	if merged.JWTTTLSec == 0 && fileOpts.JWTTTLSec != 0 {
+		merged.JWTTTLSec = fileOpts.JWTTTLSec
+	}
+	if merged.RateLimitCreatePerMin == 0 && fileOpts.RateLimitCreatePerMin != 0 {
+		merged.RateLimitCreatePerMin = fileOpts.RateLimitCreatePerMin
+	}
+	if merged.RateLimitCreateBurst == 0 && fileOpts.RateLimitCreateBurst != 0 {
+		merged.RateLimitCreateBurst = fileOpts.RateLimitCreateBurst
+	}
+	if merged.RateLimitBatchPerMin == 0 && fileOpts.RateLimitBatchPerMin != 0 {
+		merged.RateLimitBatchPerMin = fileOpts.RateLimitBatchPerMin
+	}
+	if merged.RateLimitBatchBurst == 0 && fileOpts.RateLimitBatchBurst != 0 {
+		merged.RateLimitBatchBurst = fileOpts.RateLimitBatchBurst
+	}
+	if merged.RateLimitRedirectPerMin == 0 && fileOpts.RateLimitRedirectPerMin != 0 {
+		merged.RateLimitRedirectPerMin = fileOpts.RateLimitRedirectPerMin
+	}
+	if merged.RateLimitRedirectBurst == 0 && fileOpts.RateLimitRedirectBurst != 0 {
+		merged.RateLimitRedirectBurst = fileOpts.RateLimitRedirectBurst
+	}
+	if merged.DeleteMaxAttempts == 0 && fileOpts.DeleteMaxAttempts != 0 {
+		merged.DeleteMaxAttempts = fileOpts.DeleteMaxAttempts
+	}
+	if merged.DeleteRetryBackoffSec == 0 && fileOpts.DeleteRetryBackoffSec != 0 {
+		merged.DeleteRetryBackoffSec = fileOpts.DeleteRetryBackoffSec
+	}
+	if merged.RestoreWindowSec == 0 && fileOpts.RestoreWindowSec != 0 {
+		merged.RestoreWindowSec = fileOpts.RestoreWindowSec
+	}
 	return &merged
 }
 
@@ -216,9 +460,85 @@ func setDefaultOptions(opts *Options) {
 		opts.BaseURL = "http://localhost:8080"
 	}
 	if opts.AuthCookieKey == "" {
-		opts.AuthCookieKey = "testsecret"
+		opts.AuthCookieKey = constants.DefaultAuthCookieKey
+	}
+	if opts.AuthCookieMaxAgeSec == 0 {
+		opts.AuthCookieMaxAgeSec = int(constants.AuthCookieMaxAge.Seconds())
+	}
+	if opts.AuthCookieSecure == nil {
+		secure := opts.EnableHTTPS != nil && *opts.EnableHTTPS
+		opts.AuthCookieSecure = &secure
+	}
+	if opts.AuthCookieSameSite == "" {
+		opts.AuthCookieSameSite = constants.AuthCookieSameSiteLax
 	}
 	if opts.GRPCAddress == "" {
 		opts.GRPCAddress = ":9090"
 	}
+	if opts.AliasCharset == "" {
+		opts.AliasCharset = constants.AliasCharset
+	}
+	if opts.AliasMinLength == 0 {
+		opts.AliasMinLength = constants.AliasMinLength
+	}
+	if opts.AliasMaxLength == 0 {
+		opts.AliasMaxLength = constants.AliasMaxLength
+	}
+	if opts.PurgeIntervalSec == 0 {
+		opts.PurgeIntervalSec = int(constants.PurgeInterval.Seconds())
+	}
+	if opts.PurgeRetentionSec == 0 {
+		opts.PurgeRetentionSec = int(constants.PurgeRetention.Seconds())
+	}
+	if opts.FileSyncPolicy == "" {
+		opts.FileSyncPolicy = constants.FileSyncInterval
+	}
+	if opts.FileCompactThreshold == 0 {
+		opts.FileCompactThreshold = constants.FileCompactThreshold
+	}
+	if opts.CacheSize == 0 {
+		opts.CacheSize = constants.CacheSize
+	}
+	if opts.CacheTTLSec == 0 {
+		opts.CacheTTLSec = int(constants.CacheTTL.Seconds())
+	}
+	if opts.CacheNegativeTTLSec == 0 {
+		opts.CacheNegativeTTLSec = int(constants.CacheNegativeTTL.Seconds())
+	}
+	if opts.ShortIDStrategy == "" {
+		opts.ShortIDStrategy = constants.ShortIDStrategyRandom
+	}
+	if opts.ShortIDAlphabet == "" {
+		opts.ShortIDAlphabet = constants.ShortIDAlphabet
+	}
+	if opts.ShortIDLength == 0 {
+		opts.ShortIDLength = constants.ShortIDLength
+	}
+	if opts.MetricsPath == "" {
+		opts.MetricsPath = constants.MetricsPath
+	}
+	if opts.TracingExporter == "" {
+		opts.TracingExporter = constants.TracingExporterNone
+	}
+	if opts.TracingEndpoint == "" {
+		opts.TracingEndpoint = constants.TracingEndpoint
+	}
+	if opts.JWTIssuer == "" {
+		opts.JWTIssuer = constants.JWTIssuer
+	}
+	if opts.JWTAudience == "" {
+		opts.JWTAudience = constants.JWTAudience
+	}
+	if opts.JWTTTLSec == 0 {
+		opts.JWTTTLSec = int(constants.JWTTTL.Seconds())
+	}
+	if opts.DeleteMaxAttempts == 0 {
+		opts.DeleteMaxAttempts = constants.DeleteMaxAttempts
+	}
+	if opts.DeleteRetryBackoffSec == 0 {
+		opts.DeleteRetryBackoffSec = int(constants.DeleteRetryBackoff.Seconds())
+	}
+	if opts.RestoreWindowSec == 0 {
+		opts.RestoreWindowSec = int(constants.RestoreWindow.Seconds())
+	}
 }
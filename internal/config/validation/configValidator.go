@@ -5,8 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strings"
+	"time"
 
 	"github.com/VladSnap/shortener/internal/config"
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/helpers"
 )
 
 // OptionsValidator - Структура валидатора конфигов.
@@ -25,12 +29,170 @@ func (vld *OptionsValidator) Validate(opts *config.Options) error {
 		return errors.New("incorrect -k argument, it should not be empty")
 	}
 
+	if err := validateAuthCookieOptions(opts); err != nil {
+		return err
+	}
+
 	if opts.TrustedSubnet != "" {
 		_, _, err := net.ParseCIDR(opts.TrustedSubnet)
 		if err != nil {
 			return fmt.Errorf("incorrect TrustedSubnet format, not valid CIDR: %w", err)
 		}
 	}
+	if _, err := helpers.ParseTrustedProxies("", opts.TrustedProxies); err != nil {
+		return fmt.Errorf("incorrect TrustedProxies: %w", err)
+	}
+
+	if err := validateAliasOptions(opts); err != nil {
+		return err
+	}
+
+	if opts.PurgeIntervalSec < 0 {
+		return errors.New("incorrect PurgeIntervalSec, it should not be negative")
+	}
+	if opts.PurgeRetentionSec < 0 {
+		return errors.New("incorrect PurgeRetentionSec, it should not be negative")
+	}
+
+	switch opts.FileSyncPolicy {
+	case constants.FileSyncAlways, constants.FileSyncInterval, constants.FileSyncNever:
+	default:
+		return fmt.Errorf("incorrect FileSyncPolicy '%s', it should be one of: %s, %s, %s", opts.FileSyncPolicy,
+			constants.FileSyncAlways, constants.FileSyncInterval, constants.FileSyncNever)
+	}
+	if opts.FileCompactThreshold < 0 {
+		return errors.New("incorrect FileCompactThreshold, it should not be negative")
+	}
+
+	if opts.CacheTTLSec < 0 {
+		return errors.New("incorrect CacheTTLSec, it should not be negative")
+	}
+	if opts.CacheNegativeTTLSec < 0 {
+		return errors.New("incorrect CacheNegativeTTLSec, it should not be negative")
+	}
 
+	if !strings.HasPrefix(opts.MetricsPath, "/") || opts.MetricsPath == "/" ||
+		strings.ContainsAny(opts.MetricsPath, "?#{}*") {
+		return fmt.Errorf("incorrect MetricsPath '%s', it should be an absolute path other than '/'", opts.MetricsPath)
+	}
+
+	switch opts.TracingExporter {
+	case constants.TracingExporterNone, constants.TracingExporterStdout, constants.TracingExporterOTLP:
+	default:
+		return fmt.Errorf("incorrect TracingExporter '%s', it should be one of: %s, %s, %s", opts.TracingExporter,
+			constants.TracingExporterNone, constants.TracingExporterStdout, constants.TracingExporterOTLP)
+	}
+
+	if opts.JWTSecret == "" || opts.JWTSecret == constants.DefaultAuthCookieKey {
+		return errors.New("incorrect JWTSecret, it should be set and differ from the default auth cookie key")
+	}
+	if opts.JWTTTLSec < 0 {
+		return errors.New("incorrect JWTTTLSec, it should not be negative")
+	}
+	if err := validateRateLimitOptions(opts); err != nil {
+		return err
+	}
+	if opts.DeleteMaxAttempts < 1 {
+		return errors.New("incorrect DeleteMaxAttempts, it should be greater than 0")
+	}
+	if opts.DeleteRetryBackoffSec < 1 {
+		return errors.New("incorrect DeleteRetryBackoffSec, it should be greater than 0")
+	}
+	if opts.RestoreWindowSec < 0 {
+		return errors.New("incorrect RestoreWindowSec, it should not be negative")
+	}
+
+	return validateShortIDOptions(opts)
+}
+
+// validateAuthCookieOptions - Проверяет настройки куки Auth.
+func validateAuthCookieOptions(opts *config.Options) error {
+	if opts.AuthCookieMaxAgeSec < 0 {
+		return errors.New("incorrect AuthCookieMaxAgeSec, it should not be negative")
+	}
+	switch opts.AuthCookieSameSite {
+	case constants.AuthCookieSameSiteLax, constants.AuthCookieSameSiteStrict:
+	case constants.AuthCookieSameSiteNone:
+		if opts.AuthCookieSecure == nil || !*opts.AuthCookieSecure {
+			return errors.New("incorrect AuthCookieSameSite, 'none' requires AuthCookieSecure")
+		}
+	default:
+		return fmt.Errorf("incorrect AuthCookieSameSite '%s', it should be one of: %s, %s, %s", opts.AuthCookieSameSite,
+			constants.AuthCookieSameSiteLax, constants.AuthCookieSameSiteStrict, constants.AuthCookieSameSiteNone)
+	}
+	if opts.AuthCookieLegacyUntil != "" {
+		if _, err := time.Parse(time.RFC3339, opts.AuthCookieLegacyUntil); err != nil {
+			return fmt.Errorf("incorrect AuthCookieLegacyUntil, it should be a time in RFC 3339: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateRateLimitOptions - Проверяет настройки ограничения частоты запросов.
+func validateRateLimitOptions(opts *config.Options) error {
+	limits := []struct {
+		name  string
+		value int
+	}{
+		{"RateLimitCreatePerMin", opts.RateLimitCreatePerMin},
+		{"RateLimitCreateBurst", opts.RateLimitCreateBurst},
+		{"RateLimitBatchPerMin", opts.RateLimitBatchPerMin},
+		{"RateLimitBatchBurst", opts.RateLimitBatchBurst},
+		{"RateLimitRedirectPerMin", opts.RateLimitRedirectPerMin},
+		{"RateLimitRedirectBurst", opts.RateLimitRedirectBurst},
+	}
+	for _, limit := range limits {
+		if limit.value < 0 {
+			return fmt.Errorf("incorrect %s, it should not be negative", limit.name)
+		}
+	}
+	return nil
+}
+
+// validateAliasOptions - Проверяет настройки пользовательских псевдонимов сокращенных ссылок.
+func validateAliasOptions(opts *config.Options) error {
+	if opts.AliasCharset == "" {
+		return errors.New("incorrect AliasCharset, it should not be empty")
+	}
+	if strings.ContainsAny(opts.AliasCharset, "/?#") {
+		return errors.New("incorrect AliasCharset, it should not contain '/', '?' or '#'")
+	}
+	if opts.AliasMinLength < 1 {
+		return errors.New("incorrect AliasMinLength, it should be greater than 0")
+	}
+	if opts.AliasMaxLength < opts.AliasMinLength {
+		return errors.New("incorrect AliasMaxLength, it should not be less than AliasMinLength")
+	}
+	if opts.AliasMaxLength > constants.MaxShortIDLength {
+		return fmt.Errorf("incorrect AliasMaxLength, it should not be greater than %d", constants.MaxShortIDLength)
+	}
+	return nil
+}
+
+// validateShortIDOptions - Проверяет настройки генерации идентификаторов сокращенных ссылок.
+func validateShortIDOptions(opts *config.Options) error {
+	switch opts.ShortIDStrategy {
+	case constants.ShortIDStrategyRandom, constants.ShortIDStrategySequence, constants.ShortIDStrategyHash:
+	default:
+		return fmt.Errorf("incorrect ShortIDStrategy '%s', it should be one of: %s, %s, %s", opts.ShortIDStrategy,
+			constants.ShortIDStrategyRandom, constants.ShortIDStrategySequence, constants.ShortIDStrategyHash)
+	}
+	alphabet := []rune(opts.ShortIDAlphabet)
+	if len(alphabet) < 2 {
+		return errors.New("incorrect ShortIDAlphabet, it should contain at least 2 characters")
+	}
+	if strings.ContainsAny(opts.ShortIDAlphabet, "/?#") {
+		return errors.New("incorrect ShortIDAlphabet, it should not contain '/', '?' or '#'")
+	}
+	seen := make(map[rune]bool, len(alphabet))
+	for _, r := range alphabet {
+		if seen[r] {
+			return fmt.Errorf("incorrect ShortIDAlphabet, character '%c' is repeated", r)
+		}
+		seen[r] = true
+	}
+	if opts.ShortIDLength < 1 || opts.ShortIDLength > constants.MaxShortIDLength {
+		return fmt.Errorf("incorrect ShortIDLength, it should be between 1 and %d", constants.MaxShortIDLength)
+	}
 	return nil
 }
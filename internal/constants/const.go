@@ -1,7 +1,10 @@
 // Package constants хранит общие констаны всего проекта.
 package constants
 
-import "os"
+import (
+	"os"
+	"time"
+)
 
 // KeyContext - Тип ключа для доступа к данным куки через контекст.
 type KeyContext string
@@ -13,6 +16,101 @@ const (
 	FileRWPerm = os.FileMode(0o666)
 	// UserIDContextKey - Имя ключа для доступа к данным куки через контекст.
 	UserIDContextKey = KeyContext("UserID")
-	// ShortIDLength - Длина сокращенной ссылки.
+	// APIKeyContextKey - Имя ключа для доступа к ключу API, которым аутентифицирован запрос.
+	APIKeyContextKey = KeyContext("APIKey")
+	// NewUserContextKey - Имя ключа признака того, что идентификатор пользователя создан для этого запроса.
+	NewUserContextKey = KeyContext("NewUser")
+	// ShortIDLength - Длина сокращенной ссылки по умолчанию.
 	ShortIDLength = 8
+	// ShortIDAlphabet - Набор символов сгенерированных идентификаторов сокращенных ссылок по умолчанию (base62).
+	ShortIDAlphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	// ShortIDStrategyRandom - Стратегия генерации криптографически случайных идентификаторов.
+	ShortIDStrategyRandom = "random"
+	// ShortIDStrategySequence - Стратегия генерации идентификаторов из монотонной последовательности.
+	ShortIDStrategySequence = "sequence"
+	// ShortIDStrategyHash - Стратегия генерации идентификаторов из хеша оригинального URL.
+	ShortIDStrategyHash = "hash"
+	// ShortIDMaxAttempts - Максимальное количество попыток сгенерировать свободный идентификатор.
+	ShortIDMaxAttempts = 5
+	// MaxShortIDLength - Максимально допустимая длина идентификатора сокращенной ссылки, включая псевдонимы.
+	MaxShortIDLength = 64
+	// AliasCharset - Набор символов по умолчанию, допустимых в пользовательском псевдониме.
+	AliasCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_"
+	// AliasMinLength - Минимальная длина пользовательского псевдонима по умолчанию.
+	AliasMinLength = 3
+	// AliasMaxLength - Максимальная длина пользовательского псевдонима по умолчанию.
+	AliasMaxLength = 32
+	// PurgeInterval - Интервал запуска очистки удаленных и истекших ссылок по умолчанию.
+	PurgeInterval = time.Hour
+	// PurgeRetention - Срок хранения удаленных и истекших ссылок перед физическим удалением по умолчанию.
+	PurgeRetention = 7 * 24 * time.Hour
+	// UserStatsTopLinks - Количество самых популярных ссылок в статистике пользователя.
+	UserStatsTopLinks = 5
+	// UserLinksDefaultLimit - Размер страницы ссылок пользователя по умолчанию.
+	UserLinksDefaultLimit = 100
+	// UserLinksMaxLimit - Максимальный размер страницы ссылок пользователя.
+	UserLinksMaxLimit = 1000
+	// AuditDefaultLimit - Количество событий журнала аудита в ответе по умолчанию.
+	AuditDefaultLimit = 100
+	// AuditMaxLimit - Максимальное количество событий журнала аудита в ответе.
+	AuditMaxLimit = 1000
+	// AuditRetention - Количество последних событий журнала аудита, которые хранятся в памяти и файле.
+	// Более старые события отбрасываются, в БД журнал не ограничивается.
+	AuditRetention = 100000
+	// FileSyncAlways - Сбрасывать журнал файлового хранилища на диск после каждой записи.
+	FileSyncAlways = "always"
+	// FileSyncInterval - Сбрасывать журнал файлового хранилища на диск раз в FileSyncPeriod.
+	FileSyncInterval = "interval"
+	// FileSyncNever - Не сбрасывать журнал файлового хранилища на диск явно, полагаясь на операционную систему.
+	FileSyncNever = "never"
+	// FileSyncPeriod - Период сброса журнала файлового хранилища на диск для политики FileSyncInterval.
+	FileSyncPeriod = time.Second
+	// CacheSize - Максимальное количество ссылок в кеше по умолчанию.
+	CacheSize = 10000
+	// CacheTTL - Время жизни закешированной ссылки по умолчанию.
+	CacheTTL = time.Minute
+	// CacheNegativeTTL - Время жизни закешированного отсутствия ссылки по умолчанию.
+	CacheNegativeTTL = 5 * time.Second
+	// FileCompactThreshold - Количество записей журнала файлового хранилища, после которого он уплотняется в снимок.
+	FileCompactThreshold = 10000
+	// MaxLinkTTL - Максимальное время жизни ссылки, задаваемое в TTL.
+	MaxLinkTTL = 10 * 365 * 24 * time.Hour
+	// MetricsPath - Путь HTTP эндпоинта метрик Prometheus по умолчанию.
+	MetricsPath = "/metrics"
+	// TracingExporterNone - Не экспортировать span трассировки.
+	TracingExporterNone = "none"
+	// TracingExporterStdout - Печатать span трассировки в стандартный вывод.
+	TracingExporterStdout = "stdout"
+	// TracingExporterOTLP - Отправлять span трассировки по OTLP/gRPC.
+	TracingExporterOTLP = "otlp"
+	// TracingEndpoint - Адрес OTLP/gRPC коллектора трассировки по умолчанию.
+	TracingEndpoint = "localhost:4317"
+	// JWTIssuer - Издатель (claim iss) JWT токенов доступа по умолчанию.
+	JWTIssuer = "shortener"
+	// JWTAudience - Аудитория (claim aud) JWT токенов доступа по умолчанию.
+	JWTAudience = "shortener"
+	// JWTTTL - Время жизни JWT токена доступа по умолчанию.
+	JWTTTL = 24 * time.Hour
+	// DefaultAuthCookieKey - Ключ подписи куки Auth по умолчанию, подходит только для разработки.
+	DefaultAuthCookieKey = "testsecret"
+	// AuthCookieMaxAge - Время жизни куки Auth по умолчанию.
+	AuthCookieMaxAge = 30 * 24 * time.Hour
+	// AuthCookieSameSiteLax - Атрибут SameSite=Lax куки Auth.
+	AuthCookieSameSiteLax = "lax"
+	// AuthCookieSameSiteStrict - Атрибут SameSite=Strict куки Auth.
+	AuthCookieSameSiteStrict = "strict"
+	// AuthCookieSameSiteNone - Атрибут SameSite=None куки Auth, требует Secure.
+	AuthCookieSameSiteNone = "none"
+	// DeleteMaxAttempts - Количество попыток удаления ссылки, после которого задача переносится в dead letter.
+	DeleteMaxAttempts = 5
+	// DeleteRetryBackoff - Задержка перед первой повторной попыткой удаления, далее она удваивается.
+	DeleteRetryBackoff = 5 * time.Second
+	// DeleteRetryMaxBackoff - Максимальная задержка перед повторной попыткой удаления.
+	DeleteRetryMaxBackoff = 10 * time.Minute
+	// DeleteDrainTimeout - Сколько воркер удаления при остановке выполняет накопленные задачи.
+	DeleteDrainTimeout = 10 * time.Second
+	// DeleteJobRetention - Сколько хранятся результаты выполненных запросов на удаление.
+	DeleteJobRetention = 24 * time.Hour
+	// RestoreWindow - Сколько времени после удаления ссылку можно восстановить по умолчанию.
+	RestoreWindow = 24 * time.Hour
 )
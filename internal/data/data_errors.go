@@ -18,3 +18,20 @@ func NewDuplicateError(shortURL string) error {
 func (de *DuplicateShortLinkError) Error() string {
 	return fmt.Sprintf("shortURL '%v' already exists in storage", de.ShortURL)
 }
+
+// ShortURLConflictError - Структура ошибки, когда идентификатор сокращенной ссылки уже занят.
+type ShortURLConflictError struct {
+	ShortURL string
+}
+
+// NewShortURLConflictError - Создает новую структуру ShortURLConflictError с указателем.
+func NewShortURLConflictError(shortURL string) error {
+	return &ShortURLConflictError{
+		ShortURL: shortURL,
+	}
+}
+
+// Error - Реализует интерфейс Error.
+func (ce *ShortURLConflictError) Error() string {
+	return fmt.Sprintf("shortURL '%v' is already taken", ce.ShortURL)
+}
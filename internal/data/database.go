@@ -1,6 +1,7 @@
 package data
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	_ "github.com/jackc/pgx/v5/stdlib"
 	_ "github.com/lib/pq"
 )
 
@@ -40,6 +42,14 @@ func (ds *DatabaseShortener) Close() error {
 	return nil
 }
 
+// CheckHealth - Проверяет доступность БД через уже открытый пул соединений.
+func (ds *DatabaseShortener) CheckHealth(ctx context.Context) error {
+	if err := ds.PingContext(ctx); err != nil {
+		return fmt.Errorf("database ping failed: %w", err)
+	}
+	return nil
+}
+
 // InitDatabase - Инициализирует и применяет миграции БД.
 func (ds *DatabaseShortener) InitDatabase() error {
 	driver, err := postgres.WithInstance(ds.DB, &postgres.Config{})
@@ -1,6 +1,8 @@
 // Package data хранит модели данных для БД.
 package data
 
+import "time"
+
 // ShortLinkData - Структура таблицы БД сокращенной ссылки.
 type ShortLinkData struct {
 	UUID        string `json:"uuid" db:"uuid"`
@@ -8,6 +10,22 @@ type ShortLinkData struct {
 	OriginalURL string `json:"orig_url" db:"orig_url"`
 	UserID      string `json:"user_id" db:"user_id"`
 	IsDeleted   bool   `json:"is_deleted" db:"is_deleted"`
+	// CreatedAt - Время создания ссылки.
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	// ExpiresAt - Время, после которого ссылка перестает работать. Если nil - ссылка бессрочная.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	// DeletedAt - Время пометки ссылки удаленной.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	// History - Предыдущие оригинальные URL в порядке изменения. В БД хранятся в таблице short_link_history
+	// и заполняются только в результате UpdateOriginalURL.
+	History []LinkDestinationData `json:"history,omitempty" db:"-"`
+}
+
+// LinkDestinationData - Предыдущий оригинальный URL сокращенной ссылки.
+type LinkDestinationData struct {
+	OriginalURL string `json:"orig_url" db:"orig_url"`
+	// ChangedAt - Время, когда ссылка перестала вести на этот URL.
+	ChangedAt time.Time `json:"changed_at" db:"changed_at"`
 }
 
 // NewShortLinkData - Создает новую структуру ShortLinkData с указателем.
@@ -20,6 +38,37 @@ func NewShortLinkData(id string, shortURL string, origURL string, userID string)
 	}
 }
 
+// IsExpired - Проверяет, истек ли срок действия ссылки на указанный момент времени.
+func (link *ShortLinkData) IsExpired(now time.Time) bool {
+	return link.ExpiresAt != nil && !now.Before(*link.ExpiresAt)
+}
+
+// IsPurgeable - Проверяет, можно ли физически удалить ссылку, удаленную или истекшую раньше указанного момента.
+// Ссылки, удаленные до появления колонки deleted_at, считаются удаленными давно.
+func (link *ShortLinkData) IsPurgeable(before time.Time) bool {
+	if link.IsDeleted && (link.DeletedAt == nil || link.DeletedAt.Before(before)) {
+		return true
+	}
+	return link.ExpiresAt != nil && link.ExpiresAt.Before(before)
+}
+
+// IsRestorable - Проверяет, можно ли восстановить ссылку, удаленную не раньше указанного момента.
+// Ссылки, удаленные до появления колонки deleted_at, считаются удаленными давно.
+func (link *ShortLinkData) IsRestorable(deletedAfter time.Time) bool {
+	return link.IsDeleted && link.DeletedAt != nil && !link.DeletedAt.Before(deletedAfter)
+}
+
+// WithOriginalURL - Возвращает копию ссылки, ведущую на новый оригинальный URL.
+// Текущий оригинальный URL добавляется в историю копии, история исходной ссылки не изменяется.
+func (link *ShortLinkData) WithOriginalURL(origURL string, changedAt time.Time) *ShortLinkData {
+	updated := *link
+	updated.History = make([]LinkDestinationData, 0, len(link.History)+1)
+	updated.History = append(updated.History, link.History...)
+	updated.History = append(updated.History, LinkDestinationData{OriginalURL: link.OriginalURL, ChangedAt: changedAt})
+	updated.OriginalURL = origURL
+	return &updated
+}
+
 // DeleteShortData - Структура запроса для удаления сокращенной ссылки.
 type DeleteShortData struct {
 	ShortURL string
@@ -41,3 +90,190 @@ type StatsData struct {
 func NewStatsData(urls, users int) *StatsData {
 	return &StatsData{Urls: urls, Users: users}
 }
+
+// PurgeData - Результат физического удаления ссылок.
+type PurgeData struct {
+	// Deleted - Количество удаленных ссылок, помеченных ранее как удаленные.
+	Deleted int
+	// Expired - Количество удаленных ссылок с истекшим сроком действия.
+	Expired int
+}
+
+// Add - Учитывает удаленную ссылку в результате.
+func (pd *PurgeData) Add(link *ShortLinkData) {
+	if link.IsDeleted {
+		pd.Deleted++
+	} else {
+		pd.Expired++
+	}
+}
+
+// Total - Общее количество физически удаленных ссылок.
+func (pd *PurgeData) Total() int {
+	return pd.Deleted + pd.Expired
+}
+
+// LinkCursor - Позиция в упорядоченном списке ссылок пользователя, после которой начинается следующая страница.
+type LinkCursor struct {
+	CreatedAt time.Time
+	ShortURL  string
+}
+
+// UserLinksQuery - Параметры выборки ссылок пользователя.
+type UserLinksQuery struct {
+	// Limit - Максимальное количество ссылок в выборке, 0 - без ограничения.
+	Limit int
+	// After - Курсор, после которого начинается выборка, nil - с начала списка.
+	After *LinkCursor
+	// Desc - Сортировка по убыванию времени создания.
+	Desc bool
+	// Deleted - Фильтр по признаку удаления, nil - без фильтра.
+	Deleted *bool
+	// Search - Подстрока оригинального URL без учета регистра, пустая строка - без фильтра.
+	Search string
+}
+
+// UserStatsData - Статистика по сокращенным ссылкам одного пользователя.
+type UserStatsData struct {
+	// Active - Количество действующих ссылок.
+	Active int
+	// Deleted - Количество удаленных ссылок.
+	Deleted int
+	// Expired - Количество ссылок с истекшим сроком действия, которые не были удалены.
+	Expired int
+	// ShortURLs - Идентификаторы всех ссылок пользователя.
+	ShortURLs []string
+}
+
+// Add - Учитывает ссылку пользователя в статистике на указанный момент времени.
+func (us *UserStatsData) Add(link *ShortLinkData, now time.Time) {
+	switch {
+	case link.IsDeleted:
+		us.Deleted++
+	case link.IsExpired(now):
+		us.Expired++
+	default:
+		us.Active++
+	}
+	us.ShortURLs = append(us.ShortURLs, link.ShortURL)
+}
+
+// ClickData - Структура таблицы БД перехода по сокращенной ссылке.
+type ClickData struct {
+	ShortURL  string    `json:"short_url" db:"short_url"`
+	ClickedAt time.Time `json:"clicked_at" db:"clicked_at"`
+	Referrer  string    `json:"referrer,omitempty" db:"referrer"`
+	UserAgent string    `json:"user_agent,omitempty" db:"user_agent"`
+	ClientIP  string    `json:"client_ip,omitempty" db:"client_ip"`
+}
+
+// DailyClicksData - Количество переходов по ссылке за один день.
+type DailyClicksData struct {
+	Day    time.Time
+	Clicks int
+}
+
+// ClickStatsData - Статистика переходов по сокращенной ссылке.
+type ClickStatsData struct {
+	Total int
+	Days  []DailyClicksData
+}
+
+// APIKeyData - Структура таблицы БД ключа API. Сам ключ не хранится, только его хеш.
+type APIKeyData struct {
+	// ID - Идентификатор ключа, по которому он отзывается.
+	ID string
+	// KeyHash - Хеш SHA-256 ключа в hex.
+	KeyHash string
+	// UserID - Пользователь, от имени которого действует ключ.
+	UserID string
+	// Name - Описание ключа, например, имя сервиса-клиента.
+	Name string
+	// Scopes - Разрешенные ключу действия.
+	Scopes []string
+	// CreatedAt - Время создания ключа.
+	CreatedAt time.Time
+	// RevokedAt - Время отзыва ключа. Если nil - ключ действует.
+	RevokedAt *time.Time
+}
+
+// DeleteTaskData - Задача удаления сокращенной ссылки в очереди удаления.
+type DeleteTaskData struct {
+	// ID - Порядковый номер задачи в очереди.
+	ID int64 `json:"id"`
+	// ShortURL - Идентификатор удаляемой ссылки.
+	ShortURL string `json:"short_url"`
+	// UserID - Пользователь, который запросил удаление.
+	UserID string `json:"user_id"`
+	// Attempts - Количество неудачных попыток удаления.
+	Attempts int `json:"attempts"`
+	// NextAttemptAt - Время, раньше которого задачу не нужно выполнять.
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+	// LastError - Текст ошибки последней неудачной попытки.
+	LastError string `json:"last_error,omitempty"`
+	// CreatedAt - Время постановки задачи в очередь.
+	CreatedAt time.Time `json:"created_at"`
+	// DeadAt - Время, когда задача исчерпала попытки и перестала выполняться.
+	DeadAt *time.Time `json:"dead_at,omitempty"`
+	// JobID - Идентификатор запроса на удаление, в рамках которого поставлена задача.
+	JobID string `json:"job_id,omitempty"`
+	// Outcome - Результат выполненной задачи: ссылка удалена, не найдена или принадлежит другому пользователю.
+	Outcome string `json:"outcome,omitempty"`
+	// CompletedAt - Время выполнения задачи.
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// Source - Интерфейс, через который запрошено удаление, для журнала аудита.
+	Source string `json:"source,omitempty"`
+	// ClientIP - IP адрес клиента, запросившего удаление, для журнала аудита.
+	ClientIP string `json:"client_ip,omitempty"`
+}
+
+// AuditActorData - Источник запроса, выполнившего действие со ссылкой.
+type AuditActorData struct {
+	// Source - Интерфейс, через который выполнен запрос: http или grpc.
+	Source string
+	// ClientIP - IP адрес клиента.
+	ClientIP string
+}
+
+// AuditEventData - Структура таблицы БД и строки файла журнала аудита действий со ссылками.
+type AuditEventData struct {
+	// ID - Порядковый номер события.
+	ID int64 `json:"id" db:"id"`
+	// Action - Действие: create, delete, restore или update.
+	Action string `json:"action" db:"action"`
+	// ShortURL - Идентификатор сокращенной ссылки.
+	ShortURL string `json:"short_url" db:"short_url"`
+	// UserID - Пользователь, выполнивший действие.
+	UserID string `json:"user_id" db:"user_id"`
+	// Source - Интерфейс, через который выполнено действие: http или grpc.
+	Source string `json:"source" db:"source"`
+	// ClientIP - IP адрес клиента.
+	ClientIP string `json:"client_ip,omitempty" db:"client_ip"`
+	// OriginalURL - Оригинальный URL ссылки после создания или изменения.
+	OriginalURL string `json:"orig_url,omitempty" db:"orig_url"`
+	// CreatedAt - Время действия.
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// AuditQueryData - Параметры выборки событий журнала аудита.
+type AuditQueryData struct {
+	// UserID - Пользователь, события которого нужно получить. Если пуст, возвращаются события всех пользователей.
+	UserID string
+	// From - Начало периода включительно. Если нулевое, период не ограничен снизу.
+	From time.Time
+	// To - Конец периода не включительно. Если нулевое, период не ограничен сверху.
+	To time.Time
+	// Limit - Максимальное количество событий.
+	Limit int
+}
+
+// Match - Проверяет, что событие подходит под параметры выборки без учета лимита.
+func (query *AuditQueryData) Match(event *AuditEventData) bool {
+	if query.UserID != "" && event.UserID != query.UserID {
+		return false
+	}
+	if !query.From.IsZero() && event.CreatedAt.Before(query.From) {
+		return false
+	}
+	return query.To.IsZero() || event.CreatedAt.Before(query.To)
+}
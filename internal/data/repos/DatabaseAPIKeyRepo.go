@@ -0,0 +1,118 @@
+package repos
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/VladSnap/shortener/internal/log"
+	"go.uber.org/zap"
+)
+
+// scopesSeparator - Разделитель разрешений ключа API в колонке scopes.
+const scopesSeparator = ","
+
+// apiKeyColumns - Колонки таблицы public.api_keys в порядке чтения scanAPIKey.
+const apiKeyColumns = "id, key_hash, user_id, name, scopes, created_at, revoked_at"
+
+// DatabaseAPIKeyRepo - Репозиторий для хранения ключей API в БД.
+type DatabaseAPIKeyRepo struct {
+	database *data.DatabaseShortener
+}
+
+// NewDatabaseAPIKeyRepo - Создает новую структуру DatabaseAPIKeyRepo с указателем.
+func NewDatabaseAPIKeyRepo(database *data.DatabaseShortener) *DatabaseAPIKeyRepo {
+	repo := new(DatabaseAPIKeyRepo)
+	repo.database = database
+	return repo
+}
+
+// AddAPIKey - Сохраняет новый ключ API в БД.
+func (repo *DatabaseAPIKeyRepo) AddAPIKey(ctx context.Context, key *data.APIKeyData) error {
+	sqlText := `INSERT INTO public.api_keys (id, key_hash, user_id, name, scopes, created_at)
+		VALUES($1, $2, $3, $4, $5, $6)`
+	_, err := repo.database.ExecContext(ctx, sqlText, key.ID, key.KeyHash, key.UserID, key.Name,
+		strings.Join(key.Scopes, scopesSeparator), key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed insert to public.api_keys: %w", err)
+	}
+	return nil
+}
+
+// GetAPIKeyByHash - Получает ключ API по хешу, возвращает nil, если ключ не найден.
+func (repo *DatabaseAPIKeyRepo) GetAPIKeyByHash(ctx context.Context, keyHash string) (*data.APIKeyData, error) {
+	sqlText := "SELECT " + apiKeyColumns + " FROM public.api_keys WHERE key_hash = $1"
+	key, err := scanAPIKey(repo.database.QueryRowContext(ctx, sqlText, keyHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil //nolint:nilnil // key not found
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed select from public.api_keys: %w", err)
+	}
+	return key, nil
+}
+
+// GetAPIKeys - Получает все ключи API, включая отозванные, в порядке создания.
+func (repo *DatabaseAPIKeyRepo) GetAPIKeys(ctx context.Context) ([]*data.APIKeyData, error) {
+	sqlText := "SELECT " + apiKeyColumns + " FROM public.api_keys ORDER BY created_at, id"
+	rows, err := repo.database.QueryContext(ctx, sqlText)
+	if err != nil {
+		return nil, fmt.Errorf("failed select from public.api_keys: %w", err)
+	}
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			log.Zap.Error("failed rows close for select api keys request", zap.Error(err))
+		}
+	}()
+
+	keys := make([]*data.APIKeyData, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed scan select from public.api_keys: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed iterate select from public.api_keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey - Помечает ключ API отозванным. Возвращает false, если ключ не найден.
+// Повторный отзыв не меняет время отзыва.
+func (repo *DatabaseAPIKeyRepo) RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) (bool, error) {
+	sqlText := `UPDATE public.api_keys SET revoked_at = COALESCE(revoked_at, $2) WHERE id = $1`
+	result, err := repo.database.ExecContext(ctx, sqlText, id, revokedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed update public.api_keys: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed get affected rows of update public.api_keys: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// scanAPIKey - Читает строку таблицы public.api_keys, выбранную с колонками apiKeyColumns.
+func scanAPIKey(row rowScanner) (*data.APIKeyData, error) {
+	key := &data.APIKeyData{}
+	var scopes string
+	var revokedAt sql.NullTime
+	err := row.Scan(&key.ID, &key.KeyHash, &key.UserID, &key.Name, &scopes, &key.CreatedAt, &revokedAt)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // wrapped by caller
+	}
+	if scopes != "" {
+		key.Scopes = strings.Split(scopes, scopesSeparator)
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return key, nil
+}
@@ -0,0 +1,129 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/VladSnap/shortener/internal/log"
+	"go.uber.org/zap"
+)
+
+// DatabaseAuditRepo - Репозиторий журнала аудита в таблице public.audit_log.
+// Записи не ссылаются на public.short_links, чтобы журнал сохранялся после физического удаления ссылок.
+type DatabaseAuditRepo struct {
+	database *data.DatabaseShortener
+}
+
+// NewDatabaseAuditRepo - Создает новую структуру DatabaseAuditRepo с указателем.
+func NewDatabaseAuditRepo(database *data.DatabaseShortener) *DatabaseAuditRepo {
+	repo := new(DatabaseAuditRepo)
+	repo.database = database
+	return repo
+}
+
+// AddEvents - Сохраняет пачку событий журнала аудита в БД.
+func (repo *DatabaseAuditRepo) AddEvents(ctx context.Context, events []*data.AuditEventData) error {
+	tx, err := repo.database.BeginTx(ctx, nil)
+	isCommited := false
+	if err != nil {
+		return fmt.Errorf("failed begin db transaction before insert audit events operation: %w", err)
+	}
+	defer func() {
+		if !isCommited {
+			err := tx.Rollback()
+			if err != nil {
+				log.Zap.Error("unable to rollback transaction after failed insert audit events operation",
+					zap.Error(err))
+			}
+		}
+	}()
+
+	stmt, err := tx.PrepareContext(ctx,
+		"INSERT INTO public.audit_log (action, short_url, user_id, source, client_ip, orig_url, created_at)"+
+			" VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id")
+	if err != nil {
+		return fmt.Errorf("failed prepare insert audit events: %w", err)
+	}
+	defer func() {
+		err := stmt.Close()
+		if err != nil {
+			log.Zap.Error("unable to stmt close after insert audit events operation", zap.Error(err))
+		}
+	}()
+
+	for _, event := range events {
+		err := stmt.QueryRowContext(ctx, event.Action, event.ShortURL, event.UserID, event.Source,
+			toNullString(event.ClientIP), toNullString(event.OriginalURL), event.CreatedAt).Scan(&event.ID)
+		if err != nil {
+			return fmt.Errorf("failed exec insert audit events: %w", err)
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed commit insert audit events transaction: %w", err)
+	}
+	isCommited = true
+
+	return nil
+}
+
+// GetEvents - Получает события журнала аудита по параметрам выборки от новых к старым.
+func (repo *DatabaseAuditRepo) GetEvents(ctx context.Context, query *data.AuditQueryData) (
+	[]*data.AuditEventData, error) {
+	conditions := make([]string, 0)
+	args := make([]any, 0)
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, condition+" $"+strconv.Itoa(len(args)))
+	}
+	if query.UserID != "" {
+		addCondition("user_id =", query.UserID)
+	}
+	if !query.From.IsZero() {
+		addCondition("created_at >=", query.From)
+	}
+	if !query.To.IsZero() {
+		addCondition("created_at <", query.To)
+	}
+
+	var sqlText strings.Builder
+	sqlText.WriteString(`SELECT id, action, short_url, user_id, source, COALESCE(client_ip, ''),
+		COALESCE(orig_url, ''), created_at FROM public.audit_log`)
+	if len(conditions) > 0 {
+		sqlText.WriteString(" WHERE " + strings.Join(conditions, " AND "))
+	}
+	sqlText.WriteString(" ORDER BY created_at DESC, id DESC")
+	if query.Limit > 0 {
+		args = append(args, query.Limit)
+		sqlText.WriteString(" LIMIT $" + strconv.Itoa(len(args)))
+	}
+
+	rows, err := repo.database.QueryContext(ctx, sqlText.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed select from public.audit_log: %w", err)
+	}
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			log.Zap.Error("failed rows close for select audit events request", zap.Error(err))
+		}
+	}()
+
+	events := make([]*data.AuditEventData, 0)
+	for rows.Next() {
+		event := new(data.AuditEventData)
+		err := rows.Scan(&event.ID, &event.Action, &event.ShortURL, &event.UserID, &event.Source, &event.ClientIP,
+			&event.OriginalURL, &event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed scan select from public.audit_log: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed iterate select from public.audit_log: %w", err)
+	}
+	return events, nil
+}
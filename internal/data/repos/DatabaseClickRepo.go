@@ -0,0 +1,146 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/VladSnap/shortener/internal/log"
+	"go.uber.org/zap"
+)
+
+// DatabaseClickRepo - Репозиторий для хранения переходов по ссылкам в БД.
+type DatabaseClickRepo struct {
+	database *data.DatabaseShortener
+}
+
+// NewDatabaseClickRepo - Создает новую структуру DatabaseClickRepo с указателем.
+func NewDatabaseClickRepo(database *data.DatabaseShortener) *DatabaseClickRepo {
+	repo := new(DatabaseClickRepo)
+	repo.database = database
+	return repo
+}
+
+// AddClicks - Сохраняет пачку переходов по ссылкам в БД. Переходы по ссылкам, которых уже нет в БД,
+// например удаленных очисткой, пропускаются, чтобы они не мешали сохранить остальные переходы пачки.
+func (repo *DatabaseClickRepo) AddClicks(ctx context.Context, clicks []*data.ClickData) error {
+	tx, err := repo.database.BeginTx(ctx, nil)
+	isCommited := false
+	if err != nil {
+		return fmt.Errorf("failed begin db transaction before insert clicks operation: %w", err)
+	}
+	defer func() {
+		if !isCommited {
+			err := tx.Rollback()
+			if err != nil {
+				log.Zap.Error("unable to rollback transaction after failed insert clicks operation", zap.Error(err))
+			}
+		}
+	}()
+
+	stmt, err := tx.PrepareContext(ctx,
+		"INSERT INTO public.link_clicks (short_url, clicked_at, referrer, user_agent, client_ip)"+
+			" SELECT $1::varchar, $2::timestamptz, $3::varchar, $4::varchar, $5::varchar"+
+			" WHERE EXISTS (SELECT 1 FROM public.short_links WHERE short_url = $1)")
+	if err != nil {
+		return fmt.Errorf("failed prepare insert clicks: %w", err)
+	}
+	defer func() {
+		err := stmt.Close()
+		if err != nil {
+			log.Zap.Error("unable to stmt close after insert clicks operation", zap.Error(err))
+		}
+	}()
+
+	skipped := 0
+	for _, click := range clicks {
+		res, err := stmt.ExecContext(ctx, click.ShortURL, click.ClickedAt, toNullString(click.Referrer),
+			toNullString(click.UserAgent), toNullString(click.ClientIP))
+		if err != nil {
+			return fmt.Errorf("failed exec insert clicks: %w", err)
+		}
+		inserted, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed get inserted clicks count: %w", err)
+		}
+		if inserted == 0 {
+			skipped++
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("failed commit insert clicks transaction: %w", err)
+	}
+	isCommited = true
+	if skipped > 0 {
+		log.Zap.Warn("clicks of missing short links are skipped", zap.Int("clicks", skipped))
+	}
+
+	return nil
+}
+
+// GetClickStats - Получает статистику переходов по сокращенной ссылке с группировкой по дням.
+func (repo *DatabaseClickRepo) GetClickStats(ctx context.Context, shortURL string) (*data.ClickStatsData, error) {
+	sqlText := `SELECT date_trunc('day', clicked_at AT TIME ZONE 'UTC') AS day, COUNT(*) AS clicks
+		FROM public.link_clicks WHERE short_url = $1 GROUP BY day ORDER BY day`
+	rows, err := repo.database.QueryContext(ctx, sqlText, shortURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed select from public.link_clicks: %w", err)
+	}
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			log.Zap.Error("failed rows close for select clicks request", zap.Error(err))
+		}
+	}()
+
+	stats := &data.ClickStatsData{Days: make([]data.DailyClicksData, 0)}
+	for rows.Next() {
+		day := data.DailyClicksData{}
+		err := rows.Scan(&day.Day, &day.Clicks)
+		if err != nil {
+			return nil, fmt.Errorf("failed scan select from public.link_clicks: %w", err)
+		}
+		day.Day = truncateToDay(day.Day)
+		stats.Total += day.Clicks
+		stats.Days = append(stats.Days, day)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed iterate select from public.link_clicks: %w", err)
+	}
+	return stats, nil
+}
+
+// GetClickCounts - Получает общее количество переходов по каждой из указанных ссылок.
+func (repo *DatabaseClickRepo) GetClickCounts(ctx context.Context, shortURLs []string) (map[string]int, error) {
+	result := make(map[string]int, len(shortURLs))
+	if len(shortURLs) == 0 {
+		return result, nil
+	}
+
+	sqlText := `SELECT short_url, COUNT(*) FROM public.link_clicks WHERE short_url = ANY($1) GROUP BY short_url`
+	rows, err := repo.database.QueryContext(ctx, sqlText, shortURLs)
+	if err != nil {
+		return nil, fmt.Errorf("failed select click counts from public.link_clicks: %w", err)
+	}
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			log.Zap.Error("failed rows close for select click counts request", zap.Error(err))
+		}
+	}()
+
+	for rows.Next() {
+		var shortURL string
+		var clicks int
+		if err := rows.Scan(&shortURL, &clicks); err != nil {
+			return nil, fmt.Errorf("failed scan click counts: %w", err)
+		}
+		result[shortURL] = clicks
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed iterate click counts: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,31 @@
+package repos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseClickRepo_AddClicksSkipsMissingLinks(t *testing.T) {
+	database := openTestDatabase(t)
+	_, err := NewDatabaseShortLinkRepo(database).
+		Add(t.Context(), newTestDatabaseLink("link0001", "http://a.url", uuid.NewString()))
+	require.NoError(t, err)
+	repo := NewDatabaseClickRepo(database)
+
+	clickedAt := time.Now().UTC()
+	err = repo.AddClicks(t.Context(), []*data.ClickData{
+		{ShortURL: "link0001", ClickedAt: clickedAt},
+		{ShortURL: "purged01", ClickedAt: clickedAt},
+		{ShortURL: "link0001", ClickedAt: clickedAt, Referrer: "http://ref.url"},
+	})
+	require.NoError(t, err, "click of a missing link should not fail the batch")
+
+	counts, err := repo.GetClickCounts(t.Context(), []string{"link0001", "purged01"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"link0001": 2}, counts)
+}
@@ -0,0 +1,171 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/VladSnap/shortener/internal/log"
+	"go.uber.org/zap"
+)
+
+// deleteTaskColumns - Колонки public.delete_queue в порядке сканирования в DeleteTaskData.
+const deleteTaskColumns = "id, job_id, short_url, user_id, attempts, next_attempt_at, last_error, created_at, " +
+	"dead_at, outcome, completed_at, source, client_ip"
+
+// DatabaseDeleteQueue - Очередь удаления сокращенных ссылок в таблице public.delete_queue (outbox).
+// Задачи, исчерпавшие попытки, остаются в таблице с заполненным dead_at,
+// выполненные - с результатом и completed_at до удаления по истечении срока хранения.
+type DatabaseDeleteQueue struct {
+	database *data.DatabaseShortener
+}
+
+// NewDatabaseDeleteQueue - Создает новую структуру DatabaseDeleteQueue с указателем.
+func NewDatabaseDeleteQueue(database *data.DatabaseShortener) *DatabaseDeleteQueue {
+	queue := new(DatabaseDeleteQueue)
+	queue.database = database
+	return queue
+}
+
+// Enqueue - Ставит в очередь задачи удаления ссылок запроса jobID, готовые к выполнению сразу.
+func (queue *DatabaseDeleteQueue) Enqueue(ctx context.Context, jobID string, shortIDs []data.DeleteShortData,
+	actor data.AuditActorData, now time.Time) error {
+	sqlText := `INSERT INTO public.delete_queue (job_id, short_url, user_id, next_attempt_at, created_at,
+		source, client_ip) VALUES($1, $2, $3, $4, $4, $5, $6)`
+	args := make([][]any, 0, len(shortIDs))
+	for _, sid := range shortIDs {
+		args = append(args, []any{jobID, sid.ShortURL, sid.UserID, now, actor.Source, actor.ClientIP})
+	}
+	return queue.execBatch(ctx, sqlText, "enqueue", args)
+}
+
+// FetchDue - Получает до limit задач, время выполнения которых не позже dueBefore, в порядке постановки.
+func (queue *DatabaseDeleteQueue) FetchDue(ctx context.Context, dueBefore time.Time,
+	limit int) ([]*data.DeleteTaskData, error) {
+	sqlText := `SELECT ` + deleteTaskColumns + ` FROM public.delete_queue
+		WHERE dead_at IS NULL AND completed_at IS NULL AND next_attempt_at <= $1 ORDER BY id LIMIT $2`
+	return queue.query(ctx, sqlText, dueBefore, limit)
+}
+
+// Complete - Сохраняет результат и время выполнения задач.
+func (queue *DatabaseDeleteQueue) Complete(ctx context.Context, tasks []*data.DeleteTaskData) error {
+	sqlText := `UPDATE public.delete_queue SET outcome = $2, completed_at = $3 WHERE id = $1`
+	args := make([][]any, 0, len(tasks))
+	for _, task := range tasks {
+		args = append(args, []any{task.ID, task.Outcome, task.CompletedAt})
+	}
+	return queue.execBatch(ctx, sqlText, "complete", args)
+}
+
+// Fail - Сохраняет состояние задач после неудачной попытки удаления.
+func (queue *DatabaseDeleteQueue) Fail(ctx context.Context, tasks []*data.DeleteTaskData) error {
+	sqlText := `UPDATE public.delete_queue SET attempts = $2, next_attempt_at = $3, last_error = $4, dead_at = $5
+		WHERE id = $1`
+	args := make([][]any, 0, len(tasks))
+	for _, task := range tasks {
+		args = append(args, []any{task.ID, task.Attempts, task.NextAttemptAt, task.LastError, task.DeadAt})
+	}
+	return queue.execBatch(ctx, sqlText, "fail", args)
+}
+
+// CountPending - Возвращает количество задач, ожидающих выполнения.
+func (queue *DatabaseDeleteQueue) CountPending(ctx context.Context) (int, error) {
+	var count int
+	err := queue.database.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM public.delete_queue WHERE dead_at IS NULL AND completed_at IS NULL").Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed count public.delete_queue: %w", err)
+	}
+	return count, nil
+}
+
+// GetJob - Получает задачи запроса на удаление в порядке постановки.
+func (queue *DatabaseDeleteQueue) GetJob(ctx context.Context, jobID string) ([]*data.DeleteTaskData, error) {
+	sqlText := `SELECT ` + deleteTaskColumns + ` FROM public.delete_queue WHERE job_id = $1 ORDER BY id`
+	return queue.query(ctx, sqlText, jobID)
+}
+
+// DeleteCompleted - Удаляет из очереди задачи, выполненные раньше указанного момента.
+func (queue *DatabaseDeleteQueue) DeleteCompleted(ctx context.Context, before time.Time) error {
+	_, err := queue.database.ExecContext(ctx, "DELETE FROM public.delete_queue WHERE completed_at < $1", before)
+	if err != nil {
+		return fmt.Errorf("failed delete from public.delete_queue: %w", err)
+	}
+	return nil
+}
+
+// query - Выполняет запрос задач очереди.
+func (queue *DatabaseDeleteQueue) query(ctx context.Context, sqlText string,
+	args ...any) ([]*data.DeleteTaskData, error) {
+	rows, err := queue.database.QueryContext(ctx, sqlText, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed select from public.delete_queue: %w", err)
+	}
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			log.Zap.Error("failed rows close for select delete queue request", zap.Error(err))
+		}
+	}()
+
+	tasks := make([]*data.DeleteTaskData, 0)
+	for rows.Next() {
+		task := new(data.DeleteTaskData)
+		err := rows.Scan(&task.ID, &task.JobID, &task.ShortURL, &task.UserID, &task.Attempts, &task.NextAttemptAt,
+			&task.LastError, &task.CreatedAt, &task.DeadAt, &task.Outcome, &task.CompletedAt, &task.Source,
+			&task.ClientIP)
+		if err != nil {
+			return nil, fmt.Errorf("failed scan delete task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed iterate delete tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// execBatch - Выполняет запрос для каждого набора аргументов в одной транзакции.
+func (queue *DatabaseDeleteQueue) execBatch(ctx context.Context, sqlText string, operation string,
+	args [][]any) error {
+	if len(args) == 0 {
+		return nil
+	}
+	tx, err := queue.database.BeginTx(ctx, nil)
+	isCommited := false
+	if err != nil {
+		return fmt.Errorf("failed begin db transaction before delete queue %s operation: %w", operation, err)
+	}
+	defer func() {
+		if !isCommited {
+			err := tx.Rollback()
+			if err != nil {
+				log.Zap.Error("unable to rollback transaction after failed delete queue operation",
+					zap.String("operation", operation), zap.Error(err))
+			}
+		}
+	}()
+
+	stmt, err := tx.PrepareContext(ctx, sqlText)
+	if err != nil {
+		return fmt.Errorf("failed prepare delete queue %s: %w", operation, err)
+	}
+	defer func() {
+		err := stmt.Close()
+		if err != nil {
+			log.Zap.Error("unable to stmt close after delete queue operation", zap.Error(err))
+		}
+	}()
+
+	for _, rowArgs := range args {
+		if _, err := stmt.ExecContext(ctx, rowArgs...); err != nil {
+			return fmt.Errorf("failed exec delete queue %s: %w", operation, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed commit delete queue %s transaction: %w", operation, err)
+	}
+	isCommited = true
+	return nil
+}
@@ -0,0 +1,30 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/VladSnap/shortener/internal/data"
+)
+
+// DatabaseShortIDSequence - Последовательность для генерации идентификаторов сокращенных ссылок в БД.
+type DatabaseShortIDSequence struct {
+	database *data.DatabaseShortener
+}
+
+// NewDatabaseShortIDSequence - Создает новую структуру DatabaseShortIDSequence с указателем.
+func NewDatabaseShortIDSequence(database *data.DatabaseShortener) *DatabaseShortIDSequence {
+	sequence := new(DatabaseShortIDSequence)
+	sequence.database = database
+	return sequence
+}
+
+// Next - Возвращает следующее значение последовательности public.short_link_id_seq.
+func (sequence *DatabaseShortIDSequence) Next(ctx context.Context) (uint64, error) {
+	var value uint64
+	row := sequence.database.QueryRowContext(ctx, `SELECT nextval('public.short_link_id_seq')`)
+	if err := row.Scan(&value); err != nil {
+		return 0, fmt.Errorf("failed select next value of public.short_link_id_seq: %w", err)
+	}
+	return value, nil
+}
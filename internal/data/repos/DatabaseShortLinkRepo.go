@@ -6,12 +6,33 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/VladSnap/shortener/internal/data"
 	"github.com/VladSnap/shortener/internal/log"
+	"github.com/jackc/pgx/v5/pgconn"
 	"go.uber.org/zap"
 )
 
+// shortLinkColumns - Колонки таблицы public.short_links в порядке, который ожидает scanShortLink.
+const shortLinkColumns = "uuid, short_url, orig_url, user_id, is_deleted, created_at, expires_at, deleted_at"
+
+// releaseExpiredOrigURLSQL - Освобождает оригинальный URL истекшей ссылки, чтобы его можно было сократить заново.
+// Уникальный индекс по orig_url частичный и не учитывает освобожденные ссылки, сама истекшая ссылка остается
+// до очистки. $1 - orig_url, $2 - текущий момент.
+const releaseExpiredOrigURLSQL = "UPDATE public.short_links SET orig_url_released = true " +
+	"WHERE orig_url = $1 AND expires_at <= $2 AND NOT orig_url_released"
+
+const (
+	// uniqueViolationCode - Код ошибки PostgreSQL при нарушении уникального индекса.
+	uniqueViolationCode = "23505"
+	// shortURLUniqueIndex - Имя уникального индекса по колонке short_url.
+	shortURLUniqueIndex = "short_links_short_url_unique_idx"
+	// origURLUniqueIndex - Имя уникального индекса по колонке orig_url.
+	origURLUniqueIndex = "short_links_orig_url_unique_idx"
+)
+
 // DatabaseShortLinkRepo - Репозиторий для доступа к БД сокращателя ссылок.
 type DatabaseShortLinkRepo struct {
 	database *data.DatabaseShortener
@@ -25,25 +46,56 @@ func NewDatabaseShortLinkRepo(database *data.DatabaseShortener) *DatabaseShortLi
 }
 
 // Add - Сохраняет структуру сокращенной ссылки в БД.
+// Если оригинальный URL уже сокращен неистекшей ссылкой, возвращает data.DuplicateShortLinkError с ней.
 func (repo *DatabaseShortLinkRepo) Add(ctx context.Context, link *data.ShortLinkData) (
-	*data.ShortLinkData, error) {
-	sqlText := "INSERT INTO public.short_links (uuid, short_url, orig_url, user_id, is_deleted)" +
-		"VALUES ($1, $2, $3, $4, $5) " +
-		"ON CONFLICT (orig_url) DO UPDATE " +
+	result *data.ShortLinkData, err error) {
+	sqlText := "INSERT INTO public.short_links (uuid, short_url, orig_url, user_id, is_deleted, created_at, expires_at)" +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7) " +
+		"ON CONFLICT (orig_url) WHERE NOT orig_url_released DO UPDATE " +
 		"SET orig_url = short_links.orig_url " +
 		"RETURNING short_links.short_url"
+	ctx, span := startDBSpan(ctx, "INSERT", sqlText)
+	defer func() { endDBSpan(span, err) }()
+	tx, err := repo.database.BeginTx(ctx, nil)
+	isCommited := false
+	if err != nil {
+		return nil, fmt.Errorf("failed begin db transaction before insert operation: %w", err)
+	}
+	defer func() {
+		if !isCommited {
+			err := tx.Rollback()
+			if err != nil {
+				log.Zap.Error("unable to rollback transaction after failed insert operation", zap.Error(err))
+			}
+		}
+	}()
 
+	if _, err = tx.ExecContext(ctx, releaseExpiredOrigURLSQL, link.OriginalURL, link.CreatedAt); err != nil {
+		return nil, fmt.Errorf("failed release orig_url of expired link in public.short_links: %w", err)
+	}
 	//nolint:execinquery // use ON CONFLICT and Return value
-	row := repo.database.QueryRowContext(ctx, sqlText, link.UUID, link.ShortURL,
-		link.OriginalURL, toNullString(link.UserID), link.IsDeleted)
+	row := tx.QueryRowContext(ctx, sqlText, link.UUID, link.ShortURL,
+		link.OriginalURL, toNullString(link.UserID), link.IsDeleted, link.CreatedAt, toNullTime(link.ExpiresAt))
 	if row.Err() != nil {
+		if conflictErr := asShortURLConflict(row.Err(), link.ShortURL); conflictErr != nil {
+			return nil, conflictErr
+		}
 		return nil, fmt.Errorf("failed insert to public.short_links new row: %w", row.Err())
 	}
 	var shortURL string
-	err := row.Scan(&shortURL)
+	err = row.Scan(&shortURL)
 	if err != nil {
+		if conflictErr := asShortURLConflict(err, link.ShortURL); conflictErr != nil {
+			return nil, conflictErr
+		}
 		return nil, fmt.Errorf("failed scan insert result from public.short_links new row: %w", err)
 	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("failed commit insert transaction: %w", err)
+	}
+	isCommited = true
+
 	if shortURL == link.ShortURL {
 		return link, nil
 	} else {
@@ -52,8 +104,16 @@ func (repo *DatabaseShortLinkRepo) Add(ctx context.Context, link *data.ShortLink
 }
 
 // AddBatch - Сохраняет пачку структур сокращенных ссылок в БД.
+// Для уже сокращенных оригинальных URL в результате возвращается существующая ссылка.
+// Пачка сохраняется целиком или не сохраняется вовсе.
 func (repo *DatabaseShortLinkRepo) AddBatch(ctx context.Context, links []*data.ShortLinkData) (
-	[]*data.ShortLinkData, error) {
+	result []*data.ShortLinkData, err error) {
+	sqlText := "INSERT INTO public.short_links (uuid, short_url, orig_url, user_id, is_deleted, created_at, expires_at)" +
+		" VALUES($1, $2, $3, $4, $5, $6, $7)" +
+		" ON CONFLICT (orig_url) WHERE NOT orig_url_released DO UPDATE SET orig_url = short_links.orig_url" +
+		" RETURNING " + shortLinkColumns
+	ctx, span := startDBSpan(ctx, "INSERT", sqlText)
+	defer func() { endDBSpan(span, err) }()
 	tx, err := repo.database.BeginTx(ctx, nil)
 	isCommited := false
 	if err != nil {
@@ -68,9 +128,7 @@ func (repo *DatabaseShortLinkRepo) AddBatch(ctx context.Context, links []*data.S
 		}
 	}()
 
-	stmt, err := tx.PrepareContext(ctx,
-		"INSERT INTO public.short_links (uuid, short_url, orig_url, user_id, is_deleted)"+
-			" VALUES($1, $2, $3, $4, $5)")
+	stmt, err := tx.PrepareContext(ctx, sqlText)
 	if err != nil {
 		return nil, fmt.Errorf("failed prepare insert: %w", err)
 	}
@@ -80,13 +138,33 @@ func (repo *DatabaseShortLinkRepo) AddBatch(ctx context.Context, links []*data.S
 			log.Zap.Error("unable to stmt close after insert batch operation", zap.Error(err))
 		}
 	}()
+	releaseStmt, err := tx.PrepareContext(ctx, releaseExpiredOrigURLSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed prepare release orig_url of expired links: %w", err)
+	}
+	defer func() {
+		err := releaseStmt.Close()
+		if err != nil {
+			log.Zap.Error("unable to stmt close after release orig_url of expired links", zap.Error(err))
+		}
+	}()
 
+	result = make([]*data.ShortLinkData, 0, len(links))
 	for _, link := range links {
-		_, err := stmt.ExecContext(ctx, link.UUID, link.ShortURL, link.OriginalURL,
-			toNullString(link.UserID), link.IsDeleted)
+		if _, err := releaseStmt.ExecContext(ctx, link.OriginalURL, link.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed release orig_url of expired link in public.short_links: %w", err)
+		}
+		//nolint:execinquery // use ON CONFLICT and Return value
+		row := stmt.QueryRowContext(ctx, link.UUID, link.ShortURL, link.OriginalURL,
+			toNullString(link.UserID), link.IsDeleted, link.CreatedAt, toNullTime(link.ExpiresAt))
+		stored, err := scanShortLink(row)
 		if err != nil {
+			if conflictErr := asShortURLConflict(err, link.ShortURL); conflictErr != nil {
+				return nil, conflictErr
+			}
 			return nil, fmt.Errorf("failed exec insert batch: %w", err)
 		}
+		result = append(result, stored)
 	}
 	err = tx.Commit()
 	if err != nil {
@@ -94,32 +172,35 @@ func (repo *DatabaseShortLinkRepo) AddBatch(ctx context.Context, links []*data.S
 	}
 	isCommited = true
 
-	return links, nil
+	return result, nil
 }
 
 // Get - Читает полную ссылку по сокращенной ссылке.
-func (repo *DatabaseShortLinkRepo) Get(ctx context.Context, shortID string) (*data.ShortLinkData, error) {
-	sqlText := `SELECT * FROM public.short_links WHERE short_url = $1`
+func (repo *DatabaseShortLinkRepo) Get(ctx context.Context, shortID string) (
+	result *data.ShortLinkData, err error) {
+	sqlText := `SELECT ` + shortLinkColumns + ` FROM public.short_links WHERE short_url = $1`
+	ctx, span := startDBSpan(ctx, "SELECT", sqlText)
+	defer func() { endDBSpan(span, err) }()
 	row := repo.database.QueryRowContext(ctx, sqlText, shortID)
 
-	link := data.ShortLinkData{}
-	var userID sql.NullString
-	err := row.Scan(&link.UUID, &link.ShortURL, &link.OriginalURL, &userID, &link.IsDeleted)
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return nil, fmt.Errorf("failed select from public.short_links: %w", err)
+	link, err := scanShortLink(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil //nolint:nilnil // link not found
 	}
-	if userID.Valid {
-		link.UserID = userID.String
+	if err != nil {
+		return nil, fmt.Errorf("failed select from public.short_links: %w", err)
 	}
 
-	return &link, nil
+	return link, nil
 }
 
-// GetAllByUserID - Получить все сокращенные ссылки указанного пользователя.
-func (repo *DatabaseShortLinkRepo) GetAllByUserID(ctx context.Context, userID string) (
-	[]*data.ShortLinkData, error) {
-	sqlText := `SELECT * FROM public.short_links WHERE user_id = $1`
-	rows, err := repo.database.QueryContext(ctx, sqlText, toNullString(userID))
+// GetAllByUserID - Получить сокращенные ссылки указанного пользователя по параметрам выборки.
+func (repo *DatabaseShortLinkRepo) GetAllByUserID(ctx context.Context, userID string, query *data.UserLinksQuery) (
+	result []*data.ShortLinkData, err error) {
+	sqlText, args := buildUserLinksQuery(userID, query)
+	ctx, span := startDBSpan(ctx, "SELECT", sqlText)
+	defer func() { endDBSpan(span, err) }()
+	rows, err := repo.database.QueryContext(ctx, sqlText, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed select from public.short_links: %w", err)
 	}
@@ -133,13 +214,11 @@ func (repo *DatabaseShortLinkRepo) GetAllByUserID(ctx context.Context, userID st
 	const startSizeLinks int = 10
 	links := make([]*data.ShortLinkData, 0, startSizeLinks)
 	for rows.Next() {
-		link := data.ShortLinkData{}
-		// порядок переменных должен соответствовать порядку колонок в запросе
-		err := rows.Scan(&link.UUID, &link.ShortURL, &link.OriginalURL, &link.UserID, &link.IsDeleted)
+		link, err := scanShortLink(rows)
 		if err != nil && !errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("failed scan select from public.short_links: %w", err)
 		}
-		links = append(links, &link)
+		links = append(links, link)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -148,8 +227,67 @@ func (repo *DatabaseShortLinkRepo) GetAllByUserID(ctx context.Context, userID st
 	return links, nil
 }
 
+// buildUserLinksQuery - Строит SQL запрос выборки ссылок пользователя с фильтрами, курсором и сортировкой.
+func buildUserLinksQuery(userID string, query *data.UserLinksQuery) (string, []any) {
+	if query == nil {
+		query = &data.UserLinksQuery{}
+	}
+	var sqlText strings.Builder
+	args := []any{toNullString(userID)}
+	sqlText.WriteString(`SELECT ` + shortLinkColumns + ` FROM public.short_links WHERE user_id = $1`)
+
+	if query.Deleted != nil {
+		args = append(args, *query.Deleted)
+		fmt.Fprintf(&sqlText, " AND is_deleted = $%d", len(args))
+	}
+	if query.Search != "" {
+		args = append(args, query.Search)
+		fmt.Fprintf(&sqlText, " AND strpos(lower(orig_url), lower($%d)) > 0", len(args))
+	}
+	order, cmp := "ASC", ">"
+	if query.Desc {
+		order, cmp = "DESC", "<"
+	}
+	if query.After != nil {
+		args = append(args, query.After.CreatedAt, query.After.ShortURL)
+		fmt.Fprintf(&sqlText, " AND (created_at, short_url) %s ($%d, $%d)", cmp, len(args)-1, len(args))
+	}
+	fmt.Fprintf(&sqlText, " ORDER BY created_at %s, short_url %s", order, order)
+	if query.Limit > 0 {
+		args = append(args, query.Limit)
+		fmt.Fprintf(&sqlText, " LIMIT $%d", len(args))
+	}
+	return sqlText.String(), args
+}
+
 // DeleteBatch - Удаляет пачку структур сокращенных ссылок из БД.
 func (repo *DatabaseShortLinkRepo) DeleteBatch(ctx context.Context, shortIDs []data.DeleteShortData) error {
+	sqlText := "UPDATE public.short_links SET is_deleted=true, deleted_at=now() " +
+		"WHERE is_deleted != true and short_url = $1 and user_id = $2"
+	rows := make([][]any, 0, len(shortIDs))
+	for _, shortID := range shortIDs {
+		rows = append(rows, []any{shortID.ShortURL, shortID.UserID})
+	}
+	return repo.execBatch(ctx, sqlText, rows)
+}
+
+// RestoreBatch - Снимает пометку удаления со ссылок пачки, удаленных не раньше deletedAfter.
+// Ссылки без времени удаления считаются удаленными давно и не восстанавливаются.
+func (repo *DatabaseShortLinkRepo) RestoreBatch(ctx context.Context, shortIDs []data.DeleteShortData,
+	deletedAfter time.Time) error {
+	sqlText := "UPDATE public.short_links SET is_deleted=false, deleted_at=NULL " +
+		"WHERE is_deleted = true and short_url = $1 and user_id = $2 and deleted_at >= $3"
+	rows := make([][]any, 0, len(shortIDs))
+	for _, shortID := range shortIDs {
+		rows = append(rows, []any{shortID.ShortURL, shortID.UserID, deletedAfter})
+	}
+	return repo.execBatch(ctx, sqlText, rows)
+}
+
+// execBatch - Выполняет запрос изменения для каждого набора параметров в одной транзакции.
+func (repo *DatabaseShortLinkRepo) execBatch(ctx context.Context, sqlText string, rows [][]any) (err error) {
+	ctx, span := startDBSpan(ctx, "UPDATE", sqlText)
+	defer func() { endDBSpan(span, err) }()
 	tx, err := repo.database.BeginTx(ctx, nil)
 	isCommited := false
 	if err != nil {
@@ -164,8 +302,7 @@ func (repo *DatabaseShortLinkRepo) DeleteBatch(ctx context.Context, shortIDs []d
 		}
 	}()
 
-	stmt, err := tx.PrepareContext(ctx,
-		"UPDATE public.short_links SET is_deleted=true WHERE is_deleted != true and short_url = $1 and user_id = $2")
+	stmt, err := tx.PrepareContext(ctx, sqlText)
 	if err != nil {
 		return fmt.Errorf("failed prepare batch update: %w", err)
 	}
@@ -176,8 +313,8 @@ func (repo *DatabaseShortLinkRepo) DeleteBatch(ctx context.Context, shortIDs []d
 		}
 	}()
 
-	for _, shortID := range shortIDs {
-		_, err := stmt.ExecContext(ctx, shortID.ShortURL, shortID.UserID)
+	for _, args := range rows {
+		_, err := stmt.ExecContext(ctx, args...)
 		if err != nil {
 			return fmt.Errorf("failed exec batch update: %w", err)
 		}
@@ -191,13 +328,135 @@ func (repo *DatabaseShortLinkRepo) DeleteBatch(ctx context.Context, shortIDs []d
 	return nil
 }
 
+// UpdateOriginalURL - Меняет оригинальный URL неудаленной ссылки пользователя и сохраняет прежний URL
+// в таблице short_link_history. Строка ссылки блокируется до конца транзакции, поэтому одновременные изменения
+// не теряют историю. Если новый URL уже сокращен другой ссылкой, возвращает data.DuplicateShortLinkError.
+func (repo *DatabaseShortLinkRepo) UpdateOriginalURL(ctx context.Context, shortURL string, userID string,
+	origURL string, changedAt time.Time) (result *data.ShortLinkData, err error) {
+	sqlText := "UPDATE public.short_links SET orig_url = $2 WHERE short_url = $1"
+	ctx, span := startDBSpan(ctx, "UPDATE", sqlText)
+	defer func() { endDBSpan(span, err) }()
+	tx, err := repo.database.BeginTx(ctx, nil)
+	isCommited := false
+	if err != nil {
+		return nil, fmt.Errorf("failed begin db transaction before update link: %w", err)
+	}
+	defer func() {
+		if !isCommited {
+			err := tx.Rollback()
+			if err != nil {
+				log.Zap.Error("unable to rollback transaction after failed update link", zap.Error(err))
+			}
+		}
+	}()
+
+	link, err := scanShortLink(tx.QueryRowContext(ctx, `SELECT `+shortLinkColumns+` FROM public.short_links `+
+		`WHERE short_url = $1 AND user_id = $2 AND NOT is_deleted FOR UPDATE`, shortURL, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil //nolint:nilnil // link not found
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed select link for update from public.short_links: %w", err)
+	}
+
+	if link.OriginalURL != origURL {
+		if _, err = tx.ExecContext(ctx, releaseExpiredOrigURLSQL, origURL, changedAt); err != nil {
+			return nil, fmt.Errorf("failed release orig_url of expired link in public.short_links: %w", err)
+		}
+		if _, err = tx.ExecContext(ctx, sqlText, shortURL, origURL); err != nil {
+			if isUniqueViolation(err, origURLUniqueIndex) {
+				return nil, repo.duplicateOf(ctx, origURL)
+			}
+			return nil, fmt.Errorf("failed update orig_url in public.short_links: %w", err)
+		}
+		_, err = tx.ExecContext(ctx, "INSERT INTO public.short_link_history (short_url, orig_url, changed_at) "+
+			"VALUES ($1, $2, $3)", shortURL, link.OriginalURL, changedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed insert to public.short_link_history: %w", err)
+		}
+		link.OriginalURL = origURL
+	}
+	if link.History, err = loadLinkHistory(ctx, tx, shortURL); err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("failed commit update link transaction: %w", err)
+	}
+	isCommited = true
+	return link, nil
+}
+
+// duplicateOf - Возвращает DuplicateShortLinkError со ссылкой, которая уже сокращает оригинальный URL.
+func (repo *DatabaseShortLinkRepo) duplicateOf(ctx context.Context, origURL string) error {
+	var shortURL string
+	err := repo.database.QueryRowContext(ctx, "SELECT short_url FROM public.short_links "+
+		"WHERE orig_url = $1 AND NOT orig_url_released", origURL).Scan(&shortURL)
+	if err != nil {
+		return fmt.Errorf("failed select duplicate of orig_url from public.short_links: %w", err)
+	}
+	return data.NewDuplicateError(shortURL) //nolint:wrapcheck // is new error
+}
+
+// loadLinkHistory - Читает предыдущие оригинальные URL ссылки в порядке изменения.
+func loadLinkHistory(ctx context.Context, tx *sql.Tx, shortURL string) ([]data.LinkDestinationData, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT orig_url, changed_at FROM public.short_link_history "+
+		"WHERE short_url = $1 ORDER BY id", shortURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed select from public.short_link_history: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Zap.Error("failed close rows of public.short_link_history", zap.Error(err))
+		}
+	}()
+
+	history := make([]data.LinkDestinationData, 0)
+	for rows.Next() {
+		destination := data.LinkDestinationData{}
+		if err := rows.Scan(&destination.OriginalURL, &destination.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed scan row of public.short_link_history: %w", err)
+		}
+		history = append(history, destination)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed read rows of public.short_link_history: %w", err)
+	}
+	return history, nil
+}
+
+// Purge - Физически удаляет ссылки, помеченные удаленными или истекшие раньше указанного момента.
+func (repo *DatabaseShortLinkRepo) Purge(ctx context.Context, before time.Time) (
+	result *data.PurgeData, err error) {
+	sqlText := `WITH purged AS (
+		DELETE FROM public.short_links
+		WHERE (is_deleted AND (deleted_at IS NULL OR deleted_at < $1)) OR expires_at < $1
+		RETURNING is_deleted
+	)
+	SELECT COUNT(*) FILTER (WHERE is_deleted), COUNT(*) FILTER (WHERE NOT is_deleted) FROM purged`
+	ctx, span := startDBSpan(ctx, "DELETE", sqlText)
+	defer func() { endDBSpan(span, err) }()
+	row := repo.database.QueryRowContext(ctx, sqlText, before)
+
+	purged := data.PurgeData{}
+	err = row.Scan(&purged.Deleted, &purged.Expired)
+	if err != nil {
+		return nil, fmt.Errorf("failed purge from public.short_links: %w", err)
+	}
+
+	return &purged, nil
+}
+
 // GetStats - Получает статистику о пользователях и всех ссылках.
-func (repo *DatabaseShortLinkRepo) GetStats(ctx context.Context) (*data.StatsData, error) {
+func (repo *DatabaseShortLinkRepo) GetStats(ctx context.Context) (result *data.StatsData, err error) {
 	sqlText := `SELECT COUNT(*) as urls, COUNT(distinct user_id) as users from SHORT_LINKS`
+	ctx, span := startDBSpan(ctx, "SELECT", sqlText)
+	defer func() { endDBSpan(span, err) }()
 	row := repo.database.QueryRowContext(ctx, sqlText)
 
 	stats := data.StatsData{}
-	err := row.Scan(&stats.Urls, &stats.Users)
+	err = row.Scan(&stats.Urls, &stats.Users)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return nil, fmt.Errorf("failed select stats: %w", err)
 	}
@@ -205,9 +464,101 @@ func (repo *DatabaseShortLinkRepo) GetStats(ctx context.Context) (*data.StatsDat
 	return &stats, nil
 }
 
+// GetUserStats - Получает статистику по ссылкам указанного пользователя на указанный момент времени.
+func (repo *DatabaseShortLinkRepo) GetUserStats(ctx context.Context, userID string, now time.Time) (
+	result *data.UserStatsData, err error) {
+	sqlText := `SELECT
+		COUNT(*) FILTER (WHERE NOT is_deleted AND (expires_at IS NULL OR expires_at > $2)) AS active,
+		COUNT(*) FILTER (WHERE is_deleted) AS deleted,
+		COUNT(*) FILTER (WHERE NOT is_deleted AND expires_at <= $2) AS expired
+		FROM public.short_links WHERE user_id = $1`
+	ctx, span := startDBSpan(ctx, "SELECT", sqlText)
+	defer func() { endDBSpan(span, err) }()
+	stats := data.UserStatsData{ShortURLs: make([]string, 0)}
+	row := repo.database.QueryRowContext(ctx, sqlText, toNullString(userID), now)
+	err = row.Scan(&stats.Active, &stats.Deleted, &stats.Expired)
+	if err != nil {
+		return nil, fmt.Errorf("failed select user stats: %w", err)
+	}
+
+	rows, err := repo.database.QueryContext(ctx,
+		`SELECT short_url FROM public.short_links WHERE user_id = $1`, toNullString(userID))
+	if err != nil {
+		return nil, fmt.Errorf("failed select user short urls: %w", err)
+	}
+	defer func() {
+		err := rows.Close()
+		if err != nil {
+			log.Zap.Error("failed rows close for select user short urls request", zap.Error(err))
+		}
+	}()
+
+	for rows.Next() {
+		var shortURL string
+		if err := rows.Scan(&shortURL); err != nil {
+			return nil, fmt.Errorf("failed scan user short url: %w", err)
+		}
+		stats.ShortURLs = append(stats.ShortURLs, shortURL)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed iterate user short urls: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// rowScanner - Общий интерфейс sql.Row и sql.Rows для чтения одной строки.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanShortLink - Читает строку таблицы public.short_links, выбранную с колонками shortLinkColumns.
+func scanShortLink(row rowScanner) (*data.ShortLinkData, error) {
+	link := data.ShortLinkData{}
+	var userID sql.NullString
+	var expiresAt, deletedAt sql.NullTime
+	// порядок переменных должен соответствовать порядку колонок в shortLinkColumns
+	err := row.Scan(&link.UUID, &link.ShortURL, &link.OriginalURL, &userID, &link.IsDeleted,
+		&link.CreatedAt, &expiresAt, &deletedAt)
+	if err != nil {
+		return &link, err //nolint:wrapcheck // wrapped by caller
+	}
+	if userID.Valid {
+		link.UserID = userID.String
+	}
+	if expiresAt.Valid {
+		link.ExpiresAt = &expiresAt.Time
+	}
+	if deletedAt.Valid {
+		link.DeletedAt = &deletedAt.Time
+	}
+	return &link, nil
+}
+
+func toNullTime(input *time.Time) sql.NullTime {
+	if input == nil {
+		return sql.NullTime{Valid: false}
+	}
+	return sql.NullTime{Time: *input, Valid: true}
+}
+
 func toNullString(input string) sql.NullString {
 	if input == "" {
 		return sql.NullString{String: "", Valid: false}
 	}
 	return sql.NullString{String: input, Valid: true}
 }
+
+// asShortURLConflict - Возвращает ShortURLConflictError, если ошибка вызвана нарушением уникальности short_url.
+func asShortURLConflict(err error, shortURL string) error {
+	if isUniqueViolation(err, shortURLUniqueIndex) {
+		return data.NewShortURLConflictError(shortURL) //nolint:wrapcheck // is new error
+	}
+	return nil
+}
+
+// isUniqueViolation - Проверяет, вызвана ли ошибка нарушением указанного уникального индекса.
+func isUniqueViolation(err error, index string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode && pgErr.ConstraintName == index
+}
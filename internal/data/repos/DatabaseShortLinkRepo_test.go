@@ -0,0 +1,94 @@
+package repos
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openTestDatabase - Открывает БД из переменной окружения TEST_DATABASE_DSN с примененными миграциями
+// и пустой таблицей ссылок. Без переменной тест пропускается.
+func openTestDatabase(t *testing.T) *data.DatabaseShortener {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	database, err := data.NewDatabaseShortener(dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, database.Close()) })
+	// Миграции читаются относительно корня модуля.
+	t.Chdir("../../..")
+	require.NoError(t, database.InitDatabase())
+	_, err = database.ExecContext(t.Context(), "TRUNCATE public.short_links CASCADE")
+	require.NoError(t, err)
+	return database
+}
+
+// openTestDatabaseRepo - Открывает репозиторий ссылок в БД из openTestDatabase.
+func openTestDatabaseRepo(t *testing.T) *DatabaseShortLinkRepo {
+	t.Helper()
+	return NewDatabaseShortLinkRepo(openTestDatabase(t))
+}
+
+// newTestDatabaseLink - Создает ссылку с идентификаторами в формате UUID, которого требуют колонки БД.
+func newTestDatabaseLink(shortURL, origURL, userID string) *data.ShortLinkData {
+	link := newTestLink(shortURL, origURL, userID)
+	link.UUID = uuid.NewString()
+	return link
+}
+
+func TestDatabaseShortLinkRepo_ExpiredOrigURL(t *testing.T) {
+	repo := openTestDatabaseRepo(t)
+	user1, user2 := uuid.NewString(), uuid.NewString()
+	expiresAt := time.Now().UTC().Add(-time.Minute)
+	expired := make([]*data.ShortLinkData, 0, 3)
+	for _, link := range []*data.ShortLinkData{
+		newTestDatabaseLink("expired1", "http://a.url", user1),
+		newTestDatabaseLink("expired2", "http://b.url", user1),
+		newTestDatabaseLink("expired3", "http://c.url", user1),
+	} {
+		link.CreatedAt = expiresAt.Add(-time.Hour)
+		link.ExpiresAt = &expiresAt
+		expired = append(expired, link)
+	}
+	_, err := repo.AddBatch(t.Context(), expired)
+	require.NoError(t, err)
+
+	created, err := repo.Add(t.Context(), newTestDatabaseLink("link0001", "http://a.url", user2))
+	require.NoError(t, err, "expired link should not block Add")
+	assert.Equal(t, "link0001", created.ShortURL)
+	links, err := repo.AddBatch(t.Context(), []*data.ShortLinkData{
+		newTestDatabaseLink("link0002", "http://b.url", user2),
+	})
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, "link0002", links[0].ShortURL, "expired link should not block AddBatch")
+	_, err = repo.Add(t.Context(), newTestDatabaseLink("link0003", "http://d.url", user2))
+	require.NoError(t, err)
+	updated, err := repo.UpdateOriginalURL(t.Context(), "link0003", user2, "http://c.url", time.Now().UTC())
+	require.NoError(t, err, "expired link should not block UpdateOriginalURL")
+	require.NotNil(t, updated)
+	assert.Equal(t, "http://c.url", updated.OriginalURL)
+
+	// URL занимают новые ссылки, а истекшие остаются до очистки.
+	var duplErr *data.DuplicateShortLinkError
+	for origURL, shortURL := range map[string]string{
+		"http://a.url": "link0001", "http://b.url": "link0002", "http://c.url": "link0003",
+	} {
+		_, err = repo.Add(t.Context(), newTestDatabaseLink("link0004", origURL, user1))
+		require.ErrorAs(t, err, &duplErr)
+		assert.Equal(t, shortURL, duplErr.ShortURL)
+	}
+	for _, shortURL := range []string{"expired1", "expired2", "expired3"} {
+		link, err := repo.Get(t.Context(), shortURL)
+		require.NoError(t, err)
+		require.NotNil(t, link, "expired link is kept until the purge")
+		assert.NotNil(t, link.ExpiresAt)
+	}
+}
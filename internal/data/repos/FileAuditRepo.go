@@ -0,0 +1,177 @@
+package repos
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/VladSnap/shortener/internal/log"
+	"go.uber.org/zap"
+)
+
+// FileAuditRepo - Репозиторий журнала аудита в файле в формате JSON Lines.
+// События дописываются в конец файла, для выборки последние события загружаются в память при запуске.
+// Хранит constants.AuditRetention последних событий, если не задано WithAuditRetention: когда в файле
+// накапливается вдвое больше строк, он переписывается только с хранимыми событиями.
+type FileAuditRepo struct {
+	list        *auditEvents
+	storageFile *os.File
+	path        string
+	// lines - Количество строк в файле, включая события, уже отброшенные из памяти.
+	lines int
+}
+
+// FileAuditRepoOption - Функция настройки FileAuditRepo.
+type FileAuditRepoOption func(*FileAuditRepo)
+
+// WithAuditRetention - Устанавливает количество последних событий, которые хранятся в файле и памяти.
+func WithAuditRetention(retention int) FileAuditRepoOption {
+	return func(repo *FileAuditRepo) {
+		if retention > 0 {
+			repo.list.limit = retention
+		}
+	}
+}
+
+// NewFileAuditRepo - Создает новую структуру FileAuditRepo с указателем.
+func NewFileAuditRepo(fileStoragePath string, options ...FileAuditRepoOption) (*FileAuditRepo, error) {
+	file, err := createFileStorage(fileStoragePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed create audit file storage: %w", err)
+	}
+
+	repo := &FileAuditRepo{list: newAuditEvents(constants.AuditRetention), storageFile: file, path: fileStoragePath}
+	for _, option := range options {
+		option(repo)
+	}
+	err = repo.loadEvents()
+	if err != nil {
+		return nil, fmt.Errorf("failed load audit events: %w", err)
+	}
+	repo.compactIfNeeded()
+
+	return repo, nil
+}
+
+// AddEvents - Сохраняет пачку событий журнала аудита в файле.
+func (repo *FileAuditRepo) AddEvents(ctx context.Context, events []*data.AuditEventData) error {
+	repo.list.mu.Lock()
+	defer repo.list.mu.Unlock()
+
+	nextID := repo.list.nextID
+	writer := bufio.NewWriter(repo.storageFile)
+	for _, event := range events {
+		nextID++
+		line := *event
+		line.ID = nextID
+		ed, err := json.Marshal(&line)
+		if err != nil {
+			return fmt.Errorf("failed serialize AuditEventData: %w", err)
+		}
+		if _, err := writer.Write(ed); err != nil {
+			return fmt.Errorf("failed write to file buffer: %w", err)
+		}
+		if err := writer.WriteByte('\n'); err != nil {
+			return fmt.Errorf("failed write \\n to file buffer: %w", err)
+		}
+	}
+	err := writer.Flush()
+	if err != nil {
+		return fmt.Errorf("failed flush buffer to audit file storage: %w", err)
+	}
+
+	repo.list.add(events)
+	repo.lines += len(events)
+	repo.compactIfNeeded()
+	return nil
+}
+
+// GetEvents - Получает события журнала аудита по параметрам выборки от новых к старым.
+func (repo *FileAuditRepo) GetEvents(ctx context.Context, query *data.AuditQueryData) (
+	[]*data.AuditEventData, error) {
+	return repo.list.find(query), nil
+}
+
+// Close - Закрывает файл.
+func (repo *FileAuditRepo) Close() error {
+	err := repo.storageFile.Close()
+	if err != nil {
+		return fmt.Errorf("audit file storage close error: %w", err)
+	}
+	log.Zap.Info("Audit file storage closed")
+
+	return nil
+}
+
+// loadEvents - Загружает события из файла. Оборванная последняя строка, оставшаяся после аварийного
+// завершения, отрезается от файла.
+func (repo *FileAuditRepo) loadEvents() error {
+	parse := func(line []byte) error {
+		event := new(data.AuditEventData)
+		if err := json.Unmarshal(line, event); err != nil {
+			return fmt.Errorf("failed deserialize AuditEventData: %w", err)
+		}
+		repo.list.load(event)
+		repo.lines++
+		return nil
+	}
+
+	reader := bufio.NewReader(repo.storageFile)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return recoverFileTail(repo.storageFile, line, offset, parse)
+		}
+		if err != nil {
+			return fmt.Errorf("failed read audit file storage: %w", err)
+		}
+		if err := parse(line); err != nil {
+			return fmt.Errorf("corrupted audit file storage at offset %d: %w", offset, err)
+		}
+		offset += int64(len(line))
+	}
+}
+
+// compactIfNeeded - Переписывает файл только с хранимыми событиями, когда строк в нем вдвое больше
+// ограничения. Вызывается под блокировкой на запись или до начала работы репозитория.
+// Ошибка уплотнения не теряет события, поэтому только логируется.
+func (repo *FileAuditRepo) compactIfNeeded() {
+	if repo.list.limit == 0 || repo.lines < 2*repo.list.limit {
+		return
+	}
+	if err := repo.compact(); err != nil {
+		log.Zap.Error("failed compact audit file storage", zap.Error(err))
+	}
+}
+
+// compact - Заменяет файл файлом с хранимыми событиями. Замена выполняется атомарным переименованием,
+// поэтому при сбое остается старый файл.
+func (repo *FileAuditRepo) compact() error {
+	tmpPath := repo.path + ".tmp"
+	if err := writeSnapshot(tmpPath, repo.list.events); err != nil {
+		return fmt.Errorf("failed write compacted audit file storage: %w", err)
+	}
+	if err := os.Rename(tmpPath, repo.path); err != nil {
+		return fmt.Errorf("failed replace audit file storage: %w", err)
+	}
+	syncDir(filepath.Dir(repo.path))
+
+	file, err := createFileStorage(repo.path)
+	if err != nil {
+		return fmt.Errorf("failed reopen audit file storage: %w", err)
+	}
+	if err := repo.storageFile.Close(); err != nil {
+		log.Zap.Warn("failed close replaced audit file storage", zap.Error(err))
+	}
+	repo.storageFile = file
+	repo.lines = len(repo.list.events)
+	return nil
+}
@@ -0,0 +1,120 @@
+package repos
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileAuditRepo_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.json.audit")
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	newEvent := func(action string, userID string, minutes int) *data.AuditEventData {
+		return &data.AuditEventData{Action: action, ShortURL: "link0001", UserID: userID, Source: "http",
+			ClientIP: "10.0.0.1", CreatedAt: start.Add(time.Duration(minutes) * time.Minute)}
+	}
+
+	repo, err := NewFileAuditRepo(path)
+	require.NoError(t, err)
+	require.NoError(t, repo.AddEvents(t.Context(), []*data.AuditEventData{
+		newEvent("create", "user1", 0),
+		newEvent("update", "user1", 1),
+	}))
+	require.NoError(t, repo.Close())
+
+	repo, err = NewFileAuditRepo(path)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, repo.Close()) }()
+	// Новые события получают номера после загруженных.
+	require.NoError(t, repo.AddEvents(t.Context(), []*data.AuditEventData{newEvent("delete", "user2", 2)}))
+
+	events, err := repo.GetEvents(t.Context(), &data.AuditQueryData{})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, []int64{3, 2, 1}, []int64{events[0].ID, events[1].ID, events[2].ID}, "newest events first")
+	assert.Equal(t, "delete", events[0].Action)
+	assert.Equal(t, "10.0.0.1", events[2].ClientIP)
+	assert.True(t, start.Equal(events[2].CreatedAt))
+
+	events, err = repo.GetEvents(t.Context(), &data.AuditQueryData{UserID: "user1", Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "update", events[0].Action)
+
+	events, err = repo.GetEvents(t.Context(), &data.AuditQueryData{From: start.Add(time.Minute),
+		To: start.Add(2 * time.Minute)})
+	require.NoError(t, err)
+	require.Len(t, events, 1, "from is inclusive, to is exclusive")
+	assert.Equal(t, "update", events[0].Action)
+}
+
+func TestFileAuditRepo_TruncatedLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.json.audit")
+	repo, err := NewFileAuditRepo(path)
+	require.NoError(t, err)
+	require.NoError(t, repo.AddEvents(t.Context(), []*data.AuditEventData{
+		{Action: "create", ShortURL: "link0001", UserID: "user1", Source: "http", CreatedAt: time.Now().UTC()},
+	}))
+	require.NoError(t, repo.Close())
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	// Аварийное завершение оборвало запись последнего события.
+	require.NoError(t, os.WriteFile(path, append(content, []byte(`{"id":2,"action":"del`)...), constants.FileRWPerm))
+
+	repo, err = NewFileAuditRepo(path)
+	require.NoError(t, err, "truncated last line should not fail startup")
+	defer func() { require.NoError(t, repo.Close()) }()
+	require.NoError(t, repo.AddEvents(t.Context(), []*data.AuditEventData{
+		{Action: "delete", ShortURL: "link0001", UserID: "user1", Source: "http", CreatedAt: time.Now().UTC()},
+	}))
+
+	events, err := repo.GetEvents(t.Context(), &data.AuditQueryData{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, []int64{2, 1}, []int64{events[0].ID, events[1].ID})
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(content), string(saved[:len(content)]), "broken line should be cut off")
+}
+
+func TestFileAuditRepo_Retention(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.json.audit")
+	repo, err := NewFileAuditRepo(path, WithAuditRetention(3))
+	require.NoError(t, err)
+	for i := range 7 {
+		require.NoError(t, repo.AddEvents(t.Context(), []*data.AuditEventData{
+			{Action: "create", ShortURL: fmt.Sprintf("link%04d", i), UserID: "user1", Source: "http",
+				CreatedAt: time.Now().UTC()},
+		}))
+	}
+
+	events, err := repo.GetEvents(t.Context(), &data.AuditQueryData{})
+	require.NoError(t, err)
+	require.Len(t, events, 3, "only the latest events should be kept")
+	assert.Equal(t, []int64{7, 6, 5}, []int64{events[0].ID, events[1].ID, events[2].ID})
+	require.NoError(t, repo.Close())
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	// Файл переписан после шестого события и дополнен седьмым.
+	assert.Len(t, strings.Split(strings.TrimSpace(string(content)), "\n"), 4)
+
+	repo, err = NewFileAuditRepo(path, WithAuditRetention(3))
+	require.NoError(t, err)
+	defer func() { require.NoError(t, repo.Close()) }()
+	require.NoError(t, repo.AddEvents(t.Context(), []*data.AuditEventData{
+		{Action: "delete", ShortURL: "link0000", UserID: "user1", Source: "http", CreatedAt: time.Now().UTC()},
+	}))
+	events, err = repo.GetEvents(t.Context(), &data.AuditQueryData{})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, []int64{8, 7, 6}, []int64{events[0].ID, events[1].ID, events[2].ID},
+		"numbering should continue after reload")
+}
@@ -0,0 +1,105 @@
+package repos
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/VladSnap/shortener/internal/log"
+)
+
+// FileClickRepo - Репозиторий для хранения переходов по ссылкам в файле в формате JSON Lines.
+type FileClickRepo struct {
+	counter     *clickCounter
+	storageFile *os.File
+	mu          sync.Mutex
+}
+
+// NewFileClickRepo - Создает новую структуру FileClickRepo с указателем.
+func NewFileClickRepo(fileStoragePath string) (*FileClickRepo, error) {
+	file, err := createFileStorage(fileStoragePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed create click file storage: %w", err)
+	}
+
+	repo := &FileClickRepo{counter: newClickCounter(), storageFile: file}
+	err = repo.loadClicks()
+	if err != nil {
+		return nil, fmt.Errorf("failed load clicks: %w", err)
+	}
+
+	return repo, nil
+}
+
+// AddClicks - Сохраняет пачку переходов по ссылкам в файле.
+func (repo *FileClickRepo) AddClicks(ctx context.Context, clicks []*data.ClickData) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	writer := bufio.NewWriter(repo.storageFile)
+	for _, click := range clicks {
+		cd, err := json.Marshal(click)
+		if err != nil {
+			return fmt.Errorf("failed serialize ClickData: %w", err)
+		}
+		if _, err := writer.Write(cd); err != nil {
+			return fmt.Errorf("failed write to file buffer: %w", err)
+		}
+		if err := writer.WriteByte('\n'); err != nil {
+			return fmt.Errorf("failed write \\n to file buffer: %w", err)
+		}
+	}
+	err := writer.Flush()
+	if err != nil {
+		return fmt.Errorf("failed flush buffer to click file storage: %w", err)
+	}
+
+	repo.counter.add(clicks)
+	return nil
+}
+
+// GetClickStats - Получает статистику переходов по сокращенной ссылке с группировкой по дням.
+func (repo *FileClickRepo) GetClickStats(ctx context.Context, shortURL string) (*data.ClickStatsData, error) {
+	return repo.counter.stats(shortURL), nil
+}
+
+// GetClickCounts - Получает общее количество переходов по каждой из указанных ссылок.
+func (repo *FileClickRepo) GetClickCounts(ctx context.Context, shortURLs []string) (map[string]int, error) {
+	return repo.counter.counts(shortURLs), nil
+}
+
+// Close - Закрывает файл.
+func (repo *FileClickRepo) Close() error {
+	err := repo.storageFile.Close()
+	if err != nil {
+		return fmt.Errorf("click file storage close error: %w", err)
+	}
+	log.Zap.Info("Click file storage closed")
+
+	return nil
+}
+
+func (repo *FileClickRepo) loadClicks() error {
+	scanner := bufio.NewScanner(repo.storageFile)
+	var clicks []*data.ClickData
+
+	for scanner.Scan() {
+		cd := data.ClickData{}
+		err := json.Unmarshal(scanner.Bytes(), &cd)
+		if err != nil {
+			return fmt.Errorf("failed deserialize ClickData: %w", err)
+		}
+		clicks = append(clicks, &cd)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed click file scan: %w", err)
+	}
+
+	repo.counter.add(clicks)
+	return nil
+}
@@ -0,0 +1,164 @@
+package repos
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/VladSnap/shortener/internal/log"
+	"go.uber.org/zap"
+)
+
+// FileDeleteQueue - Очередь удаления сокращенных ссылок в spool файле в формате JSON Lines.
+// Очередь держится в памяти, а после каждого изменения файл целиком перезаписывается через временный файл,
+// поэтому на диске всегда лежит согласованное состояние. Очередь обычно короткая, так что это дешево.
+type FileDeleteQueue struct {
+	queue *InMemoryDeleteQueue
+	path  string
+	mu    sync.Mutex
+}
+
+// NewFileDeleteQueue - Создает новую структуру FileDeleteQueue с указателем и загружает задачи из файла.
+func NewFileDeleteQueue(spoolPath string) (*FileDeleteQueue, error) {
+	queue := &FileDeleteQueue{queue: NewInMemoryDeleteQueue(), path: spoolPath}
+	tasks, err := readDeleteSpool(spoolPath)
+	if err != nil {
+		return nil, err
+	}
+	queue.queue.load(tasks)
+	return queue, nil
+}
+
+// Enqueue - Ставит в очередь задачи удаления ссылок запроса jobID и сохраняет очередь в файл.
+func (queue *FileDeleteQueue) Enqueue(ctx context.Context, jobID string, shortIDs []data.DeleteShortData,
+	actor data.AuditActorData, now time.Time) error {
+	return queue.update(func(mem *InMemoryDeleteQueue) bool {
+		mem.enqueue(jobID, shortIDs, actor, now)
+		return true
+	})
+}
+
+// FetchDue - Получает до limit задач, время выполнения которых не позже dueBefore, в порядке постановки.
+func (queue *FileDeleteQueue) FetchDue(ctx context.Context, dueBefore time.Time,
+	limit int) ([]*data.DeleteTaskData, error) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	return queue.queue.fetchDue(dueBefore, limit), nil
+}
+
+// Complete - Сохраняет результат и время выполнения задач в файл.
+func (queue *FileDeleteQueue) Complete(ctx context.Context, tasks []*data.DeleteTaskData) error {
+	return queue.update(func(mem *InMemoryDeleteQueue) bool {
+		mem.update(tasks)
+		return true
+	})
+}
+
+// Fail - Сохраняет состояние задач после неудачной попытки удаления в файл.
+func (queue *FileDeleteQueue) Fail(ctx context.Context, tasks []*data.DeleteTaskData) error {
+	return queue.update(func(mem *InMemoryDeleteQueue) bool {
+		mem.update(tasks)
+		return true
+	})
+}
+
+// CountPending - Возвращает количество задач, ожидающих выполнения.
+func (queue *FileDeleteQueue) CountPending(ctx context.Context) (int, error) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	return queue.queue.countPending(), nil
+}
+
+// GetJob - Получает задачи запроса на удаление в порядке постановки.
+func (queue *FileDeleteQueue) GetJob(ctx context.Context, jobID string) ([]*data.DeleteTaskData, error) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	return queue.queue.getJob(jobID), nil
+}
+
+// DeleteCompleted - Удаляет из очереди задачи, выполненные раньше указанного момента, и сохраняет очередь в файл.
+func (queue *FileDeleteQueue) DeleteCompleted(ctx context.Context, before time.Time) error {
+	return queue.update(func(mem *InMemoryDeleteQueue) bool {
+		return mem.deleteCompleted(before) > 0
+	})
+}
+
+// update - Применяет изменение к очереди в памяти и перезаписывает файл, если очередь изменилась.
+// Если файл записать не удалось, изменение откатывается, чтобы память не расходилась с диском.
+func (queue *FileDeleteQueue) update(change func(mem *InMemoryDeleteQueue) bool) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	backup := NewInMemoryDeleteQueue()
+	backup.load(copyDeleteTasks(queue.queue.all()))
+	backup.nextID = queue.queue.nextID
+
+	if !change(queue.queue) {
+		return nil
+	}
+	if err := queue.write(); err != nil {
+		queue.queue = backup
+		return err
+	}
+	return nil
+}
+
+// write - Перезаписывает файл очереди через временный файл.
+func (queue *FileDeleteQueue) write() error {
+	tmpPath := queue.path + ".tmp"
+	if err := writeSnapshot(tmpPath, queue.queue.all()); err != nil {
+		return fmt.Errorf("failed write delete queue: %w", err)
+	}
+	if err := os.Rename(tmpPath, queue.path); err != nil {
+		return fmt.Errorf("failed replace delete queue file: %w", err)
+	}
+	syncDir(filepath.Dir(queue.path))
+	return nil
+}
+
+// readDeleteSpool - Читает задачи из файла очереди. Отсутствующий файл означает пустую очередь.
+func readDeleteSpool(path string) ([]*data.DeleteTaskData, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed open delete queue file: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Zap.Error("failed close delete queue file", zap.Error(err))
+		}
+	}()
+
+	var tasks []*data.DeleteTaskData
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var task data.DeleteTaskData
+		if err := json.Unmarshal(scanner.Bytes(), &task); err != nil {
+			return nil, fmt.Errorf("failed deserialize DeleteTaskData: %w", err)
+		}
+		tasks = append(tasks, &task)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed read delete queue file: %w", err)
+	}
+	return tasks, nil
+}
+
+// copyDeleteTasks - Копирует задачи, чтобы изменения очереди не затрагивали копию.
+func copyDeleteTasks(tasks []*data.DeleteTaskData) []*data.DeleteTaskData {
+	copies := make([]*data.DeleteTaskData, 0, len(tasks))
+	for _, task := range tasks {
+		taskCopy := *task
+		copies = append(copies, &taskCopy)
+	}
+	return copies
+}
@@ -0,0 +1,119 @@
+package repos
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileDeleteQueue_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.json.delete-queue")
+	now := time.Now().UTC().Truncate(time.Second)
+
+	queue, err := NewFileDeleteQueue(path)
+	require.NoError(t, err)
+	require.NoError(t, queue.Enqueue(t.Context(), "job1", []data.DeleteShortData{
+		{ShortURL: "link0001", UserID: "user1"},
+		{ShortURL: "link0002", UserID: "user1"},
+		{ShortURL: "link0003", UserID: "user1"},
+	}, data.AuditActorData{Source: "grpc", ClientIP: "10.0.0.1"}, now))
+
+	tasks, err := queue.FetchDue(t.Context(), now, 10)
+	require.NoError(t, err)
+	require.Len(t, tasks, 3)
+	tasks[0].Outcome = "deleted"
+	tasks[0].CompletedAt = &now
+	require.NoError(t, queue.Complete(t.Context(), tasks[:1]))
+
+	// Одна задача откладывается, другая переносится в dead letter.
+	tasks[1].Attempts = 1
+	tasks[1].LastError = "database error"
+	tasks[1].NextAttemptAt = now.Add(time.Minute)
+	deadAt := now
+	tasks[2].Attempts = 5
+	tasks[2].DeadAt = &deadAt
+	require.NoError(t, queue.Fail(t.Context(), tasks[1:]))
+
+	queue, err = NewFileDeleteQueue(path)
+	require.NoError(t, err)
+
+	pending, err := queue.CountPending(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 1, pending)
+
+	due, err := queue.FetchDue(t.Context(), now, 10)
+	require.NoError(t, err)
+	assert.Empty(t, due, "retry is not due yet")
+
+	due, err = queue.FetchDue(t.Context(), now.Add(time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, "link0002", due[0].ShortURL)
+	assert.Equal(t, 1, due[0].Attempts)
+	assert.Equal(t, "database error", due[0].LastError)
+	assert.Equal(t, "grpc", due[0].Source)
+	assert.Equal(t, "10.0.0.1", due[0].ClientIP)
+
+	job, err := queue.GetJob(t.Context(), "job1")
+	require.NoError(t, err)
+	require.Len(t, job, 3, "completed and dead tasks are kept for the job status")
+	assert.Equal(t, "deleted", job[0].Outcome)
+	assert.NotNil(t, job[0].CompletedAt)
+
+	// Новые задачи получают идентификаторы после восстановленных.
+	require.NoError(t, queue.Enqueue(t.Context(), "job2",
+		[]data.DeleteShortData{{ShortURL: "link0004", UserID: "user1"}}, data.AuditActorData{}, now))
+	due, err = queue.FetchDue(t.Context(), now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Greater(t, due[0].ID, tasks[2].ID)
+	assert.Equal(t, "job2", due[0].JobID)
+
+	require.NoError(t, queue.DeleteCompleted(t.Context(), now.Add(time.Second)))
+	queue, err = NewFileDeleteQueue(path)
+	require.NoError(t, err)
+	job, err = queue.GetJob(t.Context(), "job1")
+	require.NoError(t, err)
+	assert.Len(t, job, 2, "only completed tasks are deleted after retention")
+}
+
+func TestInMemoryDeleteQueue_FetchDue(t *testing.T) {
+	now := time.Now()
+	queue := NewInMemoryDeleteQueue()
+	require.NoError(t, queue.Enqueue(t.Context(), "job1", []data.DeleteShortData{
+		{ShortURL: "link0001", UserID: "user1"},
+		{ShortURL: "link0002", UserID: "user1"},
+		{ShortURL: "link0003", UserID: "user1"},
+	}, data.AuditActorData{}, now))
+
+	tasks, err := queue.FetchDue(t.Context(), now, 2)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2, "fetch is limited")
+	assert.Equal(t, "link0001", tasks[0].ShortURL)
+	assert.Equal(t, "link0002", tasks[1].ShortURL)
+
+	// Изменение полученной задачи не меняет очередь без вызова Complete или Fail.
+	tasks[0].Attempts = 3
+	again, err := queue.FetchDue(t.Context(), now, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 0, again[0].Attempts)
+
+	tasks[0].Attempts = 0
+	for _, task := range tasks {
+		task.Outcome = "deleted"
+		task.CompletedAt = &now
+	}
+	require.NoError(t, queue.Complete(t.Context(), tasks))
+	pending, err := queue.CountPending(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 1, pending)
+
+	due, err := queue.FetchDue(t.Context(), now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1, "completed tasks are not fetched again")
+	assert.Equal(t, "link0003", due[0].ShortURL)
+}
@@ -1,50 +1,95 @@
 package repos
 
 import (
-	"bufio"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/VladSnap/shortener/internal/constants"
 	"github.com/VladSnap/shortener/internal/data"
 	"github.com/VladSnap/shortener/internal/helpers"
 	"github.com/VladSnap/shortener/internal/log"
+	"go.uber.org/zap"
 	"golang.org/x/exp/maps"
 )
 
 // FileShortLinkRepo - Репозиторий для доступа к файловому хранилищу сокращателя ссылок.
+// Состояние хранится в памяти, а каждое изменение сначала записывается в журнал (write-ahead log).
+// Безопасен для конкурентного использования.
 type FileShortLinkRepo struct {
-	links       map[string]*data.ShortLinkData
-	storageFile *os.File
+	links     map[string]*data.ShortLinkData
+	byOrigURL map[string]string
+	wal       *fileWAL
+	mu        sync.RWMutex
+
+	syncPolicy       string
+	compactThreshold int
+}
+
+// FileShortLinkRepoOption - Функция настройки FileShortLinkRepo.
+type FileShortLinkRepoOption func(*FileShortLinkRepo)
+
+// WithFileSyncPolicy - Устанавливает политику сброса журнала на диск: always, interval или never.
+func WithFileSyncPolicy(policy string) FileShortLinkRepoOption {
+	return func(repo *FileShortLinkRepo) {
+		if policy != "" {
+			repo.syncPolicy = policy
+		}
+	}
+}
+
+// WithFileCompactThreshold - Устанавливает количество записей журнала, после которого он уплотняется в снимок.
+// 0 отключает уплотнение по количеству записей.
+func WithFileCompactThreshold(threshold int) FileShortLinkRepoOption {
+	return func(repo *FileShortLinkRepo) {
+		repo.compactThreshold = threshold
+	}
 }
 
 // NewFileShortLinkRepo - Создает новую структуру FileShortLinkRepo с указателем.
-func NewFileShortLinkRepo(fileStoragePath string) (*FileShortLinkRepo, error) {
+// Восстанавливает состояние из снимка и журнала.
+func NewFileShortLinkRepo(fileStoragePath string, options ...FileShortLinkRepoOption) (*FileShortLinkRepo, error) {
 	repo := new(FileShortLinkRepo)
-	file, err := createFileStorage(fileStoragePath)
+	repo.links = make(map[string]*data.ShortLinkData)
+	repo.byOrigURL = make(map[string]string)
+	repo.syncPolicy = constants.FileSyncInterval
+	repo.compactThreshold = constants.FileCompactThreshold
+	for _, option := range options {
+		option(repo)
+	}
+
+	wal, err := openFileWAL(fileStoragePath, repo.syncPolicy, repo.compactThreshold)
 	if err != nil {
 		return nil, fmt.Errorf("failed create file storage: %w", err)
 	}
-	repo.storageFile = file
+	repo.wal = wal
 
-	links, err := repo.loadLinks()
+	err = wal.replay(repo.apply)
 	if err != nil {
 		return nil, fmt.Errorf("failed load links: %w", err)
 	}
-	repo.links = links
+	repo.compactIfNeeded()
 
 	return repo, nil
 }
 
 // Add - Сохраняет структуру сокращенной ссылки в файле.
+// Если оригинальный URL уже сокращен неистекшей ссылкой, возвращает data.DuplicateShortLinkError с ней.
 func (repo *FileShortLinkRepo) Add(ctx context.Context, link *data.ShortLinkData) (
 	*data.ShortLinkData, error) {
-	repo.links[link.ShortURL] = link
-	err := repo.writeLink(link)
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if shortURL, ok := activeShortURL(repo.links, repo.byOrigURL, link.OriginalURL, link.CreatedAt); ok {
+		return nil, data.NewDuplicateError(shortURL) //nolint:wrapcheck // is new error
+	}
+	if _, ok := repo.links[link.ShortURL]; ok {
+		return nil, data.NewShortURLConflictError(link.ShortURL) //nolint:wrapcheck // is new error
+	}
+	err := repo.write(&walRecord{Op: walOpCreate, Link: link})
 	if err != nil {
 		return nil, fmt.Errorf("failed write link to file storage: %w", err)
 	}
@@ -53,143 +98,271 @@ func (repo *FileShortLinkRepo) Add(ctx context.Context, link *data.ShortLinkData
 }
 
 // AddBatch - Сохраняет пачку структур сокращенных ссылок в файле.
+// Для уже сокращенных оригинальных URL в результате возвращается существующая ссылка.
+// Пачка сохраняется целиком или не сохраняется вовсе.
 func (repo *FileShortLinkRepo) AddBatch(ctx context.Context, links []*data.ShortLinkData) (
 	[]*data.ShortLinkData, error) {
-	for _, link := range links {
-		repo.links[link.ShortURL] = link
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	result, toStore, err := planBatch(repo.links, repo.byOrigURL, links)
+	if err != nil {
+		return nil, err
+	}
+	if len(toStore) == 0 {
+		return result, nil
 	}
 
-	err := repo.writeLinkBatch(links)
+	records := make([]*walRecord, 0, len(toStore))
+	for _, link := range toStore {
+		records = append(records, &walRecord{Op: walOpCreate, Link: link})
+	}
+	err = repo.write(records...)
 	if err != nil {
 		return nil, fmt.Errorf("failed write batch links to file storage: %w", err)
 	}
 
-	return links, nil
+	return result, nil
 }
 
 // Get - Читает полную ссылку по сокращенной ссылке.
 func (repo *FileShortLinkRepo) Get(ctx context.Context, shortID string) (*data.ShortLinkData, error) {
-	link := repo.links[shortID]
-	return link, nil
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	link, ok := repo.links[shortID]
+	if !ok {
+		return nil, nil //nolint:nilnil // link not found
+	}
+	linkCopy := *link
+	return &linkCopy, nil
 }
 
-// GetAllByUserID - Получить все сокращенные ссылки указанного пользователя.
-func (repo *FileShortLinkRepo) GetAllByUserID(ctx context.Context, userID string) (
+// GetAllByUserID - Получить сокращенные ссылки указанного пользователя по параметрам выборки.
+func (repo *FileShortLinkRepo) GetAllByUserID(ctx context.Context, userID string, query *data.UserLinksQuery) (
 	[]*data.ShortLinkData, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
 	var links []*data.ShortLinkData
 
 	for _, l := range repo.links {
 		if l.UserID == userID {
-			links = append(links, l)
+			linkCopy := *l
+			links = append(links, &linkCopy)
 		}
 	}
 
-	return links, nil
+	return applyUserLinksQuery(links, query), nil
 }
 
-// DeleteBatch - Удаляет пачку структур сокращенных ссылок в БД.
+// DeleteBatch - Помечает удаленными ссылки из пачки, принадлежащие указанным пользователям.
+// Неизвестные идентификаторы пропускаются.
 func (repo *FileShortLinkRepo) DeleteBatch(ctx context.Context, shortIDs []data.DeleteShortData) error {
-	// Сначала обновляем записи в мемори кэше.
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	now := time.Now().UTC()
+	records := make([]*walRecord, 0, len(shortIDs))
 	for _, sid := range shortIDs {
-		link := repo.links[sid.ShortURL]
-		if link.UserID == sid.UserID {
-			link.IsDeleted = true
+		link, ok := repo.links[sid.ShortURL]
+		if !ok || link.UserID != sid.UserID || link.IsDeleted {
+			continue
 		}
+		records = append(records, &walRecord{Op: walOpDelete, ShortURL: sid.ShortURL, DeletedAt: &now})
 	}
-	// Удаляем содержимое файла для перезаписи.
-	err := repo.storageFile.Truncate(0)
+	if len(records) == 0 {
+		return nil
+	}
+	err := repo.write(records...)
 	if err != nil {
-		return fmt.Errorf("failed truncate file storage: %w", err)
+		return fmt.Errorf("failed write batch delete to file storage: %w", err)
 	}
-	// Перезаписываем содержимое файла, чтобы проставить флаг is_deleted.
-	_, err = repo.AddBatch(ctx, maps.Values(repo.links))
+	return nil
+}
+
+// RestoreBatch - Снимает пометку удаления со ссылок из пачки, принадлежащих указанным пользователям
+// и удаленных не раньше deletedAfter. Остальные ссылки пропускаются.
+func (repo *FileShortLinkRepo) RestoreBatch(ctx context.Context, shortIDs []data.DeleteShortData,
+	deletedAfter time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	records := make([]*walRecord, 0, len(shortIDs))
+	for _, sid := range shortIDs {
+		link, ok := repo.links[sid.ShortURL]
+		if !ok || link.UserID != sid.UserID || !link.IsRestorable(deletedAfter) {
+			continue
+		}
+		records = append(records, &walRecord{Op: walOpRestore, ShortURL: sid.ShortURL})
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	err := repo.write(records...)
 	if err != nil {
-		return fmt.Errorf("failed rewrite file after batch delete: %w", err)
+		return fmt.Errorf("failed write batch restore to file storage: %w", err)
 	}
 	return nil
 }
 
+// UpdateOriginalURL - Меняет оригинальный URL неудаленной ссылки пользователя и сохраняет прежний URL в истории.
+// Если новый URL уже сокращен другой ссылкой, возвращает data.DuplicateShortLinkError с этой ссылкой.
+func (repo *FileShortLinkRepo) UpdateOriginalURL(ctx context.Context, shortURL string, userID string,
+	origURL string, changedAt time.Time) (*data.ShortLinkData, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	link, ok := repo.links[shortURL]
+	if !ok || link.UserID != userID || link.IsDeleted {
+		return nil, nil //nolint:nilnil // link not found
+	}
+	if link.OriginalURL != origURL {
+		if existing, ok := activeShortURL(repo.links, repo.byOrigURL, origURL, changedAt); ok {
+			return nil, data.NewDuplicateError(existing) //nolint:wrapcheck // is new error
+		}
+		err := repo.write(&walRecord{Op: walOpUpdate, ShortURL: shortURL, OriginalURL: origURL, ChangedAt: &changedAt})
+		if err != nil {
+			return nil, fmt.Errorf("failed write link update to file storage: %w", err)
+		}
+	}
+	linkCopy := *repo.links[shortURL]
+	return &linkCopy, nil
+}
+
+// Purge - Физически удаляет ссылки, помеченные удаленными или истекшие раньше указанного момента,
+// и уплотняет журнал в снимок.
+func (repo *FileShortLinkRepo) Purge(ctx context.Context, before time.Time) (*data.PurgeData, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	result := &data.PurgeData{}
+	for shortURL, link := range repo.links {
+		if link.IsPurgeable(before) {
+			result.Add(link)
+			delete(repo.links, shortURL)
+			if repo.byOrigURL[link.OriginalURL] == shortURL {
+				delete(repo.byOrigURL, link.OriginalURL)
+			}
+		}
+	}
+	if result.Total() == 0 {
+		return result, nil
+	}
+	err := repo.wal.compact(maps.Values(repo.links))
+	if err != nil {
+		return nil, fmt.Errorf("failed compact file storage after purge: %w", err)
+	}
+	return result, nil
+}
+
 // GetStats - Получает статистику о пользователях и всех ссылках.
 func (repo *FileShortLinkRepo) GetStats(ctx context.Context) (*data.StatsData, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
 	data := data.NewStatsData(len(repo.links), repo.calcAllUsers())
 	return data, nil
 }
 
-// Close - Закрывает файл.
+// GetShortIDs - Возвращает идентификаторы всех хранимых ссылок, включая помеченные удаленными.
+func (repo *FileShortLinkRepo) GetShortIDs() []string {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	return maps.Keys(repo.links)
+}
+
+// GetUserStats - Получает статистику по ссылкам указанного пользователя на указанный момент времени.
+func (repo *FileShortLinkRepo) GetUserStats(ctx context.Context, userID string, now time.Time) (
+	*data.UserStatsData, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	return calcUserStats(repo.links, userID, now), nil
+}
+
+// Close - Сбрасывает журнал на диск и закрывает файл.
 func (repo *FileShortLinkRepo) Close() error {
-	err := repo.storageFile.Close()
+	err := repo.wal.close()
 	if err != nil {
-		return fmt.Errorf("file storage close error: %w", err)
+		return err
 	}
 	log.Zap.Info("File storage closed")
 
 	return nil
 }
 
-func (repo *FileShortLinkRepo) loadFromFile() ([]*data.ShortLinkData, error) {
-	scanner := bufio.NewScanner(repo.storageFile)
-	var dataList []*data.ShortLinkData
-
-	for scanner.Scan() {
-		dataBytes := scanner.Bytes()
-		sd := data.ShortLinkData{}
-		err := json.Unmarshal(dataBytes, &sd)
-		if err != nil {
-			return nil, fmt.Errorf("failed deserialize ShortLinkData: %w", err)
-		}
-
-		dataList = append(dataList, &sd)
-	}
-
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("failed file scan: %w", err)
-	}
-
-	return dataList, nil
+// CheckHealth - Проверяет, что журнал открыт, а в каталоге хранилища можно создавать файлы.
+func (repo *FileShortLinkRepo) CheckHealth(_ context.Context) error {
+	return repo.wal.checkWritable()
 }
 
-func (repo *FileShortLinkRepo) writeLink(link *data.ShortLinkData) error {
-	writer := bufio.NewWriter(repo.storageFile)
-	sd, err := json.Marshal(link)
+// write - Записывает изменения в журнал, применяет их к состоянию и при необходимости уплотняет журнал.
+// Вызывается под блокировкой на запись.
+func (repo *FileShortLinkRepo) write(records ...*walRecord) error {
+	err := repo.wal.append(records...)
 	if err != nil {
-		return fmt.Errorf("failed serialize ShortLinkData: %w", err)
-	}
-
-	if _, err := writer.Write(sd); err != nil {
-		return fmt.Errorf("failed write to file buffer: %w", err)
+		return err
 	}
-	if err := writer.WriteByte('\n'); err != nil {
-		return fmt.Errorf("failed write \\n to file buffer: %w", err)
-	}
-
-	err = writer.Flush()
-	if err != nil {
-		return fmt.Errorf("failed flush buffer to fileStorage: %w", err)
+	for _, rec := range records {
+		repo.apply(rec)
 	}
+	repo.compactIfNeeded()
 	return nil
 }
 
-func (repo *FileShortLinkRepo) writeLinkBatch(links []*data.ShortLinkData) error {
-	writer := bufio.NewWriter(repo.storageFile)
-	for _, link := range links {
-		sd, err := json.Marshal(link)
-		if err != nil {
-			return fmt.Errorf("failed serialize ShortLinkData: %w", err)
+// apply - Применяет запись журнала к состоянию в памяти. Хранимые ссылки не изменяются на месте.
+func (repo *FileShortLinkRepo) apply(rec *walRecord) {
+	switch rec.Op {
+	case walOpCreate:
+		linkCopy := *rec.Link
+		repo.links[linkCopy.ShortURL] = &linkCopy
+		// Истекшая ссылка и ссылка, заново сократившая её URL, лежат в снимке в произвольном порядке.
+		// Замена создается после истечения прежней ссылки, поэтому индекс указывает на ссылку, истекающую позже.
+		current, ok := repo.links[repo.byOrigURL[linkCopy.OriginalURL]]
+		if !ok || current.ShortURL == linkCopy.ShortURL || !expiresBefore(&linkCopy, current) {
+			repo.byOrigURL[linkCopy.OriginalURL] = linkCopy.ShortURL
 		}
-
-		if _, err := writer.Write(sd); err != nil {
-			return fmt.Errorf("failed write to file buffer: %w", err)
+	case walOpDelete:
+		link, ok := repo.links[rec.ShortURL]
+		if !ok {
+			return
 		}
-		if err := writer.WriteByte('\n'); err != nil {
-			return fmt.Errorf("failed write \\n to file buffer: %w", err)
+		deleted := *link
+		deleted.IsDeleted = true
+		deleted.DeletedAt = rec.DeletedAt
+		repo.links[rec.ShortURL] = &deleted
+	case walOpRestore:
+		link, ok := repo.links[rec.ShortURL]
+		if !ok {
+			return
 		}
+		restored := *link
+		restored.IsDeleted = false
+		restored.DeletedAt = nil
+		repo.links[rec.ShortURL] = &restored
+	case walOpUpdate:
+		link, ok := repo.links[rec.ShortURL]
+		if !ok {
+			return
+		}
+		if repo.byOrigURL[link.OriginalURL] == link.ShortURL {
+			delete(repo.byOrigURL, link.OriginalURL)
+		}
+		repo.links[rec.ShortURL] = link.WithOriginalURL(rec.OriginalURL, *rec.ChangedAt)
+		repo.byOrigURL[rec.OriginalURL] = rec.ShortURL
+	}
+}
+
+// expiresBefore - Проверяет, истекает ли ссылка link раньше ссылки other. Ссылка без срока не истекает.
+func expiresBefore(link, other *data.ShortLinkData) bool {
+	if link.ExpiresAt == nil {
+		return false
 	}
+	return other.ExpiresAt == nil || link.ExpiresAt.Before(*other.ExpiresAt)
+}
 
-	err := writer.Flush()
+// compactIfNeeded - Уплотняет журнал в снимок, если он разросся.
+// Ошибка уплотнения не теряет данные, поэтому только логируется.
+func (repo *FileShortLinkRepo) compactIfNeeded() {
+	if !repo.wal.needsCompaction() {
+		return
+	}
+	err := repo.wal.compact(maps.Values(repo.links))
 	if err != nil {
-		return fmt.Errorf("failed flush buffer to fileStorage: %w", err)
+		log.Zap.Error("failed compact file storage", zap.Error(err))
 	}
-	return nil
 }
 
 func createFileStorage(fileStoragePath string) (*os.File, error) {
@@ -208,18 +381,6 @@ func createFileStorage(fileStoragePath string) (*os.File, error) {
 	return file, nil
 }
 
-func (repo *FileShortLinkRepo) loadLinks() (map[string]*data.ShortLinkData, error) {
-	links, err := repo.loadFromFile()
-	if err != nil {
-		return nil, fmt.Errorf("failed load from file storage: %w", err)
-	}
-	linkMap := make(map[string]*data.ShortLinkData, len(links))
-	for _, link := range links {
-		linkMap[link.ShortURL] = link
-	}
-	return linkMap, nil
-}
-
 func (repo *FileShortLinkRepo) calcAllUsers() int {
 	users := make(map[string]bool)
 
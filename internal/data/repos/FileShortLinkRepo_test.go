@@ -0,0 +1,263 @@
+package repos
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestFileRepo(t *testing.T, path string, options ...FileShortLinkRepoOption) *FileShortLinkRepo {
+	t.Helper()
+	options = append([]FileShortLinkRepoOption{WithFileSyncPolicy(constants.FileSyncAlways)}, options...)
+	repo, err := NewFileShortLinkRepo(path, options...)
+	require.NoError(t, err)
+	return repo
+}
+
+func TestFileShortLinkRepo_Replay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.json")
+	repo := openTestFileRepo(t, path)
+	_, err := repo.Add(t.Context(), newTestLink("link0001", "http://a.url", "user1"))
+	require.NoError(t, err)
+	_, err = repo.AddBatch(t.Context(), []*data.ShortLinkData{
+		newTestLink("link0002", "http://b.url", "user1"),
+		newTestLink("link0003", "http://c.url", "user2"),
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.DeleteBatch(t.Context(), []data.DeleteShortData{
+		{ShortURL: "link0002", UserID: "user1"},
+		{ShortURL: "unknown1", UserID: "user1"},
+	}))
+	require.NoError(t, repo.Close())
+
+	repo = openTestFileRepo(t, path)
+	defer func() { require.NoError(t, repo.Close()) }()
+
+	stats, err := repo.GetStats(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.Urls)
+	link, err := repo.Get(t.Context(), "link0002")
+	require.NoError(t, err)
+	assert.True(t, link.IsDeleted)
+	assert.NotNil(t, link.DeletedAt)
+}
+
+func TestFileShortLinkRepo_RestoreBatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.json")
+	repo := openTestFileRepo(t, path)
+	_, err := repo.AddBatch(t.Context(), []*data.ShortLinkData{
+		newTestLink("link0001", "http://a.url", "user1"),
+		newTestLink("link0002", "http://b.url", "user1"),
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.DeleteBatch(t.Context(), []data.DeleteShortData{
+		{ShortURL: "link0001", UserID: "user1"},
+		{ShortURL: "link0002", UserID: "user1"},
+	}))
+
+	// Ссылка другого пользователя и ссылка, удаленная раньше окна, не восстанавливаются.
+	require.NoError(t, repo.RestoreBatch(t.Context(), []data.DeleteShortData{
+		{ShortURL: "link0002", UserID: "user2"},
+	}, time.Now().Add(-time.Hour)))
+	require.NoError(t, repo.RestoreBatch(t.Context(), []data.DeleteShortData{
+		{ShortURL: "link0001", UserID: "user1"},
+	}, time.Now().Add(time.Hour)))
+	require.NoError(t, repo.RestoreBatch(t.Context(), []data.DeleteShortData{
+		{ShortURL: "link0002", UserID: "user1"},
+	}, time.Now().Add(-time.Hour)))
+	require.NoError(t, repo.Close())
+
+	repo = openTestFileRepo(t, path)
+	defer func() { require.NoError(t, repo.Close()) }()
+
+	link, err := repo.Get(t.Context(), "link0001")
+	require.NoError(t, err)
+	assert.True(t, link.IsDeleted)
+	link, err = repo.Get(t.Context(), "link0002")
+	require.NoError(t, err)
+	assert.False(t, link.IsDeleted, "restore should be replayed from the log")
+	assert.Nil(t, link.DeletedAt)
+}
+
+func TestFileShortLinkRepo_UpdateOriginalURL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.json")
+	repo := openTestFileRepo(t, path, WithFileCompactThreshold(100))
+	assertUpdateOriginalURL(t, repo)
+	require.NoError(t, repo.Close())
+
+	// История восстанавливается из журнала и переживает уплотнение в снимок.
+	repo = openTestFileRepo(t, path, WithFileCompactThreshold(1))
+	assertUpdatedLink(t, repo)
+	_, err := repo.Add(t.Context(), newTestLink("link0005", "http://e.url", "user2"))
+	require.NoError(t, err)
+	assertLogSize(t, path, 0)
+	require.NoError(t, repo.Close())
+
+	repo = openTestFileRepo(t, path)
+	defer func() { require.NoError(t, repo.Close()) }()
+	assertUpdatedLink(t, repo)
+}
+
+func assertUpdatedLink(t *testing.T, repo *FileShortLinkRepo) {
+	t.Helper()
+	link, err := repo.Get(t.Context(), "link0001")
+	require.NoError(t, err)
+	assert.Equal(t, "http://d.url", link.OriginalURL)
+	require.Len(t, link.History, 2)
+	assert.Equal(t, "http://a.url", link.History[0].OriginalURL)
+	_, err = repo.Add(t.Context(), newTestLink("link0006", "http://d.url", "user2"))
+	var duplErr *data.DuplicateShortLinkError
+	require.ErrorAs(t, err, &duplErr)
+}
+
+func TestFileShortLinkRepo_Duplicates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.json")
+	repo := openTestFileRepo(t, path)
+	_, err := repo.Add(t.Context(), newTestLink("link0001", "http://a.url", "user1"))
+	require.NoError(t, err)
+	require.NoError(t, repo.Close())
+
+	// Индекс оригинальных URL восстанавливается из журнала.
+	repo = openTestFileRepo(t, path)
+	defer func() { require.NoError(t, repo.Close()) }()
+
+	_, err = repo.Add(t.Context(), newTestLink("link0002", "http://a.url", "user2"))
+	var duplErr *data.DuplicateShortLinkError
+	require.ErrorAs(t, err, &duplErr)
+	assert.Equal(t, "link0001", duplErr.ShortURL)
+
+	links, err := repo.AddBatch(t.Context(), []*data.ShortLinkData{
+		newTestLink("link0003", "http://c.url", "user1"),
+		newTestLink("link0004", "http://a.url", "user1"),
+	})
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+	assert.Equal(t, "link0003", links[0].ShortURL)
+	assert.Equal(t, "link0001", links[1].ShortURL)
+	link, err := repo.Get(t.Context(), "link0004")
+	require.NoError(t, err)
+	assert.Nil(t, link, "duplicate must not be saved")
+}
+
+func TestFileShortLinkRepo_ExpiredOrigURL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.json")
+	// Журнал уплотняется после каждой записи, чтобы истекшие ссылки и их замены попали в снимок.
+	repo := openTestFileRepo(t, path, WithFileCompactThreshold(1))
+	assertExpiredOrigURLIsFree(t, repo)
+	require.NoError(t, repo.Close())
+
+	repo = openTestFileRepo(t, path)
+	defer func() { require.NoError(t, repo.Close()) }()
+	for origURL, shortURL := range map[string]string{
+		"http://a.url": "link0001", "http://b.url": "link0002", "http://c.url": "link0003",
+	} {
+		_, err := repo.Add(t.Context(), newTestLink("link0005", origURL, "user1"))
+		var duplErr *data.DuplicateShortLinkError
+		require.ErrorAs(t, err, &duplErr, "index should point to the new link after replay")
+		assert.Equal(t, shortURL, duplErr.ShortURL)
+	}
+}
+
+func TestFileShortLinkRepo_TruncatedLastRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.json")
+	repo := openTestFileRepo(t, path)
+	_, err := repo.Add(t.Context(), newTestLink("link0001", "http://a.url", "user1"))
+	require.NoError(t, err)
+	require.NoError(t, repo.Close())
+
+	// Имитируем сбой посреди записи второй ссылки.
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, constants.FileRWPerm)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"op":"create","link":{"uuid":"x","short_url":"link0002","orig`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	repo = openTestFileRepo(t, path)
+	_, err = repo.Add(t.Context(), newTestLink("link0003", "http://c.url", "user1"))
+	require.NoError(t, err)
+	require.NoError(t, repo.Close())
+
+	repo = openTestFileRepo(t, path)
+	defer func() { require.NoError(t, repo.Close()) }()
+	stats, err := repo.GetStats(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.Urls)
+	link, err := repo.Get(t.Context(), "link0002")
+	require.NoError(t, err)
+	assert.Nil(t, link)
+}
+
+func TestFileShortLinkRepo_CorruptedRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.json")
+	content := "{broken}\n" + `{"op":"create","link":{"short_url":"link0001","orig_url":"http://a.url"}}` + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), constants.FileRWPerm))
+
+	_, err := NewFileShortLinkRepo(path)
+
+	assert.Error(t, err)
+}
+
+func TestFileShortLinkRepo_Compaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "storage.json")
+	// Файл в формате до появления журнала: по строке на каждую версию ссылки.
+	legacy := `{"uuid":"1","short_url":"link0001","orig_url":"http://a.url","user_id":"user1","is_deleted":false}` + "\n" +
+		`{"uuid":"1","short_url":"link0001","orig_url":"http://a.url","user_id":"user1","is_deleted":true}` + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(legacy), constants.FileRWPerm))
+
+	repo := openTestFileRepo(t, path, WithFileCompactThreshold(2))
+	// Строки старого формата сразу переносятся в снимок.
+	assertLogSize(t, path, 0)
+
+	_, err := repo.Add(t.Context(), newTestLink("link0002", "http://b.url", "user1"))
+	require.NoError(t, err)
+	assertLogSize(t, path, 1)
+	_, err = repo.Add(t.Context(), newTestLink("link0003", "http://c.url", "user1"))
+	require.NoError(t, err)
+	assertLogSize(t, path, 0)
+
+	purged, err := repo.Purge(t.Context(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged.Deleted)
+	require.NoError(t, repo.Close())
+
+	repo = openTestFileRepo(t, path)
+	defer func() { require.NoError(t, repo.Close()) }()
+	links, err := repo.GetAllByUserID(t.Context(), "user1", nil)
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+	assert.Equal(t, "link0002", links[0].ShortURL)
+}
+
+func assertLogSize(t *testing.T, path string, records int) {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := 0
+	for _, b := range content {
+		if b == '\n' {
+			lines++
+		}
+	}
+	assert.Equal(t, records, lines)
+}
+
+func TestFileShortLinkRepo_CheckHealth(t *testing.T) {
+	dir := t.TempDir()
+	repo := openTestFileRepo(t, filepath.Join(dir, "storage.json"))
+	require.NoError(t, repo.CheckHealth(t.Context()))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.NotContains(t, entry.Name(), ".health-", "probe file should be removed")
+	}
+
+	require.NoError(t, repo.Close())
+	require.Error(t, repo.CheckHealth(t.Context()))
+}
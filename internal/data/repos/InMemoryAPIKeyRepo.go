@@ -0,0 +1,81 @@
+package repos
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+)
+
+// InMemoryAPIKeyRepo - Репозиторий для хранения ключей API в оперативной памяти.
+// Используется, когда БД не задана: ключи теряются при перезапуске сервиса.
+type InMemoryAPIKeyRepo struct {
+	keys   map[string]*data.APIKeyData
+	byHash map[string]*data.APIKeyData
+	mu     sync.RWMutex
+}
+
+// NewInMemoryAPIKeyRepo - Создает новую структуру InMemoryAPIKeyRepo с указателем.
+func NewInMemoryAPIKeyRepo() *InMemoryAPIKeyRepo {
+	return &InMemoryAPIKeyRepo{
+		keys:   make(map[string]*data.APIKeyData),
+		byHash: make(map[string]*data.APIKeyData),
+	}
+}
+
+// AddAPIKey - Сохраняет новый ключ API в памяти.
+func (repo *InMemoryAPIKeyRepo) AddAPIKey(ctx context.Context, key *data.APIKeyData) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	stored := *key
+	repo.keys[key.ID] = &stored
+	repo.byHash[key.KeyHash] = &stored
+	return nil
+}
+
+// GetAPIKeyByHash - Получает ключ API по хешу, возвращает nil, если ключ не найден.
+func (repo *InMemoryAPIKeyRepo) GetAPIKeyByHash(ctx context.Context, keyHash string) (*data.APIKeyData, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	key, ok := repo.byHash[keyHash]
+	if !ok {
+		return nil, nil //nolint:nilnil // key not found
+	}
+	result := *key
+	return &result, nil
+}
+
+// GetAPIKeys - Получает все ключи API, включая отозванные, в порядке создания.
+func (repo *InMemoryAPIKeyRepo) GetAPIKeys(ctx context.Context) ([]*data.APIKeyData, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	keys := make([]*data.APIKeyData, 0, len(repo.keys))
+	for _, key := range repo.keys {
+		result := *key
+		keys = append(keys, &result)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].CreatedAt.Equal(keys[j].CreatedAt) {
+			return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+		}
+		return keys[i].ID < keys[j].ID
+	})
+	return keys, nil
+}
+
+// RevokeAPIKey - Помечает ключ API отозванным. Возвращает false, если ключ не найден.
+// Повторный отзыв не меняет время отзыва.
+func (repo *InMemoryAPIKeyRepo) RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) (bool, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	key, ok := repo.keys[id]
+	if !ok {
+		return false, nil
+	}
+	if key.RevokedAt == nil {
+		key.RevokedAt = &revokedAt
+	}
+	return true, nil
+}
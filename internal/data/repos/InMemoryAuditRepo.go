@@ -0,0 +1,91 @@
+package repos
+
+import (
+	"context"
+	"sync"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/data"
+)
+
+// auditEvents - Потокобезопасный список событий журнала аудита в порядке записи.
+// Хранит не больше limit последних событий, более старые отбрасываются.
+type auditEvents struct {
+	events []*data.AuditEventData
+	nextID int64
+	limit  int
+	mu     sync.RWMutex
+}
+
+// newAuditEvents - Создает список, хранящий не больше limit последних событий. 0 снимает ограничение.
+func newAuditEvents(limit int) *auditEvents {
+	return &auditEvents{limit: limit}
+}
+
+// add - Присваивает событиям порядковые номера и добавляет их в список.
+func (list *auditEvents) add(events []*data.AuditEventData) {
+	for _, event := range events {
+		list.nextID++
+		event.ID = list.nextID
+		list.events = append(list.events, event)
+	}
+	list.trim()
+}
+
+// load - Добавляет в список сохраненные ранее события, сохраняя их порядковые номера.
+func (list *auditEvents) load(event *data.AuditEventData) {
+	list.events = append(list.events, event)
+	list.nextID = max(list.nextID, event.ID)
+	list.trim()
+}
+
+// trim - Отбрасывает самые старые события сверх ограничения. Отброшенное начало массива освобождается,
+// когда append переносит оставшиеся события в новый массив.
+func (list *auditEvents) trim() {
+	if list.limit > 0 && len(list.events) > list.limit {
+		list.events = list.events[len(list.events)-list.limit:]
+	}
+}
+
+// find - Возвращает события, подходящие под параметры выборки, от новых к старым.
+func (list *auditEvents) find(query *data.AuditQueryData) []*data.AuditEventData {
+	list.mu.RLock()
+	defer list.mu.RUnlock()
+	result := make([]*data.AuditEventData, 0)
+	for i := len(list.events) - 1; i >= 0; i-- {
+		if query.Limit > 0 && len(result) >= query.Limit {
+			break
+		}
+		if event := list.events[i]; query.Match(event) {
+			copied := *event
+			result = append(result, &copied)
+		}
+	}
+	return result
+}
+
+// InMemoryAuditRepo - Репозиторий журнала аудита в оперативной памяти.
+// Используется, когда ссылки хранятся в памяти или только в Redis: журнал теряется при перезапуске сервиса.
+// Хранит constants.AuditRetention последних событий.
+type InMemoryAuditRepo struct {
+	list *auditEvents
+}
+
+// NewInMemoryAuditRepo - Создает новую структуру InMemoryAuditRepo с указателем.
+func NewInMemoryAuditRepo() *InMemoryAuditRepo {
+	return &InMemoryAuditRepo{list: newAuditEvents(constants.AuditRetention)}
+}
+
+// AddEvents - Сохраняет пачку событий журнала аудита в памяти.
+func (repo *InMemoryAuditRepo) AddEvents(ctx context.Context, events []*data.AuditEventData) error {
+	repo.list.mu.Lock()
+	defer repo.list.mu.Unlock()
+	repo.list.add(events)
+	return nil
+}
+
+// GetEvents - Получает события журнала аудита по параметрам выборки от новых к старым.
+func (repo *InMemoryAuditRepo) GetEvents(ctx context.Context, query *data.AuditQueryData) (
+	[]*data.AuditEventData, error) {
+	return repo.list.find(query), nil
+}
@@ -0,0 +1,92 @@
+package repos
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+)
+
+// clickCounter - Потокобезопасный счетчик переходов по ссылкам с группировкой по дням.
+type clickCounter struct {
+	days map[string]map[time.Time]int
+	mu   sync.RWMutex
+}
+
+func newClickCounter() *clickCounter {
+	return &clickCounter{days: make(map[string]map[time.Time]int)}
+}
+
+func (counter *clickCounter) add(clicks []*data.ClickData) {
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+	for _, click := range clicks {
+		linkDays, ok := counter.days[click.ShortURL]
+		if !ok {
+			linkDays = make(map[time.Time]int)
+			counter.days[click.ShortURL] = linkDays
+		}
+		linkDays[truncateToDay(click.ClickedAt)]++
+	}
+}
+
+func (counter *clickCounter) stats(shortURL string) *data.ClickStatsData {
+	counter.mu.RLock()
+	defer counter.mu.RUnlock()
+	linkDays := counter.days[shortURL]
+	stats := &data.ClickStatsData{Days: make([]data.DailyClicksData, 0, len(linkDays))}
+	for day, clicks := range linkDays {
+		stats.Total += clicks
+		stats.Days = append(stats.Days, data.DailyClicksData{Day: day, Clicks: clicks})
+	}
+	sort.Slice(stats.Days, func(i, j int) bool {
+		return stats.Days[i].Day.Before(stats.Days[j].Day)
+	})
+	return stats
+}
+
+func (counter *clickCounter) counts(shortURLs []string) map[string]int {
+	counter.mu.RLock()
+	defer counter.mu.RUnlock()
+	result := make(map[string]int, len(shortURLs))
+	for _, shortURL := range shortURLs {
+		for _, clicks := range counter.days[shortURL] {
+			result[shortURL] += clicks
+		}
+	}
+	return result
+}
+
+// truncateToDay - Возвращает начало дня по UTC для указанного времени.
+func truncateToDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// InMemoryClickRepo - Репозиторий для хранения переходов по ссылкам в оперативной памяти.
+type InMemoryClickRepo struct {
+	counter *clickCounter
+}
+
+// NewInMemoryClickRepo - Создает новую структуру InMemoryClickRepo с указателем.
+func NewInMemoryClickRepo() *InMemoryClickRepo {
+	return &InMemoryClickRepo{counter: newClickCounter()}
+}
+
+// AddClicks - Сохраняет пачку переходов по ссылкам в памяти.
+func (repo *InMemoryClickRepo) AddClicks(ctx context.Context, clicks []*data.ClickData) error {
+	repo.counter.add(clicks)
+	return nil
+}
+
+// GetClickStats - Получает статистику переходов по сокращенной ссылке с группировкой по дням.
+func (repo *InMemoryClickRepo) GetClickStats(ctx context.Context, shortURL string) (*data.ClickStatsData, error) {
+	return repo.counter.stats(shortURL), nil
+}
+
+// GetClickCounts - Получает общее количество переходов по каждой из указанных ссылок.
+func (repo *InMemoryClickRepo) GetClickCounts(ctx context.Context, shortURLs []string) (map[string]int, error) {
+	return repo.counter.counts(shortURLs), nil
+}
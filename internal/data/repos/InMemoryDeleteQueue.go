@@ -0,0 +1,173 @@
+package repos
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+)
+
+// InMemoryDeleteQueue - Очередь удаления сокращенных ссылок в оперативной памяти.
+// Используется, когда ссылки хранятся в памяти или только в Redis: задачи теряются при перезапуске сервиса.
+type InMemoryDeleteQueue struct {
+	tasks  map[int64]*data.DeleteTaskData
+	nextID int64
+	mu     sync.Mutex
+}
+
+// NewInMemoryDeleteQueue - Создает новую структуру InMemoryDeleteQueue с указателем.
+func NewInMemoryDeleteQueue() *InMemoryDeleteQueue {
+	return &InMemoryDeleteQueue{tasks: make(map[int64]*data.DeleteTaskData)}
+}
+
+// Enqueue - Ставит в очередь задачи удаления ссылок запроса jobID, готовые к выполнению сразу.
+func (queue *InMemoryDeleteQueue) Enqueue(ctx context.Context, jobID string, shortIDs []data.DeleteShortData,
+	actor data.AuditActorData, now time.Time) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	queue.enqueue(jobID, shortIDs, actor, now)
+	return nil
+}
+
+// FetchDue - Получает до limit задач, время выполнения которых не позже dueBefore, в порядке постановки.
+func (queue *InMemoryDeleteQueue) FetchDue(ctx context.Context, dueBefore time.Time,
+	limit int) ([]*data.DeleteTaskData, error) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	return queue.fetchDue(dueBefore, limit), nil
+}
+
+// Complete - Сохраняет результат и время выполнения задач.
+func (queue *InMemoryDeleteQueue) Complete(ctx context.Context, tasks []*data.DeleteTaskData) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	queue.update(tasks)
+	return nil
+}
+
+// Fail - Сохраняет состояние задач после неудачной попытки удаления.
+func (queue *InMemoryDeleteQueue) Fail(ctx context.Context, tasks []*data.DeleteTaskData) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	queue.update(tasks)
+	return nil
+}
+
+// CountPending - Возвращает количество задач, ожидающих выполнения.
+func (queue *InMemoryDeleteQueue) CountPending(ctx context.Context) (int, error) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	return queue.countPending(), nil
+}
+
+// GetJob - Получает задачи запроса на удаление в порядке постановки.
+func (queue *InMemoryDeleteQueue) GetJob(ctx context.Context, jobID string) ([]*data.DeleteTaskData, error) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	return queue.getJob(jobID), nil
+}
+
+// DeleteCompleted - Удаляет из очереди задачи, выполненные раньше указанного момента.
+func (queue *InMemoryDeleteQueue) DeleteCompleted(ctx context.Context, before time.Time) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	queue.deleteCompleted(before)
+	return nil
+}
+
+func (queue *InMemoryDeleteQueue) enqueue(jobID string, shortIDs []data.DeleteShortData,
+	actor data.AuditActorData, now time.Time) {
+	for _, sid := range shortIDs {
+		queue.nextID++
+		queue.tasks[queue.nextID] = &data.DeleteTaskData{
+			ID:            queue.nextID,
+			JobID:         jobID,
+			ShortURL:      sid.ShortURL,
+			UserID:        sid.UserID,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			Source:        actor.Source,
+			ClientIP:      actor.ClientIP,
+		}
+	}
+}
+
+func (queue *InMemoryDeleteQueue) fetchDue(dueBefore time.Time, limit int) []*data.DeleteTaskData {
+	due := make([]*data.DeleteTaskData, 0)
+	for _, task := range queue.tasks {
+		if isPendingDeleteTask(task) && !task.NextAttemptAt.After(dueBefore) {
+			due = append(due, task)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].ID < due[j].ID })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	// Возвращаются копии, чтобы воркер менял состояние задач только через Complete и Fail.
+	return copyDeleteTasks(due)
+}
+
+func (queue *InMemoryDeleteQueue) update(tasks []*data.DeleteTaskData) {
+	for _, task := range tasks {
+		if _, ok := queue.tasks[task.ID]; ok {
+			taskCopy := *task
+			queue.tasks[task.ID] = &taskCopy
+		}
+	}
+}
+
+func (queue *InMemoryDeleteQueue) countPending() int {
+	count := 0
+	for _, task := range queue.tasks {
+		if isPendingDeleteTask(task) {
+			count++
+		}
+	}
+	return count
+}
+
+func (queue *InMemoryDeleteQueue) getJob(jobID string) []*data.DeleteTaskData {
+	tasks := make([]*data.DeleteTaskData, 0)
+	for _, task := range queue.all() {
+		if task.JobID == jobID {
+			tasks = append(tasks, task)
+		}
+	}
+	return copyDeleteTasks(tasks)
+}
+
+func (queue *InMemoryDeleteQueue) deleteCompleted(before time.Time) int {
+	deleted := 0
+	for id, task := range queue.tasks {
+		if task.CompletedAt != nil && task.CompletedAt.Before(before) {
+			delete(queue.tasks, id)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// all - Возвращает все задачи, включая dead letter, в порядке постановки.
+func (queue *InMemoryDeleteQueue) all() []*data.DeleteTaskData {
+	tasks := make([]*data.DeleteTaskData, 0, len(queue.tasks))
+	for _, task := range queue.tasks {
+		tasks = append(tasks, task)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return tasks
+}
+
+// load - Восстанавливает задачи, например, из файла очереди.
+func (queue *InMemoryDeleteQueue) load(tasks []*data.DeleteTaskData) {
+	for _, task := range tasks {
+		queue.tasks[task.ID] = task
+		queue.nextID = max(queue.nextID, task.ID)
+	}
+}
+
+// isPendingDeleteTask - Задача еще не выполнена и не исчерпала попытки.
+func isPendingDeleteTask(task *data.DeleteTaskData) bool {
+	return task.DeadAt == nil && task.CompletedAt == nil
+}
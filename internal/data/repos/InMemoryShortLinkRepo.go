@@ -2,71 +2,262 @@ package repos
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/VladSnap/shortener/internal/data"
 )
 
 // InMemoryShortLinkRepo - Репозиторий для доступа к хранилищу в оперативной памяти сокращателя ссылок.
+// Безопасен для конкурентного использования. Хранимые ссылки не изменяются на месте:
+// наружу отдаются копии, а изменения сохраняются новой версией ссылки.
 type InMemoryShortLinkRepo struct {
-	links map[string]*data.ShortLinkData
+	links     map[string]*data.ShortLinkData
+	byUser    map[string]map[string]struct{}
+	byOrigURL map[string]string
+	mu        sync.RWMutex
 }
 
 // NewShortLinkRepo - Создает новую структуру InMemoryShortLinkRepo с указателем.
 func NewShortLinkRepo() *InMemoryShortLinkRepo {
 	repo := new(InMemoryShortLinkRepo)
 	repo.links = make(map[string]*data.ShortLinkData)
+	repo.byUser = make(map[string]map[string]struct{})
+	repo.byOrigURL = make(map[string]string)
 	return repo
 }
 
 // Add - Сохраняет структуру сокращенной ссылки в памяти.
+// Если оригинальный URL уже сокращен неистекшей ссылкой, возвращает data.DuplicateShortLinkError с ней.
 func (repo *InMemoryShortLinkRepo) Add(ctx context.Context, link *data.ShortLinkData) (*data.ShortLinkData, error) {
-	repo.links[link.ShortURL] = link
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if shortURL, ok := activeShortURL(repo.links, repo.byOrigURL, link.OriginalURL, link.CreatedAt); ok {
+		return nil, data.NewDuplicateError(shortURL) //nolint:wrapcheck // is new error
+	}
+	if _, ok := repo.links[link.ShortURL]; ok {
+		return nil, data.NewShortURLConflictError(link.ShortURL) //nolint:wrapcheck // is new error
+	}
+	repo.store(link)
 	return link, nil
 }
 
 // AddBatch - Сохраняет пачку структур сокращенных ссылок в памяти.
+// Для уже сокращенных оригинальных URL в результате возвращается существующая ссылка.
+// Пачка сохраняется целиком или не сохраняется вовсе.
 func (repo *InMemoryShortLinkRepo) AddBatch(ctx context.Context, links []*data.ShortLinkData) (
 	[]*data.ShortLinkData, error) {
-	for _, link := range links {
-		repo.links[link.ShortURL] = link
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	result, toStore, err := planBatch(repo.links, repo.byOrigURL, links)
+	if err != nil {
+		return nil, err
 	}
-	return links, nil
+	for _, link := range toStore {
+		repo.store(link)
+	}
+	return result, nil
 }
 
 // Get - Читает полную ссылку по сокращенной ссылке.
 func (repo *InMemoryShortLinkRepo) Get(ctx context.Context, shortID string) (*data.ShortLinkData, error) {
-	return repo.links[shortID], nil
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	link, ok := repo.links[shortID]
+	if !ok {
+		return nil, nil //nolint:nilnil // link not found
+	}
+	linkCopy := *link
+	return &linkCopy, nil
 }
 
-// GetAllByUserID - Получить все сокращенные ссылки указанного пользователя.
-func (repo *InMemoryShortLinkRepo) GetAllByUserID(ctx context.Context, userID string) (
+// GetAllByUserID - Получить сокращенные ссылки указанного пользователя по параметрам выборки.
+func (repo *InMemoryShortLinkRepo) GetAllByUserID(ctx context.Context, userID string, query *data.UserLinksQuery) (
 	[]*data.ShortLinkData, error) {
-	return make([]*data.ShortLinkData, 0), nil
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	shortURLs := repo.byUser[userID]
+	links := make([]*data.ShortLinkData, 0, len(shortURLs))
+	for shortURL := range shortURLs {
+		linkCopy := *repo.links[shortURL]
+		links = append(links, &linkCopy)
+	}
+	return applyUserLinksQuery(links, query), nil
 }
 
-// DeleteBatch - Удаляет пачку структур сокращенных ссылок из файла.
+// DeleteBatch - Помечает удаленными ссылки из пачки, принадлежащие указанным пользователям.
+// Неизвестные идентификаторы пропускаются.
 func (repo *InMemoryShortLinkRepo) DeleteBatch(ctx context.Context, shortIDs []data.DeleteShortData) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	now := time.Now().UTC()
+	for _, sid := range shortIDs {
+		link, ok := repo.links[sid.ShortURL]
+		if !ok || link.UserID != sid.UserID || link.IsDeleted {
+			continue
+		}
+		deleted := *link
+		deleted.IsDeleted = true
+		deleted.DeletedAt = &now
+		repo.links[sid.ShortURL] = &deleted
+	}
+	return nil
+}
+
+// RestoreBatch - Снимает пометку удаления со ссылок из пачки, принадлежащих указанным пользователям
+// и удаленных не раньше deletedAfter. Остальные ссылки пропускаются.
+func (repo *InMemoryShortLinkRepo) RestoreBatch(ctx context.Context, shortIDs []data.DeleteShortData,
+	deletedAfter time.Time) error {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
 	for _, sid := range shortIDs {
-		link := repo.links[sid.ShortURL]
-		if link.UserID == sid.UserID {
-			link.IsDeleted = true
+		link, ok := repo.links[sid.ShortURL]
+		if !ok || link.UserID != sid.UserID || !link.IsRestorable(deletedAfter) {
+			continue
 		}
+		restored := *link
+		restored.IsDeleted = false
+		restored.DeletedAt = nil
+		repo.links[sid.ShortURL] = &restored
 	}
 	return nil
 }
 
+// UpdateOriginalURL - Меняет оригинальный URL неудаленной ссылки пользователя и сохраняет прежний URL в истории.
+// Если новый URL уже сокращен другой ссылкой, возвращает data.DuplicateShortLinkError с этой ссылкой.
+func (repo *InMemoryShortLinkRepo) UpdateOriginalURL(ctx context.Context, shortURL string, userID string,
+	origURL string, changedAt time.Time) (*data.ShortLinkData, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	link, ok := repo.links[shortURL]
+	if !ok || link.UserID != userID || link.IsDeleted {
+		return nil, nil //nolint:nilnil // link not found
+	}
+	if link.OriginalURL != origURL {
+		if existing, ok := activeShortURL(repo.links, repo.byOrigURL, origURL, changedAt); ok {
+			return nil, data.NewDuplicateError(existing) //nolint:wrapcheck // is new error
+		}
+		if repo.byOrigURL[link.OriginalURL] == shortURL {
+			delete(repo.byOrigURL, link.OriginalURL)
+		}
+		repo.store(link.WithOriginalURL(origURL, changedAt))
+	}
+	linkCopy := *repo.links[shortURL]
+	return &linkCopy, nil
+}
+
+// Purge - Физически удаляет ссылки, помеченные удаленными или истекшие раньше указанного момента.
+func (repo *InMemoryShortLinkRepo) Purge(ctx context.Context, before time.Time) (*data.PurgeData, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	result := &data.PurgeData{}
+	for _, link := range repo.links {
+		if link.IsPurgeable(before) {
+			result.Add(link)
+			repo.remove(link)
+		}
+	}
+	return result, nil
+}
+
 // GetStats - Получает статистику о пользователях и всех ссылках.
 func (repo *InMemoryShortLinkRepo) GetStats(ctx context.Context) (*data.StatsData, error) {
-	data := data.NewStatsData(len(repo.links), repo.calcAllUsers())
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	data := data.NewStatsData(len(repo.links), len(repo.byUser))
 	return data, nil
 }
 
-func (repo *InMemoryShortLinkRepo) calcAllUsers() int {
-	users := make(map[string]bool)
+// GetUserStats - Получает статистику по ссылкам указанного пользователя на указанный момент времени.
+func (repo *InMemoryShortLinkRepo) GetUserStats(ctx context.Context, userID string, now time.Time) (
+	*data.UserStatsData, error) {
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	stats := &data.UserStatsData{ShortURLs: make([]string, 0, len(repo.byUser[userID]))}
+	for shortURL := range repo.byUser[userID] {
+		stats.Add(repo.links[shortURL], now)
+	}
+	return stats, nil
+}
+
+// store - Сохраняет копию ссылки и обновляет индексы. Вызывается под блокировкой на запись.
+func (repo *InMemoryShortLinkRepo) store(link *data.ShortLinkData) {
+	linkCopy := *link
+	repo.links[link.ShortURL] = &linkCopy
+	repo.byOrigURL[link.OriginalURL] = link.ShortURL
+	userLinks, ok := repo.byUser[link.UserID]
+	if !ok {
+		userLinks = make(map[string]struct{})
+		repo.byUser[link.UserID] = userLinks
+	}
+	userLinks[link.ShortURL] = struct{}{}
+}
 
-	for _, l := range repo.links {
-		users[l.UserID] = true
+// remove - Удаляет ссылку и её записи в индексах. Вызывается под блокировкой на запись.
+func (repo *InMemoryShortLinkRepo) remove(link *data.ShortLinkData) {
+	delete(repo.links, link.ShortURL)
+	if repo.byOrigURL[link.OriginalURL] == link.ShortURL {
+		delete(repo.byOrigURL, link.OriginalURL)
+	}
+	userLinks := repo.byUser[link.UserID]
+	delete(userLinks, link.ShortURL)
+	if len(userLinks) == 0 {
+		delete(repo.byUser, link.UserID)
 	}
+}
+
+// activeShortURL - Возвращает сокращенную ссылку, которая занимает оригинальный URL на момент now.
+// Истекшая ссылка URL не занимает: его можно сократить заново, а сама ссылка удаляется при очистке.
+func activeShortURL(links map[string]*data.ShortLinkData, byOrigURL map[string]string, origURL string,
+	now time.Time) (string, bool) {
+	shortURL, ok := byOrigURL[origURL]
+	if !ok {
+		return "", false
+	}
+	if link, ok := links[shortURL]; !ok || link.IsExpired(now) {
+		return "", false
+	}
+	return shortURL, true
+}
 
-	return len(users)
+// planBatch - Разбирает пачку новых ссылок перед сохранением.
+// Возвращает результат в порядке пачки и ссылки, которые нужно сохранить.
+// Если оригинальный URL уже сокращен неистекшей ссылкой в хранилище или ранее в пачке, на месте ссылки
+// в результате возвращается существующая ссылка. Занятый идентификатор приводит к ошибке для всей пачки.
+func planBatch(stored map[string]*data.ShortLinkData, byOrigURL map[string]string, links []*data.ShortLinkData) (
+	[]*data.ShortLinkData, []*data.ShortLinkData, error) {
+	result := make([]*data.ShortLinkData, 0, len(links))
+	toStore := make([]*data.ShortLinkData, 0, len(links))
+	batchURLs := make(map[string]*data.ShortLinkData, len(links))
+	batchIDs := make(map[string]bool, len(links))
+	for _, link := range links {
+		if shortURL, ok := activeShortURL(stored, byOrigURL, link.OriginalURL, link.CreatedAt); ok {
+			existing := *stored[shortURL]
+			result = append(result, &existing)
+			continue
+		}
+		if planned, ok := batchURLs[link.OriginalURL]; ok {
+			result = append(result, planned)
+			continue
+		}
+		if _, ok := stored[link.ShortURL]; ok || batchIDs[link.ShortURL] {
+			return nil, nil, data.NewShortURLConflictError(link.ShortURL) //nolint:wrapcheck // is new error
+		}
+		batchURLs[link.OriginalURL] = link
+		batchIDs[link.ShortURL] = true
+		result = append(result, link)
+		toStore = append(toStore, link)
+	}
+	return result, toStore, nil
+}
+
+// calcUserStats - Считает статистику по ссылкам пользователя.
+func calcUserStats(links map[string]*data.ShortLinkData, userID string, now time.Time) *data.UserStatsData {
+	stats := &data.UserStatsData{ShortURLs: make([]string, 0)}
+	for _, link := range links {
+		if link.UserID == userID {
+			stats.Add(link, now)
+		}
+	}
+	return stats
 }
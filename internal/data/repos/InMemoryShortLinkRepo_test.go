@@ -0,0 +1,259 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLink(shortURL, origURL, userID string) *data.ShortLinkData {
+	return &data.ShortLinkData{
+		UUID:        "uuid-" + shortURL,
+		ShortURL:    shortURL,
+		OriginalURL: origURL,
+		UserID:      userID,
+		CreatedAt:   time.Now().UTC(),
+	}
+}
+
+func TestInMemoryShortLinkRepo_Add(t *testing.T) {
+	repo := NewShortLinkRepo()
+	_, err := repo.Add(t.Context(), newTestLink("link0001", "http://a.url", "user1"))
+	require.NoError(t, err)
+
+	t.Run("duplicate original url", func(t *testing.T) {
+		_, err := repo.Add(t.Context(), newTestLink("link0002", "http://a.url", "user2"))
+
+		var duplErr *data.DuplicateShortLinkError
+		require.ErrorAs(t, err, &duplErr)
+		assert.Equal(t, "link0001", duplErr.ShortURL)
+	})
+
+	t.Run("short url conflict", func(t *testing.T) {
+		_, err := repo.Add(t.Context(), newTestLink("link0001", "http://b.url", "user1"))
+
+		var conflictErr *data.ShortURLConflictError
+		require.ErrorAs(t, err, &conflictErr)
+	})
+
+	t.Run("duplicates in batch", func(t *testing.T) {
+		links, err := repo.AddBatch(t.Context(), []*data.ShortLinkData{
+			newTestLink("link0003", "http://c.url", "user1"),
+			newTestLink("link0004", "http://c.url", "user1"),
+			newTestLink("link0005", "http://a.url", "user1"),
+		})
+
+		require.NoError(t, err)
+		require.Len(t, links, 3)
+		assert.Equal(t, "link0003", links[0].ShortURL)
+		assert.Equal(t, "link0003", links[1].ShortURL)
+		assert.Equal(t, "link0001", links[2].ShortURL)
+		for _, shortURL := range []string{"link0004", "link0005"} {
+			link, err := repo.Get(t.Context(), shortURL)
+			require.NoError(t, err)
+			assert.Nil(t, link, "duplicate must not be saved")
+		}
+	})
+
+	t.Run("short url conflict in batch", func(t *testing.T) {
+		_, err := repo.AddBatch(t.Context(), []*data.ShortLinkData{
+			newTestLink("link0006", "http://d.url", "user1"),
+			newTestLink("link0001", "http://e.url", "user1"),
+		})
+
+		var conflictErr *data.ShortURLConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		link, err := repo.Get(t.Context(), "link0006")
+		require.NoError(t, err)
+		assert.Nil(t, link, "batch must not be saved partially")
+	})
+}
+
+// linkUpdater - Репозиторий ссылок, поддерживающий изменение оригинального URL.
+type linkUpdater interface {
+	AddBatch(ctx context.Context, links []*data.ShortLinkData) ([]*data.ShortLinkData, error)
+	Add(ctx context.Context, link *data.ShortLinkData) (*data.ShortLinkData, error)
+	UpdateOriginalURL(ctx context.Context, shortURL string, userID string, origURL string, changedAt time.Time) (
+		*data.ShortLinkData, error)
+}
+
+// assertUpdateOriginalURL - Проверяет изменение оригинального URL, одинаковое для всех репозиториев.
+func assertUpdateOriginalURL(t *testing.T, repo linkUpdater) {
+	t.Helper()
+	_, err := repo.AddBatch(t.Context(), []*data.ShortLinkData{
+		newTestLink("link0001", "http://a.url", "user1"),
+		newTestLink("link0002", "http://b.url", "user1"),
+	})
+	require.NoError(t, err)
+	changedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	link, err := repo.UpdateOriginalURL(t.Context(), "link0001", "user1", "http://c.url", changedAt)
+	require.NoError(t, err)
+	require.NotNil(t, link)
+	assert.Equal(t, "http://c.url", link.OriginalURL)
+	link, err = repo.UpdateOriginalURL(t.Context(), "link0001", "user1", "http://d.url", changedAt.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, link.History, 2)
+	assert.Equal(t, "http://a.url", link.History[0].OriginalURL)
+	assert.True(t, changedAt.Equal(link.History[0].ChangedAt))
+	assert.Equal(t, "http://c.url", link.History[1].OriginalURL)
+
+	_, err = repo.UpdateOriginalURL(t.Context(), "link0001", "user1", "http://b.url", changedAt)
+	var duplErr *data.DuplicateShortLinkError
+	require.ErrorAs(t, err, &duplErr, "URL shortened by another link")
+	assert.Equal(t, "link0002", duplErr.ShortURL)
+
+	link, err = repo.UpdateOriginalURL(t.Context(), "link0001", "user2", "http://e.url", changedAt)
+	require.NoError(t, err)
+	assert.Nil(t, link, "link of another user should not be updated")
+
+	// Прежний URL освобождается и его можно сократить заново.
+	created, err := repo.Add(t.Context(), newTestLink("link0003", "http://a.url", "user2"))
+	require.NoError(t, err)
+	assert.Equal(t, "link0003", created.ShortURL)
+	_, err = repo.Add(t.Context(), newTestLink("link0004", "http://d.url", "user2"))
+	require.ErrorAs(t, err, &duplErr, "new URL should be indexed")
+	assert.Equal(t, "link0001", duplErr.ShortURL)
+}
+
+func TestInMemoryShortLinkRepo_UpdateOriginalURL(t *testing.T) {
+	assertUpdateOriginalURL(t, NewShortLinkRepo())
+}
+
+// assertExpiredOrigURLIsFree - Проверяет, что истекшая ссылка не занимает оригинальный URL,
+// одинаково для всех репозиториев.
+func assertExpiredOrigURLIsFree(t *testing.T, repo linkUpdater) {
+	t.Helper()
+	expiresAt := time.Now().UTC().Add(-time.Minute)
+	expired := make([]*data.ShortLinkData, 0, 3)
+	for i, origURL := range []string{"http://a.url", "http://b.url", "http://c.url"} {
+		link := newTestLink(fmt.Sprintf("expired%d", i+1), origURL, "user1")
+		link.CreatedAt = expiresAt.Add(-time.Hour)
+		link.ExpiresAt = &expiresAt
+		expired = append(expired, link)
+	}
+	_, err := repo.AddBatch(t.Context(), expired)
+	require.NoError(t, err)
+
+	created, err := repo.Add(t.Context(), newTestLink("link0001", "http://a.url", "user2"))
+	require.NoError(t, err, "expired link should not block Add")
+	assert.Equal(t, "link0001", created.ShortURL)
+	links, err := repo.AddBatch(t.Context(), []*data.ShortLinkData{newTestLink("link0002", "http://b.url", "user2")})
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, "link0002", links[0].ShortURL, "expired link should not block AddBatch")
+	_, err = repo.Add(t.Context(), newTestLink("link0003", "http://d.url", "user2"))
+	require.NoError(t, err)
+	updated, err := repo.UpdateOriginalURL(t.Context(), "link0003", "user2", "http://c.url", time.Now().UTC())
+	require.NoError(t, err, "expired link should not block UpdateOriginalURL")
+	require.NotNil(t, updated)
+	assert.Equal(t, "http://c.url", updated.OriginalURL)
+
+	// URL занимают новые ссылки.
+	var duplErr *data.DuplicateShortLinkError
+	for origURL, shortURL := range map[string]string{
+		"http://a.url": "link0001", "http://b.url": "link0002", "http://c.url": "link0003",
+	} {
+		_, err = repo.Add(t.Context(), newTestLink("link0004", origURL, "user1"))
+		require.ErrorAs(t, err, &duplErr)
+		assert.Equal(t, shortURL, duplErr.ShortURL)
+	}
+}
+
+func TestInMemoryShortLinkRepo_ExpiredOrigURL(t *testing.T) {
+	repo := NewShortLinkRepo()
+	assertExpiredOrigURLIsFree(t, repo)
+
+	// Очистка истекших ссылок не освобождает URL, занятые новыми ссылками.
+	_, err := repo.Purge(t.Context(), time.Now())
+	require.NoError(t, err)
+	_, err = repo.Add(t.Context(), newTestLink("link0005", "http://a.url", "user1"))
+	var duplErr *data.DuplicateShortLinkError
+	require.ErrorAs(t, err, &duplErr)
+	assert.Equal(t, "link0001", duplErr.ShortURL)
+}
+
+func TestInMemoryShortLinkRepo_GetAllByUserID(t *testing.T) {
+	repo := NewShortLinkRepo()
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		link := newTestLink(fmt.Sprintf("link%04d", i), fmt.Sprintf("http://%d.url", i), "user1")
+		link.CreatedAt = createdAt.Add(time.Duration(i) * time.Second)
+		_, err := repo.Add(t.Context(), link)
+		require.NoError(t, err)
+	}
+	_, err := repo.Add(t.Context(), newTestLink("foreign1", "http://foreign.url", "user2"))
+	require.NoError(t, err)
+	require.NoError(t, repo.DeleteBatch(t.Context(), []data.DeleteShortData{
+		{ShortURL: "link0001", UserID: "user1"},
+		{ShortURL: "unknown1", UserID: "user1"},
+		{ShortURL: "foreign1", UserID: "user1"},
+	}))
+
+	links, err := repo.GetAllByUserID(t.Context(), "user1", nil)
+	require.NoError(t, err)
+	require.Len(t, links, 3)
+	assert.Equal(t, "link0000", links[0].ShortURL)
+
+	notDeleted := false
+	links, err = repo.GetAllByUserID(t.Context(), "user1", &data.UserLinksQuery{
+		Limit:   1,
+		Desc:    true,
+		Deleted: &notDeleted,
+	})
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, "link0002", links[0].ShortURL)
+
+	foreign, err := repo.Get(t.Context(), "foreign1")
+	require.NoError(t, err)
+	assert.False(t, foreign.IsDeleted, "link of another user must not be deleted")
+}
+
+func TestInMemoryShortLinkRepo_ConcurrentAccess(t *testing.T) {
+	repo := NewShortLinkRepo()
+	const workers = 8
+	const linksPerWorker = 50
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			userID := fmt.Sprintf("user%d", w)
+			for i := range linksPerWorker {
+				shortURL := fmt.Sprintf("l%d-%d", w, i)
+				_, err := repo.Add(t.Context(), newTestLink(shortURL, "http://"+shortURL, userID))
+				assert.NoError(t, err)
+				_, err = repo.Get(t.Context(), shortURL)
+				assert.NoError(t, err)
+				_, err = repo.GetAllByUserID(t.Context(), userID, nil)
+				assert.NoError(t, err)
+				err = repo.DeleteBatch(t.Context(), []data.DeleteShortData{{ShortURL: shortURL, UserID: userID}})
+				assert.NoError(t, err)
+				_, err = repo.GetStats(t.Context())
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats, err := repo.GetStats(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, workers*linksPerWorker, stats.Urls)
+	assert.Equal(t, workers, stats.Users)
+
+	purged, err := repo.Purge(t.Context(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, workers*linksPerWorker, purged.Deleted)
+	stats, err = repo.GetStats(t.Context())
+	require.NoError(t, err)
+	assert.Zero(t, stats.Urls)
+	assert.Zero(t, stats.Users)
+}
@@ -0,0 +1,563 @@
+package repos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKeyPrefix - Префикс всех ключей сокращателя ссылок в Redis.
+const redisKeyPrefix = "shortener:"
+
+// Ключи Redis относительно префикса. Хеш orig_urls (orig_url -> short_url) используется только в скриптах.
+const (
+	// redisLinkKey - Хеш ссылки, к ключу добавляется short_url.
+	redisLinkKey = "link:"
+	// redisUserKey - Множество short_url ссылок пользователя, к ключу добавляется user_id.
+	redisUserKey = "user:"
+	// redisUsersKey - Множество пользователей, у которых есть ссылки.
+	redisUsersKey = "users"
+	// redisLinksKey - Множество short_url всех ссылок.
+	redisLinksKey = "links"
+	// redisShortIDSeqKey - Счетчик последовательности идентификаторов сокращенных ссылок.
+	redisShortIDSeqKey = "short_id_seq"
+)
+
+// addLinksScript - Атомарно сохраняет пачку ссылок с проверкой дублей, как planBatch.
+// ARGV[1] - префикс ключей, далее по 10 значений на ссылку в порядке redisLinkArgs.
+// Истекшая ссылка не занимает оригинальный URL: индекс переходит к новой ссылке, а истекшая удаляется при очистке.
+// Возвращает {"ok", short_url...} в порядке пачки или {"conflict", short_url} без сохранения пачки.
+var addLinksScript = redis.NewScript(`
+local prefix = ARGV[1]
+local planned, ids, toStore = {}, {}, {}
+local result = {'ok'}
+for i = 2, #ARGV, 10 do
+  local short, orig = ARGV[i], ARGV[i + 1]
+  local existing = redis.call('HGET', prefix .. 'orig_urls', orig)
+  if existing then
+    local expiresAt = tonumber(redis.call('HGET', prefix .. 'link:' .. existing, 'expires_at_ms'))
+    if expiresAt and expiresAt <= tonumber(ARGV[i + 9]) then
+      existing = nil
+    end
+  end
+  if existing then
+    table.insert(result, existing)
+  elseif planned[orig] then
+    table.insert(result, planned[orig])
+  else
+    if ids[short] or redis.call('EXISTS', prefix .. 'link:' .. short) == 1 then
+      return {'conflict', short}
+    end
+    planned[orig] = short
+    ids[short] = true
+    table.insert(result, short)
+    table.insert(toStore, i)
+  end
+end
+for _, i in ipairs(toStore) do
+  local short, orig, user = ARGV[i], ARGV[i + 1], ARGV[i + 2]
+  redis.call('HSET', prefix .. 'link:' .. short, 'short_url', short, 'orig_url', orig, 'user_id', user,
+    'uuid', ARGV[i + 3], 'is_deleted', ARGV[i + 4], 'created_at', ARGV[i + 5],
+    'expires_at', ARGV[i + 6], 'deleted_at', ARGV[i + 7], 'expires_at_ms', ARGV[i + 8])
+  redis.call('HSET', prefix .. 'orig_urls', orig, short)
+  redis.call('SADD', prefix .. 'user:' .. user, short)
+  redis.call('SADD', prefix .. 'users', user)
+  redis.call('SADD', prefix .. 'links', short)
+end
+return result
+`)
+
+// deleteLinksScript - Помечает удаленными ссылки, принадлежащие указанным пользователям.
+// ARGV[1] - префикс ключей, ARGV[2] - время удаления, далее пары short_url, user_id.
+var deleteLinksScript = redis.NewScript(`
+local prefix = ARGV[1]
+for i = 3, #ARGV, 2 do
+  local key = prefix .. 'link:' .. ARGV[i]
+  local fields = redis.call('HMGET', key, 'user_id', 'is_deleted')
+  if fields[1] == ARGV[i + 1] and fields[2] == '0' then
+    redis.call('HSET', key, 'is_deleted', '1', 'deleted_at', ARGV[2])
+  end
+end
+return 0
+`)
+
+// restoreLinksScript - Снимает пометку удаления со ссылок, принадлежащих указанным пользователям,
+// если они не изменились после чтения. ARGV[1] - префикс ключей, далее тройки short_url, user_id, deleted_at.
+var restoreLinksScript = redis.NewScript(`
+local prefix = ARGV[1]
+for i = 2, #ARGV, 3 do
+  local key = prefix .. 'link:' .. ARGV[i]
+  local fields = redis.call('HMGET', key, 'user_id', 'is_deleted', 'deleted_at')
+  if fields[1] == ARGV[i + 1] and fields[2] == '1' and fields[3] == ARGV[i + 2] then
+    redis.call('HSET', key, 'is_deleted', '0', 'deleted_at', '')
+  end
+end
+return 0
+`)
+
+// updateLinkScript - Меняет оригинальный URL ссылки, если она не изменилась после чтения, и обновляет индекс
+// оригинальных URL. ARGV[1] - префикс ключей, ARGV[2] - short_url, ARGV[3] - прежний orig_url,
+// ARGV[4] - новый orig_url, ARGV[5] - история в JSON, ARGV[6] - время изменения в миллисекундах.
+// Возвращает {"ok"}, {"duplicate", short_url} для URL, сокращенного неистекшей ссылкой,
+// или {"changed"}, если ссылка изменилась.
+var updateLinkScript = redis.NewScript(`
+local prefix = ARGV[1]
+local short, oldOrig, newOrig = ARGV[2], ARGV[3], ARGV[4]
+local key = prefix .. 'link:' .. short
+local fields = redis.call('HMGET', key, 'orig_url', 'is_deleted')
+if fields[1] ~= oldOrig or fields[2] ~= '0' then
+  return {'changed'}
+end
+local existing = redis.call('HGET', prefix .. 'orig_urls', newOrig)
+if existing then
+  local expiresAt = tonumber(redis.call('HGET', prefix .. 'link:' .. existing, 'expires_at_ms'))
+  if not expiresAt or expiresAt > tonumber(ARGV[6]) then
+    return {'duplicate', existing}
+  end
+end
+redis.call('HSET', key, 'orig_url', newOrig, 'history', ARGV[5])
+if redis.call('HGET', prefix .. 'orig_urls', oldOrig) == short then
+  redis.call('HDEL', prefix .. 'orig_urls', oldOrig)
+end
+redis.call('HSET', prefix .. 'orig_urls', newOrig, short)
+return {'ok'}
+`)
+
+// removeLinksScript - Физически удаляет ссылки и их записи в индексах.
+// ARGV[1] - префикс ключей, далее short_url удаляемых ссылок.
+var removeLinksScript = redis.NewScript(`
+local prefix = ARGV[1]
+for i = 2, #ARGV do
+  local short = ARGV[i]
+  local key = prefix .. 'link:' .. short
+  local fields = redis.call('HMGET', key, 'orig_url', 'user_id')
+  if fields[1] then
+    redis.call('DEL', key)
+    if redis.call('HGET', prefix .. 'orig_urls', fields[1]) == short then
+      redis.call('HDEL', prefix .. 'orig_urls', fields[1])
+    end
+    local userKey = prefix .. 'user:' .. fields[2]
+    redis.call('SREM', userKey, short)
+    if redis.call('SCARD', userKey) == 0 then
+      redis.call('SREM', prefix .. 'users', fields[2])
+    end
+    redis.call('SREM', prefix .. 'links', short)
+  end
+end
+return 0
+`)
+
+// RedisShortLinkRepo - Репозиторий для доступа к хранилищу сокращателя ссылок в Redis.
+// Ссылка хранится хешем, индексы пользователей и оригинальных URL - множествами и хешем.
+// Изменения выполняются Lua-скриптами атомарно, поэтому хранилище можно разделять между репликами.
+// Скрипты обращаются к ключам, не переданным в KEYS, поэтому поддерживается только одиночный Redis.
+type RedisShortLinkRepo struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisShortLinkRepo - Создает новую структуру RedisShortLinkRepo с указателем.
+func NewRedisShortLinkRepo(client redis.UniversalClient) *RedisShortLinkRepo {
+	repo := new(RedisShortLinkRepo)
+	repo.client = client
+	repo.prefix = redisKeyPrefix
+	return repo
+}
+
+// Add - Сохраняет структуру сокращенной ссылки в Redis.
+// Если оригинальный URL уже сокращен, возвращает data.DuplicateShortLinkError с существующей ссылкой.
+func (repo *RedisShortLinkRepo) Add(ctx context.Context, link *data.ShortLinkData) (*data.ShortLinkData, error) {
+	shortURLs, err := repo.addLinks(ctx, []*data.ShortLinkData{link})
+	if err != nil {
+		return nil, err
+	}
+	if shortURLs[0] != link.ShortURL {
+		return nil, data.NewDuplicateError(shortURLs[0]) //nolint:wrapcheck // is new error
+	}
+	return link, nil
+}
+
+// AddBatch - Сохраняет пачку структур сокращенных ссылок в Redis.
+// Для уже сокращенных оригинальных URL в результате возвращается существующая ссылка.
+// Пачка сохраняется целиком или не сохраняется вовсе.
+func (repo *RedisShortLinkRepo) AddBatch(ctx context.Context, links []*data.ShortLinkData) (
+	[]*data.ShortLinkData, error) {
+	shortURLs, err := repo.addLinks(ctx, links)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*data.ShortLinkData, len(links))
+	stored := make(map[string]*data.ShortLinkData, len(links))
+	existingURLs := make([]string, 0)
+	for i, shortURL := range shortURLs {
+		switch {
+		case shortURL == links[i].ShortURL:
+			result[i] = links[i]
+			stored[shortURL] = links[i]
+		case stored[shortURL] != nil:
+			result[i] = stored[shortURL]
+		default:
+			existingURLs = append(existingURLs, shortURL)
+		}
+	}
+	if len(existingURLs) == 0 {
+		return result, nil
+	}
+
+	existing, err := repo.loadLinks(ctx, existingURLs)
+	if err != nil {
+		return nil, err
+	}
+	byShortURL := make(map[string]*data.ShortLinkData, len(existing))
+	for _, link := range existing {
+		byShortURL[link.ShortURL] = link
+	}
+	for i, shortURL := range shortURLs {
+		if result[i] != nil {
+			continue
+		}
+		link, ok := byShortURL[shortURL]
+		if !ok {
+			return nil, fmt.Errorf("duplicate link %s is not found in redis", shortURL)
+		}
+		result[i] = link
+	}
+	return result, nil
+}
+
+// Get - Читает полную ссылку по сокращенной ссылке.
+func (repo *RedisShortLinkRepo) Get(ctx context.Context, shortID string) (*data.ShortLinkData, error) {
+	fields, err := repo.client.HGetAll(ctx, repo.prefix+redisLinkKey+shortID).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed get link from redis: %w", err)
+	}
+	if len(fields) == 0 {
+		return nil, nil //nolint:nilnil // link not found
+	}
+	return parseRedisLink(fields)
+}
+
+// GetAllByUserID - Получить сокращенные ссылки указанного пользователя по параметрам выборки.
+func (repo *RedisShortLinkRepo) GetAllByUserID(ctx context.Context, userID string, query *data.UserLinksQuery) (
+	[]*data.ShortLinkData, error) {
+	links, err := repo.loadUserLinks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return applyUserLinksQuery(links, query), nil
+}
+
+// DeleteBatch - Помечает удаленными ссылки из пачки, принадлежащие указанным пользователям.
+// Неизвестные идентификаторы пропускаются.
+func (repo *RedisShortLinkRepo) DeleteBatch(ctx context.Context, shortIDs []data.DeleteShortData) error {
+	if len(shortIDs) == 0 {
+		return nil
+	}
+	args := make([]any, 0, 2+2*len(shortIDs))
+	args = append(args, repo.prefix, formatRedisTime(time.Now().UTC()))
+	for _, sid := range shortIDs {
+		args = append(args, sid.ShortURL, sid.UserID)
+	}
+	if err := deleteLinksScript.Run(ctx, repo.client, nil, args...).Err(); err != nil {
+		return fmt.Errorf("failed delete links in redis: %w", err)
+	}
+	return nil
+}
+
+// RestoreBatch - Снимает пометку удаления со ссылок из пачки, принадлежащих указанным пользователям
+// и удаленных не раньше deletedAfter. Время удаления в Redis хранится строкой, поэтому окно восстановления
+// проверяется после чтения ссылок, а скрипт восстанавливает только ссылки, не удаленные повторно за это время.
+func (repo *RedisShortLinkRepo) RestoreBatch(ctx context.Context, shortIDs []data.DeleteShortData,
+	deletedAfter time.Time) error {
+	shortURLs := make([]string, 0, len(shortIDs))
+	for _, sid := range shortIDs {
+		shortURLs = append(shortURLs, sid.ShortURL)
+	}
+	links, err := repo.loadLinks(ctx, shortURLs)
+	if err != nil {
+		return err
+	}
+	owners := make(map[string]string, len(shortIDs))
+	for _, sid := range shortIDs {
+		owners[sid.ShortURL] = sid.UserID
+	}
+
+	args := []any{repo.prefix}
+	for _, link := range links {
+		if owners[link.ShortURL] != link.UserID || !link.IsRestorable(deletedAfter) {
+			continue
+		}
+		args = append(args, link.ShortURL, link.UserID, formatRedisTimePtr(link.DeletedAt))
+	}
+	if len(args) == 1 {
+		return nil
+	}
+	if err := restoreLinksScript.Run(ctx, repo.client, nil, args...).Err(); err != nil {
+		return fmt.Errorf("failed restore links in redis: %w", err)
+	}
+	return nil
+}
+
+// UpdateOriginalURL - Меняет оригинальный URL неудаленной ссылки пользователя и сохраняет прежний URL в истории.
+// Если новый URL уже сокращен другой ссылкой, возвращает data.DuplicateShortLinkError с этой ссылкой.
+// Если ссылка изменилась между чтением и записью, возвращает ошибку, изменение можно повторить.
+func (repo *RedisShortLinkRepo) UpdateOriginalURL(ctx context.Context, shortURL string, userID string,
+	origURL string, changedAt time.Time) (*data.ShortLinkData, error) {
+	link, err := repo.Get(ctx, shortURL)
+	if err != nil {
+		return nil, err
+	}
+	if link == nil || link.UserID != userID || link.IsDeleted {
+		return nil, nil //nolint:nilnil // link not found
+	}
+	if link.OriginalURL == origURL {
+		return link, nil
+	}
+
+	updated := link.WithOriginalURL(origURL, changedAt)
+	history, err := json.Marshal(updated.History)
+	if err != nil {
+		return nil, fmt.Errorf("failed serialize history of link %s: %w", shortURL, err)
+	}
+	result, err := updateLinkScript.Run(ctx, repo.client, nil,
+		repo.prefix, shortURL, link.OriginalURL, origURL, string(history), changedAt.UnixMilli()).StringSlice()
+	if err != nil {
+		return nil, fmt.Errorf("failed update link in redis: %w", err)
+	}
+	switch result[0] {
+	case "duplicate":
+		return nil, data.NewDuplicateError(result[1]) //nolint:wrapcheck // is new error
+	case "changed":
+		return nil, fmt.Errorf("link %s was changed concurrently", shortURL)
+	}
+	return updated, nil
+}
+
+// GetStats - Получает статистику о пользователях и всех ссылках.
+func (repo *RedisShortLinkRepo) GetStats(ctx context.Context) (*data.StatsData, error) {
+	pipe := repo.client.Pipeline()
+	urls := pipe.SCard(ctx, repo.prefix+redisLinksKey)
+	users := pipe.SCard(ctx, repo.prefix+redisUsersKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed get stats from redis: %w", err)
+	}
+	return data.NewStatsData(int(urls.Val()), int(users.Val())), nil
+}
+
+// Purge - Физически удаляет ссылки, помеченные удаленными или истекшие раньше указанного момента.
+func (repo *RedisShortLinkRepo) Purge(ctx context.Context, before time.Time) (*data.PurgeData, error) {
+	shortURLs, err := repo.client.SMembers(ctx, repo.prefix+redisLinksKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed get links from redis: %w", err)
+	}
+	links, err := repo.loadLinks(ctx, shortURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &data.PurgeData{}
+	args := []any{repo.prefix}
+	for _, link := range links {
+		if link.IsPurgeable(before) {
+			result.Add(link)
+			args = append(args, link.ShortURL)
+		}
+	}
+	if result.Total() == 0 {
+		return result, nil
+	}
+	if err := removeLinksScript.Run(ctx, repo.client, nil, args...).Err(); err != nil {
+		return nil, fmt.Errorf("failed purge links in redis: %w", err)
+	}
+	return result, nil
+}
+
+// GetUserStats - Получает статистику по ссылкам указанного пользователя на указанный момент времени.
+func (repo *RedisShortLinkRepo) GetUserStats(ctx context.Context, userID string, now time.Time) (
+	*data.UserStatsData, error) {
+	links, err := repo.loadUserLinks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	stats := &data.UserStatsData{ShortURLs: make([]string, 0, len(links))}
+	for _, link := range links {
+		stats.Add(link, now)
+	}
+	return stats, nil
+}
+
+// CheckHealth - Проверяет доступность Redis.
+func (repo *RedisShortLinkRepo) CheckHealth(ctx context.Context) error {
+	if err := repo.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed ping redis: %w", err)
+	}
+	return nil
+}
+
+// addLinks - Сохраняет ссылки скриптом addLinksScript и возвращает short_url результата в порядке пачки.
+func (repo *RedisShortLinkRepo) addLinks(ctx context.Context, links []*data.ShortLinkData) ([]string, error) {
+	if len(links) == 0 {
+		return []string{}, nil
+	}
+	args := make([]any, 0, 1+redisLinkArgsCount*len(links))
+	args = append(args, repo.prefix)
+	for _, link := range links {
+		args = append(args, redisLinkArgs(link)...)
+	}
+	reply, err := addLinksScript.Run(ctx, repo.client, nil, args...).StringSlice()
+	if err != nil {
+		return nil, fmt.Errorf("failed add links to redis: %w", err)
+	}
+	if len(reply) == 0 {
+		return nil, errors.New("failed add links to redis: empty script reply")
+	}
+	if reply[0] == "conflict" {
+		return nil, data.NewShortURLConflictError(reply[1]) //nolint:wrapcheck // is new error
+	}
+	return reply[1:], nil
+}
+
+// loadUserLinks - Читает все ссылки пользователя.
+func (repo *RedisShortLinkRepo) loadUserLinks(ctx context.Context, userID string) ([]*data.ShortLinkData, error) {
+	shortURLs, err := repo.client.SMembers(ctx, repo.prefix+redisUserKey+userID).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed get user links from redis: %w", err)
+	}
+	return repo.loadLinks(ctx, shortURLs)
+}
+
+// loadLinks - Читает ссылки одним запросом, пропуская уже удаленные.
+func (repo *RedisShortLinkRepo) loadLinks(ctx context.Context, shortURLs []string) ([]*data.ShortLinkData, error) {
+	if len(shortURLs) == 0 {
+		return []*data.ShortLinkData{}, nil
+	}
+	pipe := repo.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, 0, len(shortURLs))
+	for _, shortURL := range shortURLs {
+		cmds = append(cmds, pipe.HGetAll(ctx, repo.prefix+redisLinkKey+shortURL))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed get links from redis: %w", err)
+	}
+
+	links := make([]*data.ShortLinkData, 0, len(cmds))
+	for _, cmd := range cmds {
+		if len(cmd.Val()) == 0 {
+			continue
+		}
+		link, err := parseRedisLink(cmd.Val())
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+// redisLinkArgsCount - Количество аргументов скрипта addLinksScript на одну ссылку.
+const redisLinkArgsCount = 10
+
+// redisLinkArgs - Возвращает аргументы ссылки для скрипта addLinksScript.
+// Время окончания действия дублируется в миллисекундах, чтобы скрипт мог сравнить его с временем создания.
+func redisLinkArgs(link *data.ShortLinkData) []any {
+	return []any{
+		link.ShortURL,
+		link.OriginalURL,
+		link.UserID,
+		link.UUID,
+		formatRedisBool(link.IsDeleted),
+		formatRedisTime(link.CreatedAt),
+		formatRedisTimePtr(link.ExpiresAt),
+		formatRedisTimePtr(link.DeletedAt),
+		formatRedisMillisPtr(link.ExpiresAt),
+		link.CreatedAt.UnixMilli(),
+	}
+}
+
+// parseRedisLink - Читает ссылку из полей хеша Redis.
+func parseRedisLink(fields map[string]string) (*data.ShortLinkData, error) {
+	link := data.NewShortLinkData(fields["uuid"], fields["short_url"], fields["orig_url"], fields["user_id"])
+	link.IsDeleted = fields["is_deleted"] == "1"
+	var err error
+	if link.CreatedAt, err = time.Parse(time.RFC3339Nano, fields["created_at"]); err != nil {
+		return nil, fmt.Errorf("failed parse created_at of link %s: %w", link.ShortURL, err)
+	}
+	if link.ExpiresAt, err = parseRedisTimePtr(fields["expires_at"]); err != nil {
+		return nil, fmt.Errorf("failed parse expires_at of link %s: %w", link.ShortURL, err)
+	}
+	if link.DeletedAt, err = parseRedisTimePtr(fields["deleted_at"]); err != nil {
+		return nil, fmt.Errorf("failed parse deleted_at of link %s: %w", link.ShortURL, err)
+	}
+	if fields["history"] != "" {
+		if err = json.Unmarshal([]byte(fields["history"]), &link.History); err != nil {
+			return nil, fmt.Errorf("failed parse history of link %s: %w", link.ShortURL, err)
+		}
+	}
+	return link, nil
+}
+
+func formatRedisBool(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+func formatRedisTime(value time.Time) string {
+	return value.Format(time.RFC3339Nano)
+}
+
+func formatRedisTimePtr(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return formatRedisTime(*value)
+}
+
+func formatRedisMillisPtr(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatInt(value.UnixMilli(), 10)
+}
+
+func parseRedisTimePtr(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil //nolint:nilnil // time is not set
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // wrapped by caller
+	}
+	return &parsed, nil
+}
+
+// RedisShortIDSequence - Последовательность для генерации идентификаторов сокращенных ссылок в Redis.
+type RedisShortIDSequence struct {
+	client redis.UniversalClient
+	key    string
+}
+
+// NewRedisShortIDSequence - Создает новую структуру RedisShortIDSequence с указателем.
+func NewRedisShortIDSequence(client redis.UniversalClient) *RedisShortIDSequence {
+	sequence := new(RedisShortIDSequence)
+	sequence.client = client
+	sequence.key = redisKeyPrefix + redisShortIDSeqKey
+	return sequence
+}
+
+// Next - Возвращает следующее значение счетчика в Redis.
+func (sequence *RedisShortIDSequence) Next(ctx context.Context) (uint64, error) {
+	value, err := sequence.client.Incr(ctx, sequence.key).Uint64()
+	if err != nil {
+		return 0, fmt.Errorf("failed increment short id sequence in redis: %w", err)
+	}
+	return value, nil
+}
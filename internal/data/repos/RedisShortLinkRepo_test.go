@@ -0,0 +1,218 @@
+package repos
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestRedisRepo(t *testing.T) *RedisShortLinkRepo {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { require.NoError(t, client.Close()) })
+	return NewRedisShortLinkRepo(client)
+}
+
+func TestRedisShortLinkRepo_Add(t *testing.T) {
+	repo := openTestRedisRepo(t)
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	link := newTestLink("link0001", "http://a.url", "user1")
+	link.ExpiresAt = &expiresAt
+	_, err := repo.Add(t.Context(), link)
+	require.NoError(t, err)
+
+	t.Run("read saved link", func(t *testing.T) {
+		saved, err := repo.Get(t.Context(), "link0001")
+
+		require.NoError(t, err)
+		assert.Equal(t, link, saved)
+	})
+
+	t.Run("unknown link", func(t *testing.T) {
+		saved, err := repo.Get(t.Context(), "unknown1")
+
+		require.NoError(t, err)
+		assert.Nil(t, saved)
+	})
+
+	t.Run("duplicate original url", func(t *testing.T) {
+		_, err := repo.Add(t.Context(), newTestLink("link0002", "http://a.url", "user2"))
+
+		var duplErr *data.DuplicateShortLinkError
+		require.ErrorAs(t, err, &duplErr)
+		assert.Equal(t, "link0001", duplErr.ShortURL)
+	})
+
+	t.Run("short url conflict", func(t *testing.T) {
+		_, err := repo.Add(t.Context(), newTestLink("link0001", "http://b.url", "user1"))
+
+		var conflictErr *data.ShortURLConflictError
+		require.ErrorAs(t, err, &conflictErr)
+	})
+
+	t.Run("duplicates in batch", func(t *testing.T) {
+		links, err := repo.AddBatch(t.Context(), []*data.ShortLinkData{
+			newTestLink("link0003", "http://c.url", "user1"),
+			newTestLink("link0004", "http://c.url", "user1"),
+			newTestLink("link0005", "http://a.url", "user1"),
+		})
+
+		require.NoError(t, err)
+		require.Len(t, links, 3)
+		assert.Equal(t, "link0003", links[0].ShortURL)
+		assert.Equal(t, "link0003", links[1].ShortURL)
+		assert.Equal(t, link, links[2])
+		saved, err := repo.Get(t.Context(), "link0004")
+		require.NoError(t, err)
+		assert.Nil(t, saved, "duplicate must not be saved")
+	})
+
+	t.Run("short url conflict in batch", func(t *testing.T) {
+		_, err := repo.AddBatch(t.Context(), []*data.ShortLinkData{
+			newTestLink("link0006", "http://d.url", "user1"),
+			newTestLink("link0001", "http://e.url", "user1"),
+		})
+
+		var conflictErr *data.ShortURLConflictError
+		require.ErrorAs(t, err, &conflictErr)
+		saved, err := repo.Get(t.Context(), "link0006")
+		require.NoError(t, err)
+		assert.Nil(t, saved, "batch must not be saved partially")
+	})
+}
+
+func TestRedisShortLinkRepo_UserLinks(t *testing.T) {
+	repo := openTestRedisRepo(t)
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		link := newTestLink(fmt.Sprintf("link%04d", i), fmt.Sprintf("http://%d.url", i), "user1")
+		link.CreatedAt = createdAt.Add(time.Duration(i) * time.Second)
+		_, err := repo.Add(t.Context(), link)
+		require.NoError(t, err)
+	}
+	_, err := repo.Add(t.Context(), newTestLink("foreign1", "http://foreign.url", "user2"))
+	require.NoError(t, err)
+	require.NoError(t, repo.DeleteBatch(t.Context(), []data.DeleteShortData{
+		{ShortURL: "link0001", UserID: "user1"},
+		{ShortURL: "unknown1", UserID: "user1"},
+		{ShortURL: "foreign1", UserID: "user1"},
+	}))
+
+	links, err := repo.GetAllByUserID(t.Context(), "user1", &data.UserLinksQuery{Desc: true, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+	assert.Equal(t, "link0002", links[0].ShortURL)
+	assert.Equal(t, "link0001", links[1].ShortURL)
+	assert.True(t, links[1].IsDeleted)
+	assert.NotNil(t, links[1].DeletedAt)
+
+	foreign, err := repo.Get(t.Context(), "foreign1")
+	require.NoError(t, err)
+	assert.False(t, foreign.IsDeleted, "link of another user must not be deleted")
+
+	userStats, err := repo.GetUserStats(t.Context(), "user1", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 2, userStats.Active)
+	assert.Equal(t, 1, userStats.Deleted)
+
+	stats, err := repo.GetStats(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, data.NewStatsData(4, 2), stats)
+}
+
+func TestRedisShortLinkRepo_Purge(t *testing.T) {
+	repo := openTestRedisRepo(t)
+	_, err := repo.AddBatch(t.Context(), []*data.ShortLinkData{
+		newTestLink("link0001", "http://a.url", "user1"),
+		newTestLink("link0002", "http://b.url", "user2"),
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.DeleteBatch(t.Context(), []data.DeleteShortData{{ShortURL: "link0002", UserID: "user2"}}))
+
+	result, err := repo.Purge(t.Context(), time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Total())
+
+	stats, err := repo.GetStats(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, data.NewStatsData(1, 1), stats)
+	// Оригинальный URL удаленной ссылки можно сократить заново.
+	_, err = repo.Add(t.Context(), newTestLink("link0003", "http://b.url", "user2"))
+	require.NoError(t, err)
+}
+
+func TestRedisShortLinkRepo_RestoreBatch(t *testing.T) {
+	repo := openTestRedisRepo(t)
+	_, err := repo.AddBatch(t.Context(), []*data.ShortLinkData{
+		newTestLink("link0001", "http://a.url", "user1"),
+		newTestLink("link0002", "http://b.url", "user1"),
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.DeleteBatch(t.Context(), []data.DeleteShortData{
+		{ShortURL: "link0001", UserID: "user1"},
+		{ShortURL: "link0002", UserID: "user1"},
+	}))
+
+	require.NoError(t, repo.RestoreBatch(t.Context(), []data.DeleteShortData{
+		{ShortURL: "link0001", UserID: "user2"},
+		{ShortURL: "link0002", UserID: "user1"},
+		{ShortURL: "unknown1", UserID: "user1"},
+	}, time.Now().Add(-time.Hour)))
+
+	link, err := repo.Get(t.Context(), "link0001")
+	require.NoError(t, err)
+	assert.True(t, link.IsDeleted, "link of another user should not be restored")
+	link, err = repo.Get(t.Context(), "link0002")
+	require.NoError(t, err)
+	assert.False(t, link.IsDeleted)
+	assert.Nil(t, link.DeletedAt)
+
+	// Ссылка, удаленная раньше окна восстановления, не восстанавливается.
+	require.NoError(t, repo.RestoreBatch(t.Context(), []data.DeleteShortData{
+		{ShortURL: "link0001", UserID: "user1"},
+	}, time.Now().Add(time.Hour)))
+	link, err = repo.Get(t.Context(), "link0001")
+	require.NoError(t, err)
+	assert.True(t, link.IsDeleted)
+}
+
+func TestRedisShortLinkRepo_UpdateOriginalURL(t *testing.T) {
+	repo := openTestRedisRepo(t)
+	assertUpdateOriginalURL(t, repo)
+
+	link, err := repo.Get(t.Context(), "link0001")
+	require.NoError(t, err)
+	assert.Equal(t, "http://d.url", link.OriginalURL)
+	assert.Len(t, link.History, 2, "history should be stored with the link")
+}
+
+func TestRedisShortLinkRepo_ExpiredOrigURL(t *testing.T) {
+	repo := openTestRedisRepo(t)
+	assertExpiredOrigURLIsFree(t, repo)
+
+	link, err := repo.Get(t.Context(), "expired1")
+	require.NoError(t, err)
+	assert.NotNil(t, link, "expired link should stay until purge")
+}
+
+func TestRedisShortIDSequence_Next(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer func() { require.NoError(t, client.Close()) }()
+	sequence := NewRedisShortIDSequence(client)
+
+	first, err := sequence.Next(t.Context())
+	require.NoError(t, err)
+	second, err := sequence.Next(t.Context())
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(1), first)
+	assert.Equal(t, uint64(2), second)
+}
@@ -0,0 +1,35 @@
+package repos
+
+import (
+	"context"
+	"errors"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/VladSnap/shortener/internal/tracing"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// shortLinksTable - Имя таблицы сокращенных ссылок для атрибутов span.
+const shortLinksTable = "public.short_links"
+
+// startDBSpan - Начинает клиентский span запроса к PostgreSQL.
+func startDBSpan(ctx context.Context, operation, query string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, operation+" "+shortLinksTable, //nolint:spancheck // ended by endDBSpan
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperationName(operation),
+			semconv.DBCollectionName(shortLinksTable), semconv.DBQueryText(query)))
+}
+
+// endDBSpan - Записывает ошибку запроса в span и завершает его.
+// Дубли и конфликты идентификаторов - ожидаемые исходы, поэтому не отмечаются как ошибки.
+func endDBSpan(span trace.Span, err error) {
+	var duplErr *data.DuplicateShortLinkError
+	var conflictErr *data.ShortURLConflictError
+	if err != nil && !errors.As(err, &duplErr) && !errors.As(err, &conflictErr) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,404 @@
+package repos
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/VladSnap/shortener/internal/log"
+	"go.uber.org/zap"
+)
+
+// walOp - Тип записи журнала файлового хранилища.
+type walOp string
+
+const (
+	// walOpCreate - Создание ссылки. При повторном применении заменяет ссылку целиком.
+	walOpCreate walOp = "create"
+	// walOpDelete - Пометка ссылки удаленной.
+	walOpDelete walOp = "delete"
+	// walOpRestore - Снятие пометки удаления со ссылки.
+	walOpRestore walOp = "restore"
+	// walOpUpdate - Изменение оригинального URL ссылки с сохранением прежнего URL в истории.
+	walOpUpdate walOp = "update"
+)
+
+// snapshotSuffix - Суффикс файла снимка рядом с файлом журнала.
+const snapshotSuffix = ".snapshot"
+
+// walRecord - Запись журнала файлового хранилища.
+// Строки журнала старого формата без поля op содержат ссылку целиком и читаются как walOpCreate.
+type walRecord struct {
+	Op        walOp               `json:"op"`
+	Link      *data.ShortLinkData `json:"link,omitempty"`
+	ShortURL  string              `json:"short_url,omitempty"`
+	DeletedAt *time.Time          `json:"deleted_at,omitempty"`
+	// OriginalURL и ChangedAt заполняются только для walOpUpdate.
+	OriginalURL string     `json:"orig_url,omitempty"`
+	ChangedAt   *time.Time `json:"changed_at,omitempty"`
+}
+
+// fileWAL - Журнал упреждающей записи (write-ahead log) файлового хранилища со снимком состояния.
+// Состояние хранилища - это снимок, к которому применены записи журнала.
+// При уплотнении текущее состояние записывается в новый снимок, а журнал очищается.
+type fileWAL struct {
+	file             *os.File
+	snapshotPath     string
+	syncPolicy       string
+	compactThreshold int
+	// records - Количество записей в журнале после последнего снимка.
+	records int
+	// hasLegacy - В журнале есть строки старого формата, их стоит перенести в снимок.
+	hasLegacy bool
+	dirty     bool
+	mu        sync.Mutex
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// openFileWAL - Открывает журнал и запускает фоновую синхронизацию, если она требуется политикой.
+func openFileWAL(path string, syncPolicy string, compactThreshold int) (*fileWAL, error) {
+	file, err := createFileStorage(path)
+	if err != nil {
+		return nil, err
+	}
+	wal := &fileWAL{
+		file:             file,
+		snapshotPath:     path + snapshotSuffix,
+		syncPolicy:       syncPolicy,
+		compactThreshold: compactThreshold,
+		done:             make(chan struct{}),
+	}
+	if syncPolicy == constants.FileSyncInterval {
+		wal.runSyncWork()
+	}
+	return wal, nil
+}
+
+// replay - Читает снимок и журнал, применяя каждую запись к состоянию через apply.
+// Оборванная последняя строка журнала, оставшаяся после аварийного завершения, отбрасывается.
+func (wal *fileWAL) replay(apply func(rec *walRecord)) error {
+	wal.mu.Lock()
+	defer wal.mu.Unlock()
+
+	if err := wal.replaySnapshot(apply); err != nil {
+		return err
+	}
+
+	reader := bufio.NewReader(wal.file)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return wal.recoverTail(line, offset, apply)
+		}
+		if err != nil {
+			return fmt.Errorf("failed read file storage log: %w", err)
+		}
+
+		rec, err := wal.parseRecord(line)
+		if err != nil {
+			return fmt.Errorf("corrupted file storage log at offset %d: %w", offset, err)
+		}
+		apply(rec)
+		wal.records++
+		offset += int64(len(line))
+	}
+}
+
+// replaySnapshot - Читает снимок состояния, если он есть.
+func (wal *fileWAL) replaySnapshot(apply func(rec *walRecord)) error {
+	snapshot, err := os.Open(wal.snapshotPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed open file storage snapshot: %w", err)
+	}
+	defer func() {
+		if err := snapshot.Close(); err != nil {
+			log.Zap.Error("failed close file storage snapshot", zap.Error(err))
+		}
+	}()
+
+	reader := bufio.NewReader(snapshot)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			link := data.ShortLinkData{}
+			if err := json.Unmarshal(line, &link); err != nil {
+				return fmt.Errorf("failed deserialize ShortLinkData from snapshot: %w", err)
+			}
+			apply(&walRecord{Op: walOpCreate, Link: &link})
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed read file storage snapshot: %w", err)
+		}
+	}
+}
+
+// recoverTail - Обрабатывает последнюю строку журнала без перевода строки.
+// Целая запись сохраняется, а оборванная - отрезается от файла.
+func (wal *fileWAL) recoverTail(line []byte, offset int64, apply func(rec *walRecord)) error {
+	return recoverFileTail(wal.file, line, offset, func(line []byte) error {
+		rec, err := wal.parseRecord(line)
+		if err != nil {
+			return err
+		}
+		apply(rec)
+		wal.records++
+		return nil
+	})
+}
+
+// recoverFileTail - Обрабатывает последнюю строку файла в формате JSON Lines без перевода строки,
+// оставшуюся после аварийного завершения. Если строка разбирается функцией apply, к ней дописывается
+// перевод строки, иначе оборванная строка отрезается от файла, начиная с offset.
+func recoverFileTail(file *os.File, line []byte, offset int64, apply func(line []byte) error) error {
+	if len(line) == 0 {
+		return nil
+	}
+	if err := apply(line); err == nil {
+		if _, err := file.Write([]byte{'\n'}); err != nil {
+			return fmt.Errorf("failed complete last record of %s: %w", file.Name(), err)
+		}
+		return nil
+	}
+
+	log.Zap.Warn("truncated record at the end of file storage is dropped",
+		zap.String("file", file.Name()), zap.Int64("offset", offset), zap.Int("bytes", len(line)))
+	if err := file.Truncate(offset); err != nil {
+		return fmt.Errorf("failed truncate broken record of %s: %w", file.Name(), err)
+	}
+	return nil
+}
+
+// parseRecord - Разбирает строку журнала, в том числе строку старого формата.
+func (wal *fileWAL) parseRecord(line []byte) (*walRecord, error) {
+	rec := walRecord{}
+	if err := json.Unmarshal(line, &rec); err != nil {
+		return nil, fmt.Errorf("failed deserialize log record: %w", err)
+	}
+	switch rec.Op {
+	case walOpCreate:
+		if rec.Link == nil {
+			return nil, errors.New("create record without link")
+		}
+	case walOpDelete, walOpRestore:
+		if rec.ShortURL == "" {
+			return nil, fmt.Errorf("%s record without short_url", rec.Op)
+		}
+	case walOpUpdate:
+		if rec.ShortURL == "" || rec.OriginalURL == "" || rec.ChangedAt == nil {
+			return nil, errors.New("update record without short_url, orig_url or changed_at")
+		}
+	case "":
+		link := data.ShortLinkData{}
+		if err := json.Unmarshal(line, &link); err != nil {
+			return nil, fmt.Errorf("failed deserialize ShortLinkData: %w", err)
+		}
+		wal.hasLegacy = true
+		return &walRecord{Op: walOpCreate, Link: &link}, nil
+	default:
+		return nil, fmt.Errorf("unknown log record op '%s'", rec.Op)
+	}
+	return &rec, nil
+}
+
+// append - Дописывает записи в журнал одной операцией записи и синхронизирует их согласно политике.
+func (wal *fileWAL) append(records ...*walRecord) error {
+	var buf bytes.Buffer
+	for _, rec := range records {
+		line, err := json.Marshal(rec)
+		if err != nil {
+			return fmt.Errorf("failed serialize log record: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	wal.mu.Lock()
+	defer wal.mu.Unlock()
+	if _, err := wal.file.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed write to file storage log: %w", err)
+	}
+	wal.records += len(records)
+
+	switch wal.syncPolicy {
+	case constants.FileSyncAlways:
+		if err := wal.file.Sync(); err != nil {
+			return fmt.Errorf("failed sync file storage log: %w", err)
+		}
+	case constants.FileSyncInterval:
+		wal.dirty = true
+	}
+	return nil
+}
+
+// needsCompaction - Проверяет, пора ли уплотнить журнал в снимок.
+func (wal *fileWAL) needsCompaction() bool {
+	wal.mu.Lock()
+	defer wal.mu.Unlock()
+	return wal.hasLegacy || (wal.compactThreshold > 0 && wal.records >= wal.compactThreshold)
+}
+
+// compact - Записывает состояние в новый снимок и очищает журнал.
+// Снимок заменяется атомарным переименованием, поэтому при сбое остается старый снимок и полный журнал,
+// а повторное применение записей журнала к новому снимку не меняет состояние.
+func (wal *fileWAL) compact(links []*data.ShortLinkData) error {
+	wal.mu.Lock()
+	defer wal.mu.Unlock()
+
+	tmpPath := wal.snapshotPath + ".tmp"
+	if err := writeSnapshot(tmpPath, links); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, wal.snapshotPath); err != nil {
+		return fmt.Errorf("failed replace file storage snapshot: %w", err)
+	}
+	syncDir(filepath.Dir(wal.snapshotPath))
+
+	if err := wal.file.Truncate(0); err != nil {
+		return fmt.Errorf("failed truncate file storage log: %w", err)
+	}
+	if err := wal.file.Sync(); err != nil {
+		return fmt.Errorf("failed sync file storage log: %w", err)
+	}
+	wal.records = 0
+	wal.hasLegacy = false
+	wal.dirty = false
+	return nil
+}
+
+// close - Останавливает фоновую синхронизацию, сбрасывает журнал на диск и закрывает файл.
+func (wal *fileWAL) close() error {
+	close(wal.done)
+	wal.wg.Wait()
+
+	wal.mu.Lock()
+	defer wal.mu.Unlock()
+	if err := wal.file.Sync(); err != nil {
+		return fmt.Errorf("failed sync file storage log: %w", err)
+	}
+	if err := wal.file.Close(); err != nil {
+		return fmt.Errorf("file storage close error: %w", err)
+	}
+	return nil
+}
+
+// checkWritable - Проверяет, что журнал не закрыт и в его каталоге можно создать файл.
+// Пробный файл сразу удаляется, сам журнал не изменяется.
+func (wal *fileWAL) checkWritable() error {
+	wal.mu.Lock()
+	defer wal.mu.Unlock()
+	if _, err := wal.file.Stat(); err != nil {
+		return fmt.Errorf("file storage log is not available: %w", err)
+	}
+	probe, err := os.CreateTemp(filepath.Dir(wal.snapshotPath), ".health-*")
+	if err != nil {
+		return fmt.Errorf("file storage directory is not writable: %w", err)
+	}
+	if err := probe.Close(); err != nil {
+		return fmt.Errorf("failed close file storage probe: %w", err)
+	}
+	if err := os.Remove(probe.Name()); err != nil {
+		return fmt.Errorf("failed remove file storage probe: %w", err)
+	}
+	return nil
+}
+
+// runSyncWork - Запускает горутину, которая периодически сбрасывает журнал на диск.
+func (wal *fileWAL) runSyncWork() {
+	ticker := time.NewTicker(constants.FileSyncPeriod)
+
+	wal.wg.Add(1)
+	go func() {
+		defer wal.wg.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				wal.syncDirty()
+			case <-wal.done:
+				return
+			}
+		}
+	}()
+}
+
+func (wal *fileWAL) syncDirty() {
+	wal.mu.Lock()
+	defer wal.mu.Unlock()
+	if !wal.dirty {
+		return
+	}
+	if err := wal.file.Sync(); err != nil {
+		log.Zap.Error("failed sync file storage log", zap.Error(err))
+		return
+	}
+	wal.dirty = false
+}
+
+// writeSnapshot - Записывает записи в файл снимка в формате JSON Lines и сбрасывает его на диск.
+func writeSnapshot[T any](path string, records []T) (err error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, constants.FileRWPerm)
+	if err != nil {
+		return fmt.Errorf("failed create file storage snapshot: %w", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed close file storage snapshot: %w", closeErr)
+		}
+	}()
+
+	writer := bufio.NewWriter(file)
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed serialize snapshot record: %w", err)
+		}
+		if _, err := writer.Write(line); err != nil {
+			return fmt.Errorf("failed write to snapshot buffer: %w", err)
+		}
+		if err := writer.WriteByte('\n'); err != nil {
+			return fmt.Errorf("failed write \\n to snapshot buffer: %w", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed flush buffer to file storage snapshot: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed sync file storage snapshot: %w", err)
+	}
+	return nil
+}
+
+// syncDir - Сбрасывает на диск каталог, чтобы переименование файла пережило сбой питания.
+// Не на всех платформах каталог можно синхронизировать, поэтому ошибка только логируется.
+func syncDir(path string) {
+	dir, err := os.Open(path)
+	if err != nil {
+		log.Zap.Warn("failed open file storage directory for sync", zap.Error(err))
+		return
+	}
+	defer func() {
+		if err := dir.Close(); err != nil {
+			log.Zap.Warn("failed close file storage directory", zap.Error(err))
+		}
+	}()
+	if err := dir.Sync(); err != nil {
+		log.Zap.Warn("failed sync file storage directory", zap.Error(err))
+	}
+}
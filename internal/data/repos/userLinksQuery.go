@@ -0,0 +1,61 @@
+package repos
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+)
+
+// applyUserLinksQuery - Фильтрует, сортирует и ограничивает ссылки пользователя по параметрам выборки.
+// Ссылки упорядочиваются по времени создания, а при равенстве - по идентификатору, как и в БД.
+func applyUserLinksQuery(links []*data.ShortLinkData, query *data.UserLinksQuery) []*data.ShortLinkData {
+	if query == nil {
+		query = &data.UserLinksQuery{}
+	}
+	search := strings.ToLower(query.Search)
+
+	result := make([]*data.ShortLinkData, 0, len(links))
+	for _, link := range links {
+		if query.Deleted != nil && link.IsDeleted != *query.Deleted {
+			continue
+		}
+		if search != "" && !strings.Contains(strings.ToLower(link.OriginalURL), search) {
+			continue
+		}
+		if query.After != nil && !isAfterCursor(link, query.After, query.Desc) {
+			continue
+		}
+		result = append(result, link)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if query.Desc {
+			return compareLinkPosition(result[j], result[i].CreatedAt, result[i].ShortURL) < 0
+		}
+		return compareLinkPosition(result[i], result[j].CreatedAt, result[j].ShortURL) < 0
+	})
+
+	if query.Limit > 0 && len(result) > query.Limit {
+		result = result[:query.Limit]
+	}
+	return result
+}
+
+// isAfterCursor - Проверяет, что ссылка идет после курсора в выбранном порядке сортировки.
+func isAfterCursor(link *data.ShortLinkData, cursor *data.LinkCursor, desc bool) bool {
+	cmp := compareLinkPosition(link, cursor.CreatedAt, cursor.ShortURL)
+	if desc {
+		return cmp < 0
+	}
+	return cmp > 0
+}
+
+// compareLinkPosition - Сравнивает позицию ссылки с позицией (createdAt, shortURL) по возрастанию.
+func compareLinkPosition(link *data.ShortLinkData, createdAt time.Time, shortURL string) int {
+	if cmp := link.CreatedAt.Compare(createdAt); cmp != 0 {
+		return cmp
+	}
+	return strings.Compare(link.ShortURL, shortURL)
+}
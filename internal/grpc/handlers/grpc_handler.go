@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"time"
 
+	"github.com/VladSnap/shortener/internal/auth"
 	"github.com/VladSnap/shortener/internal/config"
 	grpcvalidation "github.com/VladSnap/shortener/internal/grpc/validation"
 	"github.com/VladSnap/shortener/internal/handlers"
@@ -13,12 +16,10 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const (
-	// Channel sizes for delete operations.
-	toDeleteChanSize = 100
-
 	// Error message formats for wrapping.
 	validationErrorFormat     = "validation failed: %w"
 	userExtractionErrorFormat = "user extraction failed: %w"
@@ -34,7 +35,19 @@ func handleServiceError(err error, operation string) error {
 	if err == nil {
 		return nil
 	}
-	return status.Errorf(codes.Internal, "failed to %s: %v", operation, err)
+	switch {
+	case errors.Is(err, services.ErrInvalidAlias), errors.Is(err, services.ErrInvalidExpiration),
+		errors.Is(err, services.ErrInvalidPageQuery):
+		return status.Errorf(codes.InvalidArgument, "failed to %s: %v", operation, err)
+	case errors.Is(err, services.ErrAliasTaken), errors.Is(err, services.ErrOriginalURLTaken):
+		return status.Errorf(codes.AlreadyExists, "failed to %s: %v", operation, err)
+	case errors.Is(err, services.ErrLinkNotFound), errors.Is(err, services.ErrDeleteJobNotFound):
+		return status.Errorf(codes.NotFound, "failed to %s: %v", operation, err)
+	case errors.Is(err, services.ErrLinkNotOwned):
+		return status.Errorf(codes.PermissionDenied, "failed to %s: %v", operation, err)
+	default:
+		return status.Errorf(codes.Internal, "failed to %s: %v", operation, err)
+	}
 }
 
 // handleDatabaseError обрабатывает ошибки базы данных.
@@ -51,6 +64,7 @@ type ShortenerGRPCHandler struct {
 	service       handlers.ShorterService
 	deleteWorker  handlers.DeleterWorker
 	healthService *services.HealthService
+	tokens        *auth.JWTManager
 	opts          *config.Options
 	baseURL       string
 }
@@ -59,6 +73,7 @@ type ShortenerGRPCHandler struct {
 func NewShortenerGRPCHandler(
 	service handlers.ShorterService,
 	deleteWorker handlers.DeleterWorker,
+	healthService *services.HealthService,
 	baseURL string,
 	opts *config.Options,
 ) *ShortenerGRPCHandler {
@@ -67,7 +82,8 @@ func NewShortenerGRPCHandler(
 		deleteWorker:  deleteWorker,
 		baseURL:       baseURL,
 		opts:          opts,
-		healthService: services.NewHealthService(opts.DataBaseConnString),
+		healthService: healthService,
+		tokens:        auth.NewJWTManager(opts),
 	}
 }
 
@@ -80,12 +96,23 @@ func (h *ShortenerGRPCHandler) CreateShortLink(
 		return nil, fmt.Errorf(validationErrorFormat, err)
 	}
 
+	ttl, err := services.TTLFromSeconds(req.GetTtlSeconds())
+	if err != nil {
+		return nil, handleServiceError(err, "create short link")
+	}
+
 	userID, err := grpcvalidation.ExtractUserID(ctx)
 	if err != nil {
 		return nil, fmt.Errorf(userExtractionErrorFormat, err)
 	}
 
-	shortedLink, err := h.service.CreateShortLink(ctx, req.GetOriginalUrl(), userID)
+	originalLink := &services.OriginalLink{
+		URL:       req.GetOriginalUrl(),
+		Alias:     req.GetAlias(),
+		TTL:       ttl,
+		ExpiresAt: toTimePtr(req.GetExpiresAt()),
+	}
+	shortedLink, err := h.service.CreateShortLink(ctx, originalLink, userID)
 	if err != nil {
 		return nil, handleServiceError(err, "create short link")
 	}
@@ -111,35 +138,68 @@ func (h *ShortenerGRPCHandler) CreateShortLinkBatch(
 	}
 
 	// Convert gRPC request to service model
+	responseLinks := make([]*pb.ShortedLinkBatch, len(req.GetLinks()))
 	originalLinks := make([]*services.OriginalLink, 0, len(req.GetLinks()))
-	for _, link := range req.GetLinks() {
+	// linkIndexes holds positions of the request links passed to the service
+	linkIndexes := make([]int, 0, len(req.GetLinks()))
+	for i, link := range req.GetLinks() {
 		if err := grpcvalidation.ValidateOriginalURL(link.GetOriginalUrl()); err != nil {
-			return nil, fmt.Errorf(validationErrorFormat, err)
+			if !req.GetPartial() {
+				return nil, fmt.Errorf(validationErrorFormat, err)
+			}
+			responseLinks[i] = newFailedBatchItem(link.GetCorrelationId(), err)
+			continue
+		}
+		ttl, err := services.TTLFromSeconds(link.GetTtlSeconds())
+		if err != nil {
+			if !req.GetPartial() {
+				return nil, handleServiceError(err, "create batch")
+			}
+			responseLinks[i] = newFailedBatchItem(link.GetCorrelationId(), err)
+			continue
 		}
 
 		originalLinks = append(originalLinks, &services.OriginalLink{
 			CorelationID: link.GetCorrelationId(),
 			URL:          link.GetOriginalUrl(),
+			Alias:        link.GetAlias(),
+			TTL:          ttl,
+			ExpiresAt:    toTimePtr(link.GetExpiresAt()),
 		})
+		linkIndexes = append(linkIndexes, i)
 	}
 
-	shortedLinks, err := h.service.CreateShortLinkBatch(ctx, originalLinks, userID)
+	shortedLinks, err := h.service.CreateShortLinkBatch(ctx, originalLinks, userID, req.GetPartial())
 	if err != nil {
 		return nil, handleServiceError(err, "create batch")
 	}
 
 	// Convert service response to gRPC response
-	responseLinks := make([]*pb.ShortedLinkBatch, 0, len(shortedLinks))
-	for _, link := range shortedLinks {
-		responseLinks = append(responseLinks, &pb.ShortedLinkBatch{
+	for k, link := range shortedLinks {
+		responseLink := &pb.ShortedLinkBatch{
 			CorrelationId: link.CorelationID,
-			ShortUrl:      h.baseURL + "/" + link.URL,
-		})
+			Status:        toPbBatchItemStatus(link.Status),
+		}
+		if link.Err != nil {
+			responseLink.Error = link.Err.Error()
+		} else {
+			responseLink.ShortUrl = h.baseURL + "/" + link.URL
+		}
+		responseLinks[linkIndexes[k]] = responseLink
 	}
 
 	return &pb.CreateShortLinkBatchResponse{Links: responseLinks}, nil
 }
 
+// newFailedBatchItem builds the result of a batch link rejected before it reaches the service.
+func newFailedBatchItem(correlationID string, err error) *pb.ShortedLinkBatch {
+	return &pb.ShortedLinkBatch{
+		CorrelationId: correlationID,
+		Status:        pb.BatchItemStatus_BATCH_ITEM_STATUS_ERROR,
+		Error:         err.Error(),
+	}
+}
+
 // GetURL retrieves the original URL by its short identifier.
 func (h *ShortenerGRPCHandler) GetURL(ctx context.Context, req *pb.GetURLRequest) (*pb.GetURLResponse, error) {
 	if err := grpcvalidation.ValidateShortID(req.GetShortId()); err != nil {
@@ -159,13 +219,18 @@ func (h *ShortenerGRPCHandler) GetURL(ctx context.Context, req *pb.GetURLRequest
 		return nil, fmt.Errorf(urlAccessErrorFormat, status.Error(codes.FailedPrecondition, "URL has been removed"))
 	}
 
+	if shortedLink.IsExpired {
+		return nil, fmt.Errorf(urlAccessErrorFormat, status.Error(codes.OutOfRange, "URL has expired"))
+	}
+
 	return &pb.GetURLResponse{
 		OriginalUrl: shortedLink.OriginalURL,
 		IsDeleted:   shortedLink.IsDeleted,
+		ExpiresAt:   toTimestamp(shortedLink.ExpiresAt),
 	}, nil
 }
 
-// GetAllByUserID retrieves all URLs shortened by a specific user.
+// GetAllByUserID retrieves URLs shortened by a specific user, page by page when limit is set.
 func (h *ShortenerGRPCHandler) GetAllByUserID(
 	ctx context.Context,
 	req *pb.GetAllByUserIDRequest,
@@ -175,10 +240,18 @@ func (h *ShortenerGRPCHandler) GetAllByUserID(
 		return nil, fmt.Errorf(userExtractionErrorFormat, err)
 	}
 
-	shortedLinks, err := h.service.GetAllByUserID(ctx, userID)
+	query := &services.UserLinksQuery{
+		Limit:   int(req.GetLimit()),
+		Cursor:  req.GetCursor(),
+		Desc:    req.GetDesc(),
+		Deleted: req.Deleted,
+		Search:  req.GetSearch(),
+	}
+	page, err := h.service.GetAllByUserID(ctx, userID, query)
 	if err != nil {
 		return nil, handleServiceError(err, "get user URLs")
 	}
+	shortedLinks := page.Links
 
 	if len(shortedLinks) == 0 {
 		return nil, fmt.Errorf(userURLsLookupErrorFormat, status.Error(codes.NotFound, "URLs for user not found"))
@@ -193,7 +266,110 @@ func (h *ShortenerGRPCHandler) GetAllByUserID(
 		})
 	}
 
-	return &pb.GetAllByUserIDResponse{Urls: userUrls}, nil
+	return &pb.GetAllByUserIDResponse{Urls: userUrls, NextCursor: page.NextCursor}, nil
+}
+
+// UpdateLink changes the original URL of a short link owned by the user.
+func (h *ShortenerGRPCHandler) UpdateLink(
+	ctx context.Context,
+	req *pb.UpdateLinkRequest,
+) (*pb.UpdateLinkResponse, error) {
+	if err := grpcvalidation.ValidateShortID(req.GetShortId()); err != nil {
+		return nil, fmt.Errorf(validationErrorFormat, err)
+	}
+	if err := grpcvalidation.ValidateOriginalURL(req.GetOriginalUrl()); err != nil {
+		return nil, fmt.Errorf(validationErrorFormat, err)
+	}
+
+	userID, err := grpcvalidation.ExtractUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(userExtractionErrorFormat, err)
+	}
+
+	link, err := h.service.UpdateLink(ctx, req.GetShortId(), userID, req.GetOriginalUrl())
+	if err != nil {
+		return nil, handleServiceError(err, "update link")
+	}
+
+	history := make([]*pb.LinkDestination, 0, len(link.History))
+	for _, destination := range link.History {
+		history = append(history, &pb.LinkDestination{
+			OriginalUrl: destination.OriginalURL,
+			ChangedAt:   timestamppb.New(destination.ChangedAt),
+		})
+	}
+
+	return &pb.UpdateLinkResponse{
+		ShortUrl:    h.baseURL + "/" + link.URL,
+		OriginalUrl: link.OriginalURL,
+		History:     history,
+	}, nil
+}
+
+// GetLinkStats returns click statistics for a short link owned by the user.
+func (h *ShortenerGRPCHandler) GetLinkStats(
+	ctx context.Context,
+	req *pb.GetLinkStatsRequest,
+) (*pb.GetLinkStatsResponse, error) {
+	if err := grpcvalidation.ValidateShortID(req.GetShortId()); err != nil {
+		return nil, fmt.Errorf(validationErrorFormat, err)
+	}
+
+	userID, err := grpcvalidation.ExtractUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(userExtractionErrorFormat, err)
+	}
+
+	stats, err := h.service.GetLinkStats(ctx, req.GetShortId(), userID)
+	if err != nil {
+		return nil, handleServiceError(err, "get link stats")
+	}
+
+	days := make([]*pb.DailyClicks, 0, len(stats.Days))
+	for _, day := range stats.Days {
+		days = append(days, &pb.DailyClicks{
+			Date:   day.Day.Format(time.DateOnly),
+			Clicks: int64(day.Clicks),
+		})
+	}
+
+	return &pb.GetLinkStatsResponse{
+		ShortUrl:    h.baseURL + "/" + stats.ShortURL,
+		TotalClicks: int64(stats.TotalClicks),
+		Days:        days,
+	}, nil
+}
+
+// GetUserStats returns aggregate statistics for all links of the user.
+func (h *ShortenerGRPCHandler) GetUserStats(
+	ctx context.Context,
+	req *pb.GetUserStatsRequest,
+) (*pb.GetUserStatsResponse, error) {
+	userID, err := grpcvalidation.ExtractUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(userExtractionErrorFormat, err)
+	}
+
+	stats, err := h.service.GetUserStats(ctx, userID)
+	if err != nil {
+		return nil, handleServiceError(err, "get user stats")
+	}
+
+	topUrls := make([]*pb.LinkClicks, 0, len(stats.TopLinks))
+	for _, link := range stats.TopLinks {
+		topUrls = append(topUrls, &pb.LinkClicks{
+			ShortUrl: h.baseURL + "/" + link.ShortURL,
+			Clicks:   int64(link.Clicks),
+		})
+	}
+
+	return &pb.GetUserStatsResponse{
+		ActiveUrls:  int32(stats.Active),
+		DeletedUrls: int32(stats.Deleted),
+		ExpiredUrls: int32(stats.Expired),
+		TotalClicks: int64(stats.TotalClicks),
+		TopUrls:     topUrls,
+	}, nil
 }
 
 // DeleteBatch marks multiple URLs as deleted.
@@ -210,29 +386,89 @@ func (h *ShortenerGRPCHandler) DeleteBatch(
 		return nil, fmt.Errorf(userExtractionErrorFormat, err)
 	}
 
-	// Create channel for deletion
-	toDeleteChan := make(chan services.DeleteShortID, toDeleteChanSize)
-	defer close(toDeleteChan)
+	toDelete := make([]services.DeleteShortID, 0, len(req.GetShortUrls()))
+	for _, shortURL := range req.GetShortUrls() {
+		toDelete = append(toDelete, services.NewDeleteShortID(shortURL, userID))
+	}
+
+	// Persist deletion requests in the delete worker queue before replying.
+	jobID, err := h.deleteWorker.AddToDelete(ctx, toDelete)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete URLs: %v", err)
+	}
+
+	return &pb.DeleteBatchResponse{Success: true, JobId: jobID}, nil
+}
+
+// GetDeleteJob returns the status of a delete request of the user.
+func (h *ShortenerGRPCHandler) GetDeleteJob(
+	ctx context.Context,
+	req *pb.GetDeleteJobRequest,
+) (*pb.GetDeleteJobResponse, error) {
+	if req.GetJobId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "job_id is required")
+	}
+
+	userID, err := grpcvalidation.ExtractUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(userExtractionErrorFormat, err)
+	}
+
+	job, err := h.deleteWorker.GetDeleteJob(ctx, req.GetJobId(), userID)
+	if err != nil {
+		return nil, handleServiceError(err, "get delete job")
+	}
 
-	// Send deletion requests to channel
+	items := make([]*pb.DeleteJobItem, 0, len(job.Items))
+	for _, item := range job.Items {
+		items = append(items, &pb.DeleteJobItem{
+			ShortUrl: item.ShortURL,
+			Outcome:  toPbDeleteOutcome(item.Outcome),
+			Error:    item.Error,
+		})
+	}
+
+	return &pb.GetDeleteJobResponse{
+		JobId:     job.ID,
+		Status:    toPbDeleteJobStatus(job.Status),
+		CreatedAt: timestamppb.New(job.CreatedAt),
+		Items:     items,
+	}, nil
+}
+
+// RestoreBatch restores URLs of the user deleted within the restore window.
+func (h *ShortenerGRPCHandler) RestoreBatch(
+	ctx context.Context,
+	req *pb.RestoreBatchRequest,
+) (*pb.RestoreBatchResponse, error) {
+	if err := grpcvalidation.ValidateShortURLs(req.GetShortUrls()); err != nil {
+		return nil, fmt.Errorf(validationErrorFormat, err)
+	}
+
+	userID, err := grpcvalidation.ExtractUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(userExtractionErrorFormat, err)
+	}
+
+	toRestore := make([]services.DeleteShortID, 0, len(req.GetShortUrls()))
 	for _, shortURL := range req.GetShortUrls() {
-		if err := grpcvalidation.ValidateContextDeadline(ctx); err != nil {
-			return nil, fmt.Errorf(contextErrorFormat, err)
-		}
+		toRestore = append(toRestore, services.NewDeleteShortID(shortURL, userID))
+	}
 
-		deleteSID := services.NewDeleteShortID(shortURL, userID)
-		select {
-		case toDeleteChan <- deleteSID:
-		case <-ctx.Done():
-			return nil, fmt.Errorf(contextDeadlineFormat,
-				status.Error(codes.DeadlineExceeded, "context deadline exceeded"))
-		}
+	results, err := h.service.RestoreBatch(ctx, toRestore)
+	if err != nil {
+		return nil, handleServiceError(err, "restore URLs")
 	}
 
-	// Send to delete worker
-	h.deleteWorker.AddToDelete(toDeleteChan)
+	items := make([]*pb.RestoreItem, 0, len(results))
+	for _, result := range results {
+		items = append(items, &pb.RestoreItem{
+			ShortUrl: result.ShortURL,
+			Outcome:  toPbRestoreOutcome(result.Outcome),
+		})
+	}
 
-	return &pb.DeleteBatchResponse{Success: true}, nil
+	return &pb.RestoreBatchResponse{Items: items}, nil
 }
 
 // GetStats returns service statistics (only for trusted subnets).
@@ -253,15 +489,54 @@ func (h *ShortenerGRPCHandler) GetStats(ctx context.Context, req *pb.GetStatsReq
 
 // Ping checks service health.
 func (h *ShortenerGRPCHandler) Ping(ctx context.Context, req *pb.PingRequest) (*pb.PingResponse, error) {
-	// Check database connection if configured
-	err := h.healthService.PingDatabase(ctx)
+	// Check the storage in use and background workers
+	err := h.healthService.Readiness(ctx).Err()
 	if err != nil {
-		return nil, handleDatabaseError(err, "database not available")
+		return nil, handleDatabaseError(err, "service not ready")
 	}
 
 	return &pb.PingResponse{Status: "OK"}, nil
 }
 
+// CreateToken issues a JWT access token for the user identified by the auth interceptor.
+func (h *ShortenerGRPCHandler) CreateToken(
+	ctx context.Context,
+	req *pb.CreateTokenRequest,
+) (*pb.CreateTokenResponse, error) {
+	userID, err := grpcvalidation.ExtractUserID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(userExtractionErrorFormat, err)
+	}
+
+	token, expiresAt, err := h.tokens.IssueToken(userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to issue token: %v", err)
+	}
+
+	return &pb.CreateTokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresAt:   timestamppb.New(expiresAt),
+	}, nil
+}
+
+// toTimePtr converts an optional protobuf timestamp to a time pointer.
+func toTimePtr(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
+// toTimestamp converts an optional time pointer to a protobuf timestamp.
+func toTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
 // StartGRPCServer starts the gRPC server on the specified address.
 func StartGRPCServer(addr string, handler *ShortenerGRPCHandler) (*grpc.Server, net.Listener, error) {
 	lis, err := net.Listen("tcp", addr)
@@ -274,3 +549,67 @@ func StartGRPCServer(addr string, handler *ShortenerGRPCHandler) (*grpc.Server,
 
 	return s, lis, nil
 }
+
+// toPbBatchItemStatus converts a service batch item status to its protobuf value.
+func toPbBatchItemStatus(status services.BatchItemStatus) pb.BatchItemStatus {
+	switch status {
+	case services.BatchItemCreated:
+		return pb.BatchItemStatus_BATCH_ITEM_STATUS_CREATED
+	case services.BatchItemDuplicate:
+		return pb.BatchItemStatus_BATCH_ITEM_STATUS_DUPLICATE
+	case services.BatchItemFailed:
+		return pb.BatchItemStatus_BATCH_ITEM_STATUS_ERROR
+	default:
+		return pb.BatchItemStatus_BATCH_ITEM_STATUS_UNSPECIFIED
+	}
+}
+
+// toPbDeleteJobStatus converts a service delete job status to its protobuf value.
+func toPbDeleteJobStatus(jobStatus services.DeleteJobStatus) pb.DeleteJobStatus {
+	switch jobStatus {
+	case services.DeleteJobPending:
+		return pb.DeleteJobStatus_DELETE_JOB_STATUS_PENDING
+	case services.DeleteJobCompleted:
+		return pb.DeleteJobStatus_DELETE_JOB_STATUS_COMPLETED
+	case services.DeleteJobFailed:
+		return pb.DeleteJobStatus_DELETE_JOB_STATUS_FAILED
+	default:
+		return pb.DeleteJobStatus_DELETE_JOB_STATUS_UNSPECIFIED
+	}
+}
+
+// toPbDeleteOutcome converts a service delete outcome to its protobuf value.
+func toPbDeleteOutcome(outcome services.DeleteOutcome) pb.DeleteOutcome {
+	switch outcome {
+	case services.DeleteOutcomePending:
+		return pb.DeleteOutcome_DELETE_OUTCOME_PENDING
+	case services.DeleteOutcomeDeleted:
+		return pb.DeleteOutcome_DELETE_OUTCOME_DELETED
+	case services.DeleteOutcomeNotFound:
+		return pb.DeleteOutcome_DELETE_OUTCOME_NOT_FOUND
+	case services.DeleteOutcomeNotOwned:
+		return pb.DeleteOutcome_DELETE_OUTCOME_NOT_OWNED
+	case services.DeleteOutcomeFailed:
+		return pb.DeleteOutcome_DELETE_OUTCOME_FAILED
+	default:
+		return pb.DeleteOutcome_DELETE_OUTCOME_UNSPECIFIED
+	}
+}
+
+// toPbRestoreOutcome converts a service restore outcome to its protobuf value.
+func toPbRestoreOutcome(outcome services.RestoreOutcome) pb.RestoreOutcome {
+	switch outcome {
+	case services.RestoreOutcomeRestored:
+		return pb.RestoreOutcome_RESTORE_OUTCOME_RESTORED
+	case services.RestoreOutcomeNotFound:
+		return pb.RestoreOutcome_RESTORE_OUTCOME_NOT_FOUND
+	case services.RestoreOutcomeNotOwned:
+		return pb.RestoreOutcome_RESTORE_OUTCOME_NOT_OWNED
+	case services.RestoreOutcomeNotDeleted:
+		return pb.RestoreOutcome_RESTORE_OUTCOME_NOT_DELETED
+	case services.RestoreOutcomeExpired:
+		return pb.RestoreOutcome_RESTORE_OUTCOME_WINDOW_EXPIRED
+	default:
+		return pb.RestoreOutcome_RESTORE_OUTCOME_UNSPECIFIED
+	}
+}
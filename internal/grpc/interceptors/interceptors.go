@@ -3,9 +3,11 @@ package interceptors
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"runtime/debug"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,7 +15,15 @@ import (
 	"github.com/VladSnap/shortener/internal/config"
 	"github.com/VladSnap/shortener/internal/constants"
 	"github.com/VladSnap/shortener/internal/log"
+	"github.com/VladSnap/shortener/internal/metrics"
+	"github.com/VladSnap/shortener/internal/ratelimit"
+	"github.com/VladSnap/shortener/internal/services"
+	"github.com/VladSnap/shortener/internal/tracing"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -101,15 +111,75 @@ func getClientIP(ctx context.Context) (string, error) {
 	return host, nil
 }
 
+// apiKeyMetadata is the metadata key carrying the API key of a server-to-server client.
+const apiKeyMetadata = "x-api-key"
+
+// APIKeyAuthenticator looks up active API keys.
+type APIKeyAuthenticator interface {
+	// Authenticate returns the active API key or services.ErrInvalidAPIKey.
+	Authenticate(ctx context.Context, key string) (*services.APIKey, error)
+}
+
+// methodScopes maps service methods to the scope an API key needs to call them.
+// Methods missing from the map need no scope.
+var methodScopes = map[string]services.Scope{
+	"CreateShortLink":      services.ScopeCreate,
+	"CreateShortLinkBatch": services.ScopeCreate,
+	"UpdateLink":           services.ScopeCreate,
+	"GetAllByUserID":       services.ScopeRead,
+	"DeleteBatch":          services.ScopeDelete,
+	"GetDeleteJob":         services.ScopeDelete,
+	"RestoreBatch":         services.ScopeDelete,
+	"GetLinkStats":         services.ScopeStats,
+	"GetUserStats":         services.ScopeStats,
+	"GetStats":             services.ScopeStats,
+}
+
+// apiKeyDeniedMethods lists methods that cannot be called with an API key.
+var apiKeyDeniedMethods = map[string]bool{
+	"CreateToken": true,
+}
+
 // AuthInterceptor provides authentication functionality for gRPC.
-func AuthInterceptor(opts *config.Options) grpc.UnaryServerInterceptor {
+// An API key in the x-api-key metadata takes precedence over a bearer JWT in the authorization metadata,
+// which takes precedence over the auth-cookie metadata. API keys are not accepted when apiKeys is nil.
+func AuthInterceptor(opts *config.Options, cookieRing *auth.CookieKeyRing,
+	apiKeys APIKeyAuthenticator) grpc.UnaryServerInterceptor {
+	tokens := auth.NewJWTManager(opts)
 	return withErrorHandling("auth", func(ctx context.Context, req any,
 		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 		// Extract metadata from context
 		md, ok := metadata.FromIncomingContext(ctx)
 		if !ok {
 			// No metadata, create new user ID
-			userID := generateNewUserID()
+			ctx = withNewUserID(ctx)
+			return handler(ctx, req)
+		}
+
+		// Check for API key in metadata
+		if keys := md.Get(apiKeyMetadata); len(keys) > 0 && apiKeys != nil {
+			apiKey, err := authenticateAPIKey(ctx, apiKeys, keys[0], info.FullMethod)
+			if err != nil {
+				return nil, err
+			}
+			ctx = services.ContextWithAPIKey(ctx, apiKey)
+			ctx = context.WithValue(ctx, constants.UserIDContextKey, apiKey.UserID)
+			return handler(ctx, req)
+		}
+
+		// Check for bearer token in metadata
+		if authorization := md.Get("authorization"); len(authorization) > 0 {
+			token, ok := auth.ParseBearerToken(authorization[0])
+			if !ok {
+				return nil, status.Error(codes.Unauthenticated, "invalid authorization scheme, expected Bearer")
+			}
+			userID, err := tokens.VerifyToken(token)
+			if err != nil {
+				log.Zap.Warn("failed to verify gRPC bearer token",
+					zap.Error(err),
+					zap.String(zapFieldMethod, info.FullMethod))
+				return nil, status.Error(codes.Unauthenticated, "invalid token")
+			}
 			ctx = context.WithValue(ctx, constants.UserIDContextKey, userID)
 			return handler(ctx, req)
 		}
@@ -118,28 +188,22 @@ func AuthInterceptor(opts *config.Options) grpc.UnaryServerInterceptor {
 		authCookies := md.Get("auth-cookie")
 		if len(authCookies) == 0 {
 			// No auth cookie, create new user ID
-			userID := generateNewUserID()
-			ctx = context.WithValue(ctx, constants.UserIDContextKey, userID)
+			ctx = withNewUserID(ctx)
 			return handler(ctx, req)
 		}
 
-		authCookie := authCookies[0]
-
-		// Verify the signed cookie
-		if _, err := auth.VerifySignCookie(authCookie, opts.AuthCookieKey); err != nil {
+		// Verify signature and expiry of the cookie and decode the user ID
+		authData, needRotate, err := cookieRing.Verify(authCookies[0])
+		if err != nil {
 			log.Zap.Warn("failed to verify gRPC auth cookie",
 				zap.Error(err),
 				zap.String(zapFieldMethod, info.FullMethod))
 			return nil, status.Error(codes.Unauthenticated, "invalid authentication")
 		}
 
-		// Decode the cookie to get user ID
-		authData, err := auth.DecodeCookie(authCookie)
-		if err != nil {
-			log.Zap.Warn("failed to decode gRPC auth cookie",
-				zap.Error(err),
-				zap.String(zapFieldMethod, info.FullMethod))
-			return nil, status.Error(codes.Unauthenticated, "invalid authentication data")
+		// Return a cookie signed with the current key or with a new expiry in the response header
+		if needRotate {
+			rotateAuthCookie(ctx, cookieRing, authData.UserID)
 		}
 
 		// Add user ID to context
@@ -148,6 +212,39 @@ func AuthInterceptor(opts *config.Options) grpc.UnaryServerInterceptor {
 	})
 }
 
+// authenticateAPIKey verifies the API key and checks that it may call the method.
+func authenticateAPIKey(ctx context.Context, apiKeys APIKeyAuthenticator, key string,
+	fullMethod string) (*services.APIKey, error) {
+	apiKey, err := apiKeys.Authenticate(ctx, key)
+	if errors.Is(err, services.ErrInvalidAPIKey) {
+		return nil, status.Error(codes.Unauthenticated, "invalid api key")
+	}
+	if err != nil {
+		log.Zap.Error("failed to authenticate gRPC api key", zap.Error(err), zap.String(zapFieldMethod, fullMethod))
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	if apiKeyDeniedMethods[method] {
+		return nil, status.Error(codes.PermissionDenied, "method is not allowed for api keys")
+	}
+	if scope, ok := methodScopes[method]; ok && !apiKey.HasScope(scope) {
+		return nil, status.Errorf(codes.PermissionDenied, "api key has no '%s' scope", scope)
+	}
+	return apiKey, nil
+}
+
+// rotateAuthCookie sends a reissued auth cookie to the client in the auth-cookie header metadata.
+func rotateAuthCookie(ctx context.Context, cookieRing *auth.CookieKeyRing, userID string) {
+	cookie, err := cookieRing.Issue(userID)
+	if err == nil {
+		err = grpc.SetHeader(ctx, metadata.Pairs("auth-cookie", cookie))
+	}
+	if err != nil {
+		log.Zap.Warn("failed to rotate gRPC auth cookie", zap.Error(err))
+	}
+}
+
 // LoggingInterceptor provides logging functionality for gRPC.
 func LoggingInterceptor() grpc.UnaryServerInterceptor {
 	return withErrorHandling("logging", func(ctx context.Context, req any,
@@ -184,6 +281,110 @@ func LoggingInterceptor() grpc.UnaryServerInterceptor {
 	})
 }
 
+// MetricsInterceptor provides request count and latency metrics for gRPC.
+func MetricsInterceptor(recorder *metrics.Metrics) grpc.UnaryServerInterceptor {
+	return withErrorHandling("metrics", func(ctx context.Context, req any,
+		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		recorder.ObserveGRPCRequest(info.FullMethod, status.Code(err).String(), time.Since(start))
+		return resp, err
+	})
+}
+
+// RateLimitInterceptor provides per-client rate limiting for gRPC.
+// limiters maps method names like CreateShortLink to their limiter, other methods are not limited.
+// It must run after AuthInterceptor because clients are identified by the user ID in the context.
+func RateLimitInterceptor(limiters map[string]*ratelimit.Limiter,
+	recorder *metrics.Metrics) grpc.UnaryServerInterceptor {
+	return withErrorHandling("rate_limit", func(ctx context.Context, req any,
+		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		limiter := limiters[info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]]
+		if limiter == nil {
+			return handler(ctx, req)
+		}
+
+		ip, err := getClientIP(ctx)
+		if err != nil {
+			ip = "unknown"
+		}
+		allowed, wait := limiter.Allow(ratelimit.ClientKey(ctx, ip))
+		if !allowed {
+			if recorder != nil {
+				recorder.AddRateLimited(limiter.Name())
+			}
+			retryAfter := strconv.Itoa(ratelimit.RetryAfterSeconds(wait))
+			if err := grpc.SetHeader(ctx, metadata.Pairs("retry-after", retryAfter)); err != nil {
+				log.Zap.Warn("failed to set retry-after header", zap.Error(err))
+			}
+			return nil, status.Errorf(codes.ResourceExhausted, "too many requests, retry after %s seconds", retryAfter)
+		}
+		return handler(ctx, req)
+	})
+}
+
+// AuditInterceptor stores the gRPC source and the client IP in the context for the link audit log.
+func AuditInterceptor() grpc.UnaryServerInterceptor {
+	return withErrorHandling("audit", func(ctx context.Context, req any,
+		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ip, err := getClientIP(ctx)
+		if err != nil {
+			ip = "unknown"
+		}
+		ctx = services.WithAuditActor(ctx, services.AuditActor{Source: services.AuditSourceGRPC, ClientIP: ip})
+		return handler(ctx, req)
+	})
+}
+
+// metadataCarrier adapts incoming gRPC metadata to the OpenTelemetry TextMapCarrier interface.
+type metadataCarrier metadata.MD
+
+// Get returns the first value of the key.
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Set replaces the values of the key.
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys returns all metadata keys.
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// TracingInterceptor provides OpenTelemetry server spans for gRPC.
+// The parent trace context is extracted from the W3C traceparent and tracestate metadata.
+func TracingInterceptor() grpc.UnaryServerInterceptor {
+	tracer := tracing.Tracer()
+	return withErrorHandling("tracing", func(ctx context.Context, req any,
+		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+		service, method, _ := strings.Cut(strings.TrimPrefix(info.FullMethod, "/"), "/")
+		ctx, span := tracer.Start(ctx, info.FullMethod, trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.RPCSystemGRPC, semconv.RPCService(service), semconv.RPCMethod(method)))
+		defer span.End()
+
+		resp, err := handler(ctx, req)
+		code := status.Code(err)
+		span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, code.String())
+		}
+		return resp, err
+	})
+}
+
 // TrustedSubnetConfig holds configuration for trusted subnet validation.
 type TrustedSubnetConfig struct {
 	// TrustedSubnet is the CIDR notation of the trusted subnet
@@ -279,6 +480,12 @@ func ChainInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.ServerO
 	return grpc.ChainUnaryInterceptor(interceptors...)
 }
 
+// withNewUserID adds a new user ID to the context and marks it as created for this request.
+func withNewUserID(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, constants.NewUserContextKey, true)
+	return context.WithValue(ctx, constants.UserIDContextKey, generateNewUserID())
+}
+
 // generateNewUserID creates a new UUID for unauthenticated users.
 func generateNewUserID() string {
 	id, err := uuid.NewRandom()
@@ -297,6 +504,10 @@ func formatMetadata(md metadata.MD) string {
 
 	parts := make([]string, 0, len(md))
 	for k, v := range md {
+		if k == "authorization" || k == "auth-cookie" || k == apiKeyMetadata {
+			// Do not log credentials
+			v = []string{"[redacted]"}
+		}
 		parts = append(parts, fmt.Sprintf("%s: %v", k, v))
 	}
 	return strings.Join(parts, " | ")
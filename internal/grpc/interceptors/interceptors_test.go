@@ -1,7 +1,28 @@
 package interceptors
 
 import (
+	"context"
 	"testing"
+
+	"github.com/VladSnap/shortener/internal/auth"
+	"github.com/VladSnap/shortener/internal/config"
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/data/repos"
+	"github.com/VladSnap/shortener/internal/ratelimit"
+	"github.com/VladSnap/shortener/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func TestShouldValidateMethod(t *testing.T) {
@@ -66,3 +87,120 @@ func TestShouldValidateMethod(t *testing.T) {
 		})
 	}
 }
+
+func TestTracingInterceptor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	ctx := metadata.NewIncomingContext(t.Context(),
+		metadata.Pairs("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/shortener.ShortenerService/GetURL"}
+
+	_, err := TracingInterceptor()(ctx, nil, info, func(ctx context.Context, _ any) (any, error) {
+		assert.Equal(t, traceID, trace.SpanContextFromContext(ctx).TraceID().String())
+		return nil, status.Error(codes.NotFound, "not found")
+	})
+
+	require.Error(t, err)
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, info.FullMethod, spans[0].Name())
+	assert.Equal(t, traceID, spans[0].Parent().TraceID().String())
+	assert.Equal(t, otelcodes.Error, spans[0].Status().Code)
+	assert.Contains(t, spans[0].Attributes(), semconv.RPCMethod("GetURL"))
+}
+
+func TestAuthInterceptorBearerToken(t *testing.T) {
+	opts := &config.Options{AuthCookieKey: "secret", JWTSecret: "jwt-secret"}
+	token, _, err := auth.NewJWTManager(opts).IssueToken("user1")
+	require.NoError(t, err)
+
+	cookieRing, err := auth.NewCookieKeyRing(opts)
+	require.NoError(t, err)
+	interceptor := AuthInterceptor(opts, cookieRing, nil)
+	info := &grpc.UnaryServerInfo{FullMethod: "/shortener.ShortenerService/GetUserStats"}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return ctx.Value(constants.UserIDContextKey), nil
+	}
+	call := func(authorization string) (any, error) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", authorization))
+		return interceptor(ctx, nil, info, handler)
+	}
+
+	userID, err := call("Bearer " + token)
+	require.NoError(t, err)
+	assert.Equal(t, "user1", userID)
+
+	_, err = call("Bearer " + token + "x")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = call("Basic dXNlcjpwYXNz")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestAuthInterceptorAPIKey(t *testing.T) {
+	apiKeys := services.NewAPIKeyService(repos.NewInMemoryAPIKeyRepo())
+	created, err := apiKeys.CreateAPIKey(t.Context(), "user1", "importer", []services.Scope{services.ScopeCreate})
+	require.NoError(t, err)
+
+	opts := &config.Options{AuthCookieKey: "secret"}
+	cookieRing, err := auth.NewCookieKeyRing(opts)
+	require.NoError(t, err)
+	interceptor := AuthInterceptor(opts, cookieRing, apiKeys)
+	handler := func(ctx context.Context, req any) (any, error) {
+		return ctx.Value(constants.UserIDContextKey), nil
+	}
+	call := func(method, key string) (any, error) {
+		ctx := metadata.NewIncomingContext(t.Context(), metadata.Pairs("x-api-key", key))
+		return interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/shortener.ShortenerService/" + method}, handler)
+	}
+
+	userID, err := call("CreateShortLink", created.Key)
+	require.NoError(t, err)
+	assert.Equal(t, "user1", userID)
+
+	_, err = call("GetURL", created.Key)
+	require.NoError(t, err, "methods without scope are allowed")
+
+	_, err = call("GetAllByUserID", created.Key)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = call("CreateToken", created.Key)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = call("CreateShortLink", "shk_unknown")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestRateLimitInterceptor(t *testing.T) {
+	interceptor := RateLimitInterceptor(map[string]*ratelimit.Limiter{
+		"CreateShortLink": ratelimit.New(ratelimit.LimitCreate, 1, 1),
+	}, nil)
+	handler := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+	call := func(method, userID string) error {
+		ctx := metadata.NewIncomingContext(t.Context(), metadata.Pairs("x-real-ip", "10.0.0.1"))
+		ctx = context.WithValue(ctx, constants.UserIDContextKey, userID)
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/shortener.ShortenerService/" + method}, handler)
+		return err
+	}
+
+	require.NoError(t, call("CreateShortLink", "user1"))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(call("CreateShortLink", "user1")))
+	require.NoError(t, call("CreateShortLink", "user2"), "users have separate limits")
+	require.NoError(t, call("GetURL", "user1"), "methods without limiter are not limited")
+}
+
+func TestAuditInterceptor(t *testing.T) {
+	interceptor := AuditInterceptor()
+	var actor services.AuditActor
+	handler := func(ctx context.Context, req any) (any, error) {
+		actor = services.AuditActorFromContext(ctx)
+		return "ok", nil
+	}
+
+	ctx := metadata.NewIncomingContext(t.Context(), metadata.Pairs("x-real-ip", "10.0.0.1"))
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/shortener.ShortenerService/UpdateLink"}, handler)
+	require.NoError(t, err)
+	assert.Equal(t, services.AuditActor{Source: services.AuditSourceGRPC, ClientIP: "10.0.0.1"}, actor)
+}
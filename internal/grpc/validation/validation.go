@@ -42,9 +42,9 @@ func ValidateShortID(shortID string) error {
 		return fmt.Errorf(validationFailedErr, status.Error(codes.InvalidArgument, "short_id is required"))
 	}
 
-	if utf8.RuneCountInString(shortID) != constants.ShortIDLength {
+	if utf8.RuneCountInString(shortID) > constants.MaxShortIDLength {
 		return fmt.Errorf(validationFailedErr, status.Errorf(codes.InvalidArgument,
-			"short_id length must be %d", constants.ShortIDLength))
+			"short_id length must not exceed %d", constants.MaxShortIDLength))
 	}
 
 	return nil
@@ -56,9 +56,9 @@ func ValidateShortURL(shortURL string) error {
 		return fmt.Errorf(validationFailedErr, status.Error(codes.InvalidArgument, "short_url cannot be empty"))
 	}
 
-	if utf8.RuneCountInString(shortURL) != constants.ShortIDLength {
+	if utf8.RuneCountInString(shortURL) > constants.MaxShortIDLength {
 		return fmt.Errorf(validationFailedErr, status.Errorf(codes.InvalidArgument,
-			"short_url length must be %d", constants.ShortIDLength))
+			"short_url length must not exceed %d", constants.MaxShortIDLength))
 	}
 
 	return nil
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/log"
+	"github.com/VladSnap/shortener/internal/services"
+	"go.uber.org/zap"
+)
+
+// APIKeyManager - Интерфейс управления ключами API межсервисных клиентов.
+type APIKeyManager interface {
+	// CreateAPIKey - Создает ключ API. Если userID пуст, ключ получает нового пользователя.
+	CreateAPIKey(ctx context.Context, userID, name string, scopes []services.Scope) (*services.CreatedAPIKey, error)
+	// ListAPIKeys - Возвращает все ключи API, включая отозванные.
+	ListAPIKeys(ctx context.Context) ([]*services.APIKey, error)
+	// RevokeAPIKey - Отзывает ключ API.
+	RevokeAPIKey(ctx context.Context, id string) error
+}
+
+// CreateAPIKeyRequest - Структура запроса создания ключа API.
+type CreateAPIKeyRequest struct {
+	// UserID - Пользователь, от имени которого действует ключ. Если пуст, создается новый пользователь.
+	UserID string `json:"user_id,omitempty"`
+	// Name - Название ключа, например имя клиента.
+	Name string `json:"name"`
+	// Scopes - Разрешения ключа: create, read, delete, stats.
+	Scopes []string `json:"scopes"`
+}
+
+// APIKeyResponse - Структура ключа API в ответе.
+type APIKeyResponse struct {
+	// ID - Идентификатор ключа для отзыва.
+	ID string `json:"id"`
+	// Key - Сам ключ, возвращается только при создании.
+	Key string `json:"key,omitempty"`
+	// UserID - Пользователь, от имени которого действует ключ.
+	UserID string `json:"user_id"`
+	// Name - Название ключа.
+	Name string `json:"name"`
+	// Scopes - Разрешения ключа.
+	Scopes []services.Scope `json:"scopes"`
+	// CreatedAt - Время создания ключа.
+	CreatedAt time.Time `json:"created_at"`
+	// RevokedAt - Время отзыва ключа, отсутствует у действующих ключей.
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// APIKeysHandler - Обработчик создания, просмотра и отзыва ключей API. Доступен только из доверенной подсети.
+type APIKeysHandler struct {
+	keys APIKeyManager
+}
+
+// NewAPIKeysHandler - Создает новую структуру APIKeysHandler с указателем.
+func NewAPIKeysHandler(keys APIKeyManager) *APIKeysHandler {
+	handler := new(APIKeysHandler)
+	handler.keys = keys
+	return handler
+}
+
+// HandleCreate - Обрабатывает запрос создания ключа API.
+func (handler *APIKeysHandler) HandleCreate(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(res, "Http method not POST", http.StatusBadRequest)
+		return
+	}
+
+	var request CreateAPIKeyRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(res, "Failed to decode request body", http.StatusBadRequest)
+		return
+	}
+	scopes, err := services.ParseScopes(request.Scopes)
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created, err := handler.keys.CreateAPIKey(req.Context(), request.UserID, request.Name, scopes)
+	if err != nil {
+		log.Zap.Error("failed create api key", zap.Error(err))
+		http.Error(res, err.Error(), statusCodeByError(err))
+		return
+	}
+
+	result := toAPIKeyResponse(created.APIKey)
+	result.Key = created.Key
+	res.Header().Add(HeaderContentType, HeaderApplicationJSONValue)
+	res.Header().Set("Cache-Control", "no-store")
+	res.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(res).Encode(result); err != nil {
+		log.Zap.Error(ErrFailedWriteToResponse, zap.Error(err))
+	}
+}
+
+// HandleList - Обрабатывает запрос списка ключей API.
+func (handler *APIKeysHandler) HandleList(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(res, ValidateErrHTTPNotGET, http.StatusBadRequest)
+		return
+	}
+
+	keys, err := handler.keys.ListAPIKeys(req.Context())
+	if err != nil {
+		log.Zap.Error("failed list api keys", zap.Error(err))
+		http.Error(res, err.Error(), statusCodeByError(err))
+		return
+	}
+
+	result := make([]APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, toAPIKeyResponse(key))
+	}
+	res.Header().Add(HeaderContentType, HeaderApplicationJSONValue)
+	res.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(res).Encode(result); err != nil {
+		log.Zap.Error(ErrFailedWriteToResponse, zap.Error(err))
+	}
+}
+
+// HandleRevoke - Обрабатывает запрос отзыва ключа API.
+func (handler *APIKeysHandler) HandleRevoke(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodDelete {
+		http.Error(res, "Http method not DELETE", http.StatusBadRequest)
+		return
+	}
+
+	id := req.PathValue("id")
+	if id == "" {
+		http.Error(res, "Request path incorrect", http.StatusBadRequest)
+		return
+	}
+
+	if err := handler.keys.RevokeAPIKey(req.Context(), id); err != nil {
+		http.Error(res, err.Error(), statusCodeByError(err))
+		return
+	}
+	res.WriteHeader(http.StatusNoContent)
+}
+
+// toAPIKeyResponse - Преобразует ключ API в структуру ответа без самого ключа.
+func toAPIKeyResponse(key *services.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:        key.ID,
+		UserID:    key.UserID,
+		Name:      key.Name,
+		Scopes:    key.Scopes,
+		CreatedAt: key.CreatedAt,
+		RevokedAt: key.RevokedAt,
+	}
+}
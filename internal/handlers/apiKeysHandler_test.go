@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/VladSnap/shortener/internal/data/repos"
+	"github.com/VladSnap/shortener/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeysHandler(t *testing.T) {
+	keys := services.NewAPIKeyService(repos.NewInMemoryAPIKeyRepo())
+	handler := NewAPIKeysHandler(keys)
+
+	t.Run("Create", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.HandleCreate(rec, httptest.NewRequest(http.MethodPost, "/api/internal/keys",
+			strings.NewReader(`{"user_id":"user1","name":"importer","scopes":["create","read"]}`)))
+
+		require.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+		var resp APIKeyResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "user1", resp.UserID)
+		assert.Equal(t, []services.Scope{services.ScopeCreate, services.ScopeRead}, resp.Scopes)
+		key, err := keys.Authenticate(t.Context(), resp.Key)
+		require.NoError(t, err)
+		assert.Equal(t, resp.ID, key.ID)
+	})
+
+	t.Run("Unknown scope", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.HandleCreate(rec, httptest.NewRequest(http.MethodPost, "/api/internal/keys",
+			strings.NewReader(`{"name":"importer","scopes":["admin"]}`)))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("List and revoke", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.HandleList(rec, httptest.NewRequest(http.MethodGet, "/api/internal/keys", http.NoBody))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var list []APIKeyResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+		require.Len(t, list, 1)
+		assert.Empty(t, list[0].Key)
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/internal/keys/"+list[0].ID, http.NoBody)
+		req.SetPathValue("id", list[0].ID)
+		rec = httptest.NewRecorder()
+		handler.HandleRevoke(rec, req)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		req = httptest.NewRequest(http.MethodDelete, "/api/internal/keys/missing", http.NoBody)
+		req.SetPathValue("id", "missing")
+		rec = httptest.NewRecorder()
+		handler.HandleRevoke(rec, req)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
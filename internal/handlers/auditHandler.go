@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/log"
+	"github.com/VladSnap/shortener/internal/services"
+	"go.uber.org/zap"
+)
+
+// AuditReader - Интерфейс чтения журнала аудита действий со ссылками.
+type AuditReader interface {
+	// GetEvents - Получает события журнала аудита по параметрам выборки от новых к старым.
+	GetEvents(ctx context.Context, query *services.AuditQuery) ([]*services.AuditEvent, error)
+}
+
+// AuditEventResponse - Структура события журнала аудита в ответе.
+type AuditEventResponse struct {
+	// ID - Порядковый номер события.
+	ID int64 `json:"id"`
+	// Action - Действие: create, delete, restore или update.
+	Action services.AuditAction `json:"action"`
+	// ShortURL - Идентификатор сокращенной ссылки.
+	ShortURL string `json:"short_url"`
+	// UserID - Пользователь, выполнивший действие.
+	UserID string `json:"user_id"`
+	// Source - Интерфейс, через который выполнено действие: http или grpc.
+	Source services.AuditSource `json:"source"`
+	// ClientIP - IP адрес клиента.
+	ClientIP string `json:"client_ip,omitempty"`
+	// OriginalURL - Оригинальный URL ссылки после создания или изменения.
+	OriginalURL string `json:"original_url,omitempty"`
+	// CreatedAt - Время действия.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AuditHandler - Обработчик чтения журнала аудита. Доступен только из доверенной подсети.
+type AuditHandler struct {
+	audit AuditReader
+}
+
+// NewAuditHandler - Создает новую структуру AuditHandler с указателем.
+func NewAuditHandler(audit AuditReader) *AuditHandler {
+	handler := new(AuditHandler)
+	handler.audit = audit
+	return handler
+}
+
+// Handle - Обрабатывает запрос событий журнала аудита.
+// Параметры user_id, from, to (RFC 3339) и limit строки запроса ограничивают выборку.
+func (handler *AuditHandler) Handle(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(res, ValidateErrHTTPNotGET, http.StatusBadRequest)
+		return
+	}
+
+	query, err := parseAuditQuery(req.URL.Query())
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	events, err := handler.audit.GetEvents(req.Context(), query)
+	if err != nil {
+		log.Zap.Error("failed get audit events", zap.Error(err))
+		http.Error(res, err.Error(), statusCodeByError(err))
+		return
+	}
+
+	result := make([]AuditEventResponse, 0, len(events))
+	for _, event := range events {
+		result = append(result, AuditEventResponse{
+			ID:          event.ID,
+			Action:      event.Action,
+			ShortURL:    event.ShortURL,
+			UserID:      event.UserID,
+			Source:      event.Source,
+			ClientIP:    event.ClientIP,
+			OriginalURL: event.OriginalURL,
+			CreatedAt:   event.CreatedAt,
+		})
+	}
+	res.Header().Add(HeaderContentType, HeaderApplicationJSONValue)
+	res.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(res).Encode(result); err != nil {
+		log.Zap.Error(ErrFailedWriteToResponse, zap.Error(err))
+	}
+}
+
+// parseAuditQuery - Читает параметры выборки журнала аудита из строки запроса.
+func parseAuditQuery(values url.Values) (*services.AuditQuery, error) {
+	query := &services.AuditQuery{UserID: values.Get("user_id")}
+	var err error
+	if query.From, err = parseAuditTime(values, "from"); err != nil {
+		return nil, err
+	}
+	if query.To, err = parseAuditTime(values, "to"); err != nil {
+		return nil, err
+	}
+	if values.Has(queryParamLimit) {
+		limit, err := strconv.Atoi(values.Get(queryParamLimit))
+		if err != nil || limit <= 0 {
+			return nil, errors.New("limit must be a positive integer")
+		}
+		query.Limit = limit
+	}
+	return query, nil
+}
+
+// parseAuditTime - Читает время в формате RFC 3339 из параметра строки запроса, если он задан.
+func parseAuditTime(values url.Values, name string) (time.Time, error) {
+	if !values.Has(name) {
+		return time.Time{}, nil
+	}
+	value, err := time.Parse(time.RFC3339, values.Get(name))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be a time in RFC 3339 format", name)
+	}
+	return value, nil
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/VladSnap/shortener/internal/data/repos"
+	"github.com/VladSnap/shortener/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditHandler(t *testing.T) {
+	audit := services.NewAuditLog(repos.NewInMemoryAuditRepo())
+	handler := NewAuditHandler(audit)
+	ctx := services.WithAuditActor(t.Context(),
+		services.AuditActor{Source: services.AuditSourceHTTP, ClientIP: "10.0.0.1"})
+	audit.Record(ctx, []*services.AuditEvent{
+		{Action: services.AuditActionCreate, ShortURL: "link0001", UserID: "user1", OriginalURL: "http://a.url"},
+		{Action: services.AuditActionCreate, ShortURL: "link0002", UserID: "user2", OriginalURL: "http://b.url"},
+		{Action: services.AuditActionDelete, ShortURL: "link0001", UserID: "user1"},
+	})
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.Handle(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	t.Run("Filter by user", func(t *testing.T) {
+		rec := get("/api/internal/audit?user_id=user1")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp []AuditEventResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Len(t, resp, 2)
+		assert.Equal(t, services.AuditActionDelete, resp[0].Action)
+		assert.Equal(t, "http://a.url", resp[1].OriginalURL)
+		assert.Equal(t, services.AuditSourceHTTP, resp[1].Source)
+		assert.Equal(t, "10.0.0.1", resp[1].ClientIP)
+	})
+
+	t.Run("Time range", func(t *testing.T) {
+		rec := get("/api/internal/audit?to=2000-01-01T00:00:00Z")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, "[]", rec.Body.String())
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		rec := get("/api/internal/audit?limit=1")
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp []AuditEventResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Len(t, resp, 1)
+	})
+
+	t.Run("Invalid query", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get("/api/internal/audit?from=yesterday").Code)
+		assert.Equal(t, http.StatusBadRequest, get("/api/internal/audit?limit=0").Code)
+		assert.Equal(t, http.StatusBadRequest, get("/api/internal/audit?limit=100000").Code)
+		assert.Equal(t, http.StatusBadRequest,
+			get("/api/internal/audit?from=2025-01-02T00:00:00Z&to=2025-01-01T00:00:00Z").Code)
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/VladSnap/shortener/internal/constants"
 	"github.com/VladSnap/shortener/internal/log"
@@ -19,14 +21,24 @@ type ShortenRowRequest struct {
 	CorrelationID string `json:"correlation_id"`
 	// OriginalURL - Оригинальный полный URL.
 	OriginalURL string `json:"original_url"`
+	// Alias - Желаемый идентификатор сокращенной ссылки, необязательный.
+	Alias string `json:"alias,omitempty"`
+	// TTL - Время жизни ссылки в секундах, необязательный.
+	TTL int64 `json:"ttl,omitempty"`
+	// ExpiresAt - Время окончания действия ссылки в формате RFC 3339, необязательный.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
 // ShortenRowResponse - Структура ответа для BatchHandler.
 type ShortenRowResponse struct {
 	// CorrelationID - Идентификатор пачки.
 	CorrelationID string `json:"correlation_id"`
-	// ShortURL - Сокращенный URL.
-	ShortURL string `json:"short_url"`
+	// ShortURL - Сокращенный URL, пустой, если ссылка не создана.
+	ShortURL string `json:"short_url,omitempty"`
+	// Status - Результат обработки ссылки: created, duplicate или error.
+	Status string `json:"status"`
+	// Error - Причина, по которой ссылка не создана.
+	Error string `json:"error,omitempty"`
 }
 
 // BatchHandler - Обработчик запроса сокращения пачки ссылок.
@@ -57,6 +69,15 @@ func (handler *BatchHandler) Handle(res http.ResponseWriter, req *http.Request)
 		return
 	}
 
+	partial := false
+	if value := req.URL.Query().Get("partial"); value != "" {
+		var err error
+		if partial, err = strconv.ParseBool(value); err != nil {
+			http.Error(res, "Invalid partial query parameter: "+value, http.StatusBadRequest)
+			return
+		}
+	}
+
 	var requestRows []ShortenRowRequest
 
 	if err := json.NewDecoder(req.Body).Decode(&requestRows); err != nil {
@@ -64,20 +85,40 @@ func (handler *BatchHandler) Handle(res http.ResponseWriter, req *http.Request)
 		return
 	}
 
+	responseRows := make([]*ShortenRowResponse, len(requestRows))
 	links := make([]*services.OriginalLink, 0, len(requestRows))
-	for _, r := range requestRows {
+	// linkRows - Индексы строк запроса, переданных в сервис.
+	linkRows := make([]int, 0, len(requestRows))
+	for i, r := range requestRows {
 		r.OriginalURL = strings.TrimSuffix(r.OriginalURL, "\r")
 		r.OriginalURL = strings.TrimSuffix(r.OriginalURL, "\n")
-		if err := validation.ValidateURL(r.OriginalURL, "OriginalURL"); err != nil {
-			http.Error(res, err.Error(), http.StatusBadRequest)
-			return
+		err := validation.ValidateURL(r.OriginalURL, "OriginalURL")
+		var ttl time.Duration
+		if err == nil {
+			ttl, err = services.TTLFromSeconds(r.TTL)
+		}
+		if err != nil {
+			if !partial {
+				http.Error(res, err.Error(), http.StatusBadRequest)
+				return
+			}
+			responseRows[i] = &ShortenRowResponse{
+				CorrelationID: r.CorrelationID,
+				Status:        string(services.BatchItemFailed),
+				Error:         err.Error(),
+			}
+			continue
 		}
 
 		lin := &services.OriginalLink{
 			CorelationID: r.CorrelationID,
 			URL:          r.OriginalURL,
+			Alias:        r.Alias,
+			TTL:          ttl,
+			ExpiresAt:    r.ExpiresAt,
 		}
 		links = append(links, lin)
+		linkRows = append(linkRows, i)
 	}
 
 	if len(requestRows) == 0 {
@@ -89,21 +130,25 @@ func (handler *BatchHandler) Handle(res http.ResponseWriter, req *http.Request)
 	if value, ok := req.Context().Value(constants.UserIDContextKey).(string); ok {
 		userID = value
 	}
-	shortedLinks, err := handler.service.CreateShortLinkBatch(req.Context(), links, userID)
+	shortedLinks, err := handler.service.CreateShortLinkBatch(req.Context(), links, userID, partial)
 
 	if err != nil {
-		http.Error(res, fmt.Errorf("failed CreateShortLinkBatch: %w", err).Error(), http.StatusInternalServerError)
+		http.Error(res, fmt.Errorf("failed CreateShortLinkBatch: %w", err).Error(), statusCodeByError(err))
 		return
 	}
 
-	responseRows := make([]*ShortenRowResponse, 0, len(shortedLinks))
-	for _, sl := range shortedLinks {
+	for k, sl := range shortedLinks {
 		rr := &ShortenRowResponse{
 			CorrelationID: sl.CorelationID,
-			ShortURL:      handler.baseURL + "/" + sl.URL,
+			Status:        string(sl.Status),
+		}
+		if sl.Err != nil {
+			rr.Error = sl.Err.Error()
+		} else {
+			rr.ShortURL = handler.baseURL + "/" + sl.URL
 		}
 
-		responseRows = append(responseRows, rr)
+		responseRows[linkRows[k]] = rr
 	}
 
 	res.Header().Add(HeaderContentType, HeaderApplicationJSONValue)
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/VladSnap/shortener/internal/constants"
 	m "github.com/VladSnap/shortener/internal/handlers/mocks"
@@ -44,8 +47,8 @@ func TestBatchHandler_Handle(t *testing.T) {
 				code:        201,
 				contentType: HeaderApplicationJSONValue,
 				shortedURLs: []ShortenRowResponse{
-					{CorrelationID: "crid1", ShortURL: baseURL + "/hbFgvtUO"},
-					{CorrelationID: "crid2", ShortURL: baseURL + "/fVdpTFBo"},
+					{CorrelationID: "crid1", ShortURL: baseURL + "/hbFgvtUO", Status: "created"},
+					{CorrelationID: "crid2", ShortURL: baseURL + "/fVdpTFBo", Status: "created"},
 				},
 			},
 		}, {
@@ -76,10 +79,11 @@ func TestBatchHandler_Handle(t *testing.T) {
 			for i, s := range tt.shortIDs {
 				shortedLinks = append(shortedLinks, &services.ShortedLink{
 					URL:          s,
-					CorelationID: tt.originalURLs[i].CorrelationID})
+					CorelationID: tt.originalURLs[i].CorrelationID,
+					Status:       services.BatchItemCreated})
 			}
 			links := convertToShortLink(tt.originalURLs)
-			mockService.EXPECT().CreateShortLinkBatch(ctx, links, userID).
+			mockService.EXPECT().CreateShortLinkBatch(ctx, links, userID, false).
 				Return(shortedLinks, nil).
 				AnyTimes()
 
@@ -113,6 +117,54 @@ func TestBatchHandler_Handle(t *testing.T) {
 	}
 }
 
+func TestBatchHandler_HandlePartial(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := m.NewMockShorterService(ctrl)
+	batchHandler := NewBatchHandler(mockService, baseURL)
+	userID := "d1a8485a-430a-49f4-92ba-50886e1b07c6"
+	ctx := context.WithValue(t.Context(), constants.UserIDContextKey, userID)
+
+	requestRows := []ShortenRowRequest{
+		{CorrelationID: "crid1", OriginalURL: "http://test1.url/"},
+		{CorrelationID: "crid2", OriginalURL: "google.com"},
+		{CorrelationID: "crid3", OriginalURL: "http://test3.url/"},
+		{CorrelationID: "crid4", OriginalURL: "http://test4.url/", Alias: "taken"},
+		{CorrelationID: "crid5", OriginalURL: "http://test5.url/", TTL: math.MaxInt64},
+	}
+	validRows := []ShortenRowRequest{requestRows[0], requestRows[2], requestRows[3]}
+	links := convertToShortLink(validRows)
+	links[2].Alias = "taken"
+	mockService.EXPECT().CreateShortLinkBatch(ctx, links, userID, true).Return([]*services.ShortedLink{
+		{CorelationID: "crid1", URL: "hbFgvtUO", Status: services.BatchItemCreated},
+		{CorelationID: "crid3", URL: "fVdpTFBo", IsDuplicated: true, Status: services.BatchItemDuplicate},
+		{CorelationID: "crid4", Status: services.BatchItemFailed, Err: services.ErrAliasTaken},
+	}, nil)
+
+	var bufReq bytes.Buffer
+	require.NoError(t, json.NewEncoder(&bufReq).Encode(requestRows))
+	postRequest := httptest.NewRequest(http.MethodPost, "/api/shorten/batch?partial=true", &bufReq)
+	postRequest.Header.Add(HeaderContentType, HeaderApplicationJSONValue)
+	postRequest = postRequest.WithContext(ctx)
+	w := httptest.NewRecorder()
+	batchHandler.Handle(w, postRequest)
+	res := w.Result()
+	defer func() { assert.NoError(t, res.Body.Close()) }()
+
+	require.Equal(t, http.StatusCreated, res.StatusCode)
+	var responseRows []ShortenRowResponse
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&responseRows))
+	assert.Equal(t, []ShortenRowResponse{
+		{CorrelationID: "crid1", ShortURL: baseURL + "/hbFgvtUO", Status: "created"},
+		{CorrelationID: "crid2", Status: "error",
+			Error: "incorrect format OriginalURL: parse \"google.com\": invalid URI for request"},
+		{CorrelationID: "crid3", ShortURL: baseURL + "/fVdpTFBo", Status: "duplicate"},
+		{CorrelationID: "crid4", Status: "error", Error: services.ErrAliasTaken.Error()},
+		{CorrelationID: "crid5", Status: "error", Error: fmt.Sprintf(
+			"invalid expiration: ttl should not exceed %d seconds", int64(constants.MaxLinkTTL/time.Second))},
+	}, responseRows)
+}
+
 func sliceEquals[T ShortenRowResponse](a, b []T) bool {
 	if len(a) != len(b) {
 		return false
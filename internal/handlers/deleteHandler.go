@@ -5,15 +5,25 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
-	"time"
 
 	_ "net/http/pprof" // подключаем пакет pprof
 
 	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/log"
 	"github.com/VladSnap/shortener/internal/services"
 	"github.com/VladSnap/shortener/internal/validation"
+	"go.uber.org/zap"
 )
 
+// DeleteJobPath - Путь, по которому доступен статус запроса на удаление, без идентификатора запроса.
+const DeleteJobPath = "/api/user/urls/delete-jobs/"
+
+// DeleteResponse - Структура ответа для DeleteHandler.
+type DeleteResponse struct {
+	// JobID - Идентификатор запроса на удаление для получения его статуса.
+	JobID string `json:"job_id"`
+}
+
 // DeleteHandler - Обработчик запроса удаления сокращенной ссылки.
 type DeleteHandler struct {
 	deleteWorker DeleterWorker
@@ -24,7 +34,8 @@ type DeleteHandler struct {
 // DeleterWorker - Интерфейс воркера который в фоне занимается удалением сокращенных ссылок.
 type DeleterWorker interface {
 	Close() error
-	AddToDelete(shortIDs chan services.DeleteShortID)
+	AddToDelete(ctx context.Context, shortIDs []services.DeleteShortID) (string, error)
+	GetDeleteJob(ctx context.Context, jobID string, userID string) (*services.DeleteJob, error)
 	RunWork()
 }
 
@@ -71,29 +82,24 @@ func (handler *DeleteHandler) Handle(res http.ResponseWriter, req *http.Request)
 		userID = value
 	}
 
-	const toDeleteChanSize = 100
-
-	toDeleteChan := make(chan services.DeleteShortID, toDeleteChanSize)
-	defer close(toDeleteChan)
-
-	const cancelTimeoutSec = 2
-	// Создаем контекст с таймаутом 2 секунды
-	ctx, cancel := context.WithTimeout(context.Background(), cancelTimeoutSec*time.Second)
-	defer cancel()
-
-rng:
+	toDelete := make([]services.DeleteShortID, 0, len(shortURLs))
 	for _, url := range shortURLs {
-		select {
-		case <-ctx.Done():
-			break rng // Выйдем из цикла, если мы не уложились в таймаут записи данных, канал автоматически закроется.
-		default:
-			deleteSID := services.NewDeleteShortID(url, userID)
-			toDeleteChan <- deleteSID
-		}
+		toDelete = append(toDelete, services.NewDeleteShortID(url, userID))
 	}
 
-	handler.deleteWorker.AddToDelete(toDeleteChan)
+	jobID, err := handler.deleteWorker.AddToDelete(req.Context(), toDelete)
+	if err != nil {
+		log.Zap.Error("failed add short links to delete", zap.Error(err))
+		http.Error(res, "Failed to delete short links", http.StatusInternalServerError)
+		return
+	}
 
 	res.Header().Add(HeaderContentType, HeaderApplicationJSONValue)
+	res.Header().Set("Location", DeleteJobPath+jobID)
 	res.WriteHeader(http.StatusAccepted)
+	err = json.NewEncoder(res).Encode(DeleteResponse{JobID: jobID})
+	if err != nil {
+		log.Zap.Error(ErrFailedWriteToResponse, zap.Error(err))
+		return
+	}
 }
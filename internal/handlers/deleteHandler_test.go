@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/VladSnap/shortener/internal/constants"
 	m "github.com/VladSnap/shortener/internal/handlers/mocks"
+	"github.com/VladSnap/shortener/internal/services"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
@@ -68,7 +70,7 @@ func TestDeleteHandler_Handle(t *testing.T) {
 	})
 
 	t.Run("Invalid Short URL", func(t *testing.T) {
-		body := strings.NewReader(`["invalid-url"]`)
+		body := strings.NewReader(`["invalid/url"]`)
 		req := httptest.NewRequest(http.MethodDelete, "/delete", body)
 		req.Header.Set("Content-Type", "application/json")
 		rec := httptest.NewRecorder()
@@ -76,7 +78,7 @@ func TestDeleteHandler_Handle(t *testing.T) {
 		handler.Handle(rec, req)
 
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
-		assert.Contains(t, rec.Body.String(), "shortURL length should be 8\n")
+		assert.Contains(t, rec.Body.String(), "shortURL should not contain '/', '?' or '#'\n")
 	})
 
 	t.Run("Successful Request", func(t *testing.T) {
@@ -90,11 +92,28 @@ func TestDeleteHandler_Handle(t *testing.T) {
 
 		rec := httptest.NewRecorder()
 
-		mockWorker.EXPECT().AddToDelete(gomock.Any()).Times(1)
+		mockWorker.EXPECT().AddToDelete(gomock.Any(), []services.DeleteShortID{
+			{ShortURL: "gvFrtGrB", UserID: "test-user-id"},
+			{ShortURL: "rFvBHOug", UserID: "test-user-id"},
+		}).Return("job-1", nil).Times(1)
 
 		handler.Handle(rec, req)
 
 		assert.Equal(t, http.StatusAccepted, rec.Code)
 		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "/api/user/urls/delete-jobs/job-1", rec.Header().Get("Location"))
+		assert.JSONEq(t, `{"job_id":"job-1"}`, rec.Body.String())
+	})
+	t.Run("Queue Error", func(t *testing.T) {
+		bodyBytes, _ := json.Marshal([]string{"gvFrtGrB"})
+		req := httptest.NewRequest(http.MethodDelete, "/api/user/urls", bytes.NewReader(bodyBytes))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		mockWorker.EXPECT().AddToDelete(gomock.Any(), gomock.Any()).Return("", errors.New("queue error")).Times(1)
+
+		handler.Handle(rec, req)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 	})
 }
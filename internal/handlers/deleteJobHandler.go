@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/log"
+	"go.uber.org/zap"
+)
+
+// DeleteJobItemResponse - Результат удаления одной ссылки из запроса на удаление.
+type DeleteJobItemResponse struct {
+	// ShortURL - Идентификатор сокращенной ссылки из запроса на удаление.
+	ShortURL string `json:"short_url"`
+	// Outcome - Результат: pending, deleted, not_found, not_owned или failed.
+	Outcome string `json:"outcome"`
+	// Error - Ошибка последней неудачной попытки удаления.
+	Error string `json:"error,omitempty"`
+}
+
+// DeleteJobResponse - Структура ответа для DeleteJobHandler.
+type DeleteJobResponse struct {
+	// JobID - Идентификатор запроса на удаление.
+	JobID string `json:"job_id"`
+	// Status - Статус запроса: pending, completed или failed.
+	Status string `json:"status"`
+	// CreatedAt - Время создания запроса.
+	CreatedAt time.Time `json:"created_at"`
+	// Items - Результаты по каждой ссылке в порядке запроса.
+	Items []DeleteJobItemResponse `json:"items"`
+}
+
+// DeleteJobHandler - Обработчик запроса статуса запроса на удаление сокращенных ссылок.
+type DeleteJobHandler struct {
+	deleteWorker DeleterWorker
+}
+
+// NewDeleteJobHandler - Создает новую структуру DeleteJobHandler с указателем.
+func NewDeleteJobHandler(deleteWorker DeleterWorker) *DeleteJobHandler {
+	handler := new(DeleteJobHandler)
+	handler.deleteWorker = deleteWorker
+	return handler
+}
+
+// Handle - Обрабатывает входящий запрос.
+func (handler *DeleteJobHandler) Handle(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(res, ValidateErrHTTPNotGET, http.StatusBadRequest)
+		return
+	}
+
+	jobID := req.PathValue("id")
+	if jobID == "" {
+		http.Error(res, "Request path incorrect", http.StatusBadRequest)
+		return
+	}
+
+	userID := ""
+	if value, ok := req.Context().Value(constants.UserIDContextKey).(string); ok {
+		userID = value
+	}
+	job, err := handler.deleteWorker.GetDeleteJob(req.Context(), jobID, userID)
+	if err != nil {
+		http.Error(res, err.Error(), statusCodeByError(err))
+		return
+	}
+
+	result := DeleteJobResponse{
+		JobID:     job.ID,
+		Status:    string(job.Status),
+		CreatedAt: job.CreatedAt,
+		Items:     make([]DeleteJobItemResponse, 0, len(job.Items)),
+	}
+	for _, item := range job.Items {
+		result.Items = append(result.Items, DeleteJobItemResponse{
+			ShortURL: item.ShortURL,
+			Outcome:  string(item.Outcome),
+			Error:    item.Error,
+		})
+	}
+
+	res.Header().Add(HeaderContentType, HeaderApplicationJSONValue)
+	res.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(res).Encode(result)
+
+	if err != nil {
+		log.Zap.Error(ErrFailedWriteToResponse, zap.Error(err))
+		return
+	}
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	m "github.com/VladSnap/shortener/internal/handlers/mocks"
+	"github.com/VladSnap/shortener/internal/services"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteJobHandler_Handle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockWorker := m.NewMockDeleterWorker(ctrl)
+	handler := NewDeleteJobHandler(mockWorker)
+
+	newRequest := func(method, id string) *http.Request {
+		req := httptest.NewRequest(method, DeleteJobPath+id, http.NoBody)
+		req.SetPathValue("id", id)
+		ctx := context.WithValue(req.Context(), constants.UserIDContextKey, "user1")
+		return req.WithContext(ctx)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		createdAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+		mockWorker.EXPECT().GetDeleteJob(gomock.Any(), "job-1", "user1").Return(&services.DeleteJob{
+			ID:        "job-1",
+			Status:    services.DeleteJobCompleted,
+			CreatedAt: createdAt,
+			Items: []services.DeleteJobItem{
+				{ShortURL: "abc", Outcome: services.DeleteOutcomeDeleted},
+				{ShortURL: "missing", Outcome: services.DeleteOutcomeNotFound},
+				{ShortURL: "foreign", Outcome: services.DeleteOutcomeNotOwned},
+			},
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodGet, "job-1"))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"job_id":"job-1","status":"completed","created_at":"2025-03-01T10:00:00Z",`+
+			`"items":[{"short_url":"abc","outcome":"deleted"},{"short_url":"missing","outcome":"not_found"},`+
+			`{"short_url":"foreign","outcome":"not_owned"}]}`, rec.Body.String())
+	})
+
+	t.Run("Invalid HTTP Method", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodPost, "job-1"))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Job not found", func(t *testing.T) {
+		mockWorker.EXPECT().GetDeleteJob(gomock.Any(), "job-2", "user1").
+			Return(nil, fmt.Errorf("%w: 'job-2'", services.ErrDeleteJobNotFound))
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodGet, "job-2"))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -2,69 +2,325 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/data"
 	"github.com/VladSnap/shortener/internal/log"
 	"github.com/VladSnap/shortener/internal/services"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
 // Эти константы нужны, чтобы линтер не ругался на магические числа. В конфиг не вижу смысла выносить это.
 const flushToDBIntervalSec = 5
-const fanInChanSize = 10
+const deleteBatchSize = 1000
+
+// stuckFlushIntervals - Сколько интервалов сброса буфера горутина воркера может не отвечать,
+// прежде чем считаться зависшей.
+const stuckFlushIntervals = 3
 
 // DeleterWorkerImpl - Реализация воркера для удаления сокращенных ссылок интерфейса DeleterWorker.
+// Запросы на удаление хранятся в очереди DeleteQueue, воркер периодически выполняет готовые задачи,
+// повторяет неудачные с экспоненциальной задержкой и переносит в dead letter исчерпавшие попытки.
+// Результаты выполненных задач хранятся в очереди в течение constants.DeleteJobRetention,
+// чтобы клиент мог узнать статус своего запроса на удаление.
 type DeleterWorkerImpl struct {
-	fanInChan      chan chan services.DeleteShortID
 	shorterService ShorterService
-	buffer         []services.DeleteShortID
+	queue          services.DeleteQueue
+	metrics        MetricsRecorder
+	audit          *services.AuditLog
+	stop           chan struct{}
+	done           chan struct{}
+	closeOnce      sync.Once
+	maxAttempts    int
+	retryBackoff   time.Duration
+	// flushMu - Не дает горутине воркера и Close выполнять одни и те же задачи одновременно.
+	flushMu sync.Mutex
+	// running - Горутина воркера запущена и еще не завершилась.
+	running atomic.Bool
+	// heartbeat - Время последней итерации цикла воркера в наносекундах Unix.
+	heartbeat atomic.Int64
+}
+
+// DeleteWorkerOption - Функция для настройки DeleterWorkerImpl.
+type DeleteWorkerOption func(*DeleterWorkerImpl)
+
+// WithDeleteWorkerMetrics - Устанавливает получателя метрик буфера воркера удаления.
+func WithDeleteWorkerMetrics(metrics MetricsRecorder) DeleteWorkerOption {
+	return func(worker *DeleterWorkerImpl) {
+		worker.metrics = metrics
+	}
+}
+
+// WithDeleteWorkerAudit - Устанавливает журнал аудита, в который записываются удаленные ссылки.
+func WithDeleteWorkerAudit(audit *services.AuditLog) DeleteWorkerOption {
+	return func(worker *DeleterWorkerImpl) {
+		worker.audit = audit
+	}
+}
+
+// WithDeleteRetryPolicy - Устанавливает количество попыток удаления и задержку перед первой повторной попыткой.
+func WithDeleteRetryPolicy(maxAttempts int, backoff time.Duration) DeleteWorkerOption {
+	return func(worker *DeleterWorkerImpl) {
+		worker.maxAttempts = maxAttempts
+		worker.retryBackoff = backoff
+	}
 }
 
 // NewDeleteWorker - Создает новую структуру DeleterWorkerImpl с указателем.
-func NewDeleteWorker(shorterService ShorterService) *DeleterWorkerImpl {
-	return &DeleterWorkerImpl{
-		fanInChan:      make(chan chan services.DeleteShortID, fanInChanSize),
+func NewDeleteWorker(shorterService ShorterService, queue services.DeleteQueue,
+	options ...DeleteWorkerOption) *DeleterWorkerImpl {
+	worker := &DeleterWorkerImpl{
 		shorterService: shorterService,
+		queue:          queue,
+		metrics:        noopMetrics{},
+		stop:           make(chan struct{}),
+		done:           make(chan struct{}),
+		maxAttempts:    constants.DeleteMaxAttempts,
+		retryBackoff:   constants.DeleteRetryBackoff,
+	}
+	for _, option := range options {
+		option(worker)
 	}
+	return worker
 }
 
 // RunWork - Запускает горутину воркера, которая в фоне выполняет удаление сокращенных ссылок.
 func (worker *DeleterWorkerImpl) RunWork() {
-	// Таймер сброса буфера сообщений.
+	// Таймер выполнения задач из очереди.
 	ticker := time.NewTicker(flushToDBIntervalSec * time.Second)
+	worker.heartbeat.Store(time.Now().UnixNano())
+	worker.running.Store(true)
 
 	go func() {
+		defer close(worker.done)
+		defer worker.running.Store(false)
+		defer ticker.Stop()
 		for {
+			worker.heartbeat.Store(time.Now().UnixNano())
 			select {
-			case deleteShorts := <-worker.fanInChan:
-				for ds := range deleteShorts {
-					worker.buffer = append(worker.buffer, ds)
-				}
+			case <-worker.stop:
+				return
 			case <-ticker.C:
-				if len(worker.buffer) == 0 {
-					continue
-				}
-
-				err := worker.shorterService.DeleteBatch(context.Background(), worker.buffer)
-				if err != nil {
-					log.Zap.Error("failed DeleteBatch", zap.Error(err))
-					continue
-				}
-
-				worker.buffer = nil
+				worker.flush(context.Background(), time.Now())
+				worker.deleteExpiredJobs(context.Background())
 			}
 		}
 	}()
 }
 
-// AddToDelete - Добавляет канал с идентификаторами сокращенных ссылок для
-// потокобезопасного удаления используя паттерн FanIn.
-func (worker *DeleterWorkerImpl) AddToDelete(shortIDs chan services.DeleteShortID) {
-	worker.fanInChan <- shortIDs
+// CheckHealth - Проверяет, что горутина воркера запущена и не зависла, например, на удалении в хранилище.
+func (worker *DeleterWorkerImpl) CheckHealth(_ context.Context) error {
+	if !worker.running.Load() {
+		return errors.New("delete worker is not running")
+	}
+	idle := time.Since(time.Unix(0, worker.heartbeat.Load()))
+	if idle > stuckFlushIntervals*flushToDBIntervalSec*time.Second {
+		return fmt.Errorf("delete worker has not responded for %s", idle.Round(time.Second))
+	}
+	return nil
 }
 
-// Close - Останавливает воркер, чтобы остановить приложение по graceful shutdown.
+// AddToDelete - Сохраняет в очереди запрос на удаление сокращенных ссылок и возвращает его идентификатор.
+// После успешного возврата запрос не теряется при остановке сервиса, если очередь хранится на диске или в БД.
+// Источник запроса из контекста сохраняется в задачах, чтобы записать его в журнал аудита после удаления.
+func (worker *DeleterWorkerImpl) AddToDelete(ctx context.Context, shortIDs []services.DeleteShortID) (
+	string, error) {
+	toEnqueue := make([]data.DeleteShortData, 0, len(shortIDs))
+	for _, sid := range shortIDs {
+		toEnqueue = append(toEnqueue, data.NewDeleteShortData(sid.ShortURL, sid.UserID))
+	}
+	actor := services.AuditActorFromContext(ctx)
+	jobID := uuid.NewString()
+	err := worker.queue.Enqueue(ctx, jobID, toEnqueue,
+		data.AuditActorData{Source: string(actor.Source), ClientIP: actor.ClientIP}, time.Now())
+	if err != nil {
+		return "", fmt.Errorf("failed enqueue short links to delete: %w", err)
+	}
+	worker.updatePending(ctx)
+	return jobID, nil
+}
+
+// GetDeleteJob - Получает статус запроса на удаление, созданного пользователем.
+// Запросы других пользователей и запросы, результаты которых уже не хранятся, не находятся.
+func (worker *DeleterWorkerImpl) GetDeleteJob(ctx context.Context, jobID string, userID string) (
+	*services.DeleteJob, error) {
+	tasks, err := worker.queue.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed get delete job from queue: %w", err)
+	}
+	if len(tasks) == 0 || tasks[0].UserID != userID {
+		return nil, fmt.Errorf("%w: '%s'", services.ErrDeleteJobNotFound, jobID)
+	}
+	return services.NewDeleteJob(jobID, tasks), nil
+}
+
+// Close - Останавливает воркер и выполняет накопленные задачи, чтобы остановить приложение по graceful shutdown.
+// Задачи, которые не удалось выполнить, остаются в очереди до следующего запуска.
 func (worker *DeleterWorkerImpl) Close() error {
-	close(worker.fanInChan)
+	worker.closeOnce.Do(func() {
+		close(worker.stop)
+		if worker.running.Load() {
+			<-worker.done
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), constants.DeleteDrainTimeout)
+		defer cancel()
+		// При остановке выполняются и задачи, ожидающие повторной попытки.
+		worker.flush(ctx, time.Now().Add(constants.DeleteRetryMaxBackoff))
+
+		pending, err := worker.queue.CountPending(ctx)
+		if err != nil {
+			log.Zap.Error("failed count pending delete tasks", zap.Error(err))
+			return
+		}
+		if pending > 0 {
+			log.Zap.Warn("delete tasks remain in queue after shutdown", zap.Int("pending", pending))
+		}
+	})
 	return nil
 }
+
+// flush - Выполняет пачками задачи очереди, время выполнения которых не позже dueBefore.
+func (worker *DeleterWorkerImpl) flush(ctx context.Context, dueBefore time.Time) {
+	worker.flushMu.Lock()
+	defer worker.flushMu.Unlock()
+	defer worker.updatePending(ctx)
+
+	// Неудачные задачи откладываются на время после now, поэтому повторно в этом цикле не выбираются,
+	// кроме случая остановки воркера, когда dueBefore сдвинут в будущее.
+	attempted := make(map[int64]struct{})
+	for {
+		tasks, err := worker.queue.FetchDue(ctx, dueBefore, deleteBatchSize)
+		if err != nil {
+			log.Zap.Error("failed fetch delete tasks", zap.Error(err))
+			return
+		}
+		tasks = skipAttempted(tasks, attempted)
+		if len(tasks) == 0 {
+			return
+		}
+
+		worker.runBatch(ctx, tasks)
+		if len(tasks) < deleteBatchSize {
+			return
+		}
+	}
+}
+
+// runBatch - Удаляет ссылки пачки задач и сохраняет результат в очереди.
+func (worker *DeleterWorkerImpl) runBatch(ctx context.Context, tasks []*data.DeleteTaskData) {
+	shortIDs := make([]services.DeleteShortID, 0, len(tasks))
+	for _, task := range tasks {
+		shortIDs = append(shortIDs, services.NewDeleteShortID(task.ShortURL, task.UserID))
+	}
+
+	results, err := worker.shorterService.DeleteBatch(ctx, shortIDs)
+	if err == nil {
+		completedAt := time.Now()
+		for i, task := range tasks {
+			task.Outcome = string(results[i].Outcome)
+			task.CompletedAt = &completedAt
+		}
+		if err = worker.queue.Complete(ctx, tasks); err != nil {
+			// Задачи будут выполнены повторно, удаление ссылок идемпотентно.
+			log.Zap.Error("failed complete delete tasks", zap.Error(err))
+			return
+		}
+		worker.audit.Record(ctx, deletedAuditEvents(tasks))
+		return
+	}
+
+	log.Zap.Error("failed DeleteBatch", zap.Error(err))
+	worker.metrics.AddDeleteFlushFailure()
+
+	now := time.Now()
+	deadLetters := 0
+	for _, task := range tasks {
+		task.Attempts++
+		task.LastError = err.Error()
+		if task.Attempts >= worker.maxAttempts {
+			deadAt := now
+			task.DeadAt = &deadAt
+			deadLetters++
+			log.Zap.Error("delete task moved to dead letters",
+				zap.Int64("id", task.ID),
+				zap.String("short_url", task.ShortURL),
+				zap.String("user_id", task.UserID),
+				zap.Int("attempts", task.Attempts))
+			continue
+		}
+		task.NextAttemptAt = now.Add(worker.backoff(task.Attempts))
+	}
+	if deadLetters > 0 {
+		worker.metrics.AddDeleteDeadLetters(deadLetters)
+	}
+
+	if err = worker.queue.Fail(ctx, tasks); err != nil {
+		log.Zap.Error("failed save delete tasks state", zap.Error(err))
+	}
+}
+
+// backoff - Возвращает задержку перед следующей попыткой, удваивая ее после каждой неудачной попытки.
+func (worker *DeleterWorkerImpl) backoff(attempts int) time.Duration {
+	delay := worker.retryBackoff
+	for i := 1; i < attempts && delay < constants.DeleteRetryMaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, constants.DeleteRetryMaxBackoff)
+}
+
+// deleteExpiredJobs - Удаляет из очереди результаты запросов, выполненных раньше срока хранения.
+func (worker *DeleterWorkerImpl) deleteExpiredJobs(ctx context.Context) {
+	if err := worker.queue.DeleteCompleted(ctx, time.Now().Add(-constants.DeleteJobRetention)); err != nil {
+		log.Zap.Error("failed delete completed delete tasks", zap.Error(err))
+	}
+}
+
+// updatePending - Обновляет метрику количества задач, ожидающих выполнения.
+func (worker *DeleterWorkerImpl) updatePending(ctx context.Context) {
+	pending, err := worker.queue.CountPending(ctx)
+	if err != nil {
+		log.Zap.Error("failed count pending delete tasks", zap.Error(err))
+		return
+	}
+	worker.metrics.SetDeleteBufferSize(pending)
+}
+
+// deletedAuditEvents - Создает события журнала аудита для задач, ссылки которых удалены,
+// с источником запроса на удаление.
+func deletedAuditEvents(tasks []*data.DeleteTaskData) []*services.AuditEvent {
+	events := make([]*services.AuditEvent, 0, len(tasks))
+	for _, task := range tasks {
+		if task.Outcome != string(services.DeleteOutcomeDeleted) {
+			continue
+		}
+		events = append(events, &services.AuditEvent{
+			Action:    services.AuditActionDelete,
+			ShortURL:  task.ShortURL,
+			UserID:    task.UserID,
+			Source:    services.AuditSource(task.Source),
+			ClientIP:  task.ClientIP,
+			CreatedAt: task.CompletedAt.UTC(),
+		})
+	}
+	return events
+}
+
+// skipAttempted - Оставляет задачи, которые еще не выполнялись в текущем цикле, и запоминает их.
+func skipAttempted(tasks []*data.DeleteTaskData, attempted map[int64]struct{}) []*data.DeleteTaskData {
+	result := make([]*data.DeleteTaskData, 0, len(tasks))
+	for _, task := range tasks {
+		if _, ok := attempted[task.ID]; ok {
+			continue
+		}
+		attempted[task.ID] = struct{}{}
+		result = append(result, task)
+	}
+	return result
+}
@@ -5,10 +5,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/VladSnap/shortener/internal/data/repos"
 	"github.com/VladSnap/shortener/internal/handlers"
 	m "github.com/VladSnap/shortener/internal/handlers/mocks"
 	"github.com/VladSnap/shortener/internal/services"
 	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -19,105 +21,230 @@ func TestDeleterWorker_AddToDelete(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockShorterService := m.NewMockShorterService(ctrl)
-	worker := handlers.NewDeleteWorker(mockShorterService)
+	queue := repos.NewInMemoryDeleteQueue()
+	worker := handlers.NewDeleteWorker(mockShorterService, queue)
+
+	jobID, err := worker.AddToDelete(t.Context(), []services.DeleteShortID{{ShortURL: "url1", UserID: "user1"}})
+	require.NoError(t, err)
+	assert.NotEmpty(t, jobID)
+
+	pending, err := queue.CountPending(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 1, pending, "request should be stored in queue before deletion")
+
+	job, err := worker.GetDeleteJob(t.Context(), jobID, "user1")
+	require.NoError(t, err)
+	assert.Equal(t, services.DeleteJobPending, job.Status)
+	assert.Equal(t, []services.DeleteJobItem{{ShortURL: "url1", Outcome: services.DeleteOutcomePending}}, job.Items)
+
+	_, err = worker.GetDeleteJob(t.Context(), jobID, "user2")
+	require.ErrorIs(t, err, services.ErrDeleteJobNotFound, "job of another user should not be found")
+	_, err = worker.GetDeleteJob(t.Context(), "unknown", "user1")
+	require.ErrorIs(t, err, services.ErrDeleteJobNotFound)
+}
 
-	shortIDs := make(chan services.DeleteShortID, 5)
-	shortIDs <- services.DeleteShortID{ShortURL: "url1", UserID: "user1"}
-	close(shortIDs)
+func TestDeleterWorker_RunWork_FlushToDB(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
-	// Мокируем DeleteBatch для проверки передачи данных
+	mockShorterService := m.NewMockShorterService(ctrl)
+	queue := repos.NewInMemoryDeleteQueue()
+	worker := handlers.NewDeleteWorker(mockShorterService, queue)
+
+	// Мокируем DeleteBatch
 	mockShorterService.EXPECT().
 		DeleteBatch(gomock.Any(), []services.DeleteShortID{
 			{ShortURL: "url1", UserID: "user1"},
 		}).
-		Return(nil).
+		Return([]services.DeleteResult{{ShortURL: "url1", Outcome: services.DeleteOutcomeDeleted}}, nil).
 		Times(1)
 
-	worker.AddToDelete(shortIDs)
+	_, err := worker.AddToDelete(t.Context(), []services.DeleteShortID{{ShortURL: "url1", UserID: "user1"}})
+	require.NoError(t, err)
 
 	// Запускаем RunWork
-	go worker.RunWork()
+	worker.RunWork()
 
 	// Ждем срабатывания таймера (используем фиксированное время вместо приватной константы)
 	time.Sleep(flushToDBIntervalSec * time.Second) // Примерно больше, чем FlushToDBIntervalSec
+
+	pending, err := queue.CountPending(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 0, pending)
 }
 
-func TestDeleterWorker_RunWork_FlushToDB(t *testing.T) {
+func TestDeleterWorker_Close_Drain(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockShorterService := m.NewMockShorterService(ctrl)
-	worker := handlers.NewDeleteWorker(mockShorterService)
+	queue := repos.NewInMemoryDeleteQueue()
+	worker := handlers.NewDeleteWorker(mockShorterService, queue)
+	worker.RunWork()
 
-	// Мокируем DeleteBatch
+	// Задачи выполняются при остановке, не дожидаясь таймера.
 	mockShorterService.EXPECT().
 		DeleteBatch(gomock.Any(), []services.DeleteShortID{
 			{ShortURL: "url1", UserID: "user1"},
+			{ShortURL: "url2", UserID: "user1"},
 		}).
-		Return(nil).
+		Return([]services.DeleteResult{
+			{ShortURL: "url1", Outcome: services.DeleteOutcomeDeleted},
+			{ShortURL: "url2", Outcome: services.DeleteOutcomeNotOwned},
+		}, nil).
 		Times(1)
 
-	shortIDs := make(chan services.DeleteShortID, 5)
-	shortIDs <- services.DeleteShortID{ShortURL: "url1", UserID: "user1"}
-	close(shortIDs)
-
-	worker.AddToDelete(shortIDs)
-
-	// Запускаем RunWork
-	go worker.RunWork()
-
-	// Ждем срабатывания таймера (используем фиксированное время вместо приватной константы)
-	time.Sleep(flushToDBIntervalSec * time.Second) // Примерно больше, чем FlushToDBIntervalSec
+	jobID, err := worker.AddToDelete(t.Context(), []services.DeleteShortID{
+		{ShortURL: "url1", UserID: "user1"},
+		{ShortURL: "url2", UserID: "user1"},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, worker.Close())
+	require.NoError(t, worker.Close(), "repeated Close should do nothing")
+
+	pending, err := queue.CountPending(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 0, pending)
+
+	// Результаты выполненных задач доступны в статусе запроса.
+	job, err := worker.GetDeleteJob(t.Context(), jobID, "user1")
+	require.NoError(t, err)
+	assert.Equal(t, services.DeleteJobCompleted, job.Status)
+	assert.Equal(t, []services.DeleteJobItem{
+		{ShortURL: "url1", Outcome: services.DeleteOutcomeDeleted},
+		{ShortURL: "url2", Outcome: services.DeleteOutcomeNotOwned},
+	}, job.Items)
 }
 
-func TestDeleterWorker_RunWork_DeleteBatchError(t *testing.T) {
+func TestDeleterWorker_DeleteBatchError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockShorterService := m.NewMockShorterService(ctrl)
-	worker := handlers.NewDeleteWorker(mockShorterService)
+	queue := repos.NewInMemoryDeleteQueue()
+	worker := handlers.NewDeleteWorker(mockShorterService, queue)
 
 	// Первый вызов DeleteBatch завершается ошибкой
 	mockShorterService.EXPECT().
 		DeleteBatch(gomock.Any(), []services.DeleteShortID{
 			{ShortURL: "url1", UserID: "user1"},
 		}).
-		Return(errors.New("database error")).
+		Return(nil, errors.New("database error")).
 		Times(1)
 
-	// Второй вызов DeleteBatch успешен (повторная попытка после ошибки)
+	_, err := worker.AddToDelete(t.Context(), []services.DeleteShortID{{ShortURL: "url1", UserID: "user1"}})
+	require.NoError(t, err)
+	require.NoError(t, worker.Close())
+
+	// Задача остается в очереди и откладывается до следующей попытки.
+	tasks, err := queue.FetchDue(t.Context(), time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, 1, tasks[0].Attempts)
+	assert.Equal(t, "database error", tasks[0].LastError)
+	assert.True(t, tasks[0].NextAttemptAt.After(time.Now()), "retry should be delayed")
+	assert.Nil(t, tasks[0].DeadAt)
+
+	// Второй вызов DeleteBatch успешен (повторная попытка после перезапуска)
 	mockShorterService.EXPECT().
 		DeleteBatch(gomock.Any(), []services.DeleteShortID{
 			{ShortURL: "url1", UserID: "user1"},
 		}).
-		Return(nil).
+		Return([]services.DeleteResult{{ShortURL: "url1", Outcome: services.DeleteOutcomeDeleted}}, nil).
 		Times(1)
 
-	shortIDs := make(chan services.DeleteShortID, 5)
-	shortIDs <- services.DeleteShortID{ShortURL: "url1", UserID: "user1"}
-	close(shortIDs)
+	restarted := handlers.NewDeleteWorker(mockShorterService, queue)
+	require.NoError(t, restarted.Close())
 
-	worker.AddToDelete(shortIDs)
+	pending, err := queue.CountPending(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 0, pending)
+}
 
-	// Запускаем RunWork
-	go worker.RunWork()
+func TestDeleterWorker_DeadLetter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockShorterService := m.NewMockShorterService(ctrl)
+	queue := repos.NewInMemoryDeleteQueue()
+	worker := handlers.NewDeleteWorker(mockShorterService, queue,
+		handlers.WithDeleteRetryPolicy(1, time.Second))
+
+	mockShorterService.EXPECT().
+		DeleteBatch(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("database error")).
+		Times(1)
 
-	// Ждем срабатывания таймера дважды (используем фиксированное время вместо приватной константы)
-	time.Sleep(flushToDBIntervalSec * 2 * time.Second) // Два интервала по ~6 секунд
+	jobID, err := worker.AddToDelete(t.Context(), []services.DeleteShortID{{ShortURL: "url1", UserID: "user1"}})
+	require.NoError(t, err)
+	require.NoError(t, worker.Close())
+
+	// Задача, исчерпавшая попытки, больше не выполняется.
+	pending, err := queue.CountPending(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, 0, pending)
+	tasks, err := queue.FetchDue(t.Context(), time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, tasks)
+
+	job, err := worker.GetDeleteJob(t.Context(), jobID, "user1")
+	require.NoError(t, err)
+	assert.Equal(t, services.DeleteJobFailed, job.Status)
+	assert.Equal(t, []services.DeleteJobItem{
+		{ShortURL: "url1", Outcome: services.DeleteOutcomeFailed, Error: "database error"},
+	}, job.Items)
 }
 
-func TestDeleterWorker_Close(t *testing.T) {
+func TestDeleterWorker_CheckHealth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	worker := handlers.NewDeleteWorker(m.NewMockShorterService(ctrl), repos.NewInMemoryDeleteQueue())
+	require.Error(t, worker.CheckHealth(t.Context()), "worker is not started")
+
+	worker.RunWork()
+	require.NoError(t, worker.CheckHealth(t.Context()))
+
+	require.NoError(t, worker.Close())
+	require.Eventually(t, func() bool {
+		return worker.CheckHealth(t.Context()) != nil
+	}, time.Second, 10*time.Millisecond, "worker goroutine should stop after Close")
+}
+
+func TestDeleterWorker_Audit(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockShorterService := m.NewMockShorterService(ctrl)
-	worker := handlers.NewDeleteWorker(mockShorterService)
+	audit := services.NewAuditLog(repos.NewInMemoryAuditRepo())
+	worker := handlers.NewDeleteWorker(mockShorterService, repos.NewInMemoryDeleteQueue(),
+		handlers.WithDeleteWorkerAudit(audit))
 
-	err := worker.Close()
-	if err != nil {
-		t.Fatalf("expected no error, got %v", err)
-	}
+	mockShorterService.EXPECT().
+		DeleteBatch(gomock.Any(), gomock.Any()).
+		Return([]services.DeleteResult{
+			{ShortURL: "url1", Outcome: services.DeleteOutcomeDeleted},
+			{ShortURL: "url2", Outcome: services.DeleteOutcomeNotOwned},
+		}, nil).
+		Times(1)
 
-	// Проверяем, что канал закрыт, отправляя данные в него
-	require.Panics(t, func() { worker.AddToDelete(make(chan services.DeleteShortID)) }, "expected no panic")
+	// Источник запроса сохраняется в очереди и записывается в журнал после удаления.
+	ctx := services.WithAuditActor(t.Context(),
+		services.AuditActor{Source: services.AuditSourceHTTP, ClientIP: "10.0.0.1"})
+	_, err := worker.AddToDelete(ctx, []services.DeleteShortID{
+		{ShortURL: "url1", UserID: "user1"},
+		{ShortURL: "url2", UserID: "user1"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, worker.Close())
+
+	events, err := audit.GetEvents(t.Context(), &services.AuditQuery{})
+	require.NoError(t, err)
+	require.Len(t, events, 1, "only deleted links are recorded")
+	assert.Equal(t, services.AuditActionDelete, events[0].Action)
+	assert.Equal(t, "url1", events[0].ShortURL)
+	assert.Equal(t, "user1", events[0].UserID)
+	assert.Equal(t, services.AuditSourceHTTP, events[0].Source)
+	assert.Equal(t, "10.0.0.1", events[0].ClientIP)
 }
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/VladSnap/shortener/internal/services"
+)
+
+// statusCodeByError - Возвращает http статус ответа для ошибки сервиса сокращения ссылок.
+func statusCodeByError(err error) int {
+	switch {
+	case errors.Is(err, services.ErrInvalidAlias), errors.Is(err, services.ErrInvalidExpiration),
+		errors.Is(err, services.ErrInvalidPageQuery), errors.Is(err, services.ErrInvalidAPIKeyRequest),
+		errors.Is(err, services.ErrInvalidAuditQuery):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrAliasTaken), errors.Is(err, services.ErrOriginalURLTaken):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrLinkNotFound), errors.Is(err, services.ErrAPIKeyNotFound),
+		errors.Is(err, services.ErrDeleteJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrLinkNotOwned):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -1,20 +1,60 @@
 package handlers
 
 import (
+	"net"
 	"net/http"
+	"time"
 
+	"github.com/VladSnap/shortener/internal/helpers"
+	"github.com/VladSnap/shortener/internal/services"
 	"github.com/VladSnap/shortener/internal/validation"
 )
 
+//go:generate mockgen -destination=mocks/clickTracker_mock.go -package=mocks github.com/VladSnap/shortener/internal/handlers ClickTracker
+
+// ClickTracker - Интерфейс воркера, который в фоне сохраняет переходы по сокращенным ссылкам.
+type ClickTracker interface {
+	Close() error
+	Track(click *services.Click)
+	RunWork()
+}
+
 // GetHandler - Обработчик запроса чтения полной ссылки по её сокращению.
 type GetHandler struct {
-	service ShorterService
+	service      ShorterService
+	clickTracker ClickTracker
+	metrics      MetricsRecorder
+	// trustedProxies - Сети прокси, от которых принимается заголовок X-Real-IP.
+	trustedProxies []*net.IPNet
+}
+
+// GetHandlerOption - Функция для настройки GetHandler.
+type GetHandlerOption func(*GetHandler)
+
+// WithGetHandlerMetrics - Устанавливает получателя метрик переходов по ссылкам.
+func WithGetHandlerMetrics(metrics MetricsRecorder) GetHandlerOption {
+	return func(handler *GetHandler) {
+		handler.metrics = metrics
+	}
+}
+
+// WithGetHandlerTrustedProxies - Устанавливает сети прокси, от которых принимается заголовок X-Real-IP
+// с IP клиента для статистики переходов.
+func WithGetHandlerTrustedProxies(trustedProxies []*net.IPNet) GetHandlerOption {
+	return func(handler *GetHandler) {
+		handler.trustedProxies = trustedProxies
+	}
 }
 
 // NewGetHandler - Создает новую структуру GetHandler с указателем.
-func NewGetHandler(service ShorterService) *GetHandler {
+func NewGetHandler(service ShorterService, clickTracker ClickTracker, options ...GetHandlerOption) *GetHandler {
 	handler := new(GetHandler)
 	handler.service = service
+	handler.clickTracker = clickTracker
+	handler.metrics = noopMetrics{}
+	for _, option := range options {
+		option(handler)
+	}
 	return handler
 }
 
@@ -33,20 +73,38 @@ func (handler *GetHandler) Handle(res http.ResponseWriter, req *http.Request) {
 
 	url, err := handler.service.GetURL(req.Context(), shortID)
 	if err != nil {
+		handler.metrics.AddRedirect(RedirectError)
 		http.Error(res, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	if url == nil {
+		handler.metrics.AddRedirect(RedirectNotFound)
 		http.Error(res, "Url not found", http.StatusNotFound)
 		return
 	}
 
 	if url.IsDeleted {
+		handler.metrics.AddRedirect(RedirectDeleted)
 		http.Error(res, "Url has been removed", http.StatusGone)
 		return
 	}
 
+	if url.IsExpired {
+		handler.metrics.AddRedirect(RedirectExpired)
+		http.Error(res, "Url has expired", http.StatusGone)
+		return
+	}
+
+	handler.clickTracker.Track(&services.Click{
+		ShortURL:  shortID,
+		ClickedAt: time.Now(),
+		Referrer:  req.Referer(),
+		UserAgent: req.UserAgent(),
+		ClientIP:  helpers.GetClientIP(req, handler.trustedProxies),
+	})
+
+	handler.metrics.AddRedirect(RedirectFound)
 	res.Header().Set("Location", url.OriginalURL)
 	http.Redirect(res, req, url.OriginalURL, http.StatusTemporaryRedirect)
 }
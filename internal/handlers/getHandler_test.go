@@ -25,6 +25,7 @@ func TestGetHandler(t *testing.T) {
 		requestPath string
 		id          string
 		url         string
+		isExpired   bool
 		want        want
 	}{
 		{
@@ -118,12 +119,27 @@ func TestGetHandler(t *testing.T) {
 				location:     "",
 			},
 		},
+		{
+			name:        "url expired",
+			requestPath: "/{id}",
+			httpMethod:  http.MethodGet,
+			id:          "exPiReDd",
+			url:         "http://expired.url",
+			isExpired:   true,
+			want: want{
+				code:         410,
+				contentType:  "text/plain; charset=utf-8",
+				responseBody: "Url has expired\n",
+				location:     "",
+			},
+		},
 	}
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockService := m.NewMockShorterService(ctrl)
-	getHandler := NewGetHandler(mockService)
+	mockTracker := m.NewMockClickTracker(ctrl)
+	getHandler := NewGetHandler(mockService, mockTracker)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -131,7 +147,7 @@ func TestGetHandler(t *testing.T) {
 			if tt.url == "" {
 				slink = nil
 			} else {
-				slink = &services.ShortedLink{OriginalURL: tt.url}
+				slink = &services.ShortedLink{OriginalURL: tt.url, IsExpired: tt.isExpired}
 			}
 
 			request := httptest.NewRequest(tt.httpMethod, tt.requestPath, http.NoBody)
@@ -139,6 +155,13 @@ func TestGetHandler(t *testing.T) {
 			mockService.EXPECT().GetURL(request.Context(), tt.id).
 				Return(slink, nil).
 				AnyTimes()
+			if tt.want.code == http.StatusTemporaryRedirect {
+				mockTracker.EXPECT().Track(gomock.Any()).
+					Do(func(click *services.Click) {
+						assert.Equal(t, tt.id, click.ShortURL)
+						assert.Equal(t, "192.0.2.1", click.ClientIP)
+					})
+			}
 			w := httptest.NewRecorder()
 			getHandler.Handle(w, request)
 
@@ -150,7 +173,7 @@ func TestGetHandler(t *testing.T) {
 			assert.NoError(t, err, "no error for close response body")
 
 			assert.Equal(t, tt.want.contentType, res.Header.Get(HeaderContentType))
-			assert.Equal(t, tt.url, res.Header.Get("Location"))
+			assert.Equal(t, tt.want.location, res.Header.Get("Location"))
 			assert.NotEmpty(t, string(resBody))
 		})
 	}
@@ -3,23 +3,20 @@ package handlers
 import (
 	"net/http"
 
-	"github.com/VladSnap/shortener/internal/config"
 	"github.com/VladSnap/shortener/internal/log"
 	"github.com/VladSnap/shortener/internal/services"
 	"go.uber.org/zap"
 )
 
-// GetPingHandler - Обработчик запроса проверки доступности базы данных.
+// GetPingHandler - Обработчик запроса проверки доступности хранилища.
 type GetPingHandler struct {
-	opts          *config.Options
 	healthService *services.HealthService
 }
 
 // NewGetPingHandler - Создает новую структуру GetPingHandler с указателем.
-func NewGetPingHandler(opts *config.Options) *GetPingHandler {
+func NewGetPingHandler(healthService *services.HealthService) *GetPingHandler {
 	handler := new(GetPingHandler)
-	handler.opts = opts
-	handler.healthService = services.NewHealthService(opts.DataBaseConnString)
+	handler.healthService = healthService
 	return handler
 }
 
@@ -31,7 +28,7 @@ func (handler *GetPingHandler) Handle(res http.ResponseWriter, req *http.Request
 	}
 
 	ctx := req.Context()
-	err := handler.healthService.PingDatabase(ctx)
+	err := handler.healthService.Readiness(ctx).Err()
 	if err != nil {
 		http.Error(res, err.Error(), http.StatusInternalServerError)
 		return
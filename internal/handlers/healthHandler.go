@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/VladSnap/shortener/internal/log"
+	"github.com/VladSnap/shortener/internal/services"
+	"go.uber.org/zap"
+)
+
+// Статусы ответа HealthHandler.
+const (
+	// HealthStatusOK - Проверка пройдена.
+	HealthStatusOK = "ok"
+	// HealthStatusFail - Проверка не пройдена.
+	HealthStatusFail = "fail"
+)
+
+// HealthResponse - Структура ответа для HealthHandler.
+type HealthResponse struct {
+	// Status - Общий статус: ok или fail.
+	Status string `json:"status"`
+	// Checks - Результат каждой проверки: ok или текст ошибки.
+	Checks map[string]string `json:"checks"`
+}
+
+// HealthHandler - Обработчик проверки живости (liveness) или готовности (readiness) сервиса.
+type HealthHandler struct {
+	check func(ctx context.Context) services.HealthReport
+}
+
+// NewLivenessHandler - Создает обработчик проверки живости сервиса.
+func NewLivenessHandler(healthService *services.HealthService) *HealthHandler {
+	return &HealthHandler{check: healthService.Liveness}
+}
+
+// NewReadinessHandler - Создает обработчик проверки готовности сервиса обслуживать запросы.
+func NewReadinessHandler(healthService *services.HealthService) *HealthHandler {
+	return &HealthHandler{check: healthService.Readiness}
+}
+
+// Handle - Обрабатывает входящий запрос. Если хотя бы одна проверка не пройдена, возвращает 503.
+func (handler *HealthHandler) Handle(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(res, ValidateErrHTTPNotGET, http.StatusBadRequest)
+		return
+	}
+
+	report := handler.check(req.Context())
+	result := HealthResponse{Status: HealthStatusOK, Checks: make(map[string]string, len(report.Checks))}
+	for name, err := range report.Checks {
+		result.Checks[name] = HealthStatusOK
+		if err != nil {
+			result.Checks[name] = err.Error()
+		}
+	}
+	statusCode := http.StatusOK
+	if !report.Healthy {
+		result.Status = HealthStatusFail
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	res.Header().Set(HeaderContentType, HeaderApplicationJSONValue)
+	res.WriteHeader(statusCode)
+	err := json.NewEncoder(res).Encode(result)
+	if err != nil {
+		log.Zap.Error(ErrFailedWriteToResponse, zap.Error(err))
+		return
+	}
+}
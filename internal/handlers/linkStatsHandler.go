@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/log"
+	"go.uber.org/zap"
+)
+
+// DailyClicksResponse - Количество переходов по ссылке за один день.
+type DailyClicksResponse struct {
+	// Date - День в формате YYYY-MM-DD по UTC.
+	Date string `json:"date"`
+	// Clicks - Количество переходов за день.
+	Clicks int `json:"clicks"`
+}
+
+// LinkStatsResponse - Структура ответа для LinkStatsHandler.
+type LinkStatsResponse struct {
+	// ShortURL - Сокращенная ссылка.
+	ShortURL string `json:"short_url"`
+	// TotalClicks - Общее количество переходов.
+	TotalClicks int `json:"total_clicks"`
+	// Days - Количество переходов по дням.
+	Days []DailyClicksResponse `json:"days"`
+}
+
+// LinkStatsHandler - Обработчик запроса статистики переходов по сокращенной ссылке пользователя.
+type LinkStatsHandler struct {
+	service ShorterService
+	baseURL string
+}
+
+// NewLinkStatsHandler - Создает новую структуру LinkStatsHandler с указателем.
+func NewLinkStatsHandler(service ShorterService, baseURL string) *LinkStatsHandler {
+	handler := new(LinkStatsHandler)
+	handler.service = service
+	handler.baseURL = baseURL
+	return handler
+}
+
+// Handle - Обрабатывает входящий запрос.
+func (handler *LinkStatsHandler) Handle(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(res, ValidateErrHTTPNotGET, http.StatusBadRequest)
+		return
+	}
+
+	shortID := req.PathValue("id")
+	if shortID == "" {
+		http.Error(res, "Request path incorrect", http.StatusBadRequest)
+		return
+	}
+
+	userID := ""
+	if value, ok := req.Context().Value(constants.UserIDContextKey).(string); ok {
+		userID = value
+	}
+	stats, err := handler.service.GetLinkStats(req.Context(), shortID, userID)
+	if err != nil {
+		http.Error(res, err.Error(), statusCodeByError(err))
+		return
+	}
+
+	result := LinkStatsResponse{
+		ShortURL:    handler.baseURL + "/" + stats.ShortURL,
+		TotalClicks: stats.TotalClicks,
+		Days:        make([]DailyClicksResponse, 0, len(stats.Days)),
+	}
+	for _, day := range stats.Days {
+		result.Days = append(result.Days, DailyClicksResponse{Date: day.Day.Format(time.DateOnly), Clicks: day.Clicks})
+	}
+
+	res.Header().Add(HeaderContentType, HeaderApplicationJSONValue)
+	res.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(res).Encode(result)
+
+	if err != nil {
+		log.Zap.Error(ErrFailedWriteToResponse, zap.Error(err))
+		return
+	}
+}
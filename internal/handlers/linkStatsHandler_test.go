@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	m "github.com/VladSnap/shortener/internal/handlers/mocks"
+	"github.com/VladSnap/shortener/internal/services"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkStatsHandler_Handle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := m.NewMockShorterService(ctrl)
+	handler := NewLinkStatsHandler(mockService, "http://localhost")
+
+	newRequest := func(method, id string) *http.Request {
+		req := httptest.NewRequest(method, "/api/user/urls/"+id+"/stats", http.NoBody)
+		req.SetPathValue("id", id)
+		ctx := context.WithValue(req.Context(), constants.UserIDContextKey, "user1")
+		return req.WithContext(ctx)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+		mockService.EXPECT().GetLinkStats(gomock.Any(), "abc", "user1").Return(&services.LinkStats{
+			ShortURL:    "abc",
+			TotalClicks: 3,
+			Days:        []services.DailyClicks{{Day: day, Clicks: 3}},
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodGet, "abc"))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"short_url":"http://localhost/abc","total_clicks":3,`+
+			`"days":[{"date":"2025-03-01","clicks":3}]}`, rec.Body.String())
+	})
+
+	t.Run("Invalid HTTP Method", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodPost, "abc"))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Link not found", func(t *testing.T) {
+		mockService.EXPECT().GetLinkStats(gomock.Any(), "missing", "user1").
+			Return(nil, fmt.Errorf("%w: 'missing'", services.ErrLinkNotFound))
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodGet, "missing"))
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("Link of another user", func(t *testing.T) {
+		mockService.EXPECT().GetLinkStats(gomock.Any(), "foreign", "user1").
+			Return(nil, fmt.Errorf("%w: 'foreign'", services.ErrLinkNotOwned))
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodGet, "foreign"))
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
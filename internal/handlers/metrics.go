@@ -0,0 +1,42 @@
+package handlers
+
+// Результаты переходов по сокращенным ссылкам для метрик.
+const (
+	// RedirectFound - Переход выполнен.
+	RedirectFound = "found"
+	// RedirectNotFound - Сокращенная ссылка не найдена.
+	RedirectNotFound = "not_found"
+	// RedirectDeleted - Сокращенная ссылка удалена.
+	RedirectDeleted = "deleted"
+	// RedirectExpired - Срок действия сокращенной ссылки истек.
+	RedirectExpired = "expired"
+	// RedirectError - Ошибка чтения сокращенной ссылки.
+	RedirectError = "error"
+)
+
+// MetricsRecorder - Получатель метрик переходов по ссылкам и воркера удаления.
+type MetricsRecorder interface {
+	// AddRedirect - Учитывает переход по сокращенной ссылке с указанным результатом.
+	AddRedirect(result string)
+	// SetDeleteBufferSize - Устанавливает текущее количество ссылок в очереди воркера удаления.
+	SetDeleteBufferSize(size int)
+	// AddDeleteFlushFailure - Учитывает неудачную попытку удалить пачку ссылок из очереди.
+	AddDeleteFlushFailure()
+	// AddDeleteDeadLetters - Учитывает задачи удаления, исчерпавшие попытки и перенесенные в dead letter.
+	AddDeleteDeadLetters(count int)
+}
+
+// noopMetrics - Реализация MetricsRecorder, которая никуда не пишет метрики.
+type noopMetrics struct{}
+
+// AddRedirect - Ничего не делает.
+func (noopMetrics) AddRedirect(string) {}
+
+// SetDeleteBufferSize - Ничего не делает.
+func (noopMetrics) SetDeleteBufferSize(int) {}
+
+// AddDeleteFlushFailure - Ничего не делает.
+func (noopMetrics) AddDeleteFlushFailure() {}
+
+// AddDeleteDeadLetters - Ничего не делает.
+func (noopMetrics) AddDeleteDeadLetters(int) {}
@@ -0,0 +1,73 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/VladSnap/shortener/internal/handlers (interfaces: ClickTracker)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	services "github.com/VladSnap/shortener/internal/services"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockClickTracker is a mock of ClickTracker interface.
+type MockClickTracker struct {
+	ctrl     *gomock.Controller
+	recorder *MockClickTrackerMockRecorder
+}
+
+// MockClickTrackerMockRecorder is the mock recorder for MockClickTracker.
+type MockClickTrackerMockRecorder struct {
+	mock *MockClickTracker
+}
+
+// NewMockClickTracker creates a new mock instance.
+func NewMockClickTracker(ctrl *gomock.Controller) *MockClickTracker {
+	mock := &MockClickTracker{ctrl: ctrl}
+	mock.recorder = &MockClickTrackerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClickTracker) EXPECT() *MockClickTrackerMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockClickTracker) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockClickTrackerMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockClickTracker)(nil).Close))
+}
+
+// RunWork mocks base method.
+func (m *MockClickTracker) RunWork() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RunWork")
+}
+
+// RunWork indicates an expected call of RunWork.
+func (mr *MockClickTrackerMockRecorder) RunWork() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunWork", reflect.TypeOf((*MockClickTracker)(nil).RunWork))
+}
+
+// Track mocks base method.
+func (m *MockClickTracker) Track(arg0 *services.Click) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Track", arg0)
+}
+
+// Track indicates an expected call of Track.
+func (mr *MockClickTrackerMockRecorder) Track(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Track", reflect.TypeOf((*MockClickTracker)(nil).Track), arg0)
+}
@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	services "github.com/VladSnap/shortener/internal/services"
@@ -35,15 +36,18 @@ func (m *MockDeleterWorker) EXPECT() *MockDeleterWorkerMockRecorder {
 }
 
 // AddToDelete mocks base method.
-func (m *MockDeleterWorker) AddToDelete(arg0 chan services.DeleteShortID) {
+func (m *MockDeleterWorker) AddToDelete(arg0 context.Context, arg1 []services.DeleteShortID) (string, error) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "AddToDelete", arg0)
+	ret := m.ctrl.Call(m, "AddToDelete", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // AddToDelete indicates an expected call of AddToDelete.
-func (mr *MockDeleterWorkerMockRecorder) AddToDelete(arg0 interface{}) *gomock.Call {
+func (mr *MockDeleterWorkerMockRecorder) AddToDelete(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToDelete", reflect.TypeOf((*MockDeleterWorker)(nil).AddToDelete), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToDelete", reflect.TypeOf((*MockDeleterWorker)(nil).AddToDelete), arg0, arg1)
 }
 
 // Close mocks base method.
@@ -60,6 +64,21 @@ func (mr *MockDeleterWorkerMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockDeleterWorker)(nil).Close))
 }
 
+// GetDeleteJob mocks base method.
+func (m *MockDeleterWorker) GetDeleteJob(arg0 context.Context, arg1, arg2 string) (*services.DeleteJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeleteJob", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*services.DeleteJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeleteJob indicates an expected call of GetDeleteJob.
+func (mr *MockDeleterWorkerMockRecorder) GetDeleteJob(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeleteJob", reflect.TypeOf((*MockDeleterWorker)(nil).GetDeleteJob), arg0, arg1, arg2)
+}
+
 // RunWork mocks base method.
 func (m *MockDeleterWorker) RunWork() {
 	m.ctrl.T.Helper()
@@ -36,7 +36,7 @@ func (m *MockShorterService) EXPECT() *MockShorterServiceMockRecorder {
 }
 
 // CreateShortLink mocks base method.
-func (m *MockShorterService) CreateShortLink(arg0 context.Context, arg1, arg2 string) (*services.ShortedLink, error) {
+func (m *MockShorterService) CreateShortLink(arg0 context.Context, arg1 *services.OriginalLink, arg2 string) (*services.ShortedLink, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CreateShortLink", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*services.ShortedLink)
@@ -51,26 +51,27 @@ func (mr *MockShorterServiceMockRecorder) CreateShortLink(arg0, arg1, arg2 inter
 }
 
 // CreateShortLinkBatch mocks base method.
-func (m *MockShorterService) CreateShortLinkBatch(arg0 context.Context, arg1 []*services.OriginalLink, arg2 string) ([]*services.ShortedLink, error) {
+func (m *MockShorterService) CreateShortLinkBatch(arg0 context.Context, arg1 []*services.OriginalLink, arg2 string, arg3 bool) ([]*services.ShortedLink, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateShortLinkBatch", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "CreateShortLinkBatch", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].([]*services.ShortedLink)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateShortLinkBatch indicates an expected call of CreateShortLinkBatch.
-func (mr *MockShorterServiceMockRecorder) CreateShortLinkBatch(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockShorterServiceMockRecorder) CreateShortLinkBatch(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShortLinkBatch", reflect.TypeOf((*MockShorterService)(nil).CreateShortLinkBatch), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShortLinkBatch", reflect.TypeOf((*MockShorterService)(nil).CreateShortLinkBatch), arg0, arg1, arg2, arg3)
 }
 
 // DeleteBatch mocks base method.
-func (m *MockShorterService) DeleteBatch(arg0 context.Context, arg1 []services.DeleteShortID) error {
+func (m *MockShorterService) DeleteBatch(arg0 context.Context, arg1 []services.DeleteShortID) ([]services.DeleteResult, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "DeleteBatch", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].([]services.DeleteResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // DeleteBatch indicates an expected call of DeleteBatch.
@@ -80,18 +81,33 @@ func (mr *MockShorterServiceMockRecorder) DeleteBatch(arg0, arg1 interface{}) *g
 }
 
 // GetAllByUserID mocks base method.
-func (m *MockShorterService) GetAllByUserID(arg0 context.Context, arg1 string) ([]*services.ShortedLink, error) {
+func (m *MockShorterService) GetAllByUserID(arg0 context.Context, arg1 string, arg2 *services.UserLinksQuery) (*services.UserLinksPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllByUserID", arg0, arg1)
-	ret0, _ := ret[0].([]*services.ShortedLink)
+	ret := m.ctrl.Call(m, "GetAllByUserID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*services.UserLinksPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllByUserID indicates an expected call of GetAllByUserID.
-func (mr *MockShorterServiceMockRecorder) GetAllByUserID(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockShorterServiceMockRecorder) GetAllByUserID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserID", reflect.TypeOf((*MockShorterService)(nil).GetAllByUserID), arg0, arg1, arg2)
+}
+
+// GetLinkStats mocks base method.
+func (m *MockShorterService) GetLinkStats(arg0 context.Context, arg1, arg2 string) (*services.LinkStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinkStats", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*services.LinkStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinkStats indicates an expected call of GetLinkStats.
+func (mr *MockShorterServiceMockRecorder) GetLinkStats(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserID", reflect.TypeOf((*MockShorterService)(nil).GetAllByUserID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinkStats", reflect.TypeOf((*MockShorterService)(nil).GetLinkStats), arg0, arg1, arg2)
 }
 
 // GetStats mocks base method.
@@ -123,3 +139,48 @@ func (mr *MockShorterServiceMockRecorder) GetURL(arg0, arg1 interface{}) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetURL", reflect.TypeOf((*MockShorterService)(nil).GetURL), arg0, arg1)
 }
+
+// GetUserStats mocks base method.
+func (m *MockShorterService) GetUserStats(arg0 context.Context, arg1 string) (*services.UserStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserStats", arg0, arg1)
+	ret0, _ := ret[0].(*services.UserStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserStats indicates an expected call of GetUserStats.
+func (mr *MockShorterServiceMockRecorder) GetUserStats(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserStats", reflect.TypeOf((*MockShorterService)(nil).GetUserStats), arg0, arg1)
+}
+
+// RestoreBatch mocks base method.
+func (m *MockShorterService) RestoreBatch(arg0 context.Context, arg1 []services.DeleteShortID) ([]services.RestoreResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBatch", arg0, arg1)
+	ret0, _ := ret[0].([]services.RestoreResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreBatch indicates an expected call of RestoreBatch.
+func (mr *MockShorterServiceMockRecorder) RestoreBatch(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBatch", reflect.TypeOf((*MockShorterService)(nil).RestoreBatch), arg0, arg1)
+}
+
+// UpdateLink mocks base method.
+func (m *MockShorterService) UpdateLink(arg0 context.Context, arg1, arg2, arg3 string) (*services.ShortedLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLink", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*services.ShortedLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLink indicates an expected call of UpdateLink.
+func (mr *MockShorterServiceMockRecorder) UpdateLink(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLink", reflect.TypeOf((*MockShorterService)(nil).UpdateLink), arg0, arg1, arg2, arg3)
+}
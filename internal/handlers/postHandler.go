@@ -7,6 +7,7 @@ import (
 
 	"github.com/VladSnap/shortener/internal/constants"
 	"github.com/VladSnap/shortener/internal/log"
+	"github.com/VladSnap/shortener/internal/services"
 	"github.com/VladSnap/shortener/internal/validation"
 	"go.uber.org/zap"
 )
@@ -61,7 +62,8 @@ func (handler *PostHandler) Handle(res http.ResponseWriter, req *http.Request) {
 	if value, ok := req.Context().Value(constants.UserIDContextKey).(string); ok {
 		userID = value
 	}
-	shortLink, err := handler.service.CreateShortLink(req.Context(), fullURL, userID)
+	shortLink, err := handler.service.CreateShortLink(req.Context(),
+		&services.OriginalLink{URL: fullURL}, userID)
 
 	if err != nil {
 		http.Error(res, err.Error(), http.StatusInternalServerError)
@@ -131,14 +131,14 @@ func TestPostHandler(t *testing.T) {
 	ret := &services.ShortedLink{URL: ""}
 	userID := "d1a8485a-430a-49f4-92ba-50886e1b07c6"
 	ctx := context.WithValue(t.Context(), constants.UserIDContextKey, userID)
-	mockService.EXPECT().CreateShortLink(ctx, "http://test6.url", userID).
+	mockService.EXPECT().CreateShortLink(ctx, &services.OriginalLink{URL: "http://test6.url"}, userID).
 		Return(ret, errors.New("random fail")).
 		AnyTimes()
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ret = &services.ShortedLink{URL: tt.shortID}
-			mockService.EXPECT().CreateShortLink(ctx, tt.sourceURL, userID).
+			mockService.EXPECT().CreateShortLink(ctx, &services.OriginalLink{URL: tt.sourceURL}, userID).
 				Return(ret, nil).
 				AnyTimes()
 
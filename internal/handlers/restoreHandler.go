@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/log"
+	"github.com/VladSnap/shortener/internal/services"
+	"github.com/VladSnap/shortener/internal/validation"
+	"go.uber.org/zap"
+)
+
+// RestoreItemResponse - Результат восстановления одной ссылки.
+type RestoreItemResponse struct {
+	// ShortURL - Идентификатор сокращенной ссылки из запроса.
+	ShortURL string `json:"short_url"`
+	// Outcome - Результат: restored, not_found, not_owned, not_deleted или window_expired.
+	Outcome string `json:"outcome"`
+}
+
+// RestoreHandler - Обработчик запроса восстановления удаленных сокращенных ссылок.
+type RestoreHandler struct {
+	service ShorterService
+}
+
+// NewRestoreHandler - Создает новую структуру RestoreHandler с указателем.
+func NewRestoreHandler(service ShorterService) *RestoreHandler {
+	handler := new(RestoreHandler)
+	handler.service = service
+	return handler
+}
+
+// Handle - Обрабатывает входящий запрос.
+func (handler *RestoreHandler) Handle(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(res, "Http method not POST", http.StatusBadRequest)
+		return
+	}
+
+	ct := req.Header.Get(HeaderContentType)
+	if !strings.Contains(ct, HeaderApplicationJSONValue) && !strings.Contains(ct, HeaderApplicationXgzipValue) {
+		http.Error(res, "Incorrect content-type:"+ct, http.StatusBadRequest)
+		return
+	}
+
+	var shortURLs []string
+	if err := json.NewDecoder(req.Body).Decode(&shortURLs); err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, surl := range shortURLs {
+		if err := validation.ValidateShortURL(surl); err != nil {
+			http.Error(res, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if len(shortURLs) == 0 {
+		res.WriteHeader(http.StatusNotAcceptable)
+		return
+	}
+
+	userID := ""
+	if value, ok := req.Context().Value(constants.UserIDContextKey).(string); ok {
+		userID = value
+	}
+
+	toRestore := make([]services.DeleteShortID, 0, len(shortURLs))
+	for _, url := range shortURLs {
+		toRestore = append(toRestore, services.NewDeleteShortID(url, userID))
+	}
+
+	results, err := handler.service.RestoreBatch(req.Context(), toRestore)
+	if err != nil {
+		log.Zap.Error("failed restore short links", zap.Error(err))
+		http.Error(res, "Failed to restore short links", http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]RestoreItemResponse, 0, len(results))
+	for _, result := range results {
+		response = append(response, RestoreItemResponse{ShortURL: result.ShortURL, Outcome: string(result.Outcome)})
+	}
+
+	res.Header().Add(HeaderContentType, HeaderApplicationJSONValue)
+	res.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(res).Encode(response)
+	if err != nil {
+		log.Zap.Error(ErrFailedWriteToResponse, zap.Error(err))
+		return
+	}
+}
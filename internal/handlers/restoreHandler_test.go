@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	m "github.com/VladSnap/shortener/internal/handlers/mocks"
+	"github.com/VladSnap/shortener/internal/services"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestoreHandler_Handle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := m.NewMockShorterService(ctrl)
+	handler := NewRestoreHandler(mockService)
+
+	newRequest := func(method, body string) *http.Request {
+		req := httptest.NewRequest(method, "/api/user/urls/restore", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		ctx := context.WithValue(req.Context(), constants.UserIDContextKey, "user1")
+		return req.WithContext(ctx)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockService.EXPECT().RestoreBatch(gomock.Any(), []services.DeleteShortID{
+			{ShortURL: "abc", UserID: "user1"},
+			{ShortURL: "old", UserID: "user1"},
+		}).Return([]services.RestoreResult{
+			{ShortURL: "abc", Outcome: services.RestoreOutcomeRestored},
+			{ShortURL: "old", Outcome: services.RestoreOutcomeExpired},
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodPost, `["abc","old"]`))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `[{"short_url":"abc","outcome":"restored"},{"short_url":"old","outcome":"window_expired"}]`,
+			rec.Body.String())
+	})
+
+	t.Run("Invalid HTTP Method", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodGet, `["abc"]`))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Invalid Short URL", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodPost, `["invalid/url"]`))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Empty Short URLs", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodPost, `[]`))
+
+		assert.Equal(t, http.StatusNotAcceptable, rec.Code)
+	})
+
+	t.Run("Service error", func(t *testing.T) {
+		mockService.EXPECT().RestoreBatch(gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodPost, `["abc"]`))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/VladSnap/shortener/internal/constants"
 	"github.com/VladSnap/shortener/internal/log"
+	"github.com/VladSnap/shortener/internal/services"
 	"github.com/VladSnap/shortener/internal/validation"
 	"go.uber.org/zap"
 )
@@ -15,6 +17,12 @@ import (
 type ShortenRequest struct {
 	// URL - Оригинальный URL который требуется сократить.
 	URL string `json:"url"`
+	// Alias - Желаемый идентификатор сокращенной ссылки, необязательный.
+	Alias string `json:"alias,omitempty"`
+	// TTL - Время жизни ссылки в секундах, необязательный.
+	TTL int64 `json:"ttl,omitempty"`
+	// ExpiresAt - Время окончания действия ссылки в формате RFC 3339, необязательный.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
 // ShortenResponse - Структура ответа для ShortenHandler.
@@ -70,14 +78,26 @@ func (handler *ShortenHandler) Handle(res http.ResponseWriter, req *http.Request
 		return
 	}
 
+	ttl, err := services.TTLFromSeconds(request.TTL)
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	userID := ""
 	if value, ok := req.Context().Value(constants.UserIDContextKey).(string); ok {
 		userID = value
 	}
-	shortLink, err := handler.service.CreateShortLink(req.Context(), request.URL, userID)
+	originalLink := &services.OriginalLink{
+		URL:       request.URL,
+		Alias:     request.Alias,
+		TTL:       ttl,
+		ExpiresAt: request.ExpiresAt,
+	}
+	shortLink, err := handler.service.CreateShortLink(req.Context(), originalLink, userID)
 
 	if err != nil {
-		http.Error(res, err.Error(), http.StatusInternalServerError)
+		http.Error(res, err.Error(), statusCodeByError(err))
 		return
 	}
 
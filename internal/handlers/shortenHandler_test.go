@@ -7,9 +7,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/VladSnap/shortener/internal/constants"
 	m "github.com/VladSnap/shortener/internal/handlers/mocks"
@@ -30,6 +32,8 @@ func TestShortenHandler(t *testing.T) {
 		httpMethod  string
 		requestPath string
 		sourceURL   string
+		alias       string
+		ttl         int64
 		shortID     string
 		want        want
 	}{
@@ -88,6 +92,64 @@ func TestShortenHandler(t *testing.T) {
 				contentType:  "text/plain; charset=utf-8",
 				responseBody: "random fail\n",
 			},
+		}, {
+			name:        "custom alias",
+			sourceURL:   "http://test7.url",
+			alias:       "spring-sale",
+			requestPath: "/api/shorten",
+			httpMethod:  http.MethodPost,
+			shortID:     "spring-sale",
+			want: want{
+				code:         201,
+				contentType:  HeaderApplicationJSONValue,
+				responseBody: fmt.Sprintf("{\"result\":\"%v/spring-sale\"}\n", baseURL),
+			},
+		}, {
+			name:        "custom alias already taken",
+			sourceURL:   "http://test8.url",
+			alias:       "taken",
+			requestPath: "/api/shorten",
+			httpMethod:  http.MethodPost,
+			want: want{
+				code:         409,
+				contentType:  "text/plain; charset=utf-8",
+				responseBody: "alias already taken\n",
+			},
+		}, {
+			name:        "custom alias invalid",
+			sourceURL:   "http://test9.url",
+			alias:       "a!",
+			requestPath: "/api/shorten",
+			httpMethod:  http.MethodPost,
+			want: want{
+				code:         400,
+				contentType:  "text/plain; charset=utf-8",
+				responseBody: "invalid alias\n",
+			},
+		}, {
+			name:        "ttl above maximum",
+			sourceURL:   "http://test10.url",
+			ttl:         int64(constants.MaxLinkTTL/time.Second) + 1,
+			requestPath: "/api/shorten",
+			httpMethod:  http.MethodPost,
+			want: want{
+				code:        400,
+				contentType: "text/plain; charset=utf-8",
+				responseBody: fmt.Sprintf("invalid expiration: ttl should not exceed %d seconds\n",
+					int64(constants.MaxLinkTTL/time.Second)),
+			},
+		}, {
+			name:        "ttl overflowing duration",
+			sourceURL:   "http://test10.url",
+			ttl:         math.MaxInt64,
+			requestPath: "/api/shorten",
+			httpMethod:  http.MethodPost,
+			want: want{
+				code:        400,
+				contentType: "text/plain; charset=utf-8",
+				responseBody: fmt.Sprintf("invalid expiration: ttl should not exceed %d seconds\n",
+					int64(constants.MaxLinkTTL/time.Second)),
+			},
 		},
 	}
 
@@ -98,17 +160,23 @@ func TestShortenHandler(t *testing.T) {
 	ret := &services.ShortedLink{URL: ""}
 	userID := "d1a8485a-430a-49f4-92ba-50886e1b07c6"
 	ctx := context.WithValue(t.Context(), constants.UserIDContextKey, userID)
-	mockService.EXPECT().CreateShortLink(ctx, "http://test6.url", userID).
+	mockService.EXPECT().CreateShortLink(ctx, &services.OriginalLink{URL: "http://test6.url"}, userID).
 		Return(ret, errors.New("random fail")).AnyTimes()
+	mockService.EXPECT().CreateShortLink(ctx, &services.OriginalLink{URL: "http://test8.url", Alias: "taken"}, userID).
+		Return(nil, services.ErrAliasTaken).AnyTimes()
+	mockService.EXPECT().CreateShortLink(ctx, &services.OriginalLink{URL: "http://test9.url", Alias: "a!"}, userID).
+		Return(nil, services.ErrInvalidAlias).AnyTimes()
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ret = &services.ShortedLink{URL: tt.shortID}
-			mockService.EXPECT().CreateShortLink(ctx, tt.sourceURL, userID).
+			mockService.EXPECT().CreateShortLink(ctx, &services.OriginalLink{URL: tt.sourceURL, Alias: tt.alias}, userID).
 				Return(ret, nil).AnyTimes()
 
 			requestData := ShortenRequest{
-				URL: tt.sourceURL,
+				URL:   tt.sourceURL,
+				Alias: tt.alias,
+				TTL:   tt.ttl,
 			}
 			rqBytes, err := json.Marshal(requestData)
 			if err != nil {
@@ -12,16 +12,25 @@ import (
 // ShorterService - интерфейс сервиса сокращателя ссылок, который реализует основную бизнес логику данного приложения.
 type ShorterService interface {
 	// CreateShortLink - Создает объект сокращенной ссылки для конкретного пользователя.
-	CreateShortLink(ctx context.Context, originalURL string, userID string) (*services.ShortedLink, error)
+	CreateShortLink(ctx context.Context, originalLink *services.OriginalLink, userID string) (
+		*services.ShortedLink, error)
 	// CreateShortLinkBatch - Создает пачку объектов сокращенной ссылки для конкретного пользователя.
-	CreateShortLinkBatch(ctx context.Context, originalLinks []*services.OriginalLink, userID string) (
+	CreateShortLinkBatch(ctx context.Context, originalLinks []*services.OriginalLink, userID string, partial bool) (
 		[]*services.ShortedLink, error)
 	// GetURL - Читает полный URL по идентификатору сокращенной ссылки.
 	GetURL(ctx context.Context, shortID string) (*services.ShortedLink, error)
-	// GetAllByUserID - Читает все сокращенные ссылки конкретного пользователя.
-	GetAllByUserID(ctx context.Context, userID string) ([]*services.ShortedLink, error)
-	// DeleteBatch - Удаляет одной пачкой сокращенные ссылки.
-	DeleteBatch(ctx context.Context, shortIDs []services.DeleteShortID) error
+	// GetAllByUserID - Читает страницу сокращенных ссылок конкретного пользователя.
+	GetAllByUserID(ctx context.Context, userID string, query *services.UserLinksQuery) (*services.UserLinksPage, error)
+	// DeleteBatch - Удаляет одной пачкой сокращенные ссылки и возвращает результат по каждой ссылке.
+	DeleteBatch(ctx context.Context, shortIDs []services.DeleteShortID) ([]services.DeleteResult, error)
+	// RestoreBatch - Восстанавливает удаленные сокращенные ссылки и возвращает результат по каждой ссылке.
+	RestoreBatch(ctx context.Context, shortIDs []services.DeleteShortID) ([]services.RestoreResult, error)
+	// UpdateLink - Меняет оригинальный URL сокращенной ссылки конкретного пользователя.
+	UpdateLink(ctx context.Context, shortID string, userID string, originalURL string) (*services.ShortedLink, error)
 	// GetStats - Получает статистику о пользователях и всех ссылках.
 	GetStats(ctx context.Context) (*services.Stats, error)
+	// GetLinkStats - Получает статистику переходов по сокращенной ссылке конкретного пользователя.
+	GetLinkStats(ctx context.Context, shortID string, userID string) (*services.LinkStats, error)
+	// GetUserStats - Получает агрегированную статистику по ссылкам пользователя.
+	GetUserStats(ctx context.Context, userID string) (*services.UserStats, error)
 }
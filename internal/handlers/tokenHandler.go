@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/log"
+	"go.uber.org/zap"
+)
+
+// TokenIssuer - Интерфейс выпуска JWT токенов доступа.
+type TokenIssuer interface {
+	// IssueToken - Выпускает токен для пользователя и возвращает его вместе со временем истечения.
+	IssueToken(userID string) (string, time.Time, error)
+}
+
+// TokenResponse - Структура ответа для TokenHandler.
+type TokenResponse struct {
+	// AccessToken - JWT токен доступа для заголовка Authorization.
+	AccessToken string `json:"access_token"`
+	// TokenType - Тип токена, всегда Bearer.
+	TokenType string `json:"token_type"`
+	// ExpiresAt - Время истечения токена в формате RFC 3339.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// TokenHandler - Обработчик обмена текущей идентичности пользователя (куки Auth или токена) на JWT токен.
+type TokenHandler struct {
+	tokens TokenIssuer
+}
+
+// NewTokenHandler - Создает новую структуру TokenHandler с указателем.
+func NewTokenHandler(tokens TokenIssuer) *TokenHandler {
+	handler := new(TokenHandler)
+	handler.tokens = tokens
+	return handler
+}
+
+// Handle - Обрабатывает входящий запрос.
+func (handler *TokenHandler) Handle(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(res, "Http method not POST", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := req.Context().Value(constants.UserIDContextKey).(string)
+	if !ok || userID == "" {
+		http.Error(res, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	token, expiresAt, err := handler.tokens.IssueToken(userID)
+	if err != nil {
+		log.Zap.Error("failed issue token", zap.Error(err))
+		http.Error(res, "Failed issue token", http.StatusInternalServerError)
+		return
+	}
+
+	res.Header().Add(HeaderContentType, HeaderApplicationJSONValue)
+	res.Header().Set("Cache-Control", "no-store")
+	res.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(res).Encode(TokenResponse{AccessToken: token, TokenType: "Bearer", ExpiresAt: expiresAt.UTC()})
+	if err != nil {
+		log.Zap.Error(ErrFailedWriteToResponse, zap.Error(err))
+		return
+	}
+}
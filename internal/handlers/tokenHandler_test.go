@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/VladSnap/shortener/internal/auth"
+	"github.com/VladSnap/shortener/internal/config"
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenHandler_Handle(t *testing.T) {
+	tokens := auth.NewJWTManager(&config.Options{JWTSecret: "secret"})
+	handler := NewTokenHandler(tokens)
+
+	t.Run("Issues token for the current user", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/token", http.NoBody)
+		req = req.WithContext(context.WithValue(req.Context(), constants.UserIDContextKey, "user1"))
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp TokenResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "Bearer", resp.TokenType)
+		assert.False(t, resp.ExpiresAt.IsZero())
+		userID, err := tokens.VerifyToken(resp.AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, "user1", userID)
+	})
+
+	t.Run("Without user", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.Handle(rec, httptest.NewRequest(http.MethodPost, "/api/auth/token", http.NoBody))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("Wrong method", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.Handle(rec, httptest.NewRequest(http.MethodGet, "/api/auth/token", http.NoBody))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/log"
+	"github.com/VladSnap/shortener/internal/validation"
+	"go.uber.org/zap"
+)
+
+// UpdateLinkRequest - Структура запроса для UpdateLinkHandler.
+type UpdateLinkRequest struct {
+	// URL - Новый оригинальный URL сокращенной ссылки.
+	URL string `json:"url"`
+}
+
+// LinkDestinationResponse - Предыдущий оригинальный URL сокращенной ссылки.
+type LinkDestinationResponse struct {
+	// OriginalURL - Оригинальный URL, на который вела ссылка.
+	OriginalURL string `json:"original_url"`
+	// ChangedAt - Время, когда ссылка перестала вести на этот URL.
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// UpdateLinkResponse - Структура ответа для UpdateLinkHandler.
+type UpdateLinkResponse struct {
+	// ShortURL - Сокращенная ссылка.
+	ShortURL string `json:"short_url"`
+	// OriginalURL - Текущий оригинальный URL.
+	OriginalURL string `json:"original_url"`
+	// History - Предыдущие оригинальные URL в порядке изменения.
+	History []LinkDestinationResponse `json:"history"`
+}
+
+// UpdateLinkHandler - Обработчик запроса изменения оригинального URL сокращенной ссылки пользователя.
+type UpdateLinkHandler struct {
+	service ShorterService
+	baseURL string
+}
+
+// NewUpdateLinkHandler - Создает новую структуру UpdateLinkHandler с указателем.
+func NewUpdateLinkHandler(service ShorterService, baseURL string) *UpdateLinkHandler {
+	handler := new(UpdateLinkHandler)
+	handler.service = service
+	handler.baseURL = baseURL
+	return handler
+}
+
+// Handle - Обрабатывает входящий запрос.
+func (handler *UpdateLinkHandler) Handle(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPatch {
+		http.Error(res, "Http method not PATCH", http.StatusBadRequest)
+		return
+	}
+
+	ct := req.Header.Get(HeaderContentType)
+	if !strings.Contains(ct, HeaderApplicationJSONValue) && !strings.Contains(ct, HeaderApplicationXgzipValue) {
+		http.Error(res, "Incorrect content-type:"+ct, http.StatusBadRequest)
+		return
+	}
+
+	shortID := req.PathValue("id")
+	if err := validation.ValidateShortURL(shortID); err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var request UpdateLinkRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validation.ValidateURL(request.URL, "URL"); err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID := ""
+	if value, ok := req.Context().Value(constants.UserIDContextKey).(string); ok {
+		userID = value
+	}
+	link, err := handler.service.UpdateLink(req.Context(), shortID, userID, request.URL)
+	if err != nil {
+		http.Error(res, err.Error(), statusCodeByError(err))
+		return
+	}
+
+	result := UpdateLinkResponse{
+		ShortURL:    handler.baseURL + "/" + link.URL,
+		OriginalURL: link.OriginalURL,
+		History:     make([]LinkDestinationResponse, 0, len(link.History)),
+	}
+	for _, destination := range link.History {
+		result.History = append(result.History, LinkDestinationResponse{
+			OriginalURL: destination.OriginalURL,
+			ChangedAt:   destination.ChangedAt,
+		})
+	}
+
+	res.Header().Add(HeaderContentType, HeaderApplicationJSONValue)
+	res.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(res).Encode(result)
+	if err != nil {
+		log.Zap.Error(ErrFailedWriteToResponse, zap.Error(err))
+		return
+	}
+}
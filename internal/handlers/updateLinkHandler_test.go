@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	m "github.com/VladSnap/shortener/internal/handlers/mocks"
+	"github.com/VladSnap/shortener/internal/services"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateLinkHandler_Handle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := m.NewMockShorterService(ctrl)
+	handler := NewUpdateLinkHandler(mockService, "http://localhost:8080")
+
+	newRequest := func(method, id, body string) *http.Request {
+		req := httptest.NewRequest(method, "/api/user/urls/"+id, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.SetPathValue("id", id)
+		ctx := context.WithValue(req.Context(), constants.UserIDContextKey, "user1")
+		return req.WithContext(ctx)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		changedAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+		mockService.EXPECT().UpdateLink(gomock.Any(), "abc", "user1", "http://new.url").Return(&services.ShortedLink{
+			URL:         "abc",
+			OriginalURL: "http://new.url",
+			History:     []services.LinkDestination{{OriginalURL: "http://old.url", ChangedAt: changedAt}},
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodPatch, "abc", `{"url":"http://new.url"}`))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"short_url":"http://localhost:8080/abc","original_url":"http://new.url",`+
+			`"history":[{"original_url":"http://old.url","changed_at":"2025-03-01T10:00:00Z"}]}`, rec.Body.String())
+	})
+
+	t.Run("Invalid HTTP Method", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodPost, "abc", `{"url":"http://new.url"}`))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Invalid URL", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodPatch, "abc", `{"url":"not a url"}`))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("URL already shortened", func(t *testing.T) {
+		mockService.EXPECT().UpdateLink(gomock.Any(), "abc", "user1", "http://taken.url").
+			Return(nil, fmt.Errorf("%w by 'other'", services.ErrOriginalURLTaken))
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodPatch, "abc", `{"url":"http://taken.url"}`))
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("Link of another user", func(t *testing.T) {
+		mockService.EXPECT().UpdateLink(gomock.Any(), "abc", "user1", "http://new.url").
+			Return(nil, fmt.Errorf("%w: 'abc'", services.ErrLinkNotOwned))
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodPatch, "abc", `{"url":"http://new.url"}`))
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
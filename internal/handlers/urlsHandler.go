@@ -2,13 +2,26 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/VladSnap/shortener/internal/constants"
 	"github.com/VladSnap/shortener/internal/log"
+	"github.com/VladSnap/shortener/internal/services"
 	"go.uber.org/zap"
 )
 
+// Параметры строки запроса для постраничного чтения ссылок пользователя.
+const (
+	queryParamLimit   = "limit"
+	queryParamCursor  = "cursor"
+	queryParamOrder   = "order"
+	queryParamDeleted = "deleted"
+	queryParamSearch  = "search"
+)
+
 // ShortedLinkResponse - Структура ответа для UrlsHandler.
 type ShortedLinkResponse struct {
 	// OriginalURL - Оригинальный URL который был сокращен.
@@ -17,6 +30,14 @@ type ShortedLinkResponse struct {
 	ShortURL string `json:"short_url"`
 }
 
+// ShortedLinksPageResponse - Структура ответа для UrlsHandler при постраничном чтении.
+type ShortedLinksPageResponse struct {
+	// Urls - Ссылки текущей страницы.
+	Urls []*ShortedLinkResponse `json:"urls"`
+	// NextCursor - Курсор следующей страницы, отсутствует на последней странице.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
 // UrlsHandler - Обработчик запроса чтения сокращенных ссылок пользователя.
 type UrlsHandler struct {
 	service ShorterService
@@ -32,39 +53,88 @@ func NewUrlsHandler(service ShorterService, baseURL string) *UrlsHandler {
 }
 
 // Handle - Обрабатывает входящий запрос.
+// Без параметров постраничного чтения возвращает массив всех ссылок пользователя, как и раньше,
+// а с любым из параметров limit, cursor, order, deleted, search - страницу ссылок и курсор следующей.
 func (handler *UrlsHandler) Handle(res http.ResponseWriter, req *http.Request) {
 	if req.Method != http.MethodGet {
 		http.Error(res, ValidateErrHTTPNotGET, http.StatusBadRequest)
 		return
 	}
 
+	query, err := parseUserLinksQuery(req.URL.Query())
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	userID := ""
 	if value, ok := req.Context().Value(constants.UserIDContextKey).(string); ok {
 		userID = value
 	}
-	shortedLinks, err := handler.service.GetAllByUserID(req.Context(), userID)
+	page, err := handler.service.GetAllByUserID(req.Context(), userID, query)
 	if err != nil {
-		http.Error(res, err.Error(), http.StatusBadRequest)
+		http.Error(res, err.Error(), statusCodeByError(err))
 		return
 	}
 
-	if len(shortedLinks) == 0 {
+	if query == nil && len(page.Links) == 0 {
 		http.Error(res, "Urls for user not found", http.StatusNoContent)
 		return
 	}
 
-	responseRows := make([]*ShortedLinkResponse, 0, len(shortedLinks))
-	for _, sl := range shortedLinks {
+	responseRows := make([]*ShortedLinkResponse, 0, len(page.Links))
+	for _, sl := range page.Links {
 		rr := &ShortedLinkResponse{sl.OriginalURL, handler.baseURL + "/" + sl.URL}
 		responseRows = append(responseRows, rr)
 	}
 
 	res.Header().Add(HeaderContentType, HeaderApplicationJSONValue)
 	res.WriteHeader(http.StatusOK)
-	err = json.NewEncoder(res).Encode(responseRows)
+	if query == nil {
+		err = json.NewEncoder(res).Encode(responseRows)
+	} else {
+		err = json.NewEncoder(res).Encode(ShortedLinksPageResponse{Urls: responseRows, NextCursor: page.NextCursor})
+	}
 
 	if err != nil {
 		log.Zap.Error(ErrFailedWriteToResponse, zap.Error(err))
 		return
 	}
 }
+
+// parseUserLinksQuery - Читает параметры постраничного чтения из строки запроса.
+// Возвращает nil, если ни один из параметров не задан.
+func parseUserLinksQuery(values url.Values) (*services.UserLinksQuery, error) {
+	if !values.Has(queryParamLimit) && !values.Has(queryParamCursor) && !values.Has(queryParamOrder) &&
+		!values.Has(queryParamDeleted) && !values.Has(queryParamSearch) {
+		return nil, nil //nolint:nilnil // no pagination params
+	}
+
+	query := &services.UserLinksQuery{
+		Limit:  constants.UserLinksDefaultLimit,
+		Cursor: values.Get(queryParamCursor),
+		Search: values.Get(queryParamSearch),
+	}
+	if values.Has(queryParamLimit) {
+		limit, err := strconv.Atoi(values.Get(queryParamLimit))
+		if err != nil || limit <= 0 {
+			return nil, errors.New("limit must be a positive integer")
+		}
+		query.Limit = limit
+	}
+	switch values.Get(queryParamOrder) {
+	case "", "asc":
+	case "desc":
+		query.Desc = true
+	default:
+		return nil, errors.New("order must be 'asc' or 'desc'")
+	}
+	if values.Has(queryParamDeleted) {
+		deleted, err := strconv.ParseBool(values.Get(queryParamDeleted))
+		if err != nil {
+			return nil, errors.New("deleted must be 'true' or 'false'")
+		}
+		query.Deleted = &deleted
+	}
+	return query, nil
+}
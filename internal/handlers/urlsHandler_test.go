@@ -1,25 +1,105 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	m "github.com/VladSnap/shortener/internal/handlers/mocks"
+	"github.com/VladSnap/shortener/internal/services"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestUrlsHandler_Handle(t *testing.T) {
-	type args struct {
-		res http.ResponseWriter
-		req *http.Request
-	}
+	deleted := false
 	tests := []struct {
-		name    string
-		handler *UrlsHandler
-		args    args
+		name      string
+		target    string
+		query     *services.UserLinksQuery
+		page      *services.UserLinksPage
+		wantCode  int
+		wantBody  string
+		callsRepo bool
 	}{
-		// TODO: Add test cases.
+		{
+			name:      "all links without pagination",
+			target:    "/api/user/urls",
+			page:      &services.UserLinksPage{Links: []*services.ShortedLink{{URL: "abc", OriginalURL: "http://a.b"}}},
+			wantCode:  http.StatusOK,
+			wantBody:  `[{"original_url":"http://a.b","short_url":"http://localhost/abc"}]`,
+			callsRepo: true,
+		},
+		{
+			name:      "no links without pagination",
+			target:    "/api/user/urls",
+			page:      &services.UserLinksPage{},
+			wantCode:  http.StatusNoContent,
+			callsRepo: true,
+		},
+		{
+			name:   "page with filters",
+			target: "/api/user/urls?limit=1&cursor=xyz&order=desc&deleted=false&search=a.b",
+			query: &services.UserLinksQuery{
+				Limit: 1, Cursor: "xyz", Desc: true, Deleted: &deleted, Search: "a.b",
+			},
+			page: &services.UserLinksPage{
+				Links:      []*services.ShortedLink{{URL: "abc", OriginalURL: "http://a.b"}},
+				NextCursor: "next",
+			},
+			wantCode: http.StatusOK,
+			wantBody: `{"urls":[{"original_url":"http://a.b","short_url":"http://localhost/abc"}],` +
+				`"next_cursor":"next"}`,
+			callsRepo: true,
+		},
+		{
+			name:      "empty page uses default limit",
+			target:    "/api/user/urls?search=none",
+			query:     &services.UserLinksQuery{Limit: constants.UserLinksDefaultLimit, Search: "none"},
+			page:      &services.UserLinksPage{},
+			wantCode:  http.StatusOK,
+			wantBody:  `{"urls":[]}`,
+			callsRepo: true,
+		},
+		{
+			name:     "invalid limit",
+			target:   "/api/user/urls?limit=-1",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid order",
+			target:   "/api/user/urls?order=up",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid deleted",
+			target:   "/api/user/urls?deleted=maybe",
+			wantCode: http.StatusBadRequest,
+		},
 	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := m.NewMockShorterService(ctrl)
+	handler := NewUrlsHandler(mockService, "http://localhost")
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tt.handler.Handle(tt.args.res, tt.args.req)
+			req := httptest.NewRequest(http.MethodGet, tt.target, http.NoBody)
+			req = req.WithContext(context.WithValue(req.Context(), constants.UserIDContextKey, "user1"))
+			if tt.callsRepo {
+				mockService.EXPECT().GetAllByUserID(gomock.Any(), "user1", tt.query).Return(tt.page, nil)
+			}
+			rec := httptest.NewRecorder()
+
+			handler.Handle(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rec.Body.String())
+			}
 		})
 	}
 }
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/log"
+	"go.uber.org/zap"
+)
+
+// LinkClicksResponse - Количество переходов по сокращенной ссылке.
+type LinkClicksResponse struct {
+	// ShortURL - Сокращенная ссылка.
+	ShortURL string `json:"short_url"`
+	// Clicks - Количество переходов.
+	Clicks int `json:"clicks"`
+}
+
+// UserStatsResponse - Структура ответа для UserStatsHandler.
+type UserStatsResponse struct {
+	// ActiveUrls - Количество действующих ссылок пользователя.
+	ActiveUrls int `json:"active_urls"`
+	// DeletedUrls - Количество удаленных ссылок пользователя.
+	DeletedUrls int `json:"deleted_urls"`
+	// ExpiredUrls - Количество истекших ссылок пользователя.
+	ExpiredUrls int `json:"expired_urls"`
+	// TotalClicks - Общее количество переходов по ссылкам пользователя.
+	TotalClicks int `json:"total_clicks"`
+	// TopUrls - Самые популярные ссылки пользователя.
+	TopUrls []LinkClicksResponse `json:"top_urls"`
+}
+
+// UserStatsHandler - Обработчик запроса агрегированной статистики по ссылкам пользователя.
+type UserStatsHandler struct {
+	service ShorterService
+	baseURL string
+}
+
+// NewUserStatsHandler - Создает новую структуру UserStatsHandler с указателем.
+func NewUserStatsHandler(service ShorterService, baseURL string) *UserStatsHandler {
+	handler := new(UserStatsHandler)
+	handler.service = service
+	handler.baseURL = baseURL
+	return handler
+}
+
+// Handle - Обрабатывает входящий запрос.
+func (handler *UserStatsHandler) Handle(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(res, ValidateErrHTTPNotGET, http.StatusBadRequest)
+		return
+	}
+
+	userID := ""
+	if value, ok := req.Context().Value(constants.UserIDContextKey).(string); ok {
+		userID = value
+	}
+
+	stats, err := handler.service.GetUserStats(req.Context(), userID)
+	if err != nil {
+		http.Error(res, err.Error(), statusCodeByError(err))
+		return
+	}
+
+	result := UserStatsResponse{
+		ActiveUrls:  stats.Active,
+		DeletedUrls: stats.Deleted,
+		ExpiredUrls: stats.Expired,
+		TotalClicks: stats.TotalClicks,
+		TopUrls:     make([]LinkClicksResponse, 0, len(stats.TopLinks)),
+	}
+	for _, link := range stats.TopLinks {
+		result.TopUrls = append(result.TopUrls, LinkClicksResponse{
+			ShortURL: handler.baseURL + "/" + link.ShortURL,
+			Clicks:   link.Clicks,
+		})
+	}
+
+	res.Header().Add(HeaderContentType, HeaderApplicationJSONValue)
+	res.WriteHeader(http.StatusOK)
+	err = json.NewEncoder(res).Encode(result)
+
+	if err != nil {
+		log.Zap.Error(ErrFailedWriteToResponse, zap.Error(err))
+		return
+	}
+}
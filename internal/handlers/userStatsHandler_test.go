@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	m "github.com/VladSnap/shortener/internal/handlers/mocks"
+	"github.com/VladSnap/shortener/internal/services"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserStatsHandler_Handle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockService := m.NewMockShorterService(ctrl)
+	handler := NewUserStatsHandler(mockService, "http://localhost")
+
+	newRequest := func(method string) *http.Request {
+		req := httptest.NewRequest(method, "/api/user/stats", http.NoBody)
+		ctx := context.WithValue(req.Context(), constants.UserIDContextKey, "user1")
+		return req.WithContext(ctx)
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockService.EXPECT().GetUserStats(gomock.Any(), "user1").Return(&services.UserStats{
+			Active:      2,
+			Deleted:     1,
+			Expired:     1,
+			TotalClicks: 5,
+			TopLinks:    []services.LinkClicks{{ShortURL: "abc", Clicks: 5}},
+		}, nil)
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodGet))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"active_urls":2,"deleted_urls":1,"expired_urls":1,"total_clicks":5,`+
+			`"top_urls":[{"short_url":"http://localhost/abc","clicks":5}]}`, rec.Body.String())
+	})
+
+	t.Run("Invalid HTTP Method", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodPost))
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Service error", func(t *testing.T) {
+		mockService.EXPECT().GetUserStats(gomock.Any(), "user1").Return(nil, errors.New("repo failed"))
+		rec := httptest.NewRecorder()
+
+		handler.Handle(rec, newRequest(http.MethodGet))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
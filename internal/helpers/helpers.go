@@ -5,21 +5,28 @@ import (
 	crypto "crypto/rand"
 	"fmt"
 	"math/big"
+	"net"
+	"net/http"
 	"os"
 )
 
 var letters = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
 
-// RandStringRunes - Генерирует случайную строку из заданного набора символов заданной длины.
+// RandStringRunes - Генерирует случайную строку из латинских букв заданной длины.
 func RandStringRunes(n int) (string, error) {
+	return RandStringFrom(letters, n)
+}
+
+// RandStringFrom - Генерирует криптографически случайную строку из заданного набора символов заданной длины.
+func RandStringFrom(alphabet []rune, n int) (string, error) {
 	b := make([]rune, n)
-	maxLetters := big.NewInt(int64(len(letters)))
+	maxLetters := big.NewInt(int64(len(alphabet)))
 	for i := range b {
 		rndIndex, err := crypto.Int(crypto.Reader, maxLetters)
 		if err != nil {
 			return "", fmt.Errorf("failed create new letter: %w", err)
 		}
-		b[i] = letters[rndIndex.Int64()]
+		b[i] = alphabet[rndIndex.Int64()]
 	}
 	return string(b), nil
 }
@@ -39,3 +46,60 @@ func DirectoryExists(path string) (bool, error) {
 	// Возвращаем любую другую ошибку.
 	return false, fmt.Errorf("failed check directory exists: %w", err)
 }
+
+// GetClientIP - Возвращает IP адрес клиента. Заголовку X-Real-IP доверяется, только если соединение
+// пришло из доверенной сети прокси, иначе клиент мог бы подставить в него любой адрес.
+// Без заголовка или от недоверенного адреса возвращается адрес соединения.
+func GetClientIP(req *http.Request, trustedProxies []*net.IPNet) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	if realIP := req.Header.Get("X-Real-IP"); realIP != "" && isTrustedProxy(host, trustedProxies) {
+		return realIP
+	}
+	return host
+}
+
+// ParseTrustedProxies - Разбирает доверенную подсеть и адреса прокси, от которых принимается заголовок X-Real-IP.
+// Прокси задаются подсетью в формате CIDR или отдельным IP адресом.
+func ParseTrustedProxies(trustedSubnet string, proxies []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, 1+len(proxies))
+	if trustedSubnet != "" {
+		_, subnet, err := net.ParseCIDR(trustedSubnet)
+		if err != nil {
+			return nil, fmt.Errorf("failed parse trusted subnet: %w", err)
+		}
+		networks = append(networks, subnet)
+	}
+	for _, proxy := range proxies {
+		if _, subnet, err := net.ParseCIDR(proxy); err == nil {
+			networks = append(networks, subnet)
+			continue
+		}
+		ip := net.ParseIP(proxy)
+		if ip == nil {
+			return nil, fmt.Errorf("failed parse trusted proxy '%s', it should be an IP or CIDR", proxy)
+		}
+		bits := 8 * net.IPv6len
+		if ipv4 := ip.To4(); ipv4 != nil {
+			ip, bits = ipv4, 8*net.IPv4len
+		}
+		networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return networks, nil
+}
+
+// isTrustedProxy - Проверяет, что адрес соединения входит в одну из доверенных сетей прокси.
+func isTrustedProxy(host string, trustedProxies []*net.IPNet) bool {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,13 @@
 package helpers
 
-import "testing"
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
 
 const randStringRues int = 8
 
@@ -9,3 +16,33 @@ func BenchmarkRandStringRunes(b *testing.B) {
 		_, _ = RandStringRunes(randStringRues)
 	}
 }
+
+func TestGetClientIP(t *testing.T) {
+	trustedProxies, err := ParseTrustedProxies("10.0.0.0/8", []string{"192.0.2.1", "2001:db8::/32"})
+	require.NoError(t, err)
+	tests := []struct {
+		name       string
+		remoteAddr string
+		realIP     string
+		want       string
+	}{
+		{name: "trusted subnet", remoteAddr: "10.1.2.3:1234", realIP: "203.0.113.7", want: "203.0.113.7"},
+		{name: "trusted proxy IP", remoteAddr: "192.0.2.1:1234", realIP: "203.0.113.7", want: "203.0.113.7"},
+		{name: "trusted IPv6 proxy", remoteAddr: "[2001:db8::1]:1234", realIP: "203.0.113.7", want: "203.0.113.7"},
+		{name: "untrusted address", remoteAddr: "192.0.2.2:1234", realIP: "203.0.113.7", want: "192.0.2.2"},
+		{name: "without header", remoteAddr: "10.1.2.3:1234", want: "10.1.2.3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+			assert.Equal(t, tt.want, GetClientIP(req, trustedProxies))
+		})
+	}
+
+	_, err = ParseTrustedProxies("", []string{"proxy.local"})
+	assert.Error(t, err)
+}
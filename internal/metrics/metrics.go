@@ -0,0 +1,211 @@
+// Package metrics собирает метрики приложения в формате Prometheus.
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/VladSnap/shortener/internal/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace - Префикс имен всех метрик приложения.
+const namespace = "shortener"
+
+// Результаты операций репозитория.
+const (
+	resultOK       = "ok"
+	resultConflict = "conflict"
+	resultError    = "error"
+)
+
+// Metrics - Метрики HTTP и gRPC запросов, операций репозитория, воркера удаления и сокращенных ссылок.
+// Метрики регистрируются в собственном реестре, чтобы несколько серверов в одном процессе не конфликтовали.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests        *prometheus.CounterVec
+	httpDuration        *prometheus.HistogramVec
+	grpcRequests        *prometheus.CounterVec
+	grpcDuration        *prometheus.HistogramVec
+	repoDuration        *prometheus.HistogramVec
+	deleteBufferSize    prometheus.Gauge
+	deleteFlushFailures prometheus.Counter
+	deleteDeadLetters   prometheus.Counter
+	shortened           *prometheus.CounterVec
+	redirects           *prometheus.CounterVec
+	rateLimited         *prometheus.CounterVec
+}
+
+// New - Создает новую структуру Metrics с указателем и регистрирует все метрики.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of HTTP requests by method and route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_requests_total",
+			Help:      "Number of gRPC requests by method and status code.",
+		}, []string{"method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_request_duration_seconds",
+			Help:      "Duration of gRPC requests by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		repoDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_operation_duration_seconds",
+			Help:      "Duration of short link repository operations by operation and result.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "result"}),
+		deleteBufferSize: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "delete_worker_buffer_size",
+			Help:      "Number of short links waiting in the delete worker queue.",
+		}),
+		deleteFlushFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "delete_worker_flush_failures_total",
+			Help:      "Number of failed delete worker batches.",
+		}),
+		deleteDeadLetters: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "delete_worker_dead_letters_total",
+			Help:      "Number of delete tasks moved to the dead letters after exhausting retries.",
+		}),
+		shortened: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "shortened_links_total",
+			Help:      "Number of shortened links by status: created, duplicate or error.",
+		}, []string{"status"}),
+		redirects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "redirects_total",
+			Help:      "Number of short link redirects by result.",
+		}, []string{"result"}),
+		rateLimited: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_limited_requests_total",
+			Help:      "Number of requests rejected by the rate limiter by limit: create, batch or redirect.",
+		}, []string{"limit"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests, m.httpDuration,
+		m.grpcRequests, m.grpcDuration,
+		m.repoDuration,
+		m.deleteBufferSize, m.deleteFlushFailures, m.deleteDeadLetters,
+		m.shortened, m.redirects,
+		m.rateLimited,
+	)
+	return m
+}
+
+// Handler - Возвращает HTTP обработчик, отдающий метрики в текстовом формате Prometheus.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Registry - Возвращает реестр метрик, например, для проверки значений в тестах.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// ObserveHTTPRequest - Учитывает HTTP запрос. route - шаблон маршрута, а не путь, чтобы ограничить число меток.
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// ObserveGRPCRequest - Учитывает gRPC запрос.
+func (m *Metrics) ObserveGRPCRequest(method, code string, duration time.Duration) {
+	m.grpcRequests.WithLabelValues(method, code).Inc()
+	m.grpcDuration.WithLabelValues(method).Observe(duration.Seconds())
+}
+
+// ObserveRepoOperation - Учитывает длительность и результат операции репозитория.
+// Дубли и конфликты идентификаторов - ожидаемые исходы, поэтому учитываются отдельно от ошибок.
+func (m *Metrics) ObserveRepoOperation(operation string, duration time.Duration, err error) {
+	m.repoDuration.WithLabelValues(operation, repoResult(err)).Observe(duration.Seconds())
+}
+
+// AddShortened - Учитывает сокращенную ссылку с указанным статусом.
+func (m *Metrics) AddShortened(status services.BatchItemStatus) {
+	m.shortened.WithLabelValues(string(status)).Inc()
+}
+
+// AddRedirect - Учитывает переход по сокращенной ссылке с указанным результатом.
+func (m *Metrics) AddRedirect(result string) {
+	m.redirects.WithLabelValues(result).Inc()
+}
+
+// AddRateLimited - Учитывает запрос, отклоненный ограничителем частоты запросов.
+func (m *Metrics) AddRateLimited(limit string) {
+	m.rateLimited.WithLabelValues(limit).Inc()
+}
+
+// SetDeleteBufferSize - Устанавливает текущее количество ссылок в очереди воркера удаления.
+func (m *Metrics) SetDeleteBufferSize(size int) {
+	m.deleteBufferSize.Set(float64(size))
+}
+
+// AddDeleteFlushFailure - Учитывает неудачную попытку удалить пачку ссылок из очереди.
+func (m *Metrics) AddDeleteFlushFailure() {
+	m.deleteFlushFailures.Inc()
+}
+
+// AddDeleteDeadLetters - Учитывает задачи удаления, исчерпавшие попытки и перенесенные в dead letter.
+func (m *Metrics) AddDeleteDeadLetters(count int) {
+	m.deleteDeadLetters.Add(float64(count))
+}
+
+// RegisterCache - Регистрирует метрики кеша сокращенных ссылок, значения читаются из stats при сборе.
+func (m *Metrics) RegisterCache(stats func() services.CacheStats) {
+	m.registry.MustRegister(
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_hits_total",
+			Help:      "Number of short link cache hits.",
+		}, func() float64 { return float64(stats().Hits) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_misses_total",
+			Help:      "Number of short link cache misses.",
+		}, func() float64 { return float64(stats().Misses) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cache_size",
+			Help:      "Number of entries in the short link cache.",
+		}, func() float64 { return float64(stats().Size) }),
+	)
+}
+
+// repoResult - Возвращает значение метки результата операции репозитория.
+func repoResult(err error) string {
+	if err == nil {
+		return resultOK
+	}
+	var duplErr *data.DuplicateShortLinkError
+	var conflictErr *data.ShortURLConflictError
+	if errors.As(err, &duplErr) || errors.As(err, &conflictErr) {
+		return resultConflict
+	}
+	return resultError
+}
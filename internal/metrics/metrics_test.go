@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/VladSnap/shortener/internal/services"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	m := New()
+
+	t.Run("repository results", func(t *testing.T) {
+		m.ObserveRepoOperation("add", time.Millisecond, nil)
+		m.ObserveRepoOperation("add", time.Millisecond, data.NewDuplicateError("link0001"))
+		m.ObserveRepoOperation("add", time.Millisecond, errors.New("connection refused"))
+
+		// По одной серии на каждый результат: ok, conflict и error.
+		assert.Equal(t, 3, testutil.CollectAndCount(m.repoDuration))
+	})
+
+	t.Run("counters", func(t *testing.T) {
+		m.AddShortened(services.BatchItemCreated)
+		m.AddShortened(services.BatchItemCreated)
+		m.AddRedirect("found")
+		m.SetDeleteBufferSize(3)
+		m.AddDeleteFlushFailure()
+		m.AddDeleteDeadLetters(2)
+
+		assert.InDelta(t, 2, testutil.ToFloat64(m.shortened.WithLabelValues("created")), 0)
+		assert.InDelta(t, 1, testutil.ToFloat64(m.redirects.WithLabelValues("found")), 0)
+		assert.InDelta(t, 3, testutil.ToFloat64(m.deleteBufferSize), 0)
+		assert.InDelta(t, 1, testutil.ToFloat64(m.deleteFlushFailures), 0)
+		assert.InDelta(t, 2, testutil.ToFloat64(m.deleteDeadLetters), 0)
+	})
+
+	t.Run("handler exposes metrics", func(t *testing.T) {
+		m.ObserveHTTPRequest(http.MethodGet, "/{id}", http.StatusNotFound, time.Millisecond)
+		m.ObserveGRPCRequest("/shortener.ShortenerService/GetURL", "OK", time.Millisecond)
+		m.RegisterCache(func() services.CacheStats { return services.CacheStats{Hits: 5, Misses: 2, Size: 1} })
+
+		rec := httptest.NewRecorder()
+		m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		body := rec.Body.String()
+		for _, line := range []string{
+			`shortener_http_requests_total{method="GET",route="/{id}",status="404"} 1`,
+			`shortener_grpc_requests_total{code="OK",method="/shortener.ShortenerService/GetURL"} 1`,
+			`shortener_cache_hits_total 5`,
+			`shortener_delete_worker_buffer_size 3`,
+			`shortener_repository_operation_duration_seconds_count{operation="add",result="conflict"} 1`,
+		} {
+			assert.True(t, strings.Contains(body, line), line)
+		}
+	})
+}
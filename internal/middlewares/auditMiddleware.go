@@ -0,0 +1,21 @@
+package middlewares
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/VladSnap/shortener/internal/helpers"
+	"github.com/VladSnap/shortener/internal/services"
+)
+
+// AuditMiddleware - Мидлварь, которая сохраняет в контексте запроса источник HTTP и IP клиента
+// для журнала аудита действий со ссылками. Заголовок X-Real-IP учитывается только от trustedProxies.
+func AuditMiddleware(trustedProxies []*net.IPNet) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := services.WithAuditActor(r.Context(), services.AuditActor{Source: services.AuditSourceHTTP,
+				ClientIP: helpers.GetClientIP(r, trustedProxies)})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -3,6 +3,7 @@ package middlewares
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -10,28 +11,63 @@ import (
 	"github.com/VladSnap/shortener/internal/config"
 	"github.com/VladSnap/shortener/internal/constants"
 	"github.com/VladSnap/shortener/internal/log"
+	"github.com/VladSnap/shortener/internal/services"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// authCookieName - Имя куки с подписанным идентификатором пользователя.
+const authCookieName = "Auth"
+
+// apiKeyHeader - Заголовок с ключом API межсервисного клиента.
+const apiKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator - Интерфейс проверки ключей API.
+type APIKeyAuthenticator interface {
+	// Authenticate - Находит действующий ключ API.
+	Authenticate(ctx context.Context, key string) (*services.APIKey, error)
+}
+
 // AuthMiddleware - Мидлварь для аутентификации и атворизации пользователя.
-func AuthMiddleware(opts *config.Options) func(next http.Handler) http.Handler {
+// Если передан заголовок X-API-Key, пользователь определяется по ключу API, если заголовок Authorization,
+// то только по JWT токену из него, иначе по подписанной куке Auth. Если apiKeys nil, ключи API не принимаются.
+func AuthMiddleware(opts *config.Options, cookieRing *auth.CookieKeyRing,
+	apiKeys APIKeyAuthenticator) func(next http.Handler) http.Handler {
+	tokens := auth.NewJWTManager(opts)
+	cookies := newAuthCookies(opts, cookieRing)
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			authCookie, err := r.Cookie("Auth")
-			if err != nil {
-				userID := handleMissingAuthCookie(w, opts)
-				r = r.WithContext(context.WithValue(r.Context(), constants.UserIDContextKey, userID))
+			if key := r.Header.Get(apiKeyHeader); key != "" && apiKeys != nil {
+				apiKey, ok := handleAPIKey(w, r, key, apiKeys)
+				if !ok {
+					return
+				}
+				ctx := services.ContextWithAPIKey(r.Context(), apiKey)
+				r = r.WithContext(context.WithValue(ctx, constants.UserIDContextKey, apiKey.UserID))
 				next.ServeHTTP(w, r)
 				return
 			}
 
-			if !handleUnauthorized(w, authCookie, opts) {
+			if authorization := r.Header.Get("Authorization"); authorization != "" {
+				userID, ok := handleBearerToken(w, authorization, tokens)
+				if !ok {
+					return
+				}
+				r = r.WithContext(context.WithValue(r.Context(), constants.UserIDContextKey, userID))
+				next.ServeHTTP(w, r)
 				return
 			}
 
-			authData, ok := handleAuthCookie(w, authCookie)
+			authCookie, err := r.Cookie(authCookieName)
+			if err != nil {
+				userID := handleMissingAuthCookie(w, cookies)
+				ctx := context.WithValue(r.Context(), constants.NewUserContextKey, true)
+				r = r.WithContext(context.WithValue(ctx, constants.UserIDContextKey, userID))
+				next.ServeHTTP(w, r)
+				return
+			}
 
+			authData, ok := handleAuthCookie(w, authCookie, cookies)
 			if !ok {
 				return
 			}
@@ -42,8 +78,64 @@ func AuthMiddleware(opts *config.Options) func(next http.Handler) http.Handler {
 	}
 }
 
-func handleMissingAuthCookie(w http.ResponseWriter, opts *config.Options) string {
-	userID, err := setNewAuthCookie(w, opts)
+// RequireScope - Мидлварь, которая отклоняет запросы с ключом API без разрешения scope.
+func RequireScope(scope services.Scope) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !services.ScopeAllowed(r.Context(), scope) {
+				http.Error(w, fmt.Sprintf("API key has no '%s' scope", scope), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// DenyAPIKey - Мидлварь, которая отклоняет запросы с ключом API, например обмен на JWT токен.
+func DenyAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := services.APIKeyFromContext(r.Context()); ok {
+			http.Error(w, "Not allowed for API keys", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func handleAPIKey(w http.ResponseWriter, r *http.Request, key string,
+	apiKeys APIKeyAuthenticator) (*services.APIKey, bool) {
+	apiKey, err := apiKeys.Authenticate(r.Context(), key)
+	if errors.Is(err, services.ErrInvalidAPIKey) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+	if err != nil {
+		log.Zap.Error("failed authenticate api key", zap.Error(err))
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return nil, false
+	}
+	return apiKey, true
+}
+
+func handleBearerToken(w http.ResponseWriter, authorization string, tokens *auth.JWTManager) (string, bool) {
+	token, ok := auth.ParseBearerToken(authorization)
+	if !ok {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_request"`)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return "", false
+	}
+	userID, err := tokens.VerifyToken(token)
+	if err != nil {
+		log.Zap.Warn("failed verifyToken", zap.Error(err))
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return "", false
+	}
+	return userID, true
+}
+
+func handleMissingAuthCookie(w http.ResponseWriter, cookies *authCookies) string {
+	userID, err := setNewAuthCookie(w, cookies)
 	if err != nil {
 		log.Zap.Warn("failed setNewAuthCookie", zap.Error(err))
 	}
@@ -51,41 +143,77 @@ func handleMissingAuthCookie(w http.ResponseWriter, opts *config.Options) string
 	return userID
 }
 
-func handleUnauthorized(w http.ResponseWriter, authCookie *http.Cookie, opts *config.Options) bool {
-	if _, err := auth.VerifySignCookie(authCookie.Value, opts.AuthCookieKey); err != nil {
-		log.Zap.Warn("failed verifySignCookie", zap.Error(err))
-		_, err = setNewAuthCookie(w, opts)
+// handleAuthCookie - Проверяет куку. Невалидная или истекшая кука заменяется новой с новым пользователем,
+// а кука, подписанная предыдущим ключом или старая, перевыпускается для того же пользователя.
+func handleAuthCookie(w http.ResponseWriter, authCookie *http.Cookie,
+	cookies *authCookies) (*auth.CookieAuthData, bool) {
+	authData, needRotate, err := cookies.ring.Verify(authCookie.Value)
+	if err != nil {
+		log.Zap.Warn("failed verify auth cookie", zap.Error(err))
+		_, err = setNewAuthCookie(w, cookies)
 		if err != nil {
 			log.Zap.Warn("failed setNewAuthCookie", zap.Error(err))
 		}
 		http.Error(w, "Unauthorized", http.StatusUnauthorized)
-		return false
-	}
-	return true
-}
-
-func handleAuthCookie(w http.ResponseWriter, authCookie *http.Cookie) (*auth.CookieAuthData, bool) {
-	authData, err := auth.DecodeCookie(authCookie.Value)
-	if err != nil {
-		log.Zap.Warn("failed decodeCookie", zap.Error(err))
-		http.Error(w, "Not decoded cookie: %w", http.StatusInternalServerError)
 		return nil, false
 	}
+	if needRotate {
+		if err := cookies.set(w, authData.UserID); err != nil {
+			log.Zap.Warn("failed rotate auth cookie", zap.Error(err))
+		}
+	}
 	return authData, true
 }
 
-func setNewAuthCookie(w http.ResponseWriter, opts *config.Options) (string, error) {
+func setNewAuthCookie(w http.ResponseWriter, cookies *authCookies) (string, error) {
 	id, err := uuid.NewRandom()
 	if err != nil {
 		return "", fmt.Errorf("failed generate new user id: %w", err)
 	}
 
-	cookieValue, err := auth.CreateSignedCookie(id.String(), opts.AuthCookieKey)
+	if err := cookies.set(w, id.String()); err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
+
+// authCookies - Выпускает куки Auth с атрибутами из конфига.
+type authCookies struct {
+	ring     *auth.CookieKeyRing
+	secure   bool
+	sameSite http.SameSite
+}
+
+func newAuthCookies(opts *config.Options, ring *auth.CookieKeyRing) *authCookies {
+	sameSite := http.SameSiteLaxMode
+	switch opts.AuthCookieSameSite {
+	case constants.AuthCookieSameSiteStrict:
+		sameSite = http.SameSiteStrictMode
+	case constants.AuthCookieSameSiteNone:
+		sameSite = http.SameSiteNoneMode
+	}
+	return &authCookies{
+		ring:     ring,
+		secure:   opts.AuthCookieSecure != nil && *opts.AuthCookieSecure,
+		sameSite: sameSite,
+	}
+}
+
+// set - Устанавливает в ответ новую куку Auth для пользователя.
+func (cookies *authCookies) set(w http.ResponseWriter, userID string) error {
+	cookieValue, err := cookies.ring.Issue(userID)
 	if err != nil {
-		return "", fmt.Errorf("failed createSignedCookie: %w", err)
+		return fmt.Errorf("failed createSignedCookie: %w", err)
 	}
 
-	cookie := &http.Cookie{Name: "Auth", Value: cookieValue, Path: "/"}
-	http.SetCookie(w, cookie)
-	return id.String(), nil
+	http.SetCookie(w, &http.Cookie{
+		Name:     authCookieName,
+		Value:    cookieValue,
+		Path:     "/",
+		MaxAge:   int(cookies.ring.MaxAge().Seconds()),
+		Secure:   cookies.secure,
+		HttpOnly: true,
+		SameSite: cookies.sameSite,
+	})
+	return nil
 }
@@ -0,0 +1,79 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/auth"
+	"github.com/VladSnap/shortener/internal/config"
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthMiddlewareCookies(t *testing.T) {
+	secure := true
+	opts := &config.Options{AuthCookieKey: "new", AuthCookiePreviousKeys: []string{"old"},
+		AuthCookieMaxAgeSec: 3600, AuthCookieSecure: &secure, AuthCookieSameSite: constants.AuthCookieSameSiteStrict}
+	ring, err := auth.NewCookieKeyRing(opts)
+	require.NoError(t, err)
+	var userID string
+	handler := AuthMiddleware(opts, ring, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ = r.Context().Value(constants.UserIDContextKey).(string)
+	}))
+	serve := func(cookieValue string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, "/api/user/urls", http.NoBody)
+		if cookieValue != "" {
+			req.AddCookie(&http.Cookie{Name: authCookieName, Value: cookieValue})
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Result()
+	}
+
+	t.Run("New cookie has expiry and security attributes", func(t *testing.T) {
+		res := serve("")
+		require.NoError(t, res.Body.Close())
+		require.Len(t, res.Cookies(), 1)
+		cookie := res.Cookies()[0]
+		assert.Equal(t, 3600, cookie.MaxAge)
+		assert.True(t, cookie.Secure)
+		assert.True(t, cookie.HttpOnly)
+		assert.Equal(t, http.SameSiteStrictMode, cookie.SameSite)
+
+		authData, needRotate, err := ring.Verify(cookie.Value)
+		require.NoError(t, err)
+		assert.False(t, needRotate)
+		assert.Equal(t, userID, authData.UserID)
+	})
+
+	t.Run("Cookie signed with previous key is rotated", func(t *testing.T) {
+		oldCookie, err := auth.CreateSignedCookie(&auth.CookieAuthData{UserID: "user1",
+			ExpiresAt: time.Now().Add(time.Hour).Unix()}, "old")
+		require.NoError(t, err)
+
+		res := serve(oldCookie)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "user1", userID)
+		require.Len(t, res.Cookies(), 1)
+		newRing, err := auth.NewCookieKeyRing(&config.Options{AuthCookieKey: "new"})
+		require.NoError(t, err)
+		authData, needRotate, err := newRing.Verify(res.Cookies()[0].Value)
+		require.NoError(t, err)
+		assert.False(t, needRotate)
+		assert.Equal(t, "user1", authData.UserID)
+	})
+
+	t.Run("Cookie with unknown key is rejected", func(t *testing.T) {
+		cookie, err := auth.CreateSignedCookie(&auth.CookieAuthData{UserID: "user1"}, "other")
+		require.NoError(t, err)
+
+		res := serve(cookie)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		assert.Len(t, res.Cookies(), 1, "a new identity is issued")
+	})
+}
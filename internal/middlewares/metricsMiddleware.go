@@ -0,0 +1,48 @@
+package middlewares
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/metrics"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// unmatchedRoute - Метка маршрута для запросов, не подошедших ни к одному маршруту.
+const unmatchedRoute = "unmatched"
+
+// MetricsMiddleware - Мидлварь для учета количества и длительности запросов по маршрутам chi.
+func MetricsMiddleware(recorder *metrics.Metrics) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r)
+
+			route := routePattern(r)
+			if route == "" {
+				route = unmatchedRoute
+			}
+			recorder.ObserveHTTPRequest(r.Method, route, responseStatus(ww), time.Since(start))
+		})
+	}
+}
+
+// routePattern - Возвращает шаблон маршрута chi, например /{id}, или пустую строку, если маршрут не найден.
+// Шаблон известен только после того, как роутер выбрал обработчик.
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		return rctx.RoutePattern()
+	}
+	return ""
+}
+
+// responseStatus - Возвращает код ответа, по умолчанию 200, если обработчик не вызвал WriteHeader.
+func responseStatus(ww middleware.WrapResponseWriter) int {
+	if status := ww.Status(); status != 0 {
+		return status
+	}
+	return http.StatusOK
+}
@@ -0,0 +1,36 @@
+package middlewares
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+
+	"github.com/VladSnap/shortener/internal/helpers"
+	"github.com/VladSnap/shortener/internal/metrics"
+	"github.com/VladSnap/shortener/internal/ratelimit"
+)
+
+// RateLimitMiddleware - Мидлварь ограничения частоты запросов клиента. Если limiter nil, запросы не ограничиваются.
+// Клиент определяется по пользователю из контекста, поэтому на маршрутах с аутентификацией мидлварь
+// подключается после AuthMiddleware. Анонимный клиент определяется по IP, заголовок X-Real-IP учитывается только
+// от trustedProxies. Отклоненный запрос получает ответ 429 с заголовком Retry-After.
+func RateLimitMiddleware(limiter *ratelimit.Limiter, recorder *metrics.Metrics,
+	trustedProxies []*net.IPNet) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if limiter == nil {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			allowed, wait := limiter.Allow(ratelimit.ClientKey(r.Context(), helpers.GetClientIP(r, trustedProxies)))
+			if !allowed {
+				if recorder != nil {
+					recorder.AddRateLimited(limiter.Name())
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(ratelimit.RetryAfterSeconds(wait)))
+				http.Error(w, "Too many requests", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
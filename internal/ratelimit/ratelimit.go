@@ -0,0 +1,136 @@
+// Package ratelimit ограничивает частоту запросов клиентов алгоритмом token bucket.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/config"
+	"github.com/VladSnap/shortener/internal/constants"
+)
+
+// Названия лимитов, они же метки метрик отклоненных запросов.
+const (
+	// LimitCreate - Лимит создания одиночных ссылок.
+	LimitCreate = "create"
+	// LimitBatch - Лимит пакетного создания ссылок.
+	LimitBatch = "batch"
+	// LimitRedirect - Лимит переходов по сокращенным ссылкам.
+	LimitRedirect = "redirect"
+)
+
+// sweepInterval - Интервал удаления корзин клиентов, которые давно не обращались и успели наполниться.
+const sweepInterval = time.Minute
+
+// bucket - Корзина токенов одного клиента.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// Limiter - Ограничитель частоты запросов с отдельной корзиной токенов на каждый ключ клиента.
+// Корзина вмещает burst токенов и пополняется со скоростью perMinute токенов в минуту.
+type Limiter struct {
+	name      string
+	rate      float64
+	burst     float64
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// New - Создает новую структуру Limiter с указателем. Если burst не больше 0, он равен perMinute.
+// Если perMinute не больше 0, ограничение выключено и возвращается nil.
+func New(name string, perMinute, burst int) *Limiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = perMinute
+	}
+	return &Limiter{
+		name:    name,
+		rate:    float64(perMinute) / time.Minute.Seconds(),
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Name - Возвращает название лимита.
+func (limiter *Limiter) Name() string {
+	return limiter.name
+}
+
+// Allow - Забирает токен из корзины клиента key. Если токенов нет, возвращает false и время до появления токена.
+func (limiter *Limiter) Allow(key string) (bool, time.Duration) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	now := limiter.now()
+	limiter.sweep(now)
+
+	b, ok := limiter.buckets[key]
+	if !ok {
+		b = &bucket{tokens: limiter.burst, updated: now}
+		limiter.buckets[key] = b
+	}
+	b.tokens = math.Min(limiter.burst, b.tokens+now.Sub(b.updated).Seconds()*limiter.rate)
+	b.updated = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / limiter.rate * float64(time.Second))
+	return false, wait
+}
+
+// sweep - Удаляет корзины, которые наполнились бы полностью, чтобы память не росла с числом клиентов.
+func (limiter *Limiter) sweep(now time.Time) {
+	if now.Sub(limiter.lastSweep) < sweepInterval {
+		return
+	}
+	limiter.lastSweep = now
+	for key, b := range limiter.buckets {
+		if b.tokens+now.Sub(b.updated).Seconds()*limiter.rate >= limiter.burst {
+			delete(limiter.buckets, key)
+		}
+	}
+}
+
+// Limits - Лимиты создания ссылок, пакетного создания и переходов. Выключенный лимит равен nil.
+// Лимиты общие для HTTP и gRPC, поэтому клиент не может обойти их сменой протокола.
+type Limits struct {
+	Create   *Limiter
+	Batch    *Limiter
+	Redirect *Limiter
+}
+
+// NewLimits - Создает лимиты по настройкам конфига.
+func NewLimits(opts *config.Options) *Limits {
+	return &Limits{
+		Create:   New(LimitCreate, opts.RateLimitCreatePerMin, opts.RateLimitCreateBurst),
+		Batch:    New(LimitBatch, opts.RateLimitBatchPerMin, opts.RateLimitBatchBurst),
+		Redirect: New(LimitRedirect, opts.RateLimitRedirectPerMin, opts.RateLimitRedirectBurst),
+	}
+}
+
+// ClientKey - Возвращает ключ клиента: идентификатор пользователя, если он пришел с запросом, иначе IP адрес.
+// Пользователь, созданный для этого же запроса, ограничивается по IP, иначе лимит обходится запросами без куки.
+func ClientKey(ctx context.Context, ip string) string {
+	userID, _ := ctx.Value(constants.UserIDContextKey).(string)
+	isNew, _ := ctx.Value(constants.NewUserContextKey).(bool)
+	if userID != "" && !isNew {
+		return "user:" + userID
+	}
+	return "ip:" + ip
+}
+
+// RetryAfterSeconds - Округляет время ожидания вверх до целых секунд для заголовка Retry-After.
+func RetryAfterSeconds(wait time.Duration) int {
+	return max(1, int(math.Ceil(wait.Seconds())))
+}
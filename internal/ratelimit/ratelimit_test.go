@@ -0,0 +1,45 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	assert.Nil(t, New(LimitCreate, 0, 10), "zero rate disables the limit")
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	limiter := New(LimitCreate, 60, 2)
+	limiter.now = func() time.Time { return now }
+
+	allowed, _ := limiter.Allow("a")
+	assert.True(t, allowed)
+	allowed, _ = limiter.Allow("a")
+	assert.True(t, allowed)
+	allowed, wait := limiter.Allow("a")
+	assert.False(t, allowed)
+	assert.Equal(t, time.Second, wait)
+
+	allowed, _ = limiter.Allow("b")
+	assert.True(t, allowed, "clients have separate buckets")
+
+	now = now.Add(time.Second)
+	allowed, _ = limiter.Allow("a")
+	assert.True(t, allowed, "bucket refills over time")
+
+	now = now.Add(sweepInterval)
+	limiter.Allow("c")
+	assert.Len(t, limiter.buckets, 1, "full buckets are swept")
+}
+
+func TestClientKey(t *testing.T) {
+	ctx := context.WithValue(t.Context(), constants.UserIDContextKey, "user1")
+	assert.Equal(t, "user:user1", ClientKey(ctx, "10.0.0.1"))
+	assert.Equal(t, "ip:10.0.0.1", ClientKey(context.WithValue(ctx, constants.NewUserContextKey, true), "10.0.0.1"))
+	assert.Equal(t, "ip:10.0.0.1", ClientKey(t.Context(), "10.0.0.1"))
+	assert.Equal(t, 1, RetryAfterSeconds(10*time.Millisecond))
+}
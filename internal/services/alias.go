@@ -0,0 +1,64 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/VladSnap/shortener/internal/constants"
+)
+
+// AliasRules - Правила валидации пользовательских псевдонимов сокращенных ссылок.
+type AliasRules struct {
+	// Charset - Допустимые символы псевдонима.
+	Charset string
+	// MinLength - Минимальная длина псевдонима.
+	MinLength int
+	// MaxLength - Максимальная длина псевдонима.
+	MaxLength int
+	// reserved - Первые сегменты зарезервированных путей HTTP API в нижнем регистре.
+	reserved map[string]bool
+}
+
+// NewAliasRules - Создает новую структуру AliasRules. Первые сегменты reservedPaths, путей, которые
+// регистрирует роутер HTTP API, не могут быть заняты псевдонимом или сгенерированным идентификатором.
+func NewAliasRules(charset string, minLength, maxLength int, reservedPaths []string) AliasRules {
+	reserved := make(map[string]bool, len(reservedPaths))
+	for _, path := range reservedPaths {
+		reserved[firstPathSegment(path)] = true
+	}
+	return AliasRules{Charset: charset, MinLength: minLength, MaxLength: maxLength, reserved: reserved}
+}
+
+// DefaultAliasRules - Возвращает правила валидации псевдонимов по умолчанию без зарезервированных путей.
+func DefaultAliasRules() AliasRules {
+	return NewAliasRules(constants.AliasCharset, constants.AliasMinLength, constants.AliasMaxLength, nil)
+}
+
+// Validate - Проверяет псевдоним на соответствие правилам.
+func (rules AliasRules) Validate(alias string) error {
+	length := utf8.RuneCountInString(alias)
+	if length < rules.MinLength || length > rules.MaxLength {
+		return fmt.Errorf("%w: length should be between %d and %d", ErrInvalidAlias, rules.MinLength, rules.MaxLength)
+	}
+	for _, r := range alias {
+		if !strings.ContainsRune(rules.Charset, r) {
+			return fmt.Errorf("%w: character '%c' is not allowed", ErrInvalidAlias, r)
+		}
+	}
+	if rules.isReserved(alias) {
+		return fmt.Errorf("%w: '%s' is reserved", ErrInvalidAlias, alias)
+	}
+	return nil
+}
+
+// isReserved - Проверяет, что идентификатор совпадает с первым сегментом пути HTTP API.
+func (rules AliasRules) isReserved(shortID string) bool {
+	return rules.reserved[strings.ToLower(shortID)]
+}
+
+// firstPathSegment - Возвращает первый сегмент пути в нижнем регистре.
+func firstPathSegment(path string) string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return strings.ToLower(segment)
+}
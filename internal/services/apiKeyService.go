@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/google/uuid"
+)
+
+// Scope - Действие, разрешенное ключу API.
+type Scope string
+
+const (
+	// ScopeCreate - Создание сокращенных ссылок.
+	ScopeCreate Scope = "create"
+	// ScopeRead - Чтение списка ссылок пользователя.
+	ScopeRead Scope = "read"
+	// ScopeDelete - Удаление ссылок пользователя.
+	ScopeDelete Scope = "delete"
+	// ScopeStats - Чтение статистики переходов и статистики сервиса.
+	ScopeStats Scope = "stats"
+)
+
+// AllScopes - Все разрешения ключей API.
+var AllScopes = []Scope{ScopeCreate, ScopeRead, ScopeDelete, ScopeStats}
+
+// apiKeyPrefix - Префикс ключей API, по которому их легко опознать, например, в логах или репозитории.
+const apiKeyPrefix = "shk_"
+
+// apiKeyRandomBytes - Количество случайных байт ключа API.
+const apiKeyRandomBytes = 32
+
+// APIKeyRepo - Интерфейс репозитория ключей API.
+type APIKeyRepo interface {
+	// AddAPIKey - Сохраняет новый ключ API.
+	AddAPIKey(ctx context.Context, key *data.APIKeyData) error
+	// GetAPIKeyByHash - Получает ключ API по хешу, возвращает nil, если ключ не найден.
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*data.APIKeyData, error)
+	// GetAPIKeys - Получает все ключи API, включая отозванные, в порядке создания.
+	GetAPIKeys(ctx context.Context) ([]*data.APIKeyData, error)
+	// RevokeAPIKey - Помечает ключ API отозванным. Возвращает false, если ключ не найден.
+	RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) (bool, error)
+}
+
+// APIKey - Доменный объект ключа API без самого ключа.
+type APIKey struct {
+	ID        string
+	UserID    string
+	Name      string
+	Scopes    []Scope
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+// HasScope - Проверяет, что ключу разрешено действие.
+func (key *APIKey) HasScope(scope Scope) bool {
+	return slices.Contains(key.Scopes, scope)
+}
+
+// CreatedAPIKey - Созданный ключ API. Key возвращается клиенту один раз и нигде не хранится.
+type CreatedAPIKey struct {
+	*APIKey
+	Key string
+}
+
+// APIKeyService - Сервис создания, проверки и отзыва ключей API для межсервисных клиентов.
+type APIKeyService struct {
+	repo APIKeyRepo
+	now  func() time.Time
+}
+
+// NewAPIKeyService - Создает новую структуру APIKeyService с указателем.
+func NewAPIKeyService(repo APIKeyRepo) *APIKeyService {
+	return &APIKeyService{repo: repo, now: time.Now}
+}
+
+// ParseScopes - Проверяет и возвращает разрешения ключа API без повторов.
+func ParseScopes(values []string) ([]Scope, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", ErrInvalidAPIKeyRequest)
+	}
+	scopes := make([]Scope, 0, len(values))
+	for _, value := range values {
+		scope := Scope(strings.ToLower(strings.TrimSpace(value)))
+		if !slices.Contains(AllScopes, scope) {
+			return nil, fmt.Errorf("%w: unknown scope '%s'", ErrInvalidAPIKeyRequest, value)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+// CreateAPIKey - Создает ключ API с разрешениями scopes. Если userID пуст, ключ получает нового пользователя.
+func (service *APIKeyService) CreateAPIKey(ctx context.Context, userID, name string,
+	scopes []Scope) (*CreatedAPIKey, error) {
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", ErrInvalidAPIKeyRequest)
+	}
+	if userID == "" {
+		id, err := uuid.NewRandom()
+		if err != nil {
+			return nil, fmt.Errorf("failed generate user id for api key: %w", err)
+		}
+		userID = id.String()
+	}
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, fmt.Errorf("failed generate api key id: %w", err)
+	}
+	secret := make([]byte, apiKeyRandomBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed generate api key: %w", err)
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	keyData := &data.APIKeyData{
+		ID:        id.String(),
+		KeyHash:   hashAPIKey(key),
+		UserID:    userID,
+		Name:      name,
+		Scopes:    make([]string, 0, len(scopes)),
+		CreatedAt: service.now().UTC(),
+	}
+	for _, scope := range scopes {
+		keyData.Scopes = append(keyData.Scopes, string(scope))
+	}
+	if err := service.repo.AddAPIKey(ctx, keyData); err != nil {
+		return nil, fmt.Errorf("failed add api key: %w", err)
+	}
+	return &CreatedAPIKey{APIKey: toAPIKey(keyData), Key: key}, nil
+}
+
+// Authenticate - Находит действующий ключ API. Для неизвестного или отозванного ключа возвращает ErrInvalidAPIKey.
+func (service *APIKeyService) Authenticate(ctx context.Context, key string) (*APIKey, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+	keyData, err := service.repo.GetAPIKeyByHash(ctx, hashAPIKey(key))
+	if err != nil {
+		return nil, fmt.Errorf("failed get api key: %w", err)
+	}
+	if keyData == nil || keyData.RevokedAt != nil {
+		return nil, ErrInvalidAPIKey
+	}
+	return toAPIKey(keyData), nil
+}
+
+// ListAPIKeys - Возвращает все ключи API, включая отозванные.
+func (service *APIKeyService) ListAPIKeys(ctx context.Context) ([]*APIKey, error) {
+	keysData, err := service.repo.GetAPIKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed get api keys: %w", err)
+	}
+	keys := make([]*APIKey, 0, len(keysData))
+	for _, keyData := range keysData {
+		keys = append(keys, toAPIKey(keyData))
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey - Отзывает ключ API. Для неизвестного ключа возвращает ErrAPIKeyNotFound.
+func (service *APIKeyService) RevokeAPIKey(ctx context.Context, id string) error {
+	found, err := service.repo.RevokeAPIKey(ctx, id, service.now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed revoke api key: %w", err)
+	}
+	if !found {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// ContextWithAPIKey - Сохраняет в контексте ключ API, которым аутентифицирован запрос.
+func ContextWithAPIKey(ctx context.Context, key *APIKey) context.Context {
+	return context.WithValue(ctx, constants.APIKeyContextKey, key)
+}
+
+// APIKeyFromContext - Возвращает ключ API из контекста. Для запросов с кукой или JWT токеном возвращает false.
+func APIKeyFromContext(ctx context.Context) (*APIKey, bool) {
+	key, ok := ctx.Value(constants.APIKeyContextKey).(*APIKey)
+	return key, ok && key != nil
+}
+
+// ScopeAllowed - Проверяет, что запросу разрешено действие.
+// Ограничения действуют только для ключей API, пользователям с кукой или JWT токеном разрешено все.
+func ScopeAllowed(ctx context.Context, scope Scope) bool {
+	key, ok := APIKeyFromContext(ctx)
+	return !ok || key.HasScope(scope)
+}
+
+// hashAPIKey - Возвращает хеш SHA-256 ключа в hex. Ключ случайный и длинный, поэтому соль не нужна.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// toAPIKey - Преобразует данные ключа API из хранилища в доменный объект.
+func toAPIKey(keyData *data.APIKeyData) *APIKey {
+	key := &APIKey{
+		ID:        keyData.ID,
+		UserID:    keyData.UserID,
+		Name:      keyData.Name,
+		Scopes:    make([]Scope, 0, len(keyData.Scopes)),
+		CreatedAt: keyData.CreatedAt,
+		RevokedAt: keyData.RevokedAt,
+	}
+	for _, scope := range keyData.Scopes {
+		key.Scopes = append(key.Scopes, Scope(scope))
+	}
+	return key
+}
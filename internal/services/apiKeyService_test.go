@@ -0,0 +1,55 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/VladSnap/shortener/internal/data/repos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyService(t *testing.T) {
+	service := NewAPIKeyService(repos.NewInMemoryAPIKeyRepo())
+
+	created, err := service.CreateAPIKey(t.Context(), "user1", "importer",
+		[]Scope{ScopeCreate, ScopeRead})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Key, "shk_"))
+	assert.Equal(t, "user1", created.UserID)
+
+	key, err := service.Authenticate(t.Context(), created.Key)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, key.ID)
+	assert.True(t, key.HasScope(ScopeRead))
+	assert.False(t, key.HasScope(ScopeDelete))
+
+	_, err = service.Authenticate(t.Context(), created.Key+"x")
+	require.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	withNewUser, err := service.CreateAPIKey(t.Context(), "", "reporter", []Scope{ScopeStats})
+	require.NoError(t, err)
+	assert.NotEmpty(t, withNewUser.UserID)
+
+	require.NoError(t, service.RevokeAPIKey(t.Context(), created.ID))
+	_, err = service.Authenticate(t.Context(), created.Key)
+	require.ErrorIs(t, err, ErrInvalidAPIKey)
+	require.ErrorIs(t, service.RevokeAPIKey(t.Context(), "missing"), ErrAPIKeyNotFound)
+
+	keys, err := service.ListAPIKeys(t.Context())
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.NotNil(t, keys[0].RevokedAt)
+	assert.Nil(t, keys[1].RevokedAt)
+}
+
+func TestParseScopes(t *testing.T) {
+	scopes, err := ParseScopes([]string{"create", " Stats ", "create"})
+	require.NoError(t, err)
+	assert.Equal(t, []Scope{ScopeCreate, ScopeStats}, scopes)
+
+	_, err = ParseScopes([]string{"admin"})
+	require.ErrorIs(t, err, ErrInvalidAPIKeyRequest)
+	_, err = ParseScopes(nil)
+	require.ErrorIs(t, err, ErrInvalidAPIKeyRequest)
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/VladSnap/shortener/internal/log"
+	"go.uber.org/zap"
+)
+
+// AuditAction - Действие со ссылкой, которое записывается в журнал аудита.
+type AuditAction string
+
+const (
+	// AuditActionCreate - Ссылка создана.
+	AuditActionCreate AuditAction = "create"
+	// AuditActionDelete - Ссылка удалена.
+	AuditActionDelete AuditAction = "delete"
+	// AuditActionRestore - Удаленная ссылка восстановлена.
+	AuditActionRestore AuditAction = "restore"
+	// AuditActionUpdate - Изменен оригинальный URL ссылки.
+	AuditActionUpdate AuditAction = "update"
+)
+
+// AuditSource - Интерфейс, через который выполнено действие.
+type AuditSource string
+
+const (
+	// AuditSourceHTTP - Действие выполнено через HTTP API.
+	AuditSourceHTTP AuditSource = "http"
+	// AuditSourceGRPC - Действие выполнено через gRPC API.
+	AuditSourceGRPC AuditSource = "grpc"
+)
+
+// AuditRepo - Интерфейс репозитория журнала аудита действий со ссылками.
+type AuditRepo interface {
+	// AddEvents - Сохраняет пачку событий журнала аудита.
+	AddEvents(ctx context.Context, events []*data.AuditEventData) error
+	// GetEvents - Получает события журнала аудита по параметрам выборки от новых к старым.
+	GetEvents(ctx context.Context, query *data.AuditQueryData) ([]*data.AuditEventData, error)
+}
+
+// AuditActor - Источник запроса, выполняющего действие со ссылкой.
+type AuditActor struct {
+	Source   AuditSource
+	ClientIP string
+}
+
+// auditActorKey - Ключ контекста для источника запроса.
+type auditActorKey struct{}
+
+// WithAuditActor - Возвращает контекст с источником запроса для журнала аудита.
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// AuditActorFromContext - Возвращает источник запроса из контекста или пустой источник, если он не задан.
+func AuditActorFromContext(ctx context.Context) AuditActor {
+	actor, _ := ctx.Value(auditActorKey{}).(AuditActor)
+	return actor
+}
+
+// AuditEvent - Доменный объект события журнала аудита.
+type AuditEvent struct {
+	ID          int64
+	Action      AuditAction
+	ShortURL    string
+	UserID      string
+	Source      AuditSource
+	ClientIP    string
+	OriginalURL string
+	CreatedAt   time.Time
+}
+
+// AuditQuery - Параметры выборки событий журнала аудита.
+type AuditQuery struct {
+	// UserID - Пользователь, события которого нужно получить. Если пуст, возвращаются события всех пользователей.
+	UserID string
+	// From - Начало периода включительно. Если нулевое, период не ограничен снизу.
+	From time.Time
+	// To - Конец периода не включительно. Если нулевое, период не ограничен сверху.
+	To time.Time
+	// Limit - Максимальное количество событий. Если 0, используется constants.AuditDefaultLimit.
+	Limit int
+}
+
+// AuditLog - Журнал аудита создания, удаления, восстановления и изменения ссылок.
+type AuditLog struct {
+	repo AuditRepo
+	now  func() time.Time
+}
+
+// NewAuditLog - Создает новую структуру AuditLog с указателем.
+func NewAuditLog(repo AuditRepo) *AuditLog {
+	return &AuditLog{repo: repo, now: time.Now}
+}
+
+// Record - Записывает события в журнал. Событиям без источника присваивается источник запроса из контекста,
+// без времени - текущее время. Ошибка записи не возвращается, так как действие со ссылкой уже выполнено.
+// Если журнал не задан, ничего не делает.
+func (audit *AuditLog) Record(ctx context.Context, events []*AuditEvent) {
+	if audit == nil || len(events) == 0 {
+		return
+	}
+
+	actor := AuditActorFromContext(ctx)
+	now := audit.now().UTC()
+	toSave := make([]*data.AuditEventData, 0, len(events))
+	for _, event := range events {
+		if event.Source == "" {
+			event.Source = actor.Source
+			event.ClientIP = actor.ClientIP
+		}
+		if event.CreatedAt.IsZero() {
+			event.CreatedAt = now
+		}
+		toSave = append(toSave, &data.AuditEventData{
+			Action:      string(event.Action),
+			ShortURL:    event.ShortURL,
+			UserID:      event.UserID,
+			Source:      string(event.Source),
+			ClientIP:    event.ClientIP,
+			OriginalURL: event.OriginalURL,
+			CreatedAt:   event.CreatedAt,
+		})
+	}
+
+	if err := audit.repo.AddEvents(ctx, toSave); err != nil {
+		log.Zap.Error("failed add audit events", zap.Error(err), zap.Int("events", len(toSave)))
+	}
+}
+
+// GetEvents - Получает события журнала аудита по параметрам выборки от новых к старым.
+func (audit *AuditLog) GetEvents(ctx context.Context, query *AuditQuery) ([]*AuditEvent, error) {
+	if query.Limit < 0 || query.Limit > constants.AuditMaxLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidAuditQuery, constants.AuditMaxLimit)
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidAuditQuery)
+	}
+	repoQuery := &data.AuditQueryData{UserID: query.UserID, From: query.From, To: query.To, Limit: query.Limit}
+	if repoQuery.Limit == 0 {
+		repoQuery.Limit = constants.AuditDefaultLimit
+	}
+
+	events, err := audit.repo.GetEvents(ctx, repoQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed get audit events from repo: %w", err)
+	}
+	result := make([]*AuditEvent, 0, len(events))
+	for _, event := range events {
+		result = append(result, &AuditEvent{
+			ID:          event.ID,
+			Action:      AuditAction(event.Action),
+			ShortURL:    event.ShortURL,
+			UserID:      event.UserID,
+			Source:      AuditSource(event.Source),
+			ClientIP:    event.ClientIP,
+			OriginalURL: event.OriginalURL,
+			CreatedAt:   event.CreatedAt,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/data/repos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNaiveShortenService_Audit(t *testing.T) {
+	audit := NewAuditLog(repos.NewInMemoryAuditRepo())
+	service := NewNaiveShorterService(repos.NewShortLinkRepo(), WithAuditLog(audit))
+	ctx := WithAuditActor(t.Context(), AuditActor{Source: AuditSourceGRPC, ClientIP: "10.0.0.1"})
+
+	created, err := service.CreateShortLink(ctx, &OriginalLink{URL: "http://a.url"}, "owner")
+	require.NoError(t, err)
+	// Повторное сокращение того же URL не создает ссылку и не записывается в журнал.
+	_, err = service.CreateShortLink(ctx, &OriginalLink{URL: "http://a.url"}, "owner")
+	require.NoError(t, err)
+	batch, err := service.CreateShortLinkBatch(ctx, []*OriginalLink{{URL: "http://a.url"}, {URL: "http://b.url"}},
+		"owner", false)
+	require.NoError(t, err)
+	_, err = service.UpdateLink(ctx, created.URL, "owner", "http://c.url")
+	require.NoError(t, err)
+	_, err = service.UpdateLink(ctx, created.URL, "owner", "http://c.url")
+	require.NoError(t, err, "same URL does not change the link")
+	_, err = service.DeleteBatch(ctx, []DeleteShortID{NewDeleteShortID(created.URL, "owner")})
+	require.NoError(t, err)
+	_, err = service.RestoreBatch(ctx, []DeleteShortID{
+		NewDeleteShortID(created.URL, "owner"),
+		NewDeleteShortID(batch[1].URL, "owner"),
+	})
+	require.NoError(t, err)
+
+	events, err := audit.GetEvents(t.Context(), &AuditQuery{UserID: "owner"})
+	require.NoError(t, err)
+	actions := make([]AuditAction, 0, len(events))
+	for _, event := range events {
+		actions = append(actions, event.Action)
+	}
+	// Удаление записывает воркер удаления, невосстановленные ссылки не попадают в журнал.
+	assert.Equal(t, []AuditAction{AuditActionRestore, AuditActionUpdate, AuditActionCreate, AuditActionCreate},
+		actions)
+	assert.Equal(t, "http://c.url", events[1].OriginalURL)
+	assert.Equal(t, batch[1].URL, events[2].ShortURL)
+	assert.Equal(t, AuditSourceGRPC, events[3].Source)
+	assert.Equal(t, "10.0.0.1", events[3].ClientIP)
+	assert.False(t, events[3].CreatedAt.IsZero())
+
+	events, err = audit.GetEvents(t.Context(), &AuditQuery{UserID: "someone"})
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestAuditLog_GetEventsValidation(t *testing.T) {
+	audit := NewAuditLog(repos.NewInMemoryAuditRepo())
+	now := time.Now()
+
+	_, err := audit.GetEvents(t.Context(), &AuditQuery{Limit: constants.AuditMaxLimit + 1})
+	require.ErrorIs(t, err, ErrInvalidAuditQuery)
+	_, err = audit.GetEvents(t.Context(), &AuditQuery{From: now, To: now})
+	require.ErrorIs(t, err, ErrInvalidAuditQuery)
+	_, err = audit.GetEvents(t.Context(), &AuditQuery{From: now})
+	require.NoError(t, err)
+}
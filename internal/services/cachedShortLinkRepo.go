@@ -0,0 +1,244 @@
+package services
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/VladSnap/shortener/internal/log"
+	"go.uber.org/zap"
+)
+
+// CacheStats - Счетчики кеша сокращенных ссылок.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	Size   int
+}
+
+// cacheEntry - Запись кеша, link равен nil для неизвестного идентификатора.
+type cacheEntry struct {
+	shortURL  string
+	link      *data.ShortLinkData
+	expiresAt time.Time
+}
+
+// CachedShortLinkRepo - Декоратор ShortLinkRepo с ограниченным LRU кешем для Get.
+// Кеширует и найденные ссылки, и неизвестные идентификаторы (на меньшее время).
+// Записи сбрасываются при создании, удалении и очистке ссылок через этот декоратор,
+// изменения, сделанные другими репликами, становятся видны по истечении TTL.
+// Остальные методы вызываются у исходного репозитория без кеширования.
+type CachedShortLinkRepo struct {
+	ShortLinkRepo
+	capacity    int
+	ttl         time.Duration
+	negativeTTL time.Duration
+	now         func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+	// generation - Увеличивается при каждом сбросе записей, чтобы не закешировать устаревший результат чтения.
+	generation uint64
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// CachedShortLinkRepoOption - Функция для настройки CachedShortLinkRepo.
+type CachedShortLinkRepoOption func(*CachedShortLinkRepo)
+
+// WithCacheTTL - Устанавливает время жизни закешированной ссылки.
+func WithCacheTTL(ttl time.Duration) CachedShortLinkRepoOption {
+	return func(repo *CachedShortLinkRepo) {
+		repo.ttl = ttl
+	}
+}
+
+// WithCacheNegativeTTL - Устанавливает время жизни записи о неизвестном идентификаторе.
+func WithCacheNegativeTTL(ttl time.Duration) CachedShortLinkRepoOption {
+	return func(repo *CachedShortLinkRepo) {
+		repo.negativeTTL = ttl
+	}
+}
+
+// NewCachedShortLinkRepo - Создает новую структуру CachedShortLinkRepo с указателем.
+// capacity - Максимальное количество записей в кеше.
+func NewCachedShortLinkRepo(repo ShortLinkRepo, capacity int,
+	options ...CachedShortLinkRepoOption) *CachedShortLinkRepo {
+	cached := new(CachedShortLinkRepo)
+	cached.ShortLinkRepo = repo
+	cached.capacity = capacity
+	cached.ttl = constants.CacheTTL
+	cached.negativeTTL = constants.CacheNegativeTTL
+	cached.now = time.Now
+	cached.entries = make(map[string]*list.Element, capacity)
+	cached.order = list.New()
+	for _, option := range options {
+		option(cached)
+	}
+	return cached
+}
+
+// Add - Сохраняет ссылку и сбрасывает запись кеша о её идентификаторе.
+func (repo *CachedShortLinkRepo) Add(ctx context.Context, link *data.ShortLinkData) (*data.ShortLinkData, error) {
+	defer repo.invalidate(link.ShortURL)
+	return repo.ShortLinkRepo.Add(ctx, link) //nolint:wrapcheck // decorator
+}
+
+// AddBatch - Сохраняет пачку ссылок и сбрасывает записи кеша об их идентификаторах.
+func (repo *CachedShortLinkRepo) AddBatch(ctx context.Context, links []*data.ShortLinkData) (
+	[]*data.ShortLinkData, error) {
+	shortURLs := make([]string, 0, len(links))
+	for _, link := range links {
+		shortURLs = append(shortURLs, link.ShortURL)
+	}
+	defer repo.invalidate(shortURLs...)
+	return repo.ShortLinkRepo.AddBatch(ctx, links) //nolint:wrapcheck // decorator
+}
+
+// Get - Читает ссылку из кеша, а при промахе - из репозитория с сохранением результата в кеш.
+func (repo *CachedShortLinkRepo) Get(ctx context.Context, shortID string) (*data.ShortLinkData, error) {
+	repo.mu.Lock()
+	if link, ok := repo.lookup(shortID); ok {
+		repo.mu.Unlock()
+		repo.hits.Add(1)
+		return link, nil
+	}
+	generation := repo.generation
+	repo.mu.Unlock()
+	repo.misses.Add(1)
+
+	link, err := repo.ShortLinkRepo.Get(ctx, shortID)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // decorator
+	}
+
+	repo.mu.Lock()
+	if generation == repo.generation {
+		repo.store(shortID, link)
+	}
+	repo.mu.Unlock()
+	return copyLink(link), nil
+}
+
+// DeleteBatch - Удаляет пачку ссылок и сбрасывает записи кеша о них.
+func (repo *CachedShortLinkRepo) DeleteBatch(ctx context.Context, shortIDs []data.DeleteShortData) error {
+	shortURLs := make([]string, 0, len(shortIDs))
+	for _, sid := range shortIDs {
+		shortURLs = append(shortURLs, sid.ShortURL)
+	}
+	defer repo.invalidate(shortURLs...)
+	return repo.ShortLinkRepo.DeleteBatch(ctx, shortIDs) //nolint:wrapcheck // decorator
+}
+
+// RestoreBatch - Восстанавливает пачку удаленных ссылок и сбрасывает записи кеша о них.
+func (repo *CachedShortLinkRepo) RestoreBatch(ctx context.Context, shortIDs []data.DeleteShortData,
+	deletedAfter time.Time) error {
+	shortURLs := make([]string, 0, len(shortIDs))
+	for _, sid := range shortIDs {
+		shortURLs = append(shortURLs, sid.ShortURL)
+	}
+	defer repo.invalidate(shortURLs...)
+	return repo.ShortLinkRepo.RestoreBatch(ctx, shortIDs, deletedAfter) //nolint:wrapcheck // decorator
+}
+
+// UpdateOriginalURL - Меняет оригинальный URL ссылки и сбрасывает запись кеша о ней.
+func (repo *CachedShortLinkRepo) UpdateOriginalURL(ctx context.Context, shortURL string, userID string,
+	origURL string, changedAt time.Time) (*data.ShortLinkData, error) {
+	defer repo.invalidate(shortURL)
+	return repo.ShortLinkRepo.UpdateOriginalURL(ctx, shortURL, userID, origURL, changedAt) //nolint:wrapcheck // decorator
+}
+
+// Purge - Физически удаляет ссылки и полностью сбрасывает кеш.
+func (repo *CachedShortLinkRepo) Purge(ctx context.Context, before time.Time) (*data.PurgeData, error) {
+	defer repo.clear()
+	return repo.ShortLinkRepo.Purge(ctx, before) //nolint:wrapcheck // decorator
+}
+
+// Stats - Возвращает счетчики попаданий и промахов кеша.
+func (repo *CachedShortLinkRepo) Stats() CacheStats {
+	repo.mu.Lock()
+	size := repo.order.Len()
+	repo.mu.Unlock()
+	return CacheStats{Hits: repo.hits.Load(), Misses: repo.misses.Load(), Size: size}
+}
+
+// Close - Записывает в лог итоговые счетчики кеша.
+func (repo *CachedShortLinkRepo) Close() error {
+	stats := repo.Stats()
+	log.Zap.Info("Short link cache closed",
+		zap.Uint64("hits", stats.Hits), zap.Uint64("misses", stats.Misses), zap.Int("size", stats.Size))
+	return nil
+}
+
+// lookup - Ищет неистекшую запись и поднимает её в начало LRU. Вызывается под блокировкой.
+func (repo *CachedShortLinkRepo) lookup(shortID string) (*data.ShortLinkData, bool) {
+	elem, ok := repo.entries[shortID]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry) //nolint:forcetypeassert // list holds only cacheEntry
+	if !repo.now().Before(entry.expiresAt) {
+		repo.order.Remove(elem)
+		delete(repo.entries, shortID)
+		return nil, false
+	}
+	repo.order.MoveToFront(elem)
+	return copyLink(entry.link), true
+}
+
+// store - Сохраняет запись и вытесняет самую давнюю при переполнении. Вызывается под блокировкой.
+func (repo *CachedShortLinkRepo) store(shortID string, link *data.ShortLinkData) {
+	ttl := repo.ttl
+	if link == nil {
+		ttl = repo.negativeTTL
+	}
+	entry := &cacheEntry{shortURL: shortID, link: copyLink(link), expiresAt: repo.now().Add(ttl)}
+	if elem, ok := repo.entries[shortID]; ok {
+		elem.Value = entry
+		repo.order.MoveToFront(elem)
+		return
+	}
+	repo.entries[shortID] = repo.order.PushFront(entry)
+	for repo.order.Len() > repo.capacity {
+		oldest := repo.order.Back()
+		repo.order.Remove(oldest)
+		delete(repo.entries, oldest.Value.(*cacheEntry).shortURL) //nolint:forcetypeassert // list holds only cacheEntry
+	}
+}
+
+// invalidate - Сбрасывает записи кеша об указанных идентификаторах.
+func (repo *CachedShortLinkRepo) invalidate(shortURLs ...string) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.generation++
+	for _, shortURL := range shortURLs {
+		if elem, ok := repo.entries[shortURL]; ok {
+			repo.order.Remove(elem)
+			delete(repo.entries, shortURL)
+		}
+	}
+}
+
+// clear - Сбрасывает все записи кеша.
+func (repo *CachedShortLinkRepo) clear() {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	repo.generation++
+	repo.entries = make(map[string]*list.Element, repo.capacity)
+	repo.order.Init()
+}
+
+// copyLink - Возвращает копию ссылки, чтобы вызывающий код не мог изменить закешированную запись.
+func copyLink(link *data.ShortLinkData) *data.ShortLinkData {
+	if link == nil {
+		return nil
+	}
+	linkCopy := *link
+	return &linkCopy
+}
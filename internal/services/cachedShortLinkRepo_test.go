@@ -0,0 +1,107 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedShortLinkRepo_Get(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	link := &data.ShortLinkData{ShortURL: "link0001", OriginalURL: "http://test.url", UserID: "user1"}
+
+	t.Run("second read is served from cache", func(t *testing.T) {
+		mockRepo := NewMockShortLinkRepo(ctrl)
+		repo := NewCachedShortLinkRepo(mockRepo, 10)
+		mockRepo.EXPECT().Get(gomock.Any(), "link0001").Return(link, nil).Times(1)
+
+		for range 2 {
+			got, err := repo.Get(t.Context(), "link0001")
+			require.NoError(t, err)
+			assert.Equal(t, link, got)
+		}
+		assert.Equal(t, CacheStats{Hits: 1, Misses: 1, Size: 1}, repo.Stats())
+	})
+
+	t.Run("unknown id is cached until link is added", func(t *testing.T) {
+		mockRepo := NewMockShortLinkRepo(ctrl)
+		repo := NewCachedShortLinkRepo(mockRepo, 10)
+		gomock.InOrder(
+			mockRepo.EXPECT().Get(gomock.Any(), "link0001").Return(nil, nil),
+			mockRepo.EXPECT().Add(gomock.Any(), link).Return(link, nil),
+			mockRepo.EXPECT().Get(gomock.Any(), "link0001").Return(link, nil),
+		)
+
+		for range 2 {
+			got, err := repo.Get(t.Context(), "link0001")
+			require.NoError(t, err)
+			assert.Nil(t, got)
+		}
+		_, err := repo.Add(t.Context(), link)
+		require.NoError(t, err)
+		got, err := repo.Get(t.Context(), "link0001")
+		require.NoError(t, err)
+		assert.Equal(t, link, got)
+	})
+
+	t.Run("entries expire after ttl", func(t *testing.T) {
+		mockRepo := NewMockShortLinkRepo(ctrl)
+		now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+		repo := NewCachedShortLinkRepo(mockRepo, 10, WithCacheTTL(time.Minute), WithCacheNegativeTTL(time.Second))
+		repo.now = func() time.Time { return now }
+		mockRepo.EXPECT().Get(gomock.Any(), "link0001").Return(link, nil).Times(2)
+		mockRepo.EXPECT().Get(gomock.Any(), "unknown1").Return(nil, nil).Times(2)
+
+		for _, shortID := range []string{"link0001", "unknown1"} {
+			_, err := repo.Get(t.Context(), shortID)
+			require.NoError(t, err)
+		}
+		now = now.Add(30 * time.Second)
+		for _, shortID := range []string{"link0001", "unknown1"} {
+			_, err := repo.Get(t.Context(), shortID)
+			require.NoError(t, err)
+		}
+		now = now.Add(time.Minute)
+		_, err := repo.Get(t.Context(), "link0001")
+		require.NoError(t, err)
+	})
+
+	t.Run("least recently used entry is evicted", func(t *testing.T) {
+		mockRepo := NewMockShortLinkRepo(ctrl)
+		repo := NewCachedShortLinkRepo(mockRepo, 2)
+		mockRepo.EXPECT().Get(gomock.Any(), "a").Return(nil, nil).Times(1)
+		mockRepo.EXPECT().Get(gomock.Any(), "b").Return(nil, nil).Times(2)
+		mockRepo.EXPECT().Get(gomock.Any(), "c").Return(nil, nil).Times(1)
+
+		for _, shortID := range []string{"a", "b", "a", "c", "a", "b"} {
+			_, err := repo.Get(t.Context(), shortID)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, repo.Stats().Size)
+	})
+
+	t.Run("delete invalidates entries", func(t *testing.T) {
+		mockRepo := NewMockShortLinkRepo(ctrl)
+		repo := NewCachedShortLinkRepo(mockRepo, 10)
+		deleted := *link
+		deleted.IsDeleted = true
+		shortIDs := []data.DeleteShortData{data.NewDeleteShortData("link0001", "user1")}
+		gomock.InOrder(
+			mockRepo.EXPECT().Get(gomock.Any(), "link0001").Return(link, nil),
+			mockRepo.EXPECT().DeleteBatch(gomock.Any(), shortIDs).Return(nil),
+			mockRepo.EXPECT().Get(gomock.Any(), "link0001").Return(&deleted, nil),
+		)
+
+		_, err := repo.Get(t.Context(), "link0001")
+		require.NoError(t, err)
+		require.NoError(t, repo.DeleteBatch(t.Context(), shortIDs))
+		got, err := repo.Get(t.Context(), "link0001")
+		require.NoError(t, err)
+		assert.True(t, got.IsDeleted)
+	})
+}
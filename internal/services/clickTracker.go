@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/VladSnap/shortener/internal/log"
+	"go.uber.org/zap"
+)
+
+// Эти константы нужны, чтобы линтер не ругался на магические числа.
+const (
+	clickQueueSize     = 1000
+	clickFlushSize     = 100
+	clickFlushInterval = time.Second
+	clickFlushRetries  = 3
+)
+
+// ClickTracker - Воркер, который асинхронно накапливает переходы по ссылкам и сохраняет их пачками,
+// чтобы запись статистики не замедляла редирект.
+type ClickTracker struct {
+	clickRepo ClickRepo
+	clicks    chan *data.ClickData
+	buffer    []*data.ClickData
+	// failedFlushes - Количество неудачных попыток сохранить текущий буфер подряд.
+	failedFlushes int
+	done          chan struct{}
+	wg            sync.WaitGroup
+}
+
+// NewClickTracker - Создает новую структуру ClickTracker с указателем.
+func NewClickTracker(repo ClickRepo) *ClickTracker {
+	return &ClickTracker{
+		clickRepo: repo,
+		clicks:    make(chan *data.ClickData, clickQueueSize),
+		done:      make(chan struct{}),
+	}
+}
+
+// Track - Ставит переход в очередь на сохранение. Не блокирует вызывающего:
+// если очередь переполнена, переход отбрасывается.
+func (tracker *ClickTracker) Track(click *Click) {
+	cd := &data.ClickData{
+		ShortURL:  click.ShortURL,
+		ClickedAt: click.ClickedAt.UTC(),
+		Referrer:  click.Referrer,
+		UserAgent: click.UserAgent,
+		ClientIP:  click.ClientIP,
+	}
+	select {
+	case tracker.clicks <- cd:
+	default:
+		log.Zap.Warn("click queue is full, click dropped", zap.String("short_url", click.ShortURL))
+	}
+}
+
+// RunWork - Запускает горутину воркера, которая сохраняет накопленные переходы
+// по таймеру или при заполнении буфера.
+func (tracker *ClickTracker) RunWork() {
+	ticker := time.NewTicker(clickFlushInterval)
+
+	tracker.wg.Add(1)
+	go func() {
+		defer tracker.wg.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case click := <-tracker.clicks:
+				tracker.buffer = append(tracker.buffer, click)
+				if len(tracker.buffer) >= clickFlushSize {
+					tracker.flush()
+				}
+			case <-ticker.C:
+				tracker.flush()
+			case <-tracker.done:
+				tracker.drain()
+				return
+			}
+		}
+	}()
+}
+
+// Close - Останавливает воркер и сохраняет все переходы, оставшиеся в очереди.
+func (tracker *ClickTracker) Close() error {
+	close(tracker.done)
+	tracker.wg.Wait()
+	return nil
+}
+
+func (tracker *ClickTracker) drain() {
+	for {
+		select {
+		case click := <-tracker.clicks:
+			tracker.buffer = append(tracker.buffer, click)
+		default:
+			tracker.flush()
+			return
+		}
+	}
+}
+
+func (tracker *ClickTracker) flush() {
+	if len(tracker.buffer) == 0 {
+		return
+	}
+
+	err := tracker.clickRepo.AddClicks(context.Background(), tracker.buffer)
+	if err != nil {
+		log.Zap.Error("failed AddClicks", zap.Error(err), zap.Int("clicks", len(tracker.buffer)))
+		// Буфер повторяется ограниченное число раз: пачка, которую не удается сохранить,
+		// отбрасывается, а не блокирует запись последующих переходов.
+		tracker.failedFlushes++
+		if tracker.failedFlushes < clickFlushRetries && len(tracker.buffer) < clickQueueSize {
+			return
+		}
+		log.Zap.Warn("clicks dropped after failed AddClicks", zap.Int("clicks", len(tracker.buffer)))
+	}
+	tracker.buffer = nil
+	tracker.failedFlushes = 0
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClickTracker_FlushOnClose(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockClickRepo(ctrl)
+	tracker := NewClickTracker(mockRepo)
+
+	var saved []*data.ClickData
+	mockRepo.EXPECT().AddClicks(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, clicks []*data.ClickData) error {
+			saved = append(saved, clicks...)
+			return nil
+		}).MinTimes(1)
+
+	tracker.RunWork()
+	clickedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	for range 3 {
+		tracker.Track(&Click{ShortURL: "abc", ClickedAt: clickedAt, ClientIP: "192.0.2.1"})
+	}
+	require.NoError(t, tracker.Close())
+
+	require.Len(t, saved, 3)
+	assert.Equal(t, "abc", saved[0].ShortURL)
+	assert.Equal(t, "192.0.2.1", saved[0].ClientIP)
+}
+
+func TestClickTracker_DropWhenQueueFull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockClickRepo(ctrl)
+	tracker := NewClickTracker(mockRepo)
+
+	// Воркер не запущен, поэтому очередь не разбирается.
+	for range clickQueueSize + 1 {
+		tracker.Track(&Click{ShortURL: "abc", ClickedAt: time.Now()})
+	}
+
+	assert.Len(t, tracker.clicks, clickQueueSize)
+}
+
+func TestClickTracker_DropAfterFailedFlushes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockClickRepo(ctrl)
+	tracker := NewClickTracker(mockRepo)
+
+	mockRepo.EXPECT().AddClicks(gomock.Any(), gomock.Any()).Return(errors.New("db error")).
+		Times(clickFlushRetries)
+	tracker.buffer = []*data.ClickData{{ShortURL: "abc", ClickedAt: time.Now()}}
+	for range clickFlushRetries - 1 {
+		tracker.flush()
+		require.Len(t, tracker.buffer, 1, "failed clicks are retried")
+	}
+	tracker.flush()
+	assert.Empty(t, tracker.buffer, "clicks are dropped after the last retry")
+
+	mockRepo.EXPECT().AddClicks(gomock.Any(), gomock.Any()).Return(nil)
+	tracker.buffer = []*data.ClickData{{ShortURL: "def", ClickedAt: time.Now()}}
+	tracker.flush()
+	assert.Empty(t, tracker.buffer)
+	assert.Zero(t, tracker.failedFlushes)
+}
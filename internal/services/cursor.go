@@ -0,0 +1,64 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/data"
+)
+
+// linkCursorPayload - Содержимое курсора страницы ссылок пользователя.
+type linkCursorPayload struct {
+	CreatedAt time.Time `json:"t"`
+	ShortURL  string    `json:"id"`
+}
+
+// encodeLinkCursor - Кодирует позицию ссылки в непрозрачный для клиента курсор.
+func encodeLinkCursor(link *data.ShortLinkData) (string, error) {
+	payload, err := json.Marshal(linkCursorPayload{CreatedAt: link.CreatedAt, ShortURL: link.ShortURL})
+	if err != nil {
+		return "", fmt.Errorf("failed marshal link cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+// decodeLinkCursor - Декодирует курсор, полученный от клиента.
+func decodeLinkCursor(cursor string) (*data.LinkCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidPageQuery)
+	}
+	payload := linkCursorPayload{}
+	if err := json.Unmarshal(raw, &payload); err != nil || payload.ShortURL == "" {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidPageQuery)
+	}
+	return &data.LinkCursor{CreatedAt: payload.CreatedAt, ShortURL: payload.ShortURL}, nil
+}
+
+// toRepoLinksQuery - Проверяет параметры выборки и преобразует их в параметры репозитория.
+func toRepoLinksQuery(query *UserLinksQuery) (*data.UserLinksQuery, error) {
+	if query == nil {
+		return &data.UserLinksQuery{}, nil
+	}
+	if query.Limit < 0 || query.Limit > constants.UserLinksMaxLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidPageQuery, constants.UserLinksMaxLimit)
+	}
+
+	repoQuery := &data.UserLinksQuery{
+		Limit:   query.Limit,
+		Desc:    query.Desc,
+		Deleted: query.Deleted,
+		Search:  query.Search,
+	}
+	if query.Cursor != "" {
+		after, err := decodeLinkCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		repoQuery.After = after
+	}
+	return repoQuery, nil
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+)
+
+// DeleteOutcome - Результат удаления одной сокращенной ссылки.
+type DeleteOutcome string
+
+const (
+	// DeleteOutcomePending - Ссылка еще не обработана.
+	DeleteOutcomePending DeleteOutcome = "pending"
+	// DeleteOutcomeDeleted - Ссылка удалена или уже была удалена.
+	DeleteOutcomeDeleted DeleteOutcome = "deleted"
+	// DeleteOutcomeNotFound - Ссылка не найдена.
+	DeleteOutcomeNotFound DeleteOutcome = "not_found"
+	// DeleteOutcomeNotOwned - Ссылка принадлежит другому пользователю и не удалена.
+	DeleteOutcomeNotOwned DeleteOutcome = "not_owned"
+	// DeleteOutcomeFailed - Ссылку не удалось удалить за все попытки.
+	DeleteOutcomeFailed DeleteOutcome = "failed"
+)
+
+// DeleteJobStatus - Статус запроса на удаление ссылок.
+type DeleteJobStatus string
+
+const (
+	// DeleteJobPending - Часть ссылок еще ожидает удаления.
+	DeleteJobPending DeleteJobStatus = "pending"
+	// DeleteJobCompleted - Все ссылки обработаны.
+	DeleteJobCompleted DeleteJobStatus = "completed"
+	// DeleteJobFailed - Все ссылки обработаны, но часть из них не удалось удалить.
+	DeleteJobFailed DeleteJobStatus = "failed"
+)
+
+// DeleteResult - Результат удаления сокращенной ссылки из пачки.
+type DeleteResult struct {
+	ShortURL string
+	Outcome  DeleteOutcome
+}
+
+// DeleteJobItem - Состояние удаления одной ссылки в запросе на удаление.
+type DeleteJobItem struct {
+	ShortURL string
+	Outcome  DeleteOutcome
+	// Error - Ошибка последней неудачной попытки удаления.
+	Error string
+}
+
+// DeleteJob - Запрос на удаление ссылок и его статус.
+type DeleteJob struct {
+	ID        string
+	Status    DeleteJobStatus
+	CreatedAt time.Time
+	Items     []DeleteJobItem
+}
+
+// NewDeleteJob - Собирает статус запроса на удаление по его задачам в очереди.
+func NewDeleteJob(jobID string, tasks []*data.DeleteTaskData) *DeleteJob {
+	job := &DeleteJob{ID: jobID, Status: DeleteJobCompleted, Items: make([]DeleteJobItem, 0, len(tasks))}
+	hasPending := false
+	for _, task := range tasks {
+		if job.CreatedAt.IsZero() || task.CreatedAt.Before(job.CreatedAt) {
+			job.CreatedAt = task.CreatedAt
+		}
+		item := DeleteJobItem{ShortURL: task.ShortURL, Outcome: DeleteOutcome(task.Outcome), Error: task.LastError}
+		switch {
+		case task.CompletedAt != nil:
+			item.Error = ""
+		case task.DeadAt != nil:
+			item.Outcome = DeleteOutcomeFailed
+			job.Status = DeleteJobFailed
+		default:
+			item.Outcome = DeleteOutcomePending
+			hasPending = true
+		}
+		job.Items = append(job.Items, item)
+	}
+	if hasPending {
+		job.Status = DeleteJobPending
+	}
+	return job
+}
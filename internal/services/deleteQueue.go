@@ -0,0 +1,32 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+)
+
+// DeleteQueue - Интерфейс очереди удаления сокращенных ссылок.
+// Запрос на удаление сохраняется в очереди до ответа клиенту, поэтому не теряется при остановке сервиса
+// или ошибке хранилища ссылок, если очередь хранится на диске или в БД.
+// Выполненные задачи хранят результат, по которому строится статус запроса на удаление.
+type DeleteQueue interface {
+	// Enqueue - Ставит в очередь задачи удаления ссылок запроса jobID, готовые к выполнению сразу.
+	// Источник запроса actor сохраняется в задачах для журнала аудита.
+	Enqueue(ctx context.Context, jobID string, shortIDs []data.DeleteShortData, actor data.AuditActorData,
+		now time.Time) error
+	// FetchDue - Получает до limit задач, время выполнения которых не позже dueBefore, в порядке постановки.
+	// Выполненные задачи и задачи, исчерпавшие попытки, не возвращаются.
+	FetchDue(ctx context.Context, dueBefore time.Time, limit int) ([]*data.DeleteTaskData, error)
+	// Complete - Сохраняет результат и время выполнения задач.
+	Complete(ctx context.Context, tasks []*data.DeleteTaskData) error
+	// Fail - Сохраняет количество попыток, время следующей попытки, ошибку и время перевода в dead letter задач.
+	Fail(ctx context.Context, tasks []*data.DeleteTaskData) error
+	// CountPending - Возвращает количество задач, ожидающих выполнения.
+	CountPending(ctx context.Context) (int, error)
+	// GetJob - Получает задачи запроса на удаление в порядке постановки.
+	GetJob(ctx context.Context, jobID string) ([]*data.DeleteTaskData, error)
+	// DeleteCompleted - Удаляет из очереди задачи, выполненные раньше указанного момента.
+	DeleteCompleted(ctx context.Context, before time.Time) error
+}
@@ -0,0 +1,30 @@
+package services
+
+import "errors"
+
+var (
+	// ErrInvalidAlias - Пользовательский псевдоним не прошел валидацию.
+	ErrInvalidAlias = errors.New("invalid alias")
+	// ErrAliasTaken - Пользовательский псевдоним уже занят другой сокращенной ссылкой.
+	ErrAliasTaken = errors.New("alias already taken")
+	// ErrInvalidExpiration - Срок действия ссылки задан некорректно.
+	ErrInvalidExpiration = errors.New("invalid expiration")
+	// ErrLinkNotFound - Сокращенная ссылка не найдена.
+	ErrLinkNotFound = errors.New("link not found")
+	// ErrLinkNotOwned - Сокращенная ссылка принадлежит другому пользователю.
+	ErrLinkNotOwned = errors.New("link belongs to another user")
+	// ErrInvalidPageQuery - Параметры постраничной выборки заданы некорректно.
+	ErrInvalidPageQuery = errors.New("invalid page query")
+	// ErrInvalidAPIKey - Ключ API неизвестен или отозван.
+	ErrInvalidAPIKey = errors.New("invalid api key")
+	// ErrAPIKeyNotFound - Ключ API с указанным идентификатором не найден.
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrInvalidAPIKeyRequest - Параметры создания ключа API заданы некорректно.
+	ErrInvalidAPIKeyRequest = errors.New("invalid api key request")
+	// ErrDeleteJobNotFound - Запрос на удаление не найден или создан другим пользователем.
+	ErrDeleteJobNotFound = errors.New("delete job not found")
+	// ErrOriginalURLTaken - Новый оригинальный URL ссылки уже сокращен другой ссылкой.
+	ErrOriginalURLTaken = errors.New("original url already shortened")
+	// ErrInvalidAuditQuery - Параметры выборки журнала аудита заданы некорректно.
+	ErrInvalidAuditQuery = errors.New("invalid audit query")
+)
@@ -2,50 +2,121 @@ package services
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
-
-	"github.com/VladSnap/shortener/internal/log"
-	_ "github.com/jackc/pgx/v5/stdlib"
-	"go.uber.org/zap"
 )
 
-// HealthService provides health check functionality for the application.
-type HealthService struct {
-	dbConnString string
+// healthCheckTimeout bounds a single health check so that a hanging dependency does not hang the probe.
+const healthCheckTimeout = 5 * time.Second
+
+// HealthChecker checks that a dependency of the application is able to serve requests.
+type HealthChecker interface {
+	// CheckHealth returns an error if the dependency is not healthy.
+	CheckHealth(ctx context.Context) error
 }
 
-// NewHealthService creates a new HealthService instance.
-func NewHealthService(dbConnString string) *HealthService {
-	return &HealthService{
-		dbConnString: dbConnString,
-	}
+// HealthCheckFunc adapts a function to the HealthChecker interface.
+type HealthCheckFunc func(ctx context.Context) error
+
+// CheckHealth calls the function.
+func (f HealthCheckFunc) CheckHealth(ctx context.Context) error {
+	return f(ctx)
 }
 
-// PingDatabase checks if the database connection is healthy.
-func (s *HealthService) PingDatabase(ctx context.Context) error {
-	if s.dbConnString == "" {
-		return nil // No database configured, consider healthy
-	}
+// HealthReport is the result of running a set of health checks.
+type HealthReport struct {
+	// Healthy is true when all checks passed.
+	Healthy bool
+	// Checks maps the check name to its error, nil for a passed check.
+	Checks map[string]error
+}
 
-	db, err := sql.Open("pgx", s.dbConnString)
-	if err != nil {
-		return fmt.Errorf("failed to open database connection: %w", err)
+// Err joins errors of the failed checks prefixed with their names, nil if all checks passed.
+func (r HealthReport) Err() error {
+	names := make([]string, 0, len(r.Checks))
+	for name := range r.Checks {
+		names = append(names, name)
 	}
-	defer func() {
-		if closeErr := db.Close(); closeErr != nil {
-			log.Zap.Error("failed to close database connection", zap.Error(closeErr))
+	sort.Strings(names)
+	var errs []error
+	for _, name := range names {
+		if err := r.Checks[name]; err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
 		}
-	}()
+	}
+	return errors.Join(errs...)
+}
 
-	const timeOutPingSec = 5
-	pingCtx, cancel := context.WithTimeout(ctx, timeOutPingSec*time.Second)
-	defer cancel()
+// namedCheck is a registered health check.
+type namedCheck struct {
+	name    string
+	checker HealthChecker
+}
+
+// HealthService runs liveness and readiness checks of the application.
+// Liveness checks detect a broken process that has to be restarted,
+// readiness checks additionally verify the storage in use and are a superset of liveness checks.
+type HealthService struct {
+	mu        sync.RWMutex
+	liveness  []namedCheck
+	readiness []namedCheck
+}
 
-	if err = db.PingContext(pingCtx); err != nil {
-		return fmt.Errorf("database ping failed: %w", err)
+// NewHealthService creates a new HealthService instance without checks.
+func NewHealthService() *HealthService {
+	return &HealthService{}
+}
+
+// AddLivenessCheck registers a check that runs for both liveness and readiness probes.
+func (s *HealthService) AddLivenessCheck(name string, checker HealthChecker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.liveness = append(s.liveness, namedCheck{name: name, checker: checker})
+	s.readiness = append(s.readiness, namedCheck{name: name, checker: checker})
+}
+
+// AddReadinessCheck registers a check that runs only for readiness probes.
+func (s *HealthService) AddReadinessCheck(name string, checker HealthChecker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readiness = append(s.readiness, namedCheck{name: name, checker: checker})
+}
+
+// Liveness runs liveness checks.
+func (s *HealthService) Liveness(ctx context.Context) HealthReport {
+	s.mu.RLock()
+	checks := s.liveness
+	s.mu.RUnlock()
+	return runChecks(ctx, checks)
+}
+
+// Readiness runs readiness checks.
+func (s *HealthService) Readiness(ctx context.Context) HealthReport {
+	s.mu.RLock()
+	checks := s.readiness
+	s.mu.RUnlock()
+	return runChecks(ctx, checks)
+}
+
+// runChecks runs the checks one by one, each with its own timeout.
+func runChecks(ctx context.Context, checks []namedCheck) HealthReport {
+	report := HealthReport{Healthy: true, Checks: make(map[string]error, len(checks))}
+	for _, check := range checks {
+		err := runCheck(ctx, check.checker)
+		if err != nil {
+			report.Healthy = false
+		}
+		report.Checks[check.name] = err
 	}
+	return report
+}
 
-	return nil
+// runCheck runs a single check with healthCheckTimeout.
+func runCheck(ctx context.Context, checker HealthChecker) error {
+	checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	return checker.CheckHealth(checkCtx) //nolint:wrapcheck // reported under the check name
 }
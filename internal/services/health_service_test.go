@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthService(t *testing.T) {
+	healthy := HealthCheckFunc(func(ctx context.Context) error { return nil })
+	broken := HealthCheckFunc(func(ctx context.Context) error { return errors.New("connection refused") })
+
+	t.Run("readiness includes liveness checks", func(t *testing.T) {
+		hs := NewHealthService()
+		hs.AddLivenessCheck("worker", healthy)
+		hs.AddReadinessCheck("storage", broken)
+
+		liveness := hs.Liveness(t.Context())
+		assert.True(t, liveness.Healthy)
+		assert.Len(t, liveness.Checks, 1)
+		require.NoError(t, liveness.Err())
+
+		readiness := hs.Readiness(t.Context())
+		assert.False(t, readiness.Healthy)
+		assert.Len(t, readiness.Checks, 2)
+		require.NoError(t, readiness.Checks["worker"])
+		require.EqualError(t, readiness.Err(), "storage: connection refused")
+	})
+
+	t.Run("no checks is healthy", func(t *testing.T) {
+		report := NewHealthService().Readiness(t.Context())
+		assert.True(t, report.Healthy)
+		require.NoError(t, report.Err())
+	})
+
+	t.Run("check gets a deadline", func(t *testing.T) {
+		hs := NewHealthService()
+		hs.AddLivenessCheck("deadline", HealthCheckFunc(func(ctx context.Context) error {
+			if _, ok := ctx.Deadline(); !ok {
+				return errors.New("no deadline")
+			}
+			return nil
+		}))
+		require.NoError(t, hs.Liveness(t.Context()).Err())
+	})
+}
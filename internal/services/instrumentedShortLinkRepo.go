@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+)
+
+// InstrumentedShortLinkRepo - Декоратор ShortLinkRepo, замеряющий длительность и результат каждой операции.
+type InstrumentedShortLinkRepo struct {
+	repo    ShortLinkRepo
+	metrics MetricsRecorder
+}
+
+// NewInstrumentedShortLinkRepo - Создает новую структуру InstrumentedShortLinkRepo с указателем.
+func NewInstrumentedShortLinkRepo(repo ShortLinkRepo, metrics MetricsRecorder) *InstrumentedShortLinkRepo {
+	return &InstrumentedShortLinkRepo{repo: repo, metrics: metrics}
+}
+
+// Unwrap - Возвращает исходный репозиторий.
+func (repo *InstrumentedShortLinkRepo) Unwrap() ShortLinkRepo {
+	return repo.repo
+}
+
+// Add - Сохраняет структуру сокращенной ссылки.
+func (repo *InstrumentedShortLinkRepo) Add(ctx context.Context, link *data.ShortLinkData) (
+	result *data.ShortLinkData, err error) {
+	defer repo.observe("add", time.Now(), &err)
+	return repo.repo.Add(ctx, link) //nolint:wrapcheck // decorator
+}
+
+// AddBatch - Сохраняет пачку структур сокращенных ссылок.
+func (repo *InstrumentedShortLinkRepo) AddBatch(ctx context.Context, links []*data.ShortLinkData) (
+	result []*data.ShortLinkData, err error) {
+	defer repo.observe("add_batch", time.Now(), &err)
+	return repo.repo.AddBatch(ctx, links) //nolint:wrapcheck // decorator
+}
+
+// Get - Читает полную ссылку по сокращенной ссылке.
+func (repo *InstrumentedShortLinkRepo) Get(ctx context.Context, shortID string) (
+	link *data.ShortLinkData, err error) {
+	defer repo.observe("get", time.Now(), &err)
+	return repo.repo.Get(ctx, shortID) //nolint:wrapcheck // decorator
+}
+
+// GetAllByUserID - Получить сокращенные ссылки указанного пользователя по параметрам выборки.
+func (repo *InstrumentedShortLinkRepo) GetAllByUserID(ctx context.Context, userID string,
+	query *data.UserLinksQuery) (links []*data.ShortLinkData, err error) {
+	defer repo.observe("get_all_by_user_id", time.Now(), &err)
+	return repo.repo.GetAllByUserID(ctx, userID, query) //nolint:wrapcheck // decorator
+}
+
+// DeleteBatch - Удаляет пачку структур сокращенных ссылок.
+func (repo *InstrumentedShortLinkRepo) DeleteBatch(ctx context.Context, shortIDs []data.DeleteShortData) (
+	err error) {
+	defer repo.observe("delete_batch", time.Now(), &err)
+	return repo.repo.DeleteBatch(ctx, shortIDs) //nolint:wrapcheck // decorator
+}
+
+// RestoreBatch - Снимает пометку удаления со ссылок пачки, удаленных не раньше deletedAfter.
+func (repo *InstrumentedShortLinkRepo) RestoreBatch(ctx context.Context, shortIDs []data.DeleteShortData,
+	deletedAfter time.Time) (err error) {
+	defer repo.observe("restore_batch", time.Now(), &err)
+	return repo.repo.RestoreBatch(ctx, shortIDs, deletedAfter) //nolint:wrapcheck // decorator
+}
+
+// UpdateOriginalURL - Меняет оригинальный URL ссылки пользователя и сохраняет прежний URL в истории.
+func (repo *InstrumentedShortLinkRepo) UpdateOriginalURL(ctx context.Context, shortURL string, userID string,
+	origURL string, changedAt time.Time) (link *data.ShortLinkData, err error) {
+	defer repo.observe("update_original_url", time.Now(), &err)
+	return repo.repo.UpdateOriginalURL(ctx, shortURL, userID, origURL, changedAt) //nolint:wrapcheck // decorator
+}
+
+// GetStats - Получает статистику о пользователях и всех ссылках.
+func (repo *InstrumentedShortLinkRepo) GetStats(ctx context.Context) (stats *data.StatsData, err error) {
+	defer repo.observe("get_stats", time.Now(), &err)
+	return repo.repo.GetStats(ctx) //nolint:wrapcheck // decorator
+}
+
+// Purge - Физически удаляет ссылки, помеченные удаленными или истекшие раньше указанного момента.
+func (repo *InstrumentedShortLinkRepo) Purge(ctx context.Context, before time.Time) (
+	result *data.PurgeData, err error) {
+	defer repo.observe("purge", time.Now(), &err)
+	return repo.repo.Purge(ctx, before) //nolint:wrapcheck // decorator
+}
+
+// GetUserStats - Получает статистику по ссылкам указанного пользователя на указанный момент времени.
+func (repo *InstrumentedShortLinkRepo) GetUserStats(ctx context.Context, userID string, now time.Time) (
+	stats *data.UserStatsData, err error) {
+	defer repo.observe("get_user_stats", time.Now(), &err)
+	return repo.repo.GetUserStats(ctx, userID, now) //nolint:wrapcheck // decorator
+}
+
+// observe - Передает в метрики длительность операции от start и её ошибку.
+func (repo *InstrumentedShortLinkRepo) observe(operation string, start time.Time, err *error) {
+	repo.metrics.ObserveRepoOperation(operation, time.Since(start), *err)
+}
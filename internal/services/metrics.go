@@ -0,0 +1,20 @@
+package services
+
+import "time"
+
+// MetricsRecorder - Получатель метрик сервиса сокращения ссылок и операций репозитория.
+type MetricsRecorder interface {
+	// ObserveRepoOperation - Учитывает длительность и результат операции репозитория.
+	ObserveRepoOperation(operation string, duration time.Duration, err error)
+	// AddShortened - Учитывает сокращенную ссылку с указанным статусом.
+	AddShortened(status BatchItemStatus)
+}
+
+// noopMetrics - Реализация MetricsRecorder, которая никуда не пишет метрики.
+type noopMetrics struct{}
+
+// ObserveRepoOperation - Ничего не делает.
+func (noopMetrics) ObserveRepoOperation(string, time.Duration, error) {}
+
+// AddShortened - Ничего не делает.
+func (noopMetrics) AddShortened(BatchItemStatus) {}
@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/VladSnap/shortener/internal/services (interfaces: ShortLinkRepo)
+// Source: github.com/VladSnap/shortener/internal/services (interfaces: ShortLinkRepo,ClickRepo)
 
 // Package services is a generated GoMock package.
 package services
@@ -7,6 +7,7 @@ package services
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	data "github.com/VladSnap/shortener/internal/data"
 	gomock "github.com/golang/mock/gomock"
@@ -95,18 +96,18 @@ func (mr *MockShortLinkRepoMockRecorder) Get(arg0, arg1 interface{}) *gomock.Cal
 }
 
 // GetAllByUserID mocks base method.
-func (m *MockShortLinkRepo) GetAllByUserID(arg0 context.Context, arg1 string) ([]*data.ShortLinkData, error) {
+func (m *MockShortLinkRepo) GetAllByUserID(arg0 context.Context, arg1 string, arg2 *data.UserLinksQuery) ([]*data.ShortLinkData, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllByUserID", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetAllByUserID", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]*data.ShortLinkData)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllByUserID indicates an expected call of GetAllByUserID.
-func (mr *MockShortLinkRepoMockRecorder) GetAllByUserID(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockShortLinkRepoMockRecorder) GetAllByUserID(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserID", reflect.TypeOf((*MockShortLinkRepo)(nil).GetAllByUserID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserID", reflect.TypeOf((*MockShortLinkRepo)(nil).GetAllByUserID), arg0, arg1, arg2)
 }
 
 // GetStats mocks base method.
@@ -123,3 +124,129 @@ func (mr *MockShortLinkRepoMockRecorder) GetStats(arg0 interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockShortLinkRepo)(nil).GetStats), arg0)
 }
+
+// GetUserStats mocks base method.
+func (m *MockShortLinkRepo) GetUserStats(arg0 context.Context, arg1 string, arg2 time.Time) (*data.UserStatsData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserStats", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*data.UserStatsData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserStats indicates an expected call of GetUserStats.
+func (mr *MockShortLinkRepoMockRecorder) GetUserStats(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserStats", reflect.TypeOf((*MockShortLinkRepo)(nil).GetUserStats), arg0, arg1, arg2)
+}
+
+// Purge mocks base method.
+func (m *MockShortLinkRepo) Purge(arg0 context.Context, arg1 time.Time) (*data.PurgeData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", arg0, arg1)
+	ret0, _ := ret[0].(*data.PurgeData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockShortLinkRepoMockRecorder) Purge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockShortLinkRepo)(nil).Purge), arg0, arg1)
+}
+
+// RestoreBatch mocks base method.
+func (m *MockShortLinkRepo) RestoreBatch(arg0 context.Context, arg1 []data.DeleteShortData, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBatch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreBatch indicates an expected call of RestoreBatch.
+func (mr *MockShortLinkRepoMockRecorder) RestoreBatch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBatch", reflect.TypeOf((*MockShortLinkRepo)(nil).RestoreBatch), arg0, arg1, arg2)
+}
+
+// UpdateOriginalURL mocks base method.
+func (m *MockShortLinkRepo) UpdateOriginalURL(arg0 context.Context, arg1, arg2, arg3 string, arg4 time.Time) (*data.ShortLinkData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOriginalURL", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*data.ShortLinkData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOriginalURL indicates an expected call of UpdateOriginalURL.
+func (mr *MockShortLinkRepoMockRecorder) UpdateOriginalURL(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOriginalURL", reflect.TypeOf((*MockShortLinkRepo)(nil).UpdateOriginalURL), arg0, arg1, arg2, arg3, arg4)
+}
+
+// MockClickRepo is a mock of ClickRepo interface.
+type MockClickRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockClickRepoMockRecorder
+}
+
+// MockClickRepoMockRecorder is the mock recorder for MockClickRepo.
+type MockClickRepoMockRecorder struct {
+	mock *MockClickRepo
+}
+
+// NewMockClickRepo creates a new mock instance.
+func NewMockClickRepo(ctrl *gomock.Controller) *MockClickRepo {
+	mock := &MockClickRepo{ctrl: ctrl}
+	mock.recorder = &MockClickRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClickRepo) EXPECT() *MockClickRepoMockRecorder {
+	return m.recorder
+}
+
+// AddClicks mocks base method.
+func (m *MockClickRepo) AddClicks(arg0 context.Context, arg1 []*data.ClickData) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddClicks", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddClicks indicates an expected call of AddClicks.
+func (mr *MockClickRepoMockRecorder) AddClicks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddClicks", reflect.TypeOf((*MockClickRepo)(nil).AddClicks), arg0, arg1)
+}
+
+// GetClickCounts mocks base method.
+func (m *MockClickRepo) GetClickCounts(arg0 context.Context, arg1 []string) (map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClickCounts", arg0, arg1)
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClickCounts indicates an expected call of GetClickCounts.
+func (mr *MockClickRepoMockRecorder) GetClickCounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClickCounts", reflect.TypeOf((*MockClickRepo)(nil).GetClickCounts), arg0, arg1)
+}
+
+// GetClickStats mocks base method.
+func (m *MockClickRepo) GetClickStats(arg0 context.Context, arg1 string) (*data.ClickStatsData, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClickStats", arg0, arg1)
+	ret0, _ := ret[0].(*data.ClickStatsData)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClickStats indicates an expected call of GetClickStats.
+func (mr *MockClickRepoMockRecorder) GetClickStats(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClickStats", reflect.TypeOf((*MockClickRepo)(nil).GetClickStats), arg0, arg1)
+}
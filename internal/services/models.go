@@ -1,12 +1,32 @@
 // Package services этот пакет хранит модели и сервисы суровня бизнес логики.
 package services
 
+import "time"
+
 // OriginalLink - Структура и доменный объект оригинальной ссылки.
 type OriginalLink struct {
 	CorelationID string
 	URL          string
+	// Alias - Пользовательский идентификатор сокращенной ссылки, если пусто - генерируется случайный.
+	Alias string
+	// TTL - Время жизни ссылки с момента создания, взаимоисключающее с ExpiresAt.
+	TTL time.Duration
+	// ExpiresAt - Абсолютное время окончания действия ссылки, взаимоисключающее с TTL.
+	ExpiresAt *time.Time
 }
 
+// BatchItemStatus - Результат обработки одной ссылки из пачки.
+type BatchItemStatus string
+
+const (
+	// BatchItemCreated - Создана новая сокращенная ссылка.
+	BatchItemCreated BatchItemStatus = "created"
+	// BatchItemDuplicate - Оригинальный URL уже сокращен, возвращена существующая ссылка.
+	BatchItemDuplicate BatchItemStatus = "duplicate"
+	// BatchItemFailed - Ссылка не создана из-за ошибки, только при частичном создании пачки.
+	BatchItemFailed BatchItemStatus = "error"
+)
+
 // ShortedLink - Структура и доменный объект сокращенной ссылки.
 type ShortedLink struct {
 	UUID         string
@@ -15,6 +35,21 @@ type ShortedLink struct {
 	URL          string
 	IsDuplicated bool
 	IsDeleted    bool
+	IsExpired    bool
+	ExpiresAt    *time.Time
+	// Status - Результат обработки ссылки, заполняется только при создании пачки.
+	Status BatchItemStatus
+	// Err - Причина, по которой ссылка из пачки не создана, если Status равен BatchItemFailed.
+	Err error
+	// History - Предыдущие оригинальные URL в порядке изменения, заполняется только при изменении ссылки.
+	History []LinkDestination
+}
+
+// LinkDestination - Предыдущий оригинальный URL сокращенной ссылки.
+type LinkDestination struct {
+	OriginalURL string
+	// ChangedAt - Время, когда ссылка перестала вести на этот URL.
+	ChangedAt time.Time
 }
 
 // NewShortedLink - Создает новую структуру ShortedLink с указателем.
@@ -50,3 +85,61 @@ type Stats struct {
 func NewStats(urls, users int) *Stats {
 	return &Stats{Urls: urls, Users: users}
 }
+
+// Click - Доменный объект перехода по сокращенной ссылке.
+type Click struct {
+	ShortURL  string
+	ClickedAt time.Time
+	Referrer  string
+	UserAgent string
+	ClientIP  string
+}
+
+// DailyClicks - Количество переходов по ссылке за один день.
+type DailyClicks struct {
+	Day    time.Time
+	Clicks int
+}
+
+// LinkStats - Статистика переходов по сокращенной ссылке.
+type LinkStats struct {
+	ShortURL    string
+	TotalClicks int
+	Days        []DailyClicks
+}
+
+// LinkClicks - Количество переходов по сокращенной ссылке.
+type LinkClicks struct {
+	ShortURL string
+	Clicks   int
+}
+
+// UserStats - Статистика по сокращенным ссылкам пользователя.
+type UserStats struct {
+	Active      int
+	Deleted     int
+	Expired     int
+	TotalClicks int
+	TopLinks    []LinkClicks
+}
+
+// UserLinksQuery - Параметры постраничной выборки ссылок пользователя.
+type UserLinksQuery struct {
+	// Limit - Размер страницы, 0 - все ссылки без разбиения на страницы.
+	Limit int
+	// Cursor - Непрозрачный курсор из NextCursor предыдущей страницы.
+	Cursor string
+	// Desc - Сортировка по убыванию времени создания.
+	Desc bool
+	// Deleted - Фильтр по признаку удаления, nil - без фильтра.
+	Deleted *bool
+	// Search - Подстрока оригинального URL без учета регистра.
+	Search string
+}
+
+// UserLinksPage - Страница ссылок пользователя.
+type UserLinksPage struct {
+	Links []*ShortedLink
+	// NextCursor - Курсор следующей страницы, пустой, если страница последняя.
+	NextCursor string
+}
@@ -0,0 +1,48 @@
+package services
+
+import "time"
+
+// ShorterServiceOption - Функция для настройки NaiveShorterService.
+type ShorterServiceOption func(*NaiveShorterService)
+
+// WithAliasRules - Устанавливает правила валидации пользовательских псевдонимов.
+func WithAliasRules(rules AliasRules) ShorterServiceOption {
+	return func(service *NaiveShorterService) {
+		service.aliasRules = rules
+	}
+}
+
+// WithClickRepo - Устанавливает репозиторий переходов по ссылкам для статистики.
+func WithClickRepo(repo ClickRepo) ShorterServiceOption {
+	return func(service *NaiveShorterService) {
+		service.clickRepo = repo
+	}
+}
+
+// WithShortIDGenerator - Устанавливает стратегию генерации идентификаторов сокращенных ссылок.
+func WithShortIDGenerator(generator ShortIDGenerator) ShorterServiceOption {
+	return func(service *NaiveShorterService) {
+		service.idGenerator = generator
+	}
+}
+
+// WithMetrics - Устанавливает получателя метрик сокращенных ссылок.
+func WithMetrics(metrics MetricsRecorder) ShorterServiceOption {
+	return func(service *NaiveShorterService) {
+		service.metrics = metrics
+	}
+}
+
+// WithRestoreWindow - Устанавливает, сколько времени после удаления ссылку можно восстановить.
+func WithRestoreWindow(window time.Duration) ShorterServiceOption {
+	return func(service *NaiveShorterService) {
+		service.restoreWindow = window
+	}
+}
+
+// WithAuditLog - Устанавливает журнал аудита создания, восстановления и изменения ссылок.
+func WithAuditLog(audit *AuditLog) ShorterServiceOption {
+	return func(service *NaiveShorterService) {
+		service.audit = audit
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/VladSnap/shortener/internal/log"
+	"go.uber.org/zap"
+)
+
+// PurgeWorker - Воркер, который по расписанию физически удаляет ссылки,
+// помеченные удаленными или истекшие дольше срока хранения.
+type PurgeWorker struct {
+	shortLinkRepo ShortLinkRepo
+	interval      time.Duration
+	retention     time.Duration
+	done          chan struct{}
+	wg            sync.WaitGroup
+	now           func() time.Time
+}
+
+// NewPurgeWorker - Создает новую структуру PurgeWorker с указателем.
+func NewPurgeWorker(repo ShortLinkRepo, interval time.Duration, retention time.Duration) *PurgeWorker {
+	return &PurgeWorker{
+		shortLinkRepo: repo,
+		interval:      interval,
+		retention:     retention,
+		done:          make(chan struct{}),
+		now:           time.Now,
+	}
+}
+
+// RunWork - Запускает горутину воркера, которая в фоне выполняет очистку с заданным интервалом.
+func (worker *PurgeWorker) RunWork() {
+	ticker := time.NewTicker(worker.interval)
+
+	worker.wg.Add(1)
+	go func() {
+		defer worker.wg.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-worker.done:
+				return
+			case <-ticker.C:
+				_, err := worker.Purge(context.Background())
+				if err != nil {
+					log.Zap.Error("failed purge short links", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// Purge - Выполняет один проход очистки и логирует количество удаленных ссылок.
+func (worker *PurgeWorker) Purge(ctx context.Context) (*data.PurgeData, error) {
+	before := worker.now().UTC().Add(-worker.retention)
+	result, err := worker.shortLinkRepo.Purge(ctx, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed Purge in repo: %w", err)
+	}
+
+	log.Zap.Info("short links purged",
+		zap.Int("deleted", result.Deleted),
+		zap.Int("expired", result.Expired),
+		zap.Int("total", result.Total()),
+		zap.Time("before", before))
+	return result, nil
+}
+
+// Close - Останавливает воркер и дожидается завершения текущей очистки.
+func (worker *PurgeWorker) Close() error {
+	close(worker.done)
+	worker.wg.Wait()
+	return nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurgeWorker_Purge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockShortLinkRepo(ctrl)
+	worker := NewPurgeWorker(mockRepo, time.Hour, 24*time.Hour)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	worker.now = func() time.Time { return now }
+
+	mockRepo.EXPECT().Purge(gomock.Any(), now.Add(-24*time.Hour)).
+		Return(&data.PurgeData{Deleted: 2, Expired: 3}, nil)
+
+	result, err := worker.Purge(t.Context())
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Total())
+}
+
+func TestPurgeWorker_RunWork(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockShortLinkRepo(ctrl)
+	worker := NewPurgeWorker(mockRepo, 10*time.Millisecond, time.Hour)
+
+	purged := make(chan struct{}, 1)
+	mockRepo.EXPECT().Purge(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ any, _ time.Time) (*data.PurgeData, error) {
+			select {
+			case purged <- struct{}{}:
+			default:
+			}
+			return &data.PurgeData{}, nil
+		}).MinTimes(1)
+
+	worker.RunWork()
+
+	select {
+	case <-purged:
+	case <-time.After(time.Second):
+		t.Fatal("purge was not called")
+	}
+	require.NoError(t, worker.Close())
+}
+
+func TestShortLinkData_IsPurgeable(t *testing.T) {
+	before := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	older := before.Add(-time.Hour)
+	newer := before.Add(time.Hour)
+
+	tests := []struct {
+		name string
+		link data.ShortLinkData
+		want bool
+	}{
+		{name: "active link", link: data.ShortLinkData{}, want: false},
+		{name: "deleted long ago", link: data.ShortLinkData{IsDeleted: true, DeletedAt: &older}, want: true},
+		{name: "deleted recently", link: data.ShortLinkData{IsDeleted: true, DeletedAt: &newer}, want: false},
+		{name: "deleted without timestamp", link: data.ShortLinkData{IsDeleted: true}, want: true},
+		{name: "expired long ago", link: data.ShortLinkData{ExpiresAt: &older}, want: true},
+		{name: "expired recently", link: data.ShortLinkData{ExpiresAt: &newer}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.link.IsPurgeable(before))
+		})
+	}
+}
@@ -24,13 +24,14 @@ func (rm *ResourceManager) Register(cleanupFunc func() error) {
 	rm.cleanupFuncs = append(rm.cleanupFuncs, cleanupFunc)
 }
 
-// Cleanup - вызывает все зарегистрированные функции очистки.
+// Cleanup - вызывает все зарегистрированные функции очистки в порядке, обратном регистрации,
+// чтобы воркеры завершались раньше хранилищ, от которых они зависят.
 func (rm *ResourceManager) Cleanup() error {
 	log.Zap.Info("ResourceManager.Cleanup start")
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
-	for _, cleanup := range rm.cleanupFuncs {
-		err := cleanup()
+	for i := len(rm.cleanupFuncs) - 1; i >= 0; i-- {
+		err := rm.cleanupFuncs[i]()
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,23 @@
+package services
+
+// RestoreOutcome - Результат восстановления одной удаленной сокращенной ссылки.
+type RestoreOutcome string
+
+const (
+	// RestoreOutcomeRestored - Ссылка восстановлена.
+	RestoreOutcomeRestored RestoreOutcome = "restored"
+	// RestoreOutcomeNotFound - Ссылка не найдена, например, уже удалена физически.
+	RestoreOutcomeNotFound RestoreOutcome = "not_found"
+	// RestoreOutcomeNotOwned - Ссылка принадлежит другому пользователю и не восстановлена.
+	RestoreOutcomeNotOwned RestoreOutcome = "not_owned"
+	// RestoreOutcomeNotDeleted - Ссылка не удалена.
+	RestoreOutcomeNotDeleted RestoreOutcome = "not_deleted"
+	// RestoreOutcomeExpired - Ссылка удалена раньше окна восстановления.
+	RestoreOutcomeExpired RestoreOutcome = "window_expired"
+)
+
+// RestoreResult - Результат восстановления сокращенной ссылки из пачки.
+type RestoreResult struct {
+	ShortURL string
+	Outcome  RestoreOutcome
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/helpers"
+)
+
+// ShortIDGenerator - Стратегия генерации идентификаторов сокращенных ссылок.
+type ShortIDGenerator interface {
+	// Generate - Генерирует идентификатор для оригинального URL.
+	// attempt - Номер попытки начиная с 0, увеличивается после каждой коллизии идентификатора.
+	Generate(ctx context.Context, originalURL string, attempt int) (string, error)
+}
+
+// ShortIDSequence - Монотонная последовательность чисел для генерации идентификаторов.
+type ShortIDSequence interface {
+	// Next - Возвращает следующее значение последовательности.
+	Next(ctx context.Context) (uint64, error)
+}
+
+// NewShortIDGenerator - Создает генератор идентификаторов по имени стратегии.
+// Для стратегии constants.ShortIDStrategySequence обязательна последовательность sequence.
+func NewShortIDGenerator(strategy, alphabet string, length int, sequence ShortIDSequence) (ShortIDGenerator, error) {
+	switch strategy {
+	case constants.ShortIDStrategyRandom:
+		return NewRandomIDGenerator(alphabet, length), nil
+	case constants.ShortIDStrategySequence:
+		if sequence == nil {
+			return nil, fmt.Errorf("short id strategy '%s' requires a sequence", strategy)
+		}
+		return NewSequenceIDGenerator(sequence, alphabet, length), nil
+	case constants.ShortIDStrategyHash:
+		return NewHashIDGenerator(alphabet, length), nil
+	default:
+		return nil, fmt.Errorf("unknown short id strategy '%s'", strategy)
+	}
+}
+
+// RandomIDGenerator - Генерирует криптографически случайные идентификаторы заданной длины.
+type RandomIDGenerator struct {
+	alphabet []rune
+	length   int
+}
+
+// NewRandomIDGenerator - Создает новую структуру RandomIDGenerator с указателем.
+func NewRandomIDGenerator(alphabet string, length int) *RandomIDGenerator {
+	return &RandomIDGenerator{alphabet: []rune(alphabet), length: length}
+}
+
+// Generate - Генерирует случайный идентификатор, оригинальный URL и номер попытки не используются.
+func (gen *RandomIDGenerator) Generate(ctx context.Context, originalURL string, attempt int) (string, error) {
+	shortID, err := helpers.RandStringFrom(gen.alphabet, gen.length)
+	if err != nil {
+		return "", fmt.Errorf("failed create random short id: %w", err)
+	}
+	return shortID, nil
+}
+
+// SequenceIDGenerator - Генерирует идентификаторы из значений монотонной последовательности
+// в системе счисления по основанию размера алфавита.
+type SequenceIDGenerator struct {
+	sequence  ShortIDSequence
+	alphabet  []rune
+	minLength int
+}
+
+// NewSequenceIDGenerator - Создает новую структуру SequenceIDGenerator с указателем.
+// Идентификаторы короче minLength дополняются слева первым символом алфавита.
+func NewSequenceIDGenerator(sequence ShortIDSequence, alphabet string, minLength int) *SequenceIDGenerator {
+	return &SequenceIDGenerator{sequence: sequence, alphabet: []rune(alphabet), minLength: minLength}
+}
+
+// Generate - Генерирует идентификатор из следующего значения последовательности.
+func (gen *SequenceIDGenerator) Generate(ctx context.Context, originalURL string, attempt int) (string, error) {
+	value, err := gen.sequence.Next(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed get next short id sequence value: %w", err)
+	}
+	return encodeDigits(new(big.Int).SetUint64(value), gen.alphabet, gen.minLength, false), nil
+}
+
+// HashIDGenerator - Генерирует детерминированные идентификаторы из хеша оригинального URL.
+type HashIDGenerator struct {
+	alphabet []rune
+	length   int
+}
+
+// NewHashIDGenerator - Создает новую структуру HashIDGenerator с указателем.
+func NewHashIDGenerator(alphabet string, length int) *HashIDGenerator {
+	return &HashIDGenerator{alphabet: []rune(alphabet), length: length}
+}
+
+// Generate - Генерирует идентификатор из SHA-256 оригинального URL.
+// При повторной попытке к URL добавляется номер попытки, чтобы получить другой идентификатор.
+func (gen *HashIDGenerator) Generate(ctx context.Context, originalURL string, attempt int) (string, error) {
+	input := originalURL
+	if attempt > 0 {
+		input += "#" + strconv.Itoa(attempt)
+	}
+	sum := sha256.Sum256([]byte(input))
+	return encodeDigits(new(big.Int).SetBytes(sum[:]), gen.alphabet, gen.length, true), nil
+}
+
+// CounterSequence - Последовательность на локальном счетчике в памяти процесса.
+type CounterSequence struct {
+	value atomic.Uint64
+}
+
+// NewCounterSequence - Создает новую структуру CounterSequence с указателем.
+// Первое значение последовательности будет равно start + 1.
+func NewCounterSequence(start uint64) *CounterSequence {
+	sequence := new(CounterSequence)
+	sequence.value.Store(start)
+	return sequence
+}
+
+// Next - Возвращает следующее значение счетчика.
+func (sequence *CounterSequence) Next(ctx context.Context) (uint64, error) {
+	return sequence.value.Add(1), nil
+}
+
+// MaxSequenceValue - Возвращает наибольшее значение последовательности среди сохраненных идентификаторов.
+// Идентификаторы с символами не из алфавита и значениями больше uint64 пропускаются.
+// Используется, чтобы локальный счетчик продолжался после уже выданных идентификаторов.
+func MaxSequenceValue(shortIDs []string, alphabet string) uint64 {
+	digits := make(map[rune]uint64, len(alphabet))
+	for i, char := range []rune(alphabet) {
+		digits[char] = uint64(i)
+	}
+	base := uint64(len(digits))
+
+	var result uint64
+	for _, shortID := range shortIDs {
+		value, ok := decodeDigits(shortID, digits, base)
+		if ok && value > result {
+			result = value
+		}
+	}
+	return result
+}
+
+// decodeDigits - Читает число, записанное в системе счисления по основанию base.
+// Возвращает false, если встречен символ не из алфавита или число не помещается в uint64.
+func decodeDigits(shortID string, digits map[rune]uint64, base uint64) (uint64, bool) {
+	var value uint64
+	for _, char := range shortID {
+		digit, ok := digits[char]
+		if !ok || value > (math.MaxUint64-digit)/base {
+			return 0, false
+		}
+		value = value*base + digit
+	}
+	return value, true
+}
+
+// encodeDigits - Записывает число в системе счисления по основанию размера алфавита.
+// Если exact равен true, результат обрезается или дополняется ровно до length символов,
+// иначе дополняется слева до length символов.
+func encodeDigits(value *big.Int, alphabet []rune, length int, exact bool) string {
+	base := big.NewInt(int64(len(alphabet)))
+	digit := new(big.Int)
+	digits := make([]rune, 0, length)
+	for value.Sign() > 0 && (!exact || len(digits) < length) {
+		value.DivMod(value, base, digit)
+		digits = append(digits, alphabet[digit.Int64()])
+	}
+	for len(digits) < length {
+		digits = append(digits, alphabet[0])
+	}
+	// Цифры получены от младших к старшим.
+	for i, j := 0, len(digits)-1; i < j; i, j = i+1, j-1 {
+		digits[i], digits[j] = digits[j], digits[i]
+	}
+	return string(digits)
+}
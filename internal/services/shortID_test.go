@@ -0,0 +1,125 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/VladSnap/shortener/internal/data"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShortIDGenerators(t *testing.T) {
+	t.Run("random uses alphabet and length", func(t *testing.T) {
+		gen := NewRandomIDGenerator("ab", 12)
+		shortID, err := gen.Generate(t.Context(), "http://test.url", 0)
+
+		require.NoError(t, err)
+		assert.Len(t, shortID, 12)
+		assert.Empty(t, strings.Trim(shortID, "ab"))
+	})
+
+	t.Run("sequence encodes counter in base of alphabet", func(t *testing.T) {
+		gen := NewSequenceIDGenerator(NewCounterSequence(60), constants.ShortIDAlphabet, 3)
+		var ids []string
+		for range 3 {
+			shortID, err := gen.Generate(t.Context(), "http://test.url", 0)
+			require.NoError(t, err)
+			ids = append(ids, shortID)
+		}
+
+		assert.Equal(t, []string{"00Z", "010", "011"}, ids)
+	})
+
+	t.Run("sequence is not truncated to min length", func(t *testing.T) {
+		gen := NewSequenceIDGenerator(NewCounterSequence(61), "01", 2)
+		shortID, err := gen.Generate(t.Context(), "http://test.url", 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, "111110", shortID)
+	})
+
+	t.Run("hash is deterministic per attempt", func(t *testing.T) {
+		gen := NewHashIDGenerator(constants.ShortIDAlphabet, 10)
+		first, err := gen.Generate(t.Context(), "http://test.url", 0)
+		require.NoError(t, err)
+		second, err := gen.Generate(t.Context(), "http://test.url", 0)
+		require.NoError(t, err)
+		retry, err := gen.Generate(t.Context(), "http://test.url", 1)
+		require.NoError(t, err)
+
+		assert.Len(t, first, 10)
+		assert.Equal(t, first, second)
+		assert.NotEqual(t, first, retry)
+	})
+
+	t.Run("max sequence value skips foreign and overflowing ids", func(t *testing.T) {
+		value := MaxSequenceValue([]string{"00Z", "010", "my-alias", "zzzzzzzzzzzzzzzzzzzz"}, constants.ShortIDAlphabet)
+
+		assert.Equal(t, uint64(62), value)
+	})
+
+	t.Run("unknown strategy", func(t *testing.T) {
+		_, err := NewShortIDGenerator("unknown", constants.ShortIDAlphabet, constants.ShortIDLength, nil)
+		assert.Error(t, err)
+		_, err = NewShortIDGenerator(constants.ShortIDStrategySequence, constants.ShortIDAlphabet, 8, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestNaiveShortenService_ShortIDCollisionRetry(t *testing.T) {
+	const userID = "d1a8485a-430a-49f4-92ba-50886e1b07c6"
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockShortLinkRepo(ctrl)
+	gen := NewSequenceIDGenerator(NewCounterSequence(0), constants.ShortIDAlphabet, 3)
+	service := NewNaiveShorterService(mockRepo, WithShortIDGenerator(gen))
+	saveLink := func(_ any, link *data.ShortLinkData) (*data.ShortLinkData, error) {
+		return link, nil
+	}
+
+	t.Run("single link", func(t *testing.T) {
+		gomock.InOrder(
+			mockRepo.EXPECT().Add(gomock.Any(), gomock.Any()).Return(nil, data.NewShortURLConflictError("001")),
+			mockRepo.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(saveLink),
+		)
+		result, err := service.CreateShortLink(t.Context(), &OriginalLink{URL: "http://test.url"}, userID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "002", result.URL)
+	})
+
+	t.Run("attempts are limited", func(t *testing.T) {
+		mockRepo.EXPECT().Add(gomock.Any(), gomock.Any()).
+			Return(nil, data.NewShortURLConflictError("taken")).Times(constants.ShortIDMaxAttempts)
+		_, err := service.CreateShortLink(t.Context(), &OriginalLink{URL: "http://test.url"}, userID)
+
+		var conflictErr *data.ShortURLConflictError
+		assert.ErrorAs(t, err, &conflictErr)
+	})
+
+	t.Run("batch link", func(t *testing.T) {
+		gomock.InOrder(
+			mockRepo.EXPECT().AddBatch(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ any, links []*data.ShortLinkData) ([]*data.ShortLinkData, error) {
+					return nil, data.NewShortURLConflictError(links[1].ShortURL)
+				}),
+			mockRepo.EXPECT().AddBatch(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ any, links []*data.ShortLinkData) ([]*data.ShortLinkData, error) {
+					return links, nil
+				}),
+		)
+		links := []*OriginalLink{
+			{CorelationID: "1", URL: "http://test1.url"},
+			{CorelationID: "2", URL: "http://test2.url"},
+		}
+		result, err := service.CreateShortLinkBatch(t.Context(), links, userID, false)
+
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, BatchItemCreated, result[1].Status)
+		assert.NotEqual(t, result[0].URL, result[1].URL)
+	})
+}
@@ -1,13 +1,17 @@
 package services
 
 import (
+	"errors"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/VladSnap/shortener/internal/constants"
 	"github.com/VladSnap/shortener/internal/data"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNaiveShortenService_CreateShortLink(t *testing.T) {
@@ -32,7 +36,7 @@ func TestNaiveShortenService_CreateShortLink(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			retLink := getNewShortLink("tttttttt", tt.sourceURL)
 			mockRepo.EXPECT().Add(ctx, gomock.Any()).Return(retLink, nil)
-			result, err := service.CreateShortLink(t.Context(), tt.sourceURL, userID)
+			result, err := service.CreateShortLink(t.Context(), &OriginalLink{URL: tt.sourceURL}, userID)
 
 			assert.Nil(t, err)
 			assert.NotNil(t, result)
@@ -42,6 +46,229 @@ func TestNaiveShortenService_CreateShortLink(t *testing.T) {
 	}
 }
 
+func TestNaiveShortenService_CreateShortLinkWithAlias(t *testing.T) {
+	const userID = "d1a8485a-430a-49f4-92ba-50886e1b07c6"
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockShortLinkRepo(ctrl)
+	service := NewNaiveShorterService(mockRepo, WithAliasRules(NewAliasRules("abcdefghijklmnopqrstuvwxyz-", 3, 12,
+		[]string{"/ping", "/stats/prometheus"})))
+
+	t.Run("alias used as short id", func(t *testing.T) {
+		mockRepo.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, link *data.ShortLinkData) (*data.ShortLinkData, error) {
+				return link, nil
+			})
+		result, err := service.CreateShortLink(t.Context(), &OriginalLink{URL: "http://test.url", Alias: "spring-sale"}, userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "spring-sale", result.URL)
+		assert.False(t, result.IsDuplicated)
+	})
+
+	t.Run("alias already taken", func(t *testing.T) {
+		mockRepo.EXPECT().Add(gomock.Any(), gomock.Any()).Return(nil, data.NewShortURLConflictError("taken"))
+		_, err := service.CreateShortLink(t.Context(), &OriginalLink{URL: "http://test.url", Alias: "taken"}, userID)
+
+		assert.ErrorIs(t, err, ErrAliasTaken)
+	})
+
+	invalidAliases := []string{"ab", "too-long-alias", "Upper", "with/slash", "ping", "stats"}
+	for _, alias := range invalidAliases {
+		t.Run("invalid alias "+alias, func(t *testing.T) {
+			_, err := service.CreateShortLink(t.Context(), &OriginalLink{URL: "http://test.url", Alias: alias}, userID)
+
+			assert.ErrorIs(t, err, ErrInvalidAlias)
+		})
+	}
+
+	t.Run("default metrics path is free with custom metrics path", func(t *testing.T) {
+		mockRepo.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, link *data.ShortLinkData) (*data.ShortLinkData, error) {
+				return link, nil
+			})
+		result, err := service.CreateShortLink(t.Context(), &OriginalLink{URL: "http://test.url", Alias: "metrics"}, userID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "metrics", result.URL)
+	})
+}
+
+func TestNaiveShortenService_CreateShortLinkBatchWithAlias(t *testing.T) {
+	const userID = "d1a8485a-430a-49f4-92ba-50886e1b07c6"
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockShortLinkRepo(ctrl)
+	service := NewNaiveShorterService(mockRepo)
+
+	t.Run("repeated alias in batch", func(t *testing.T) {
+		links := []*OriginalLink{
+			{CorelationID: "1", URL: "http://test1.url", Alias: "promo"},
+			{CorelationID: "2", URL: "http://test2.url", Alias: "promo"},
+		}
+		_, err := service.CreateShortLinkBatch(t.Context(), links, userID, false)
+
+		assert.ErrorIs(t, err, ErrAliasTaken)
+	})
+
+	t.Run("alias taken in storage", func(t *testing.T) {
+		links := []*OriginalLink{
+			{CorelationID: "1", URL: "http://test1.url", Alias: "promo"},
+			{CorelationID: "2", URL: "http://test2.url"},
+		}
+		mockRepo.EXPECT().AddBatch(gomock.Any(), gomock.Any()).Return(nil, data.NewShortURLConflictError("promo"))
+		_, err := service.CreateShortLinkBatch(t.Context(), links, userID, false)
+
+		assert.ErrorIs(t, err, ErrAliasTaken)
+	})
+}
+
+func TestNaiveShortenService_CreateShortLinkBatchStatuses(t *testing.T) {
+	const userID = "d1a8485a-430a-49f4-92ba-50886e1b07c6"
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockShortLinkRepo(ctrl)
+	service := NewNaiveShorterService(mockRepo)
+	existing := &data.ShortLinkData{UUID: "existing", ShortURL: "exist001", OriginalURL: "http://test2.url"}
+
+	t.Run("duplicates are reported per link", func(t *testing.T) {
+		mockRepo.EXPECT().AddBatch(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, links []*data.ShortLinkData) ([]*data.ShortLinkData, error) {
+				return []*data.ShortLinkData{links[0], existing}, nil
+			})
+		links := []*OriginalLink{
+			{CorelationID: "1", URL: "http://test1.url"},
+			{CorelationID: "2", URL: "http://test2.url"},
+		}
+		result, err := service.CreateShortLinkBatch(t.Context(), links, userID, false)
+
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, BatchItemCreated, result[0].Status)
+		assert.False(t, result[0].IsDuplicated)
+		assert.Equal(t, "2", result[1].CorelationID)
+		assert.Equal(t, BatchItemDuplicate, result[1].Status)
+		assert.Equal(t, "exist001", result[1].URL)
+		assert.True(t, result[1].IsDuplicated)
+	})
+
+	t.Run("partial batch skips invalid links", func(t *testing.T) {
+		var savedBatches [][]*data.ShortLinkData
+		gomock.InOrder(
+			mockRepo.EXPECT().AddBatch(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ any, links []*data.ShortLinkData) ([]*data.ShortLinkData, error) {
+					savedBatches = append(savedBatches, links)
+					return nil, data.NewShortURLConflictError("taken")
+				}),
+			mockRepo.EXPECT().AddBatch(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ any, links []*data.ShortLinkData) ([]*data.ShortLinkData, error) {
+					savedBatches = append(savedBatches, links)
+					return links, nil
+				}),
+		)
+		links := []*OriginalLink{
+			{CorelationID: "1", URL: "http://test1.url", Alias: "promo"},
+			{CorelationID: "2", URL: "http://test2.url", Alias: "promo"},
+			{CorelationID: "3", URL: "http://test3.url", Alias: "bad alias"},
+			{CorelationID: "4", URL: "http://test4.url", Alias: "taken"},
+			{CorelationID: "5", URL: "http://test5.url"},
+		}
+		result, err := service.CreateShortLinkBatch(t.Context(), links, userID, true)
+
+		require.NoError(t, err)
+		require.Len(t, savedBatches, 2)
+		assert.Len(t, savedBatches[0], 3)
+		assert.Len(t, savedBatches[1], 2)
+		require.Len(t, result, 5)
+		assert.Equal(t, BatchItemCreated, result[0].Status)
+		assert.Equal(t, "promo", result[0].URL)
+		assert.Equal(t, BatchItemFailed, result[1].Status)
+		assert.ErrorIs(t, result[1].Err, ErrAliasTaken)
+		assert.Equal(t, BatchItemFailed, result[2].Status)
+		assert.ErrorIs(t, result[2].Err, ErrInvalidAlias)
+		assert.Equal(t, BatchItemFailed, result[3].Status)
+		assert.ErrorIs(t, result[3].Err, ErrAliasTaken)
+		assert.Equal(t, "4", result[3].CorelationID)
+		assert.Equal(t, BatchItemCreated, result[4].Status)
+	})
+}
+
+func TestNaiveShortenService_CreateShortLinkWithExpiration(t *testing.T) {
+	const userID = "d1a8485a-430a-49f4-92ba-50886e1b07c6"
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockShortLinkRepo(ctrl)
+	service := NewNaiveShorterService(mockRepo)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	future := now.Add(48 * time.Hour)
+	past := now.Add(-time.Hour)
+
+	t.Run("ttl sets expires_at", func(t *testing.T) {
+		mockRepo.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, link *data.ShortLinkData) (*data.ShortLinkData, error) {
+				return link, nil
+			})
+		result, err := service.CreateShortLink(t.Context(), &OriginalLink{URL: "http://test.url", TTL: time.Hour}, userID)
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, result.ExpiresAt) {
+			assert.Equal(t, now.Add(time.Hour), *result.ExpiresAt)
+		}
+	})
+
+	t.Run("absolute expires_at", func(t *testing.T) {
+		mockRepo.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, link *data.ShortLinkData) (*data.ShortLinkData, error) {
+				assert.Equal(t, now, link.CreatedAt)
+				return link, nil
+			})
+		result, err := service.CreateShortLink(t.Context(), &OriginalLink{URL: "http://test.url", ExpiresAt: &future}, userID)
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, result.ExpiresAt) {
+			assert.Equal(t, future, *result.ExpiresAt)
+		}
+	})
+
+	invalid := map[string]*OriginalLink{
+		"ttl and expires_at": {URL: "http://test.url", TTL: time.Hour, ExpiresAt: &future},
+		"negative ttl":       {URL: "http://test.url", TTL: -time.Hour},
+		"ttl above maximum":  {URL: "http://test.url", TTL: constants.MaxLinkTTL + time.Second},
+		"expires_at in past": {URL: "http://test.url", ExpiresAt: &past},
+	}
+	for name, link := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.CreateShortLink(t.Context(), link, userID)
+
+			assert.ErrorIs(t, err, ErrInvalidExpiration)
+		})
+	}
+
+	t.Run("expired link", func(t *testing.T) {
+		link := getNewShortLink("expiredL", "http://test.url")
+		link.ExpiresAt = &past
+		mockRepo.EXPECT().Get(gomock.Any(), "expiredL").Return(link, nil)
+		result, err := service.GetURL(t.Context(), "expiredL")
+
+		assert.NoError(t, err)
+		assert.True(t, result.IsExpired)
+	})
+}
+
+func TestTTLFromSeconds(t *testing.T) {
+	maxSeconds := int64(constants.MaxLinkTTL / time.Second)
+
+	ttl, err := TTLFromSeconds(maxSeconds)
+	require.NoError(t, err)
+	assert.Equal(t, constants.MaxLinkTTL, ttl)
+
+	for _, seconds := range []int64{maxSeconds + 1, math.MaxInt64, -1, math.MinInt64} {
+		_, err := TTLFromSeconds(seconds)
+		assert.ErrorIs(t, err, ErrInvalidExpiration, "seconds %d", seconds)
+	}
+}
+
 func TestNaiveShortenService_GetURL(t *testing.T) {
 	type want struct {
 		fullURL string
@@ -83,6 +310,302 @@ func TestNaiveShortenService_GetURL(t *testing.T) {
 	}
 }
 
+func TestNaiveShortenService_GetLinkStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockShortLinkRepo(ctrl)
+	mockClickRepo := NewMockClickRepo(ctrl)
+	service := NewNaiveShorterService(mockRepo, WithClickRepo(mockClickRepo))
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("owner gets stats", func(t *testing.T) {
+		link := getNewShortLink("statLink", "http://test.url")
+		link.UserID = "owner"
+		mockRepo.EXPECT().Get(gomock.Any(), "statLink").Return(link, nil)
+		mockClickRepo.EXPECT().GetClickStats(gomock.Any(), "statLink").
+			Return(&data.ClickStatsData{Total: 2, Days: []data.DailyClicksData{{Day: day, Clicks: 2}}}, nil)
+
+		result, err := service.GetLinkStats(t.Context(), "statLink", "owner")
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.TotalClicks)
+		assert.Equal(t, []DailyClicks{{Day: day, Clicks: 2}}, result.Days)
+	})
+
+	t.Run("link not found", func(t *testing.T) {
+		mockRepo.EXPECT().Get(gomock.Any(), "notFOUND").Return(nil, nil)
+
+		_, err := service.GetLinkStats(t.Context(), "notFOUND", "owner")
+
+		assert.ErrorIs(t, err, ErrLinkNotFound)
+	})
+
+	t.Run("link of another user", func(t *testing.T) {
+		link := getNewShortLink("foreign1", "http://test.url")
+		link.UserID = "someone"
+		mockRepo.EXPECT().Get(gomock.Any(), "foreign1").Return(link, nil)
+
+		_, err := service.GetLinkStats(t.Context(), "foreign1", "owner")
+
+		assert.ErrorIs(t, err, ErrLinkNotOwned)
+	})
+}
+
+func TestNaiveShortenService_DeleteBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockShortLinkRepo(ctrl)
+	service := NewNaiveShorterService(mockRepo)
+
+	t.Run("outcome per link", func(t *testing.T) {
+		owned := getNewShortLink("owned001", "http://a.url")
+		owned.UserID = "owner"
+		foreign := getNewShortLink("foreign1", "http://b.url")
+		foreign.UserID = "someone"
+		mockRepo.EXPECT().Get(gomock.Any(), "owned001").Return(owned, nil)
+		mockRepo.EXPECT().Get(gomock.Any(), "notFOUND").Return(nil, nil)
+		mockRepo.EXPECT().Get(gomock.Any(), "foreign1").Return(foreign, nil)
+		// Удаляются только ссылки пользователя.
+		mockRepo.EXPECT().DeleteBatch(gomock.Any(),
+			[]data.DeleteShortData{data.NewDeleteShortData("owned001", "owner")}).Return(nil)
+
+		results, err := service.DeleteBatch(t.Context(), []DeleteShortID{
+			NewDeleteShortID("owned001", "owner"),
+			NewDeleteShortID("notFOUND", "owner"),
+			NewDeleteShortID("foreign1", "owner"),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []DeleteResult{
+			{ShortURL: "owned001", Outcome: DeleteOutcomeDeleted},
+			{ShortURL: "notFOUND", Outcome: DeleteOutcomeNotFound},
+			{ShortURL: "foreign1", Outcome: DeleteOutcomeNotOwned},
+		}, results)
+	})
+
+	t.Run("nothing to delete", func(t *testing.T) {
+		mockRepo.EXPECT().Get(gomock.Any(), "notFOUND").Return(nil, nil)
+
+		results, err := service.DeleteBatch(t.Context(), []DeleteShortID{NewDeleteShortID("notFOUND", "owner")})
+
+		require.NoError(t, err)
+		assert.Equal(t, []DeleteResult{{ShortURL: "notFOUND", Outcome: DeleteOutcomeNotFound}}, results)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		owned := getNewShortLink("owned001", "http://a.url")
+		owned.UserID = "owner"
+		mockRepo.EXPECT().Get(gomock.Any(), "owned001").Return(owned, nil)
+		mockRepo.EXPECT().DeleteBatch(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+
+		_, err := service.DeleteBatch(t.Context(), []DeleteShortID{NewDeleteShortID("owned001", "owner")})
+
+		require.Error(t, err)
+	})
+}
+
+func TestNaiveShortenService_RestoreBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockShortLinkRepo(ctrl)
+	service := NewNaiveShorterService(mockRepo, WithRestoreWindow(time.Hour))
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	newDeleted := func(shortURL string, userID string, deletedAt *time.Time) *data.ShortLinkData {
+		link := getNewShortLink(shortURL, "http://"+shortURL+".url")
+		link.UserID = userID
+		link.IsDeleted = true
+		link.DeletedAt = deletedAt
+		return link
+	}
+	recent := now.Add(-time.Minute)
+	old := now.Add(-2 * time.Hour)
+
+	t.Run("outcome per link", func(t *testing.T) {
+		active := getNewShortLink("active01", "http://active.url")
+		active.UserID = "owner"
+		mockRepo.EXPECT().Get(gomock.Any(), "recent01").Return(newDeleted("recent01", "owner", &recent), nil)
+		mockRepo.EXPECT().Get(gomock.Any(), "notFOUND").Return(nil, nil)
+		mockRepo.EXPECT().Get(gomock.Any(), "foreign1").Return(newDeleted("foreign1", "someone", &recent), nil)
+		mockRepo.EXPECT().Get(gomock.Any(), "active01").Return(active, nil)
+		mockRepo.EXPECT().Get(gomock.Any(), "old00001").Return(newDeleted("old00001", "owner", &old), nil)
+		mockRepo.EXPECT().Get(gomock.Any(), "legacy01").Return(newDeleted("legacy01", "owner", nil), nil)
+		// Восстанавливаются только ссылки пользователя, удаленные в окне восстановления.
+		mockRepo.EXPECT().RestoreBatch(gomock.Any(),
+			[]data.DeleteShortData{data.NewDeleteShortData("recent01", "owner")}, now.Add(-time.Hour)).Return(nil)
+
+		results, err := service.RestoreBatch(t.Context(), []DeleteShortID{
+			NewDeleteShortID("recent01", "owner"),
+			NewDeleteShortID("notFOUND", "owner"),
+			NewDeleteShortID("foreign1", "owner"),
+			NewDeleteShortID("active01", "owner"),
+			NewDeleteShortID("old00001", "owner"),
+			NewDeleteShortID("legacy01", "owner"),
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []RestoreResult{
+			{ShortURL: "recent01", Outcome: RestoreOutcomeRestored},
+			{ShortURL: "notFOUND", Outcome: RestoreOutcomeNotFound},
+			{ShortURL: "foreign1", Outcome: RestoreOutcomeNotOwned},
+			{ShortURL: "active01", Outcome: RestoreOutcomeNotDeleted},
+			{ShortURL: "old00001", Outcome: RestoreOutcomeExpired},
+			{ShortURL: "legacy01", Outcome: RestoreOutcomeExpired},
+		}, results)
+	})
+
+	t.Run("repo error", func(t *testing.T) {
+		mockRepo.EXPECT().Get(gomock.Any(), "recent01").Return(newDeleted("recent01", "owner", &recent), nil)
+		mockRepo.EXPECT().RestoreBatch(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+
+		_, err := service.RestoreBatch(t.Context(), []DeleteShortID{NewDeleteShortID("recent01", "owner")})
+
+		require.Error(t, err)
+	})
+}
+
+func TestNaiveShortenService_UpdateLink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockShortLinkRepo(ctrl)
+	service := NewNaiveShorterService(mockRepo)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	newOwned := func() *data.ShortLinkData {
+		link := getNewShortLink("owned001", "http://a.url")
+		link.UserID = "owner"
+		return link
+	}
+
+	t.Run("success", func(t *testing.T) {
+		updated := newOwned().WithOriginalURL("http://b.url", now)
+		mockRepo.EXPECT().Get(gomock.Any(), "owned001").Return(newOwned(), nil)
+		mockRepo.EXPECT().UpdateOriginalURL(gomock.Any(), "owned001", "owner", "http://b.url", now).Return(updated, nil)
+
+		link, err := service.UpdateLink(t.Context(), "owned001", "owner", "http://b.url")
+
+		require.NoError(t, err)
+		assert.Equal(t, "http://b.url", link.OriginalURL)
+		assert.Equal(t, []LinkDestination{{OriginalURL: "http://a.url", ChangedAt: now}}, link.History)
+	})
+
+	t.Run("not owned", func(t *testing.T) {
+		mockRepo.EXPECT().Get(gomock.Any(), "owned001").Return(newOwned(), nil)
+
+		_, err := service.UpdateLink(t.Context(), "owned001", "someone", "http://b.url")
+
+		require.ErrorIs(t, err, ErrLinkNotOwned)
+	})
+
+	t.Run("deleted", func(t *testing.T) {
+		deleted := newOwned()
+		deleted.IsDeleted = true
+		mockRepo.EXPECT().Get(gomock.Any(), "owned001").Return(deleted, nil)
+
+		_, err := service.UpdateLink(t.Context(), "owned001", "owner", "http://b.url")
+
+		require.ErrorIs(t, err, ErrLinkNotFound)
+	})
+
+	t.Run("url already shortened", func(t *testing.T) {
+		mockRepo.EXPECT().Get(gomock.Any(), "owned001").Return(newOwned(), nil)
+		mockRepo.EXPECT().UpdateOriginalURL(gomock.Any(), "owned001", "owner", "http://b.url", now).
+			Return(nil, data.NewDuplicateError("other001"))
+
+		_, err := service.UpdateLink(t.Context(), "owned001", "owner", "http://b.url")
+
+		require.ErrorIs(t, err, ErrOriginalURLTaken)
+		assert.Contains(t, err.Error(), "other001")
+	})
+}
+
+func TestNaiveShortenService_GetUserStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockShortLinkRepo(ctrl)
+	mockClickRepo := NewMockClickRepo(ctrl)
+	service := NewNaiveShorterService(mockRepo, WithClickRepo(mockClickRepo))
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	shortURLs := []string{"link0001", "link0002", "link0003", "link0004", "link0005", "link0006", "link0007"}
+
+	mockRepo.EXPECT().GetUserStats(gomock.Any(), "owner", now).
+		Return(&data.UserStatsData{Active: 5, Deleted: 1, Expired: 1, ShortURLs: shortURLs}, nil)
+	mockClickRepo.EXPECT().GetClickCounts(gomock.Any(), shortURLs).Return(map[string]int{
+		"link0001": 1, "link0002": 7, "link0003": 3, "link0004": 3, "link0005": 2, "link0006": 4, "link0007": 0,
+	}, nil)
+
+	result, err := service.GetUserStats(t.Context(), "owner")
+
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Active)
+	assert.Equal(t, 1, result.Deleted)
+	assert.Equal(t, 1, result.Expired)
+	assert.Equal(t, 20, result.TotalClicks)
+	assert.Equal(t, []LinkClicks{
+		{ShortURL: "link0002", Clicks: 7},
+		{ShortURL: "link0006", Clicks: 4},
+		{ShortURL: "link0003", Clicks: 3},
+		{ShortURL: "link0004", Clicks: 3},
+		{ShortURL: "link0005", Clicks: 2},
+	}, result.TopLinks)
+}
+
+func TestNaiveShortenService_GetAllByUserID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockRepo := NewMockShortLinkRepo(ctrl)
+	service := NewNaiveShorterService(mockRepo)
+	createdAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	links := []*data.ShortLinkData{
+		{ShortURL: "link0001", OriginalURL: "http://1.url", CreatedAt: createdAt},
+		{ShortURL: "link0002", OriginalURL: "http://2.url", CreatedAt: createdAt.Add(time.Second)},
+		{ShortURL: "link0003", OriginalURL: "http://3.url", CreatedAt: createdAt.Add(2 * time.Second)},
+	}
+
+	t.Run("first page returns next cursor", func(t *testing.T) {
+		mockRepo.EXPECT().GetAllByUserID(gomock.Any(), "owner", &data.UserLinksQuery{Limit: 3}).Return(links, nil)
+
+		page, err := service.GetAllByUserID(t.Context(), "owner", &UserLinksQuery{Limit: 2})
+
+		require.NoError(t, err)
+		require.Len(t, page.Links, 2)
+		assert.Equal(t, "link0002", page.Links[1].URL)
+		require.NotEmpty(t, page.NextCursor)
+
+		cursor, err := decodeLinkCursor(page.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, "link0002", cursor.ShortURL)
+		assert.True(t, links[1].CreatedAt.Equal(cursor.CreatedAt))
+	})
+
+	t.Run("last page has no next cursor", func(t *testing.T) {
+		cursor, err := encodeLinkCursor(links[1])
+		require.NoError(t, err)
+		mockRepo.EXPECT().GetAllByUserID(gomock.Any(), "owner", gomock.Any()).
+			DoAndReturn(func(_ any, _ string, query *data.UserLinksQuery) ([]*data.ShortLinkData, error) {
+				assert.Equal(t, "link0002", query.After.ShortURL)
+				return links[2:], nil
+			})
+
+		page, err := service.GetAllByUserID(t.Context(), "owner", &UserLinksQuery{Limit: 2, Cursor: cursor})
+
+		require.NoError(t, err)
+		require.Len(t, page.Links, 1)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		_, err := service.GetAllByUserID(t.Context(), "owner", &UserLinksQuery{Limit: constants.UserLinksMaxLimit + 1})
+		assert.ErrorIs(t, err, ErrInvalidPageQuery)
+
+		_, err = service.GetAllByUserID(t.Context(), "owner", &UserLinksQuery{Limit: 1, Cursor: "not a cursor"})
+		assert.ErrorIs(t, err, ErrInvalidPageQuery)
+	})
+}
+
 func getNewShortLink(shortID string, originalURL string) *data.ShortLinkData {
 	id := uuid.MustParse("2093ad7c-6227-4d97-8f83-9e837ab6474b")
 	return &data.ShortLinkData{UUID: id.String(), ShortURL: shortID, OriginalURL: originalURL}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"time"
 
 	"github.com/VladSnap/shortener/internal/constants"
 	"github.com/VladSnap/shortener/internal/data"
-	"github.com/VladSnap/shortener/internal/helpers"
 	"github.com/google/uuid"
 )
 
@@ -19,50 +21,109 @@ type ShortLinkRepo interface {
 	AddBatch(ctx context.Context, links []*data.ShortLinkData) ([]*data.ShortLinkData, error)
 	// Get - Читает полную ссылку по сокращенной ссылке.
 	Get(ctx context.Context, shortID string) (*data.ShortLinkData, error)
-	// GetAllByUserID - Получить все сокращенные ссылки указанного пользователя.
-	GetAllByUserID(ctx context.Context, userID string) ([]*data.ShortLinkData, error)
+	// GetAllByUserID - Получить сокращенные ссылки указанного пользователя по параметрам выборки.
+	GetAllByUserID(ctx context.Context, userID string, query *data.UserLinksQuery) ([]*data.ShortLinkData, error)
 	// DeleteBatch - Удаляет пачку структур сокращенных ссылок.
 	DeleteBatch(ctx context.Context, shortIDs []data.DeleteShortData) error
+	// RestoreBatch - Снимает пометку удаления со ссылок пачки, удаленных не раньше deletedAfter.
+	RestoreBatch(ctx context.Context, shortIDs []data.DeleteShortData, deletedAfter time.Time) error
+	// UpdateOriginalURL - Меняет оригинальный URL неудаленной ссылки пользователя и сохраняет прежний URL в истории.
+	// Возвращает обновленную ссылку с историей или nil, если такой ссылки нет.
+	// Если новый URL уже сокращен, возвращает DuplicateShortLinkError.
+	UpdateOriginalURL(ctx context.Context, shortURL string, userID string, origURL string, changedAt time.Time) (
+		*data.ShortLinkData, error)
 	// GetStats - Получает статистику о пользователях и всех ссылках.
 	GetStats(ctx context.Context) (*data.StatsData, error)
+	// Purge - Физически удаляет ссылки, помеченные удаленными или истекшие раньше указанного момента.
+	Purge(ctx context.Context, before time.Time) (*data.PurgeData, error)
+	// GetUserStats - Получает статистику по ссылкам указанного пользователя на указанный момент времени.
+	GetUserStats(ctx context.Context, userID string, now time.Time) (*data.UserStatsData, error)
 }
 
-// Генерирует мок для ShortLinkRepo
-//go:generate mockgen -destination=mock_services_test.go -package services github.com/VladSnap/shortener/internal/services ShortLinkRepo
+// ClickRepo - Интерфейс репозитория переходов по сокращенным ссылкам.
+type ClickRepo interface {
+	// AddClicks - Сохраняет пачку переходов по ссылкам.
+	AddClicks(ctx context.Context, clicks []*data.ClickData) error
+	// GetClickStats - Получает статистику переходов по сокращенной ссылке с группировкой по дням.
+	GetClickStats(ctx context.Context, shortURL string) (*data.ClickStatsData, error)
+	// GetClickCounts - Получает общее количество переходов по каждой из указанных ссылок.
+	GetClickCounts(ctx context.Context, shortURLs []string) (map[string]int, error)
+}
+
+// Генерирует мок для ShortLinkRepo и ClickRepo
+//go:generate mockgen -destination=mock_services_test.go -package services github.com/VladSnap/shortener/internal/services ShortLinkRepo,ClickRepo
 
 // NaiveShorterService - Структура сервиса сокращателя ссылок.
 type NaiveShorterService struct {
 	shortLinkRepo ShortLinkRepo
+	clickRepo     ClickRepo
+	aliasRules    AliasRules
+	idGenerator   ShortIDGenerator
+	metrics       MetricsRecorder
+	restoreWindow time.Duration
+	audit         *AuditLog
+	now           func() time.Time
 }
 
 // NewNaiveShorterService - Создает новую структуру NaiveShorterService с указателем.
-func NewNaiveShorterService(repo ShortLinkRepo) *NaiveShorterService {
+func NewNaiveShorterService(repo ShortLinkRepo, options ...ShorterServiceOption) *NaiveShorterService {
 	service := new(NaiveShorterService)
 	service.shortLinkRepo = repo
+	service.aliasRules = DefaultAliasRules()
+	service.idGenerator = NewRandomIDGenerator(constants.ShortIDAlphabet, constants.ShortIDLength)
+	service.metrics = noopMetrics{}
+	service.restoreWindow = constants.RestoreWindow
+	service.now = time.Now
+	for _, option := range options {
+		option(service)
+	}
 	return service
 }
 
 // CreateShortLink - Создает сокращенную ссылку.
+// Если сгенерированный идентификатор уже занят, генерирует новый, но не более constants.ShortIDMaxAttempts раз.
 func (service *NaiveShorterService) CreateShortLink(ctx context.Context,
-	originalURL string, userID string) (*ShortedLink, error) {
-	id, shortID, err := createNewIds()
-	if err != nil {
-		return nil, fmt.Errorf("failed create ids: %w", err)
-	}
-	newLink := data.NewShortLinkData(id.String(), shortID, originalURL, userID)
-	createdLink, err := service.shortLinkRepo.Add(ctx, newLink)
-	if err != nil {
-		var duplErr *data.DuplicateShortLinkError
-		if errors.As(err, &duplErr) {
-			res := NewShortedLink("", "", "", duplErr.ShortURL, true, false)
-			return res, nil
+	originalLink *OriginalLink, userID string) (*ShortedLink, error) {
+	for attempt := 0; ; attempt++ {
+		id, shortID, err := service.createNewIds(ctx, originalLink, attempt)
+		if err != nil {
+			return nil, fmt.Errorf("failed create ids: %w", err)
+		}
+		newLink, err := service.newShortLinkData(id.String(), shortID, originalLink, userID)
+		if err != nil {
+			return nil, err
+		}
+		createdLink, err := service.shortLinkRepo.Add(ctx, newLink)
+		if err != nil {
+			var duplErr *data.DuplicateShortLinkError
+			if errors.As(err, &duplErr) {
+				service.metrics.AddShortened(BatchItemDuplicate)
+				res := NewShortedLink("", "", "", duplErr.ShortURL, true, false)
+				return res, nil
+			}
+			if aliasErr := asAliasTaken(err, originalLink.Alias); aliasErr != nil {
+				return nil, aliasErr
+			}
+			var conflictErr *data.ShortURLConflictError
+			if errors.As(err, &conflictErr) && attempt+1 < constants.ShortIDMaxAttempts {
+				continue
+			}
+			return nil, fmt.Errorf("failed create short link object: %w", err)
 		}
-		return nil, fmt.Errorf("failed create short link object: %w", err)
+		// Если короткие ссылки разные, значит был найден дубль и возвращено его значение.
+		isDuplicate := shortID != createdLink.ShortURL
+		if isDuplicate {
+			service.metrics.AddShortened(BatchItemDuplicate)
+		} else {
+			service.metrics.AddShortened(BatchItemCreated)
+			service.audit.Record(ctx, []*AuditEvent{
+				newLinkAuditEvent(AuditActionCreate, createdLink.ShortURL, userID, createdLink.OriginalURL),
+			})
+		}
+		res := NewShortedLink(createdLink.UUID, "", createdLink.OriginalURL, createdLink.ShortURL, isDuplicate, false)
+		res.ExpiresAt = createdLink.ExpiresAt
+		return res, nil
 	}
-	// Если короткие ссылки разные, значит был найден дубль и возвращено его значение.
-	isDuplicate := shortID != createdLink.ShortURL
-	res := NewShortedLink(createdLink.UUID, "", createdLink.OriginalURL, createdLink.ShortURL, isDuplicate, false)
-	return res, nil
 }
 
 // GetURL - Читает оригинальную ссылку по сокращенной ссылке.
@@ -71,65 +132,248 @@ func (service *NaiveShorterService) GetURL(ctx context.Context, shortID string)
 	if err != nil {
 		return nil, fmt.Errorf("failed get url from repo: %w", err)
 	} else if link != nil {
-		return NewShortedLink(link.UUID, "", link.OriginalURL, link.ShortURL, false, link.IsDeleted), nil
+		return service.toShortedLink(link), nil
 	}
 	return nil, nil //nolint:nilnil // expected return nil
 }
 
 // CreateShortLinkBatch - Создает пачку сокращенных ссылок.
+// Результат возвращается в порядке пачки, для уже сокращенных URL возвращается существующая ссылка.
+// Занятые сгенерированные идентификаторы генерируются заново, как в CreateShortLink.
+// Если partial равен false, ошибка в любой ссылке отменяет всю пачку, иначе такие ссылки
+// возвращаются со статусом BatchItemFailed, а остальные создаются.
 func (service *NaiveShorterService) CreateShortLinkBatch(ctx context.Context,
-	originalLinks []*OriginalLink, userID string) ([]*ShortedLink, error) {
-	dataModels := make([]*data.ShortLinkData, 0, len(originalLinks))
-	createdModels := make([]*ShortedLink, 0, len(originalLinks))
-
-	if len(originalLinks) == 0 {
-		return createdModels, nil
-	}
+	originalLinks []*OriginalLink, userID string, partial bool) ([]*ShortedLink, error) {
+	results := make([]*ShortedLink, len(originalLinks))
+	dataModels := make([]*data.ShortLinkData, len(originalLinks))
+	// pending - Индексы ссылок, которые нужно сохранить в репозитории.
+	pending := make([]int, 0, len(originalLinks))
+	// attempts - Номера попыток генерации идентификаторов ссылок.
+	attempts := make([]int, len(originalLinks))
 
-	for _, ol := range originalLinks {
-		id, shortID, err := createNewIds()
+	aliases := make(map[string]bool, len(originalLinks))
+	for i, ol := range originalLinks {
+		dm, err := service.newBatchLinkData(ctx, ol, userID, aliases)
 		if err != nil {
-			return nil, fmt.Errorf("failed create ids: %w", err)
+			if !partial {
+				return nil, err
+			}
+			results[i] = newFailedShortedLink(ol, err)
+			continue
 		}
-		dm := data.NewShortLinkData(id.String(), shortID, ol.URL, userID)
-		dataModels = append(dataModels, dm)
-		cm := NewShortedLink(id.String(), ol.CorelationID, ol.URL, shortID, false, false)
-		createdModels = append(createdModels, cm)
+		dataModels[i] = dm
+		pending = append(pending, i)
 	}
 
-	_, err := service.shortLinkRepo.AddBatch(ctx, dataModels)
-	if err != nil {
-		return nil, fmt.Errorf("failed add batch in repo: %w", err)
+	for len(pending) > 0 {
+		batch := make([]*data.ShortLinkData, 0, len(pending))
+		for _, i := range pending {
+			batch = append(batch, dataModels[i])
+		}
+		storedLinks, err := service.shortLinkRepo.AddBatch(ctx, batch)
+		if err == nil {
+			for k, i := range pending {
+				results[i] = toBatchShortedLink(originalLinks[i], dataModels[i], storedLinks[k])
+			}
+			break
+		}
+
+		var conflictErr *data.ShortURLConflictError
+		if !errors.As(err, &conflictErr) {
+			return nil, fmt.Errorf("failed add batch in repo: %w", err)
+		}
+		regenerated, genErr := service.regenerateBatchID(ctx, conflictErr.ShortURL, pending,
+			originalLinks, dataModels, attempts)
+		if genErr != nil {
+			return nil, fmt.Errorf("failed add batch in repo: %w", errors.Join(err, genErr))
+		}
+		if regenerated {
+			continue
+		}
+		if !aliases[conflictErr.ShortURL] {
+			return nil, fmt.Errorf("failed add batch in repo: %w", err)
+		}
+		aliasErr := fmt.Errorf("%w: '%s'", ErrAliasTaken, conflictErr.ShortURL)
+		if !partial {
+			return nil, aliasErr
+		}
+		// Исключаем ссылку с занятым псевдонимом и повторяем сохранение остальных.
+		pendingCount := len(pending)
+		pending = slices.DeleteFunc(pending, func(i int) bool {
+			if originalLinks[i].Alias == "" || dataModels[i].ShortURL != conflictErr.ShortURL {
+				return false
+			}
+			results[i] = newFailedShortedLink(originalLinks[i], aliasErr)
+			return true
+		})
+		if len(pending) == pendingCount {
+			return nil, fmt.Errorf("failed add batch in repo: %w", err)
+		}
 	}
-	// todo: Тут по хорошему надо обновить ShortURL в моделях, если в репозитории будет логика проверки дублей
 
-	return createdModels, nil
+	events := make([]*AuditEvent, 0, len(results))
+	for _, result := range results {
+		service.metrics.AddShortened(result.Status)
+		if result.Status == BatchItemCreated {
+			events = append(events, newLinkAuditEvent(AuditActionCreate, result.URL, userID, result.OriginalURL))
+		}
+	}
+	service.audit.Record(ctx, events)
+	return results, nil
 }
 
-// GetAllByUserID - Получить все сокращенные ссылки указанного пользователя.
-func (service *NaiveShorterService) GetAllByUserID(ctx context.Context, userID string) (
-	[]*ShortedLink, error) {
-	links, err := service.shortLinkRepo.GetAllByUserID(ctx, userID)
+// GetAllByUserID - Получить страницу сокращенных ссылок указанного пользователя.
+// Если query равен nil или лимит не задан, возвращаются все ссылки пользователя.
+func (service *NaiveShorterService) GetAllByUserID(ctx context.Context, userID string, query *UserLinksQuery) (
+	*UserLinksPage, error) {
+	repoQuery, err := toRepoLinksQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	limit := repoQuery.Limit
+	if limit > 0 {
+		// Запрашиваем на одну ссылку больше, чтобы узнать, есть ли следующая страница.
+		repoQuery.Limit++
+	}
+
+	links, err := service.shortLinkRepo.GetAllByUserID(ctx, userID, repoQuery)
 	if err != nil {
 		return nil, fmt.Errorf("failed GetAllByUserId: %w", err)
 	}
 
-	shortedLinks := make([]*ShortedLink, 0, len(links))
+	page := &UserLinksPage{}
+	if limit > 0 && len(links) > limit {
+		links = links[:limit]
+		page.NextCursor, err = encodeLinkCursor(links[limit-1])
+		if err != nil {
+			return nil, err
+		}
+	}
+	page.Links = make([]*ShortedLink, 0, len(links))
 	for _, sl := range links {
-		shortedLink := NewShortedLink(sl.UUID, "", sl.OriginalURL, sl.ShortURL, false, sl.IsDeleted)
-		shortedLinks = append(shortedLinks, shortedLink)
+		page.Links = append(page.Links, service.toShortedLink(sl))
+	}
+
+	return page, nil
+}
+
+// DeleteBatch - Удаляет пачку сокращенных ссылок и возвращает результат по каждой ссылке в порядке запроса.
+// Ссылки, которые не найдены или принадлежат другому пользователю, не удаляются.
+// Удаление записывается в журнал аудита воркером удаления, который хранит источник запроса в задачах.
+func (service *NaiveShorterService) DeleteBatch(ctx context.Context, shortIDs []DeleteShortID) (
+	[]DeleteResult, error) {
+	results := make([]DeleteResult, 0, len(shortIDs))
+	toDelete := make([]DeleteShortID, 0, len(shortIDs))
+	for _, sid := range shortIDs {
+		link, err := service.shortLinkRepo.Get(ctx, sid.ShortURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed get link from repo: %w", err)
+		}
+		result := DeleteResult{ShortURL: sid.ShortURL, Outcome: DeleteOutcomeDeleted}
+		switch {
+		case link == nil:
+			result.Outcome = DeleteOutcomeNotFound
+		case link.UserID != sid.UserID:
+			result.Outcome = DeleteOutcomeNotOwned
+		default:
+			toDelete = append(toDelete, sid)
+		}
+		results = append(results, result)
+	}
+
+	if len(toDelete) > 0 {
+		err := service.shortLinkRepo.DeleteBatch(ctx, convertDeleteShort(toDelete))
+		if err != nil {
+			return nil, fmt.Errorf("failed DeleteBatch in repo: %w", err)
+		}
+	}
+	return results, nil
+}
+
+// RestoreBatch - Восстанавливает пачку удаленных сокращенных ссылок и возвращает результат по каждой ссылке
+// в порядке запроса. Восстанавливаются только ссылки пользователя, удаленные не раньше окна восстановления.
+func (service *NaiveShorterService) RestoreBatch(ctx context.Context, shortIDs []DeleteShortID) (
+	[]RestoreResult, error) {
+	deletedAfter := service.now().Add(-service.restoreWindow)
+	results := make([]RestoreResult, 0, len(shortIDs))
+	toRestore := make([]DeleteShortID, 0, len(shortIDs))
+	for _, sid := range shortIDs {
+		link, err := service.shortLinkRepo.Get(ctx, sid.ShortURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed get link from repo: %w", err)
+		}
+		result := RestoreResult{ShortURL: sid.ShortURL, Outcome: RestoreOutcomeRestored}
+		switch {
+		case link == nil:
+			result.Outcome = RestoreOutcomeNotFound
+		case link.UserID != sid.UserID:
+			result.Outcome = RestoreOutcomeNotOwned
+		case !link.IsDeleted:
+			result.Outcome = RestoreOutcomeNotDeleted
+		case !link.IsRestorable(deletedAfter):
+			result.Outcome = RestoreOutcomeExpired
+		default:
+			toRestore = append(toRestore, sid)
+		}
+		results = append(results, result)
 	}
 
-	return shortedLinks, nil
+	if len(toRestore) > 0 {
+		err := service.shortLinkRepo.RestoreBatch(ctx, convertDeleteShort(toRestore), deletedAfter)
+		if err != nil {
+			return nil, fmt.Errorf("failed RestoreBatch in repo: %w", err)
+		}
+		events := make([]*AuditEvent, 0, len(toRestore))
+		for _, sid := range toRestore {
+			events = append(events, newLinkAuditEvent(AuditActionRestore, sid.ShortURL, sid.UserID, ""))
+		}
+		service.audit.Record(ctx, events)
+	}
+	return results, nil
 }
 
-// DeleteBatch - Удаляет пачку структур сокращенных ссылок.
-func (service *NaiveShorterService) DeleteBatch(ctx context.Context, shortIDs []DeleteShortID) error {
-	err := service.shortLinkRepo.DeleteBatch(ctx, convertDeleteShort(shortIDs))
+// UpdateLink - Меняет оригинальный URL неудаленной сокращенной ссылки пользователя.
+// Возвращает обновленную ссылку с историей прежних URL. Если новый URL совпадает с текущим, ссылка не меняется.
+func (service *NaiveShorterService) UpdateLink(ctx context.Context, shortID string, userID string,
+	originalURL string) (*ShortedLink, error) {
+	link, err := service.shortLinkRepo.Get(ctx, shortID)
 	if err != nil {
-		return fmt.Errorf("failed DeleteBatch in repo: %w", err)
+		return nil, fmt.Errorf("failed get link from repo: %w", err)
 	}
-	return nil
+	if link == nil || link.IsDeleted {
+		return nil, fmt.Errorf("%w: '%s'", ErrLinkNotFound, shortID)
+	}
+	if link.UserID != userID {
+		return nil, fmt.Errorf("%w: '%s'", ErrLinkNotOwned, shortID)
+	}
+
+	updated, err := service.shortLinkRepo.UpdateOriginalURL(ctx, shortID, userID, originalURL, service.now())
+	if err != nil {
+		var duplErr *data.DuplicateShortLinkError
+		if errors.As(err, &duplErr) {
+			return nil, fmt.Errorf("%w by '%s'", ErrOriginalURLTaken, duplErr.ShortURL)
+		}
+		return nil, fmt.Errorf("failed update link in repo: %w", err)
+	}
+	// Ссылку могли удалить между чтением и изменением.
+	if updated == nil {
+		return nil, fmt.Errorf("%w: '%s'", ErrLinkNotFound, shortID)
+	}
+	if updated.OriginalURL != link.OriginalURL {
+		service.audit.Record(ctx, []*AuditEvent{
+			newLinkAuditEvent(AuditActionUpdate, shortID, userID, updated.OriginalURL),
+		})
+	}
+
+	res := service.toShortedLink(updated)
+	res.History = make([]LinkDestination, 0, len(updated.History))
+	for _, destination := range updated.History {
+		res.History = append(res.History, LinkDestination{
+			OriginalURL: destination.OriginalURL,
+			ChangedAt:   destination.ChangedAt,
+		})
+	}
+	return res, nil
 }
 
 // GetStats - Получает статистику о пользователях и всех ссылках.
@@ -141,20 +385,169 @@ func (service *NaiveShorterService) GetStats(ctx context.Context) (*Stats, error
 	return NewStats(stats.Urls, stats.Users), nil
 }
 
-func createNewIds() (id uuid.UUID, shortID string, err error) {
+// GetLinkStats - Получает статистику переходов по сокращенной ссылке, принадлежащей пользователю.
+func (service *NaiveShorterService) GetLinkStats(ctx context.Context, shortID string, userID string) (
+	*LinkStats, error) {
+	link, err := service.shortLinkRepo.Get(ctx, shortID)
+	if err != nil {
+		return nil, fmt.Errorf("failed get link from repo: %w", err)
+	}
+	if link == nil {
+		return nil, fmt.Errorf("%w: '%s'", ErrLinkNotFound, shortID)
+	}
+	if link.UserID != userID {
+		return nil, fmt.Errorf("%w: '%s'", ErrLinkNotOwned, shortID)
+	}
+
+	stats := &LinkStats{ShortURL: link.ShortURL, Days: make([]DailyClicks, 0)}
+	if service.clickRepo == nil {
+		return stats, nil
+	}
+	clickStats, err := service.clickRepo.GetClickStats(ctx, link.ShortURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetClickStats in repo: %w", err)
+	}
+	stats.TotalClicks = clickStats.Total
+	for _, day := range clickStats.Days {
+		stats.Days = append(stats.Days, DailyClicks{Day: day.Day, Clicks: day.Clicks})
+	}
+	return stats, nil
+}
+
+// GetUserStats - Получает статистику по ссылкам пользователя: количество действующих, удаленных и истекших ссылок,
+// общее количество переходов и самые популярные ссылки.
+func (service *NaiveShorterService) GetUserStats(ctx context.Context, userID string) (*UserStats, error) {
+	userStats, err := service.shortLinkRepo.GetUserStats(ctx, userID, service.now())
+	if err != nil {
+		return nil, fmt.Errorf("failed GetUserStats in repo: %w", err)
+	}
+
+	stats := &UserStats{
+		Active:   userStats.Active,
+		Deleted:  userStats.Deleted,
+		Expired:  userStats.Expired,
+		TopLinks: make([]LinkClicks, 0),
+	}
+	if service.clickRepo == nil || len(userStats.ShortURLs) == 0 {
+		return stats, nil
+	}
+
+	counts, err := service.clickRepo.GetClickCounts(ctx, userStats.ShortURLs)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetClickCounts in repo: %w", err)
+	}
+	for shortURL, clicks := range counts {
+		if clicks == 0 {
+			continue
+		}
+		stats.TotalClicks += clicks
+		stats.TopLinks = append(stats.TopLinks, LinkClicks{ShortURL: shortURL, Clicks: clicks})
+	}
+	sort.Slice(stats.TopLinks, func(i, j int) bool {
+		if stats.TopLinks[i].Clicks != stats.TopLinks[j].Clicks {
+			return stats.TopLinks[i].Clicks > stats.TopLinks[j].Clicks
+		}
+		return stats.TopLinks[i].ShortURL < stats.TopLinks[j].ShortURL
+	})
+	if len(stats.TopLinks) > constants.UserStatsTopLinks {
+		stats.TopLinks = stats.TopLinks[:constants.UserStatsTopLinks]
+	}
+	return stats, nil
+}
+
+// newShortLinkData - Создает модель новой ссылки для репозитория с временем создания и сроком действия.
+func (service *NaiveShorterService) newShortLinkData(id string, shortID string, originalLink *OriginalLink,
+	userID string) (*data.ShortLinkData, error) {
+	now := service.now().UTC()
+	expiresAt, err := resolveExpiresAt(originalLink, now)
+	if err != nil {
+		return nil, err
+	}
+	link := data.NewShortLinkData(id, shortID, originalLink.URL, userID)
+	link.CreatedAt = now
+	link.ExpiresAt = expiresAt
+	return link, nil
+}
+
+// toShortedLink - Преобразует модель репозитория в доменный объект сокращенной ссылки.
+func (service *NaiveShorterService) toShortedLink(link *data.ShortLinkData) *ShortedLink {
+	res := NewShortedLink(link.UUID, "", link.OriginalURL, link.ShortURL, false, link.IsDeleted)
+	res.ExpiresAt = link.ExpiresAt
+	res.IsExpired = link.IsExpired(service.now())
+	return res
+}
+
+// TTLFromSeconds - Переводит время жизни ссылки из секунд запроса в time.Duration.
+// Отрицательные значения и значения больше constants.MaxLinkTTL отклоняются до перевода, чтобы он не переполнился.
+func TTLFromSeconds(seconds int64) (time.Duration, error) {
+	switch {
+	case seconds < 0:
+		return 0, fmt.Errorf("%w: ttl should be positive", ErrInvalidExpiration)
+	case seconds > int64(constants.MaxLinkTTL/time.Second):
+		return 0, fmt.Errorf("%w: ttl should not exceed %d seconds", ErrInvalidExpiration,
+			int64(constants.MaxLinkTTL/time.Second))
+	default:
+		return time.Duration(seconds) * time.Second, nil
+	}
+}
+
+// resolveExpiresAt - Вычисляет абсолютное время окончания действия ссылки из TTL или ExpiresAt.
+func resolveExpiresAt(originalLink *OriginalLink, now time.Time) (*time.Time, error) {
+	switch {
+	case originalLink.TTL != 0 && originalLink.ExpiresAt != nil:
+		return nil, fmt.Errorf("%w: ttl and expires_at are mutually exclusive", ErrInvalidExpiration)
+	case originalLink.TTL < 0:
+		return nil, fmt.Errorf("%w: ttl should be positive", ErrInvalidExpiration)
+	case originalLink.TTL > constants.MaxLinkTTL:
+		return nil, fmt.Errorf("%w: ttl should not exceed %s", ErrInvalidExpiration, constants.MaxLinkTTL)
+	case originalLink.TTL > 0:
+		expiresAt := now.Add(originalLink.TTL)
+		return &expiresAt, nil
+	case originalLink.ExpiresAt != nil:
+		if !originalLink.ExpiresAt.After(now) {
+			return nil, fmt.Errorf("%w: expires_at should be in the future", ErrInvalidExpiration)
+		}
+		expiresAt := originalLink.ExpiresAt.UTC()
+		return &expiresAt, nil
+	default:
+		return nil, nil //nolint:nilnil // link without expiration
+	}
+}
+
+// createNewIds - Создает идентификаторы новой ссылки. Если задан псевдоним, он валидируется и
+// используется как идентификатор сокращенной ссылки, иначе идентификатор генерируется.
+// attempt - Номер попытки генерации идентификатора после коллизий.
+func (service *NaiveShorterService) createNewIds(ctx context.Context, originalLink *OriginalLink, attempt int) (
+	id uuid.UUID, shortID string, err error) {
 	id, err = uuid.NewRandom()
 	if err != nil {
 		err = fmt.Errorf("failed create random: %w", err)
 		return
 	}
-	shortID, err = helpers.RandStringRunes(constants.ShortIDLength)
-	if err != nil {
-		err = fmt.Errorf("failed create short url: %w", err)
+	if originalLink.Alias != "" {
+		err = service.aliasRules.Validate(originalLink.Alias)
+		shortID = originalLink.Alias
 		return
 	}
+	shortID, err = service.generateShortID(ctx, originalLink.URL, attempt)
 	return
 }
 
+// generateShortID - Генерирует идентификатор сокращенной ссылки, пропуская зарезервированные пути HTTP API.
+func (service *NaiveShorterService) generateShortID(ctx context.Context, originalURL string, attempt int) (
+	string, error) {
+	for i := range constants.ShortIDMaxAttempts {
+		shortID, err := service.idGenerator.Generate(ctx, originalURL, attempt+i)
+		if err != nil {
+			return "", fmt.Errorf("failed create short url: %w", err)
+		}
+		if !service.aliasRules.isReserved(shortID) {
+			return shortID, nil
+		}
+	}
+	return "", errors.New("failed create short url: all generated ids are reserved")
+}
+
 func convertDeleteShort(shortIDs []DeleteShortID) []data.DeleteShortData {
 	dbModels := make([]data.DeleteShortData, 0, len(shortIDs))
 	for _, sid := range shortIDs {
@@ -162,3 +555,79 @@ func convertDeleteShort(shortIDs []DeleteShortID) []data.DeleteShortData {
 	}
 	return dbModels
 }
+
+// newBatchLinkData - Создает модель ссылки из пачки, проверяя повтор псевдонима в пачке.
+func (service *NaiveShorterService) newBatchLinkData(ctx context.Context, ol *OriginalLink, userID string,
+	aliases map[string]bool) (*data.ShortLinkData, error) {
+	if ol.Alias != "" {
+		if aliases[ol.Alias] {
+			return nil, fmt.Errorf("%w: '%s' is repeated in batch", ErrAliasTaken, ol.Alias)
+		}
+		aliases[ol.Alias] = true
+	}
+	id, shortID, err := service.createNewIds(ctx, ol, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed create ids: %w", err)
+	}
+	return service.newShortLinkData(id.String(), shortID, ol, userID)
+}
+
+// regenerateBatchID - Генерирует новый идентификатор ссылке из пачки, сгенерированный идентификатор которой занят.
+// Возвращает false, если занятый идентификатор не был сгенерирован, например, это псевдоним.
+func (service *NaiveShorterService) regenerateBatchID(ctx context.Context, shortURL string, pending []int,
+	originalLinks []*OriginalLink, dataModels []*data.ShortLinkData, attempts []int) (bool, error) {
+	for _, i := range pending {
+		if originalLinks[i].Alias != "" || dataModels[i].ShortURL != shortURL {
+			continue
+		}
+		attempts[i]++
+		if attempts[i] >= constants.ShortIDMaxAttempts {
+			return false, fmt.Errorf("no free short id after %d attempts", attempts[i])
+		}
+		shortID, err := service.generateShortID(ctx, originalLinks[i].URL, attempts[i])
+		if err != nil {
+			return false, err
+		}
+		dataModels[i].ShortURL = shortID
+		return true, nil
+	}
+	return false, nil
+}
+
+// toBatchShortedLink - Преобразует сохраненную ссылку из пачки в доменный объект с результатом обработки.
+func toBatchShortedLink(ol *OriginalLink, newLink, storedLink *data.ShortLinkData) *ShortedLink {
+	// Если короткие ссылки разные, значит был найден дубль и возвращено его значение.
+	isDuplicate := newLink.ShortURL != storedLink.ShortURL
+	res := NewShortedLink(storedLink.UUID, ol.CorelationID, storedLink.OriginalURL, storedLink.ShortURL,
+		isDuplicate, storedLink.IsDeleted)
+	res.ExpiresAt = storedLink.ExpiresAt
+	res.Status = BatchItemCreated
+	if isDuplicate {
+		res.Status = BatchItemDuplicate
+	}
+	return res
+}
+
+// newFailedShortedLink - Создает доменный объект ссылки из пачки, которую не удалось создать.
+func newFailedShortedLink(ol *OriginalLink, err error) *ShortedLink {
+	return &ShortedLink{
+		CorelationID: ol.CorelationID,
+		OriginalURL:  ol.URL,
+		Status:       BatchItemFailed,
+		Err:          err,
+	}
+}
+
+// newLinkAuditEvent - Создает событие журнала аудита, источник и время которого заполняются при записи.
+func newLinkAuditEvent(action AuditAction, shortURL string, userID string, originalURL string) *AuditEvent {
+	return &AuditEvent{Action: action, ShortURL: shortURL, UserID: userID, OriginalURL: originalURL}
+}
+
+// asAliasTaken - Возвращает ErrAliasTaken, если репозиторий отклонил занятый пользовательский псевдоним.
+func asAliasTaken(err error, alias string) error {
+	var conflictErr *data.ShortURLConflictError
+	if alias != "" && errors.As(err, &conflictErr) {
+		return fmt.Errorf("%w: '%s'", ErrAliasTaken, conflictErr.ShortURL)
+	}
+	return nil
+}
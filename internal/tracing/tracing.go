@@ -0,0 +1,85 @@
+// Package tracing настраивает трассировку OpenTelemetry.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/VladSnap/shortener/internal/config"
+	"github.com/VladSnap/shortener/internal/constants"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName - Имя трейсера, которым подписываются все span приложения.
+const tracerName = "github.com/VladSnap/shortener"
+
+// serviceName - Имя сервиса в ресурсе трассировки.
+const serviceName = "shortener"
+
+// Tracer - Возвращает трейсер приложения из глобального провайдера.
+// Глобальный трейсер делегирует провайдеру, установленному позже, поэтому его можно получать заранее.
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// Setup - Устанавливает W3C propagator и глобальный провайдер трассировки с экспортером из конфига.
+// Возвращает функцию, которая отправляет накопленные span и останавливает провайдер.
+// Для экспортера none провайдер не создается: span не записываются, но контекст трассировки передается дальше.
+func Setup(ctx context.Context, cfg *config.Options) (func() error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if exporter == nil {
+		return func() error { return nil }, nil
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	return func() error {
+		if err := provider.Shutdown(context.Background()); err != nil {
+			return fmt.Errorf("failed shutdown tracer provider: %w", err)
+		}
+		return nil
+	}, nil
+}
+
+// newExporter - Создает экспортер span по настройкам конфига, для экспортера none возвращает nil.
+func newExporter(ctx context.Context, cfg *config.Options) (sdktrace.SpanExporter, error) {
+	switch cfg.TracingExporter {
+	case constants.TracingExporterNone, "":
+		return nil, nil //nolint:nilnil // tracing disabled
+	case constants.TracingExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("failed create stdout trace exporter: %w", err)
+		}
+		return exporter, nil
+	case constants.TracingExporterOTLP:
+		options := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.TracingEndpoint)}
+		if cfg.TracingInsecure != nil && *cfg.TracingInsecure {
+			options = append(options, otlptracegrpc.WithInsecure())
+		}
+		exporter, err := otlptracegrpc.New(ctx, options...)
+		if err != nil {
+			return nil, fmt.Errorf("failed create OTLP trace exporter: %w", err)
+		}
+		return exporter, nil
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter '%s'", cfg.TracingExporter)
+	}
+}
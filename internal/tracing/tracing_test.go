@@ -0,0 +1,34 @@
+package tracing
+
+import (
+	"testing"
+
+	"github.com/VladSnap/shortener/internal/config"
+	"github.com/VladSnap/shortener/internal/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup(t *testing.T) {
+	t.Run("none exporter only sets propagator", func(t *testing.T) {
+		shutdown, err := Setup(t.Context(), &config.Options{TracingExporter: constants.TracingExporterNone})
+
+		require.NoError(t, err)
+		require.NoError(t, shutdown())
+		assert.Contains(t, otel.GetTextMapPropagator().Fields(), "traceparent")
+	})
+
+	t.Run("stdout exporter", func(t *testing.T) {
+		shutdown, err := Setup(t.Context(), &config.Options{TracingExporter: constants.TracingExporterStdout})
+
+		require.NoError(t, err)
+		require.NoError(t, shutdown())
+	})
+
+	t.Run("unknown exporter", func(t *testing.T) {
+		_, err := Setup(t.Context(), &config.Options{TracingExporter: "jaeger"})
+
+		assert.Error(t, err)
+	})
+}
@@ -16,8 +16,11 @@ func ValidateShortURL(inputURL string) error {
 	if inputURL == "" {
 		return errors.New("shortURL should not be empty")
 	}
-	if utf8.RuneCountInString(inputURL) != constants.ShortIDLength {
-		return errors.New("shortURL length should be 8")
+	if utf8.RuneCountInString(inputURL) > constants.MaxShortIDLength {
+		return fmt.Errorf("shortURL length should not be greater than %d", constants.MaxShortIDLength)
+	}
+	if strings.ContainsAny(inputURL, "/?#") {
+		return errors.New("shortURL should not contain '/', '?' or '#'")
 	}
 	return nil
 }
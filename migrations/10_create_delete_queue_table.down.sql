@@ -0,0 +1 @@
+DROP TABLE IF EXISTS public.delete_queue
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS public.delete_queue (
+  id bigserial NOT NULL,
+  short_url varchar NOT NULL,
+  user_id varchar NOT NULL,
+  attempts integer NOT NULL DEFAULT 0,
+  next_attempt_at timestamptz NOT NULL,
+  last_error varchar NOT NULL DEFAULT '',
+  created_at timestamptz NOT NULL,
+  dead_at timestamptz NULL,
+  PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS delete_queue_next_attempt_at_idx on public.delete_queue (next_attempt_at)
+  WHERE dead_at IS NULL;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS public.delete_queue_next_attempt_at_idx;
+CREATE INDEX IF NOT EXISTS delete_queue_next_attempt_at_idx on public.delete_queue (next_attempt_at)
+  WHERE dead_at IS NULL;
+DROP INDEX IF EXISTS public.delete_queue_completed_at_idx;
+DROP INDEX IF EXISTS public.delete_queue_job_id_idx;
+ALTER TABLE public.delete_queue DROP COLUMN completed_at;
+ALTER TABLE public.delete_queue DROP COLUMN outcome;
+ALTER TABLE public.delete_queue DROP COLUMN job_id;
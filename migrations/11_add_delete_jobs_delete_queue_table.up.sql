@@ -0,0 +1,9 @@
+ALTER TABLE public.delete_queue ADD COLUMN job_id varchar NOT NULL DEFAULT '';
+ALTER TABLE public.delete_queue ADD COLUMN outcome varchar NOT NULL DEFAULT '';
+ALTER TABLE public.delete_queue ADD COLUMN completed_at timestamptz NULL;
+CREATE INDEX IF NOT EXISTS delete_queue_job_id_idx on public.delete_queue (job_id);
+CREATE INDEX IF NOT EXISTS delete_queue_completed_at_idx on public.delete_queue (completed_at)
+  WHERE completed_at IS NOT NULL;
+DROP INDEX IF EXISTS public.delete_queue_next_attempt_at_idx;
+CREATE INDEX IF NOT EXISTS delete_queue_next_attempt_at_idx on public.delete_queue (next_attempt_at)
+  WHERE dead_at IS NULL AND completed_at IS NULL;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS public.short_link_history
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS public.short_link_history (
+  id bigserial NOT NULL,
+  short_url varchar NOT NULL REFERENCES public.short_links (short_url) ON DELETE CASCADE,
+  orig_url varchar NOT NULL,
+  changed_at timestamptz NOT NULL,
+  PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS short_link_history_short_url_idx on public.short_link_history (short_url, id);
\ No newline at end of file
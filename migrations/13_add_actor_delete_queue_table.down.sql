@@ -0,0 +1,2 @@
+ALTER TABLE public.delete_queue DROP COLUMN client_ip;
+ALTER TABLE public.delete_queue DROP COLUMN source;
\ No newline at end of file
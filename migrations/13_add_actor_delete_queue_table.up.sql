@@ -0,0 +1,2 @@
+ALTER TABLE public.delete_queue ADD COLUMN source varchar NOT NULL DEFAULT '';
+ALTER TABLE public.delete_queue ADD COLUMN client_ip varchar NOT NULL DEFAULT '';
\ No newline at end of file
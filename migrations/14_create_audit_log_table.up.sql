@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS public.audit_log (
+  id bigserial NOT NULL,
+  action varchar NOT NULL,
+  short_url varchar NOT NULL,
+  user_id varchar NOT NULL,
+  source varchar NOT NULL,
+  client_ip varchar NULL,
+  orig_url varchar NULL,
+  created_at timestamptz NOT NULL,
+  PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx on public.audit_log (created_at);
+CREATE INDEX IF NOT EXISTS audit_log_user_id_created_at_idx on public.audit_log (user_id, created_at);
\ No newline at end of file
@@ -0,0 +1,4 @@
+DELETE FROM public.short_links WHERE orig_url_released;
+DROP INDEX IF EXISTS public.short_links_orig_url_unique_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS short_links_orig_url_unique_idx on public.short_links (orig_url);
+ALTER TABLE public.short_links DROP COLUMN orig_url_released;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE public.short_links ADD COLUMN orig_url_released boolean NOT NULL DEFAULT false;
+DROP INDEX IF EXISTS public.short_links_orig_url_unique_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS short_links_orig_url_unique_idx on public.short_links (orig_url) WHERE NOT orig_url_released;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS public.short_links_expires_at_idx;
+ALTER TABLE public.short_links DROP COLUMN expires_at;
+ALTER TABLE public.short_links DROP COLUMN created_at;
\ No newline at end of file
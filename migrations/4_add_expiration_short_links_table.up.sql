@@ -0,0 +1,3 @@
+ALTER TABLE public.short_links ADD COLUMN created_at timestamptz NOT NULL DEFAULT now();
+ALTER TABLE public.short_links ADD COLUMN expires_at timestamptz NULL;
+CREATE INDEX IF NOT EXISTS short_links_expires_at_idx on public.short_links (expires_at) WHERE expires_at IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE public.short_links DROP COLUMN deleted_at;
\ No newline at end of file
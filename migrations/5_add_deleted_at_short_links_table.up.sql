@@ -0,0 +1 @@
+ALTER TABLE public.short_links ADD COLUMN deleted_at timestamptz NULL;
\ No newline at end of file
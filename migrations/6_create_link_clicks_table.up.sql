@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS public.link_clicks (
+  id bigserial NOT NULL,
+  short_url varchar NOT NULL REFERENCES public.short_links (short_url) ON DELETE CASCADE,
+  clicked_at timestamptz NOT NULL,
+  referrer varchar NULL,
+  user_agent varchar NULL,
+  client_ip varchar NULL,
+  PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS link_clicks_short_url_clicked_at_idx on public.link_clicks (short_url, clicked_at);
\ No newline at end of file
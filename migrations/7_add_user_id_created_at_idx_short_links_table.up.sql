@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS short_links_user_id_created_at_idx on public.short_links (user_id, created_at, short_url);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP SEQUENCE IF EXISTS public.short_link_id_seq;
\ No newline at end of file
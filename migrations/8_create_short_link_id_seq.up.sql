@@ -0,0 +1 @@
+CREATE SEQUENCE IF NOT EXISTS public.short_link_id_seq;
\ No newline at end of file
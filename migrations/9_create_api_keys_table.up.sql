@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS public.api_keys (
+  id varchar NOT NULL,
+  key_hash varchar NOT NULL,
+  user_id varchar NOT NULL,
+  name varchar NOT NULL DEFAULT '',
+  scopes varchar NOT NULL DEFAULT '',
+  created_at timestamptz NOT NULL,
+  revoked_at timestamptz NULL,
+  PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS api_keys_key_hash_idx on public.api_keys (key_hash);
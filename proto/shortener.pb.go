@@ -9,6 +9,7 @@ package shortener
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,10 +22,256 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// BatchItemStatus is the outcome of processing a single link of a batch
+type BatchItemStatus int32
+
+const (
+	BatchItemStatus_BATCH_ITEM_STATUS_UNSPECIFIED BatchItemStatus = 0
+	// BATCH_ITEM_STATUS_CREATED means a new short link was created
+	BatchItemStatus_BATCH_ITEM_STATUS_CREATED BatchItemStatus = 1
+	// BATCH_ITEM_STATUS_DUPLICATE means the original URL was already shortened and the existing link is returned
+	BatchItemStatus_BATCH_ITEM_STATUS_DUPLICATE BatchItemStatus = 2
+	// BATCH_ITEM_STATUS_ERROR means the link was not created, only in partial mode
+	BatchItemStatus_BATCH_ITEM_STATUS_ERROR BatchItemStatus = 3
+)
+
+// Enum value maps for BatchItemStatus.
+var (
+	BatchItemStatus_name = map[int32]string{
+		0: "BATCH_ITEM_STATUS_UNSPECIFIED",
+		1: "BATCH_ITEM_STATUS_CREATED",
+		2: "BATCH_ITEM_STATUS_DUPLICATE",
+		3: "BATCH_ITEM_STATUS_ERROR",
+	}
+	BatchItemStatus_value = map[string]int32{
+		"BATCH_ITEM_STATUS_UNSPECIFIED": 0,
+		"BATCH_ITEM_STATUS_CREATED":     1,
+		"BATCH_ITEM_STATUS_DUPLICATE":   2,
+		"BATCH_ITEM_STATUS_ERROR":       3,
+	}
+)
+
+func (x BatchItemStatus) Enum() *BatchItemStatus {
+	p := new(BatchItemStatus)
+	*p = x
+	return p
+}
+
+func (x BatchItemStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BatchItemStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_shortener_proto_enumTypes[0].Descriptor()
+}
+
+func (BatchItemStatus) Type() protoreflect.EnumType {
+	return &file_proto_shortener_proto_enumTypes[0]
+}
+
+func (x BatchItemStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BatchItemStatus.Descriptor instead.
+func (BatchItemStatus) EnumDescriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{0}
+}
+
+// DeleteJobStatus is the status of a delete request
+type DeleteJobStatus int32
+
+const (
+	DeleteJobStatus_DELETE_JOB_STATUS_UNSPECIFIED DeleteJobStatus = 0
+	// DELETE_JOB_STATUS_PENDING means some URLs are still waiting to be deleted
+	DeleteJobStatus_DELETE_JOB_STATUS_PENDING DeleteJobStatus = 1
+	// DELETE_JOB_STATUS_COMPLETED means all URLs were processed
+	DeleteJobStatus_DELETE_JOB_STATUS_COMPLETED DeleteJobStatus = 2
+	// DELETE_JOB_STATUS_FAILED means all URLs were processed but some could not be deleted
+	DeleteJobStatus_DELETE_JOB_STATUS_FAILED DeleteJobStatus = 3
+)
+
+// Enum value maps for DeleteJobStatus.
+var (
+	DeleteJobStatus_name = map[int32]string{
+		0: "DELETE_JOB_STATUS_UNSPECIFIED",
+		1: "DELETE_JOB_STATUS_PENDING",
+		2: "DELETE_JOB_STATUS_COMPLETED",
+		3: "DELETE_JOB_STATUS_FAILED",
+	}
+	DeleteJobStatus_value = map[string]int32{
+		"DELETE_JOB_STATUS_UNSPECIFIED": 0,
+		"DELETE_JOB_STATUS_PENDING":     1,
+		"DELETE_JOB_STATUS_COMPLETED":   2,
+		"DELETE_JOB_STATUS_FAILED":      3,
+	}
+)
+
+func (x DeleteJobStatus) Enum() *DeleteJobStatus {
+	p := new(DeleteJobStatus)
+	*p = x
+	return p
+}
+
+func (x DeleteJobStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DeleteJobStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_shortener_proto_enumTypes[1].Descriptor()
+}
+
+func (DeleteJobStatus) Type() protoreflect.EnumType {
+	return &file_proto_shortener_proto_enumTypes[1]
+}
+
+func (x DeleteJobStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DeleteJobStatus.Descriptor instead.
+func (DeleteJobStatus) EnumDescriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{1}
+}
+
+// DeleteOutcome is the result of deleting a single URL
+type DeleteOutcome int32
+
+const (
+	DeleteOutcome_DELETE_OUTCOME_UNSPECIFIED DeleteOutcome = 0
+	// DELETE_OUTCOME_PENDING means the URL has not been processed yet
+	DeleteOutcome_DELETE_OUTCOME_PENDING DeleteOutcome = 1
+	// DELETE_OUTCOME_DELETED means the URL was deleted or had already been deleted
+	DeleteOutcome_DELETE_OUTCOME_DELETED DeleteOutcome = 2
+	// DELETE_OUTCOME_NOT_FOUND means the URL does not exist
+	DeleteOutcome_DELETE_OUTCOME_NOT_FOUND DeleteOutcome = 3
+	// DELETE_OUTCOME_NOT_OWNED means the URL belongs to another user and was not deleted
+	DeleteOutcome_DELETE_OUTCOME_NOT_OWNED DeleteOutcome = 4
+	// DELETE_OUTCOME_FAILED means the URL could not be deleted after all retries
+	DeleteOutcome_DELETE_OUTCOME_FAILED DeleteOutcome = 5
+)
+
+// Enum value maps for DeleteOutcome.
+var (
+	DeleteOutcome_name = map[int32]string{
+		0: "DELETE_OUTCOME_UNSPECIFIED",
+		1: "DELETE_OUTCOME_PENDING",
+		2: "DELETE_OUTCOME_DELETED",
+		3: "DELETE_OUTCOME_NOT_FOUND",
+		4: "DELETE_OUTCOME_NOT_OWNED",
+		5: "DELETE_OUTCOME_FAILED",
+	}
+	DeleteOutcome_value = map[string]int32{
+		"DELETE_OUTCOME_UNSPECIFIED": 0,
+		"DELETE_OUTCOME_PENDING":     1,
+		"DELETE_OUTCOME_DELETED":     2,
+		"DELETE_OUTCOME_NOT_FOUND":   3,
+		"DELETE_OUTCOME_NOT_OWNED":   4,
+		"DELETE_OUTCOME_FAILED":      5,
+	}
+)
+
+func (x DeleteOutcome) Enum() *DeleteOutcome {
+	p := new(DeleteOutcome)
+	*p = x
+	return p
+}
+
+func (x DeleteOutcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DeleteOutcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_shortener_proto_enumTypes[2].Descriptor()
+}
+
+func (DeleteOutcome) Type() protoreflect.EnumType {
+	return &file_proto_shortener_proto_enumTypes[2]
+}
+
+func (x DeleteOutcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DeleteOutcome.Descriptor instead.
+func (DeleteOutcome) EnumDescriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{2}
+}
+
+// RestoreOutcome is the result of restoring a single URL
+type RestoreOutcome int32
+
+const (
+	RestoreOutcome_RESTORE_OUTCOME_UNSPECIFIED RestoreOutcome = 0
+	// RESTORE_OUTCOME_RESTORED means the URL was restored
+	RestoreOutcome_RESTORE_OUTCOME_RESTORED RestoreOutcome = 1
+	// RESTORE_OUTCOME_NOT_FOUND means the URL does not exist or has already been purged
+	RestoreOutcome_RESTORE_OUTCOME_NOT_FOUND RestoreOutcome = 2
+	// RESTORE_OUTCOME_NOT_OWNED means the URL belongs to another user and was not restored
+	RestoreOutcome_RESTORE_OUTCOME_NOT_OWNED RestoreOutcome = 3
+	// RESTORE_OUTCOME_NOT_DELETED means the URL is not deleted
+	RestoreOutcome_RESTORE_OUTCOME_NOT_DELETED RestoreOutcome = 4
+	// RESTORE_OUTCOME_WINDOW_EXPIRED means the URL was deleted before the restore window
+	RestoreOutcome_RESTORE_OUTCOME_WINDOW_EXPIRED RestoreOutcome = 5
+)
+
+// Enum value maps for RestoreOutcome.
+var (
+	RestoreOutcome_name = map[int32]string{
+		0: "RESTORE_OUTCOME_UNSPECIFIED",
+		1: "RESTORE_OUTCOME_RESTORED",
+		2: "RESTORE_OUTCOME_NOT_FOUND",
+		3: "RESTORE_OUTCOME_NOT_OWNED",
+		4: "RESTORE_OUTCOME_NOT_DELETED",
+		5: "RESTORE_OUTCOME_WINDOW_EXPIRED",
+	}
+	RestoreOutcome_value = map[string]int32{
+		"RESTORE_OUTCOME_UNSPECIFIED":    0,
+		"RESTORE_OUTCOME_RESTORED":       1,
+		"RESTORE_OUTCOME_NOT_FOUND":      2,
+		"RESTORE_OUTCOME_NOT_OWNED":      3,
+		"RESTORE_OUTCOME_NOT_DELETED":    4,
+		"RESTORE_OUTCOME_WINDOW_EXPIRED": 5,
+	}
+)
+
+func (x RestoreOutcome) Enum() *RestoreOutcome {
+	p := new(RestoreOutcome)
+	*p = x
+	return p
+}
+
+func (x RestoreOutcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RestoreOutcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_shortener_proto_enumTypes[3].Descriptor()
+}
+
+func (RestoreOutcome) Type() protoreflect.EnumType {
+	return &file_proto_shortener_proto_enumTypes[3]
+}
+
+func (x RestoreOutcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RestoreOutcome.Descriptor instead.
+func (RestoreOutcome) EnumDescriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{3}
+}
+
 // CreateShortLinkRequest represents a request to create a single short link
 type CreateShortLinkRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	OriginalUrl   string                 `protobuf:"bytes,1,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	OriginalUrl string                 `protobuf:"bytes,1,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	// alias is an optional custom short ID, a random one is generated when empty
+	Alias string `protobuf:"bytes,2,opt,name=alias,proto3" json:"alias,omitempty"`
+	// ttl_seconds is an optional link lifetime, mutually exclusive with expires_at
+	TtlSeconds int64 `protobuf:"varint,3,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	// expires_at is an optional absolute link expiration time, mutually exclusive with ttl_seconds
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -66,6 +313,27 @@ func (x *CreateShortLinkRequest) GetOriginalUrl() string {
 	return ""
 }
 
+func (x *CreateShortLinkRequest) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *CreateShortLinkRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+func (x *CreateShortLinkRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
 // CreateShortLinkResponse represents the response for creating a short link
 type CreateShortLinkResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -124,6 +392,12 @@ type OriginalLinkBatch struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	CorrelationId string                 `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
 	OriginalUrl   string                 `protobuf:"bytes,2,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	// alias is an optional custom short ID, a random one is generated when empty
+	Alias string `protobuf:"bytes,3,opt,name=alias,proto3" json:"alias,omitempty"`
+	// ttl_seconds is an optional link lifetime, mutually exclusive with expires_at
+	TtlSeconds int64 `protobuf:"varint,4,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	// expires_at is an optional absolute link expiration time, mutually exclusive with ttl_seconds
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -172,10 +446,33 @@ func (x *OriginalLinkBatch) GetOriginalUrl() string {
 	return ""
 }
 
+func (x *OriginalLinkBatch) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *OriginalLinkBatch) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+func (x *OriginalLinkBatch) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
 // CreateShortLinkBatchRequest represents a request to create multiple short links
 type CreateShortLinkBatchRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Links         []*OriginalLinkBatch   `protobuf:"bytes,1,rep,name=links,proto3" json:"links,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Links []*OriginalLinkBatch   `protobuf:"bytes,1,rep,name=links,proto3" json:"links,omitempty"`
+	// partial creates the valid links and reports errors per link instead of failing the whole batch
+	Partial       bool `protobuf:"varint,2,opt,name=partial,proto3" json:"partial,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -217,11 +514,22 @@ func (x *CreateShortLinkBatchRequest) GetLinks() []*OriginalLinkBatch {
 	return nil
 }
 
+func (x *CreateShortLinkBatchRequest) GetPartial() bool {
+	if x != nil {
+		return x.Partial
+	}
+	return false
+}
+
 // ShortedLinkBatch represents a single shortened URL in a batch response
 type ShortedLinkBatch struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	CorrelationId string                 `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
-	ShortUrl      string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	// short_url is empty when the link was not created
+	ShortUrl string          `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Status   BatchItemStatus `protobuf:"varint,3,opt,name=status,proto3,enum=shortener.BatchItemStatus" json:"status,omitempty"`
+	// error describes why the link was not created
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -270,6 +578,20 @@ func (x *ShortedLinkBatch) GetShortUrl() string {
 	return ""
 }
 
+func (x *ShortedLinkBatch) GetStatus() BatchItemStatus {
+	if x != nil {
+		return x.Status
+	}
+	return BatchItemStatus_BATCH_ITEM_STATUS_UNSPECIFIED
+}
+
+func (x *ShortedLinkBatch) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 // CreateShortLinkBatchResponse represents the response for creating multiple short links
 type CreateShortLinkBatchResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -362,9 +684,11 @@ func (x *GetURLRequest) GetShortId() string {
 
 // GetURLResponse represents the response containing the original URL
 type GetURLResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	OriginalUrl   string                 `protobuf:"bytes,1,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
-	IsDeleted     bool                   `protobuf:"varint,2,opt,name=is_deleted,json=isDeleted,proto3" json:"is_deleted,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	OriginalUrl string                 `protobuf:"bytes,1,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	IsDeleted   bool                   `protobuf:"varint,2,opt,name=is_deleted,json=isDeleted,proto3" json:"is_deleted,omitempty"`
+	// expires_at is set when the link has an expiration time
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -413,9 +737,27 @@ func (x *GetURLResponse) GetIsDeleted() bool {
 	return false
 }
 
+func (x *GetURLResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
 // GetAllByUserIDRequest represents a request to get all URLs for a user
 type GetAllByUserIDRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// User ID is extracted from authentication context by interceptors
+	// limit is the page size, 0 returns all URLs of the user without pagination
+	Limit int32 `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	// cursor is next_cursor from the previous page, empty for the first page
+	Cursor string `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// desc sorts URLs by creation time in descending order
+	Desc bool `protobuf:"varint,3,opt,name=desc,proto3" json:"desc,omitempty"`
+	// deleted filters URLs by deleted state, unset returns both
+	Deleted *bool `protobuf:"varint,4,opt,name=deleted,proto3,oneof" json:"deleted,omitempty"`
+	// search filters URLs by a case-insensitive substring of the original URL
+	Search        string `protobuf:"bytes,5,opt,name=search,proto3" json:"search,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -450,6 +792,41 @@ func (*GetAllByUserIDRequest) Descriptor() ([]byte, []int) {
 	return file_proto_shortener_proto_rawDescGZIP(), []int{8}
 }
 
+func (x *GetAllByUserIDRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetAllByUserIDRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetAllByUserIDRequest) GetDesc() bool {
+	if x != nil {
+		return x.Desc
+	}
+	return false
+}
+
+func (x *GetAllByUserIDRequest) GetDeleted() bool {
+	if x != nil && x.Deleted != nil {
+		return *x.Deleted
+	}
+	return false
+}
+
+func (x *GetAllByUserIDRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
 // UserURL represents a single URL belonging to a user
 type UserURL struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -505,8 +882,10 @@ func (x *UserURL) GetShortUrl() string {
 
 // GetAllByUserIDResponse represents the response containing all user URLs
 type GetAllByUserIDResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Urls          []*UserURL             `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Urls  []*UserURL             `protobuf:"bytes,1,rep,name=urls,proto3" json:"urls,omitempty"`
+	// next_cursor is set when there are more URLs after this page
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -548,28 +927,36 @@ func (x *GetAllByUserIDResponse) GetUrls() []*UserURL {
 	return nil
 }
 
-// DeleteBatchRequest represents a request to delete multiple URLs
-type DeleteBatchRequest struct {
+func (x *GetAllByUserIDResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// UpdateLinkRequest represents a request to change the original URL of a short link
+type UpdateLinkRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ShortUrls     []string               `protobuf:"bytes,1,rep,name=short_urls,json=shortUrls,proto3" json:"short_urls,omitempty"`
+	ShortId       string                 `protobuf:"bytes,1,opt,name=short_id,json=shortId,proto3" json:"short_id,omitempty"`
+	OriginalUrl   string                 `protobuf:"bytes,2,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteBatchRequest) Reset() {
-	*x = DeleteBatchRequest{}
+func (x *UpdateLinkRequest) Reset() {
+	*x = UpdateLinkRequest{}
 	mi := &file_proto_shortener_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteBatchRequest) String() string {
+func (x *UpdateLinkRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteBatchRequest) ProtoMessage() {}
+func (*UpdateLinkRequest) ProtoMessage() {}
 
-func (x *DeleteBatchRequest) ProtoReflect() protoreflect.Message {
+func (x *UpdateLinkRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shortener_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -581,40 +968,49 @@ func (x *DeleteBatchRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteBatchRequest.ProtoReflect.Descriptor instead.
-func (*DeleteBatchRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdateLinkRequest.ProtoReflect.Descriptor instead.
+func (*UpdateLinkRequest) Descriptor() ([]byte, []int) {
 	return file_proto_shortener_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *DeleteBatchRequest) GetShortUrls() []string {
+func (x *UpdateLinkRequest) GetShortId() string {
 	if x != nil {
-		return x.ShortUrls
+		return x.ShortId
 	}
-	return nil
+	return ""
 }
 
-// DeleteBatchResponse represents the response for deleting URLs
-type DeleteBatchResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+func (x *UpdateLinkRequest) GetOriginalUrl() string {
+	if x != nil {
+		return x.OriginalUrl
+	}
+	return ""
+}
+
+// LinkDestination represents a previous original URL of a short link
+type LinkDestination struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	OriginalUrl string                 `protobuf:"bytes,1,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	// changed_at is the time the link stopped pointing to this URL
+	ChangedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteBatchResponse) Reset() {
-	*x = DeleteBatchResponse{}
+func (x *LinkDestination) Reset() {
+	*x = LinkDestination{}
 	mi := &file_proto_shortener_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteBatchResponse) String() string {
+func (x *LinkDestination) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteBatchResponse) ProtoMessage() {}
+func (*LinkDestination) ProtoMessage() {}
 
-func (x *DeleteBatchResponse) ProtoReflect() protoreflect.Message {
+func (x *LinkDestination) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shortener_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -626,39 +1022,50 @@ func (x *DeleteBatchResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteBatchResponse.ProtoReflect.Descriptor instead.
-func (*DeleteBatchResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use LinkDestination.ProtoReflect.Descriptor instead.
+func (*LinkDestination) Descriptor() ([]byte, []int) {
 	return file_proto_shortener_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *DeleteBatchResponse) GetSuccess() bool {
+func (x *LinkDestination) GetOriginalUrl() string {
 	if x != nil {
-		return x.Success
+		return x.OriginalUrl
 	}
-	return false
+	return ""
 }
 
-// GetStatsRequest represents a request to get service statistics
-type GetStatsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+func (x *LinkDestination) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+// UpdateLinkResponse represents the updated short link
+type UpdateLinkResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl    string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	OriginalUrl string                 `protobuf:"bytes,2,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	// history lists previous original URLs in the order they were changed
+	History       []*LinkDestination `protobuf:"bytes,3,rep,name=history,proto3" json:"history,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetStatsRequest) Reset() {
-	*x = GetStatsRequest{}
+func (x *UpdateLinkResponse) Reset() {
+	*x = UpdateLinkResponse{}
 	mi := &file_proto_shortener_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetStatsRequest) String() string {
+func (x *UpdateLinkResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetStatsRequest) ProtoMessage() {}
+func (*UpdateLinkResponse) ProtoMessage() {}
 
-func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+func (x *UpdateLinkResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shortener_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -670,34 +1077,54 @@ func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
-func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdateLinkResponse.ProtoReflect.Descriptor instead.
+func (*UpdateLinkResponse) Descriptor() ([]byte, []int) {
 	return file_proto_shortener_proto_rawDescGZIP(), []int{13}
 }
 
-// GetStatsResponse represents the response containing service statistics
-type GetStatsResponse struct {
+func (x *UpdateLinkResponse) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *UpdateLinkResponse) GetOriginalUrl() string {
+	if x != nil {
+		return x.OriginalUrl
+	}
+	return ""
+}
+
+func (x *UpdateLinkResponse) GetHistory() []*LinkDestination {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+// GetLinkStatsRequest represents a request to get click statistics for a short link
+type GetLinkStatsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Urls          int32                  `protobuf:"varint,1,opt,name=urls,proto3" json:"urls,omitempty"`
-	Users         int32                  `protobuf:"varint,2,opt,name=users,proto3" json:"users,omitempty"`
+	ShortId       string                 `protobuf:"bytes,1,opt,name=short_id,json=shortId,proto3" json:"short_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetStatsResponse) Reset() {
-	*x = GetStatsResponse{}
+func (x *GetLinkStatsRequest) Reset() {
+	*x = GetLinkStatsRequest{}
 	mi := &file_proto_shortener_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetStatsResponse) String() string {
+func (x *GetLinkStatsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetStatsResponse) ProtoMessage() {}
+func (*GetLinkStatsRequest) ProtoMessage() {}
 
-func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
+func (x *GetLinkStatsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_shortener_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -709,9 +1136,795 @@ func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
+// Deprecated: Use GetLinkStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetLinkStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetLinkStatsRequest) GetShortId() string {
+	if x != nil {
+		return x.ShortId
+	}
+	return ""
+}
+
+// DailyClicks represents the number of clicks for a single UTC day
+type DailyClicks struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// date is formatted as YYYY-MM-DD
+	Date          string `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	Clicks        int64  `protobuf:"varint,2,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DailyClicks) Reset() {
+	*x = DailyClicks{}
+	mi := &file_proto_shortener_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailyClicks) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailyClicks) ProtoMessage() {}
+
+func (x *DailyClicks) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailyClicks.ProtoReflect.Descriptor instead.
+func (*DailyClicks) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DailyClicks) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *DailyClicks) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+// GetLinkStatsResponse represents click statistics for a short link
+type GetLinkStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl      string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	TotalClicks   int64                  `protobuf:"varint,2,opt,name=total_clicks,json=totalClicks,proto3" json:"total_clicks,omitempty"`
+	Days          []*DailyClicks         `protobuf:"bytes,3,rep,name=days,proto3" json:"days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLinkStatsResponse) Reset() {
+	*x = GetLinkStatsResponse{}
+	mi := &file_proto_shortener_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLinkStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLinkStatsResponse) ProtoMessage() {}
+
+func (x *GetLinkStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLinkStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetLinkStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetLinkStatsResponse) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *GetLinkStatsResponse) GetTotalClicks() int64 {
+	if x != nil {
+		return x.TotalClicks
+	}
+	return 0
+}
+
+func (x *GetLinkStatsResponse) GetDays() []*DailyClicks {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+// GetUserStatsRequest represents a request to get aggregate statistics for the user
+type GetUserStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserStatsRequest) Reset() {
+	*x = GetUserStatsRequest{}
+	mi := &file_proto_shortener_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserStatsRequest) ProtoMessage() {}
+
+func (x *GetUserStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetUserStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{17}
+}
+
+// LinkClicks represents the number of clicks for a single short link
+type LinkClicks struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl      string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Clicks        int64                  `protobuf:"varint,2,opt,name=clicks,proto3" json:"clicks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkClicks) Reset() {
+	*x = LinkClicks{}
+	mi := &file_proto_shortener_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkClicks) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkClicks) ProtoMessage() {}
+
+func (x *LinkClicks) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkClicks.ProtoReflect.Descriptor instead.
+func (*LinkClicks) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *LinkClicks) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *LinkClicks) GetClicks() int64 {
+	if x != nil {
+		return x.Clicks
+	}
+	return 0
+}
+
+// GetUserStatsResponse represents aggregate statistics for all links of the user
+type GetUserStatsResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ActiveUrls  int32                  `protobuf:"varint,1,opt,name=active_urls,json=activeUrls,proto3" json:"active_urls,omitempty"`
+	DeletedUrls int32                  `protobuf:"varint,2,opt,name=deleted_urls,json=deletedUrls,proto3" json:"deleted_urls,omitempty"`
+	ExpiredUrls int32                  `protobuf:"varint,3,opt,name=expired_urls,json=expiredUrls,proto3" json:"expired_urls,omitempty"`
+	TotalClicks int64                  `protobuf:"varint,4,opt,name=total_clicks,json=totalClicks,proto3" json:"total_clicks,omitempty"`
+	// top_urls contains the most clicked links of the user, most clicked first
+	TopUrls       []*LinkClicks `protobuf:"bytes,5,rep,name=top_urls,json=topUrls,proto3" json:"top_urls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserStatsResponse) Reset() {
+	*x = GetUserStatsResponse{}
+	mi := &file_proto_shortener_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserStatsResponse) ProtoMessage() {}
+
+func (x *GetUserStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetUserStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetUserStatsResponse) GetActiveUrls() int32 {
+	if x != nil {
+		return x.ActiveUrls
+	}
+	return 0
+}
+
+func (x *GetUserStatsResponse) GetDeletedUrls() int32 {
+	if x != nil {
+		return x.DeletedUrls
+	}
+	return 0
+}
+
+func (x *GetUserStatsResponse) GetExpiredUrls() int32 {
+	if x != nil {
+		return x.ExpiredUrls
+	}
+	return 0
+}
+
+func (x *GetUserStatsResponse) GetTotalClicks() int64 {
+	if x != nil {
+		return x.TotalClicks
+	}
+	return 0
+}
+
+func (x *GetUserStatsResponse) GetTopUrls() []*LinkClicks {
+	if x != nil {
+		return x.TopUrls
+	}
+	return nil
+}
+
+// DeleteBatchRequest represents a request to delete multiple URLs
+type DeleteBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrls     []string               `protobuf:"bytes,1,rep,name=short_urls,json=shortUrls,proto3" json:"short_urls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteBatchRequest) Reset() {
+	*x = DeleteBatchRequest{}
+	mi := &file_proto_shortener_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBatchRequest) ProtoMessage() {}
+
+func (x *DeleteBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBatchRequest.ProtoReflect.Descriptor instead.
+func (*DeleteBatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DeleteBatchRequest) GetShortUrls() []string {
+	if x != nil {
+		return x.ShortUrls
+	}
+	return nil
+}
+
+// DeleteBatchResponse represents the response for deleting URLs
+type DeleteBatchResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	// job_id identifies the delete request for GetDeleteJob
+	JobId         string `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteBatchResponse) Reset() {
+	*x = DeleteBatchResponse{}
+	mi := &file_proto_shortener_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteBatchResponse) ProtoMessage() {}
+
+func (x *DeleteBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteBatchResponse.ProtoReflect.Descriptor instead.
+func (*DeleteBatchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DeleteBatchResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteBatchResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+// GetDeleteJobRequest represents a request to get the status of a delete request
+type GetDeleteJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeleteJobRequest) Reset() {
+	*x = GetDeleteJobRequest{}
+	mi := &file_proto_shortener_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeleteJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeleteJobRequest) ProtoMessage() {}
+
+func (x *GetDeleteJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeleteJobRequest.ProtoReflect.Descriptor instead.
+func (*GetDeleteJobRequest) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetDeleteJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+// DeleteJobItem represents the result for a single URL of a delete request
+type DeleteJobItem struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Outcome  DeleteOutcome          `protobuf:"varint,2,opt,name=outcome,proto3,enum=shortener.DeleteOutcome" json:"outcome,omitempty"`
+	// error is the error of the last failed attempt
+	Error         string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteJobItem) Reset() {
+	*x = DeleteJobItem{}
+	mi := &file_proto_shortener_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteJobItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteJobItem) ProtoMessage() {}
+
+func (x *DeleteJobItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteJobItem.ProtoReflect.Descriptor instead.
+func (*DeleteJobItem) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DeleteJobItem) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *DeleteJobItem) GetOutcome() DeleteOutcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return DeleteOutcome_DELETE_OUTCOME_UNSPECIFIED
+}
+
+func (x *DeleteJobItem) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// GetDeleteJobResponse represents the status of a delete request
+type GetDeleteJobResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	JobId     string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Status    DeleteJobStatus        `protobuf:"varint,2,opt,name=status,proto3,enum=shortener.DeleteJobStatus" json:"status,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// items are in the order of the DeleteBatch request
+	Items         []*DeleteJobItem `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeleteJobResponse) Reset() {
+	*x = GetDeleteJobResponse{}
+	mi := &file_proto_shortener_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeleteJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeleteJobResponse) ProtoMessage() {}
+
+func (x *GetDeleteJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeleteJobResponse.ProtoReflect.Descriptor instead.
+func (*GetDeleteJobResponse) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetDeleteJobResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *GetDeleteJobResponse) GetStatus() DeleteJobStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DeleteJobStatus_DELETE_JOB_STATUS_UNSPECIFIED
+}
+
+func (x *GetDeleteJobResponse) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *GetDeleteJobResponse) GetItems() []*DeleteJobItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// RestoreBatchRequest represents a request to restore multiple deleted URLs
+type RestoreBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrls     []string               `protobuf:"bytes,1,rep,name=short_urls,json=shortUrls,proto3" json:"short_urls,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreBatchRequest) Reset() {
+	*x = RestoreBatchRequest{}
+	mi := &file_proto_shortener_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreBatchRequest) ProtoMessage() {}
+
+func (x *RestoreBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreBatchRequest.ProtoReflect.Descriptor instead.
+func (*RestoreBatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RestoreBatchRequest) GetShortUrls() []string {
+	if x != nil {
+		return x.ShortUrls
+	}
+	return nil
+}
+
+// RestoreItem represents the result for a single URL of a restore request
+type RestoreItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl      string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Outcome       RestoreOutcome         `protobuf:"varint,2,opt,name=outcome,proto3,enum=shortener.RestoreOutcome" json:"outcome,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreItem) Reset() {
+	*x = RestoreItem{}
+	mi := &file_proto_shortener_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreItem) ProtoMessage() {}
+
+func (x *RestoreItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreItem.ProtoReflect.Descriptor instead.
+func (*RestoreItem) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RestoreItem) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *RestoreItem) GetOutcome() RestoreOutcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return RestoreOutcome_RESTORE_OUTCOME_UNSPECIFIED
+}
+
+// RestoreBatchResponse represents the response for restoring URLs
+type RestoreBatchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// items are in the order of the request
+	Items         []*RestoreItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreBatchResponse) Reset() {
+	*x = RestoreBatchResponse{}
+	mi := &file_proto_shortener_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreBatchResponse) ProtoMessage() {}
+
+func (x *RestoreBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreBatchResponse.ProtoReflect.Descriptor instead.
+func (*RestoreBatchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *RestoreBatchResponse) GetItems() []*RestoreItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// GetStatsRequest represents a request to get service statistics
+type GetStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_proto_shortener_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{28}
+}
+
+// GetStatsResponse represents the response containing service statistics
+type GetStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Urls          int32                  `protobuf:"varint,1,opt,name=urls,proto3" json:"urls,omitempty"`
+	Users         int32                  `protobuf:"varint,2,opt,name=users,proto3" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsResponse) Reset() {
+	*x = GetStatsResponse{}
+	mi := &file_proto_shortener_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsResponse) ProtoMessage() {}
+
+func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
 func (*GetStatsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_shortener_proto_rawDescGZIP(), []int{14}
+	return file_proto_shortener_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *GetStatsResponse) GetUrls() int32 {
@@ -737,7 +1950,7 @@ type PingRequest struct {
 
 func (x *PingRequest) Reset() {
 	*x = PingRequest{}
-	mi := &file_proto_shortener_proto_msgTypes[15]
+	mi := &file_proto_shortener_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -749,7 +1962,7 @@ func (x *PingRequest) String() string {
 func (*PingRequest) ProtoMessage() {}
 
 func (x *PingRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_shortener_proto_msgTypes[15]
+	mi := &file_proto_shortener_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -762,7 +1975,7 @@ func (x *PingRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PingRequest.ProtoReflect.Descriptor instead.
 func (*PingRequest) Descriptor() ([]byte, []int) {
-	return file_proto_shortener_proto_rawDescGZIP(), []int{15}
+	return file_proto_shortener_proto_rawDescGZIP(), []int{30}
 }
 
 // PingResponse represents a health check response
@@ -775,7 +1988,7 @@ type PingResponse struct {
 
 func (x *PingResponse) Reset() {
 	*x = PingResponse{}
-	mi := &file_proto_shortener_proto_msgTypes[16]
+	mi := &file_proto_shortener_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -787,7 +2000,7 @@ func (x *PingResponse) String() string {
 func (*PingResponse) ProtoMessage() {}
 
 func (x *PingResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_shortener_proto_msgTypes[16]
+	mi := &file_proto_shortener_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -800,7 +2013,7 @@ func (x *PingResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PingResponse.ProtoReflect.Descriptor instead.
 func (*PingResponse) Descriptor() ([]byte, []int) {
-	return file_proto_shortener_proto_rawDescGZIP(), []int{16}
+	return file_proto_shortener_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *PingResponse) GetStatus() string {
@@ -810,58 +2023,272 @@ func (x *PingResponse) GetStatus() string {
 	return ""
 }
 
+// CreateTokenRequest represents a request to exchange the current identity for a JWT access token
+type CreateTokenRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTokenRequest) Reset() {
+	*x = CreateTokenRequest{}
+	mi := &file_proto_shortener_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTokenRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTokenRequest) ProtoMessage() {}
+
+func (x *CreateTokenRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTokenRequest.ProtoReflect.Descriptor instead.
+func (*CreateTokenRequest) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{32}
+}
+
+// CreateTokenResponse represents an issued JWT access token
+type CreateTokenResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// access_token is sent as "authorization: Bearer <access_token>" metadata
+	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	TokenType     string                 `protobuf:"bytes,2,opt,name=token_type,json=tokenType,proto3" json:"token_type,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTokenResponse) Reset() {
+	*x = CreateTokenResponse{}
+	mi := &file_proto_shortener_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTokenResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTokenResponse) ProtoMessage() {}
+
+func (x *CreateTokenResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_shortener_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTokenResponse.ProtoReflect.Descriptor instead.
+func (*CreateTokenResponse) Descriptor() ([]byte, []int) {
+	return file_proto_shortener_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *CreateTokenResponse) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+func (x *CreateTokenResponse) GetTokenType() string {
+	if x != nil {
+		return x.TokenType
+	}
+	return ""
+}
+
+func (x *CreateTokenResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
 var File_proto_shortener_proto protoreflect.FileDescriptor
 
 const file_proto_shortener_proto_rawDesc = "" +
 	"\n" +
-	"\x15proto/shortener.proto\x12\tshortener\";\n" +
+	"\x15proto/shortener.proto\x12\tshortener\x1a\x1fgoogle/protobuf/timestamp.proto\"\xad\x01\n" +
 	"\x16CreateShortLinkRequest\x12!\n" +
-	"\foriginal_url\x18\x01 \x01(\tR\voriginalUrl\"Y\n" +
+	"\foriginal_url\x18\x01 \x01(\tR\voriginalUrl\x12\x14\n" +
+	"\x05alias\x18\x02 \x01(\tR\x05alias\x12\x1f\n" +
+	"\vttl_seconds\x18\x03 \x01(\x03R\n" +
+	"ttlSeconds\x129\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"Y\n" +
 	"\x17CreateShortLinkResponse\x12\x1b\n" +
 	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12!\n" +
-	"\fis_duplicate\x18\x02 \x01(\bR\visDuplicate\"]\n" +
+	"\fis_duplicate\x18\x02 \x01(\bR\visDuplicate\"\xcf\x01\n" +
 	"\x11OriginalLinkBatch\x12%\n" +
 	"\x0ecorrelation_id\x18\x01 \x01(\tR\rcorrelationId\x12!\n" +
-	"\foriginal_url\x18\x02 \x01(\tR\voriginalUrl\"Q\n" +
+	"\foriginal_url\x18\x02 \x01(\tR\voriginalUrl\x12\x14\n" +
+	"\x05alias\x18\x03 \x01(\tR\x05alias\x12\x1f\n" +
+	"\vttl_seconds\x18\x04 \x01(\x03R\n" +
+	"ttlSeconds\x129\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"k\n" +
 	"\x1bCreateShortLinkBatchRequest\x122\n" +
-	"\x05links\x18\x01 \x03(\v2\x1c.shortener.OriginalLinkBatchR\x05links\"V\n" +
+	"\x05links\x18\x01 \x03(\v2\x1c.shortener.OriginalLinkBatchR\x05links\x12\x18\n" +
+	"\apartial\x18\x02 \x01(\bR\apartial\"\xa0\x01\n" +
 	"\x10ShortedLinkBatch\x12%\n" +
 	"\x0ecorrelation_id\x18\x01 \x01(\tR\rcorrelationId\x12\x1b\n" +
-	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\"Q\n" +
+	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\x122\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x1a.shortener.BatchItemStatusR\x06status\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"Q\n" +
 	"\x1cCreateShortLinkBatchResponse\x121\n" +
 	"\x05links\x18\x01 \x03(\v2\x1b.shortener.ShortedLinkBatchR\x05links\"*\n" +
 	"\rGetURLRequest\x12\x19\n" +
-	"\bshort_id\x18\x01 \x01(\tR\ashortId\"R\n" +
+	"\bshort_id\x18\x01 \x01(\tR\ashortId\"\x8d\x01\n" +
 	"\x0eGetURLResponse\x12!\n" +
 	"\foriginal_url\x18\x01 \x01(\tR\voriginalUrl\x12\x1d\n" +
 	"\n" +
-	"is_deleted\x18\x02 \x01(\bR\tisDeleted\"\x17\n" +
-	"\x15GetAllByUserIDRequest\"I\n" +
+	"is_deleted\x18\x02 \x01(\bR\tisDeleted\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\x9c\x01\n" +
+	"\x15GetAllByUserIDRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x02 \x01(\tR\x06cursor\x12\x12\n" +
+	"\x04desc\x18\x03 \x01(\bR\x04desc\x12\x1d\n" +
+	"\adeleted\x18\x04 \x01(\bH\x00R\adeleted\x88\x01\x01\x12\x16\n" +
+	"\x06search\x18\x05 \x01(\tR\x06searchB\n" +
+	"\n" +
+	"\b_deleted\"I\n" +
 	"\aUserURL\x12!\n" +
 	"\foriginal_url\x18\x01 \x01(\tR\voriginalUrl\x12\x1b\n" +
-	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\"@\n" +
+	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\"a\n" +
 	"\x16GetAllByUserIDResponse\x12&\n" +
-	"\x04urls\x18\x01 \x03(\v2\x12.shortener.UserURLR\x04urls\"3\n" +
+	"\x04urls\x18\x01 \x03(\v2\x12.shortener.UserURLR\x04urls\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"Q\n" +
+	"\x11UpdateLinkRequest\x12\x19\n" +
+	"\bshort_id\x18\x01 \x01(\tR\ashortId\x12!\n" +
+	"\foriginal_url\x18\x02 \x01(\tR\voriginalUrl\"o\n" +
+	"\x0fLinkDestination\x12!\n" +
+	"\foriginal_url\x18\x01 \x01(\tR\voriginalUrl\x129\n" +
+	"\n" +
+	"changed_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt\"\x8a\x01\n" +
+	"\x12UpdateLinkResponse\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12!\n" +
+	"\foriginal_url\x18\x02 \x01(\tR\voriginalUrl\x124\n" +
+	"\ahistory\x18\x03 \x03(\v2\x1a.shortener.LinkDestinationR\ahistory\"0\n" +
+	"\x13GetLinkStatsRequest\x12\x19\n" +
+	"\bshort_id\x18\x01 \x01(\tR\ashortId\"9\n" +
+	"\vDailyClicks\x12\x12\n" +
+	"\x04date\x18\x01 \x01(\tR\x04date\x12\x16\n" +
+	"\x06clicks\x18\x02 \x01(\x03R\x06clicks\"\x82\x01\n" +
+	"\x14GetLinkStatsResponse\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12!\n" +
+	"\ftotal_clicks\x18\x02 \x01(\x03R\vtotalClicks\x12*\n" +
+	"\x04days\x18\x03 \x03(\v2\x16.shortener.DailyClicksR\x04days\"\x15\n" +
+	"\x13GetUserStatsRequest\"A\n" +
+	"\n" +
+	"LinkClicks\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06clicks\x18\x02 \x01(\x03R\x06clicks\"\xd2\x01\n" +
+	"\x14GetUserStatsResponse\x12\x1f\n" +
+	"\vactive_urls\x18\x01 \x01(\x05R\n" +
+	"activeUrls\x12!\n" +
+	"\fdeleted_urls\x18\x02 \x01(\x05R\vdeletedUrls\x12!\n" +
+	"\fexpired_urls\x18\x03 \x01(\x05R\vexpiredUrls\x12!\n" +
+	"\ftotal_clicks\x18\x04 \x01(\x03R\vtotalClicks\x120\n" +
+	"\btop_urls\x18\x05 \x03(\v2\x15.shortener.LinkClicksR\atopUrls\"3\n" +
 	"\x12DeleteBatchRequest\x12\x1d\n" +
 	"\n" +
-	"short_urls\x18\x01 \x03(\tR\tshortUrls\"/\n" +
+	"short_urls\x18\x01 \x03(\tR\tshortUrls\"F\n" +
 	"\x13DeleteBatchResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"\x11\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x15\n" +
+	"\x06job_id\x18\x02 \x01(\tR\x05jobId\",\n" +
+	"\x13GetDeleteJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"v\n" +
+	"\rDeleteJobItem\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x122\n" +
+	"\aoutcome\x18\x02 \x01(\x0e2\x18.shortener.DeleteOutcomeR\aoutcome\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\xcc\x01\n" +
+	"\x14GetDeleteJobResponse\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x122\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x1a.shortener.DeleteJobStatusR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12.\n" +
+	"\x05items\x18\x04 \x03(\v2\x18.shortener.DeleteJobItemR\x05items\"4\n" +
+	"\x13RestoreBatchRequest\x12\x1d\n" +
+	"\n" +
+	"short_urls\x18\x01 \x03(\tR\tshortUrls\"_\n" +
+	"\vRestoreItem\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x123\n" +
+	"\aoutcome\x18\x02 \x01(\x0e2\x19.shortener.RestoreOutcomeR\aoutcome\"D\n" +
+	"\x14RestoreBatchResponse\x12,\n" +
+	"\x05items\x18\x01 \x03(\v2\x16.shortener.RestoreItemR\x05items\"\x11\n" +
 	"\x0fGetStatsRequest\"<\n" +
 	"\x10GetStatsResponse\x12\x12\n" +
 	"\x04urls\x18\x01 \x01(\x05R\x04urls\x12\x14\n" +
 	"\x05users\x18\x02 \x01(\x05R\x05users\"\r\n" +
 	"\vPingRequest\"&\n" +
 	"\fPingResponse\x12\x16\n" +
-	"\x06status\x18\x01 \x01(\tR\x06status2\xb7\x04\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"\x14\n" +
+	"\x12CreateTokenRequest\"\x92\x01\n" +
+	"\x13CreateTokenResponse\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\x12\x1d\n" +
+	"\n" +
+	"token_type\x18\x02 \x01(\tR\ttokenType\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt*\x91\x01\n" +
+	"\x0fBatchItemStatus\x12!\n" +
+	"\x1dBATCH_ITEM_STATUS_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19BATCH_ITEM_STATUS_CREATED\x10\x01\x12\x1f\n" +
+	"\x1bBATCH_ITEM_STATUS_DUPLICATE\x10\x02\x12\x1b\n" +
+	"\x17BATCH_ITEM_STATUS_ERROR\x10\x03*\x92\x01\n" +
+	"\x0fDeleteJobStatus\x12!\n" +
+	"\x1dDELETE_JOB_STATUS_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19DELETE_JOB_STATUS_PENDING\x10\x01\x12\x1f\n" +
+	"\x1bDELETE_JOB_STATUS_COMPLETED\x10\x02\x12\x1c\n" +
+	"\x18DELETE_JOB_STATUS_FAILED\x10\x03*\xbe\x01\n" +
+	"\rDeleteOutcome\x12\x1e\n" +
+	"\x1aDELETE_OUTCOME_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16DELETE_OUTCOME_PENDING\x10\x01\x12\x1a\n" +
+	"\x16DELETE_OUTCOME_DELETED\x10\x02\x12\x1c\n" +
+	"\x18DELETE_OUTCOME_NOT_FOUND\x10\x03\x12\x1c\n" +
+	"\x18DELETE_OUTCOME_NOT_OWNED\x10\x04\x12\x19\n" +
+	"\x15DELETE_OUTCOME_FAILED\x10\x05*\xd2\x01\n" +
+	"\x0eRestoreOutcome\x12\x1f\n" +
+	"\x1bRESTORE_OUTCOME_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18RESTORE_OUTCOME_RESTORED\x10\x01\x12\x1d\n" +
+	"\x19RESTORE_OUTCOME_NOT_FOUND\x10\x02\x12\x1d\n" +
+	"\x19RESTORE_OUTCOME_NOT_OWNED\x10\x03\x12\x1f\n" +
+	"\x1bRESTORE_OUTCOME_NOT_DELETED\x10\x04\x12\"\n" +
+	"\x1eRESTORE_OUTCOME_WINDOW_EXPIRED\x10\x052\x94\b\n" +
 	"\x10ShortenerService\x12X\n" +
 	"\x0fCreateShortLink\x12!.shortener.CreateShortLinkRequest\x1a\".shortener.CreateShortLinkResponse\x12g\n" +
 	"\x14CreateShortLinkBatch\x12&.shortener.CreateShortLinkBatchRequest\x1a'.shortener.CreateShortLinkBatchResponse\x12=\n" +
 	"\x06GetURL\x12\x18.shortener.GetURLRequest\x1a\x19.shortener.GetURLResponse\x12U\n" +
-	"\x0eGetAllByUserID\x12 .shortener.GetAllByUserIDRequest\x1a!.shortener.GetAllByUserIDResponse\x12L\n" +
-	"\vDeleteBatch\x12\x1d.shortener.DeleteBatchRequest\x1a\x1e.shortener.DeleteBatchResponse\x12C\n" +
+	"\x0eGetAllByUserID\x12 .shortener.GetAllByUserIDRequest\x1a!.shortener.GetAllByUserIDResponse\x12I\n" +
+	"\n" +
+	"UpdateLink\x12\x1c.shortener.UpdateLinkRequest\x1a\x1d.shortener.UpdateLinkResponse\x12O\n" +
+	"\fGetLinkStats\x12\x1e.shortener.GetLinkStatsRequest\x1a\x1f.shortener.GetLinkStatsResponse\x12O\n" +
+	"\fGetUserStats\x12\x1e.shortener.GetUserStatsRequest\x1a\x1f.shortener.GetUserStatsResponse\x12L\n" +
+	"\vDeleteBatch\x12\x1d.shortener.DeleteBatchRequest\x1a\x1e.shortener.DeleteBatchResponse\x12O\n" +
+	"\fGetDeleteJob\x12\x1e.shortener.GetDeleteJobRequest\x1a\x1f.shortener.GetDeleteJobResponse\x12O\n" +
+	"\fRestoreBatch\x12\x1e.shortener.RestoreBatchRequest\x1a\x1f.shortener.RestoreBatchResponse\x12C\n" +
 	"\bGetStats\x12\x1a.shortener.GetStatsRequest\x1a\x1b.shortener.GetStatsResponse\x127\n" +
-	"\x04Ping\x12\x16.shortener.PingRequest\x1a\x17.shortener.PingResponseB3Z1github.com/VladSnap/shortener/proto/gen/shortenerb\x06proto3"
+	"\x04Ping\x12\x16.shortener.PingRequest\x1a\x17.shortener.PingResponse\x12L\n" +
+	"\vCreateToken\x12\x1d.shortener.CreateTokenRequest\x1a\x1e.shortener.CreateTokenResponseB3Z1github.com/VladSnap/shortener/proto/gen/shortenerb\x06proto3"
 
 var (
 	file_proto_shortener_proto_rawDescOnce sync.Once
@@ -875,49 +2302,99 @@ func file_proto_shortener_proto_rawDescGZIP() []byte {
 	return file_proto_shortener_proto_rawDescData
 }
 
-var file_proto_shortener_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_proto_shortener_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_proto_shortener_proto_msgTypes = make([]protoimpl.MessageInfo, 34)
 var file_proto_shortener_proto_goTypes = []any{
-	(*CreateShortLinkRequest)(nil),       // 0: shortener.CreateShortLinkRequest
-	(*CreateShortLinkResponse)(nil),      // 1: shortener.CreateShortLinkResponse
-	(*OriginalLinkBatch)(nil),            // 2: shortener.OriginalLinkBatch
-	(*CreateShortLinkBatchRequest)(nil),  // 3: shortener.CreateShortLinkBatchRequest
-	(*ShortedLinkBatch)(nil),             // 4: shortener.ShortedLinkBatch
-	(*CreateShortLinkBatchResponse)(nil), // 5: shortener.CreateShortLinkBatchResponse
-	(*GetURLRequest)(nil),                // 6: shortener.GetURLRequest
-	(*GetURLResponse)(nil),               // 7: shortener.GetURLResponse
-	(*GetAllByUserIDRequest)(nil),        // 8: shortener.GetAllByUserIDRequest
-	(*UserURL)(nil),                      // 9: shortener.UserURL
-	(*GetAllByUserIDResponse)(nil),       // 10: shortener.GetAllByUserIDResponse
-	(*DeleteBatchRequest)(nil),           // 11: shortener.DeleteBatchRequest
-	(*DeleteBatchResponse)(nil),          // 12: shortener.DeleteBatchResponse
-	(*GetStatsRequest)(nil),              // 13: shortener.GetStatsRequest
-	(*GetStatsResponse)(nil),             // 14: shortener.GetStatsResponse
-	(*PingRequest)(nil),                  // 15: shortener.PingRequest
-	(*PingResponse)(nil),                 // 16: shortener.PingResponse
+	(BatchItemStatus)(0),                 // 0: shortener.BatchItemStatus
+	(DeleteJobStatus)(0),                 // 1: shortener.DeleteJobStatus
+	(DeleteOutcome)(0),                   // 2: shortener.DeleteOutcome
+	(RestoreOutcome)(0),                  // 3: shortener.RestoreOutcome
+	(*CreateShortLinkRequest)(nil),       // 4: shortener.CreateShortLinkRequest
+	(*CreateShortLinkResponse)(nil),      // 5: shortener.CreateShortLinkResponse
+	(*OriginalLinkBatch)(nil),            // 6: shortener.OriginalLinkBatch
+	(*CreateShortLinkBatchRequest)(nil),  // 7: shortener.CreateShortLinkBatchRequest
+	(*ShortedLinkBatch)(nil),             // 8: shortener.ShortedLinkBatch
+	(*CreateShortLinkBatchResponse)(nil), // 9: shortener.CreateShortLinkBatchResponse
+	(*GetURLRequest)(nil),                // 10: shortener.GetURLRequest
+	(*GetURLResponse)(nil),               // 11: shortener.GetURLResponse
+	(*GetAllByUserIDRequest)(nil),        // 12: shortener.GetAllByUserIDRequest
+	(*UserURL)(nil),                      // 13: shortener.UserURL
+	(*GetAllByUserIDResponse)(nil),       // 14: shortener.GetAllByUserIDResponse
+	(*UpdateLinkRequest)(nil),            // 15: shortener.UpdateLinkRequest
+	(*LinkDestination)(nil),              // 16: shortener.LinkDestination
+	(*UpdateLinkResponse)(nil),           // 17: shortener.UpdateLinkResponse
+	(*GetLinkStatsRequest)(nil),          // 18: shortener.GetLinkStatsRequest
+	(*DailyClicks)(nil),                  // 19: shortener.DailyClicks
+	(*GetLinkStatsResponse)(nil),         // 20: shortener.GetLinkStatsResponse
+	(*GetUserStatsRequest)(nil),          // 21: shortener.GetUserStatsRequest
+	(*LinkClicks)(nil),                   // 22: shortener.LinkClicks
+	(*GetUserStatsResponse)(nil),         // 23: shortener.GetUserStatsResponse
+	(*DeleteBatchRequest)(nil),           // 24: shortener.DeleteBatchRequest
+	(*DeleteBatchResponse)(nil),          // 25: shortener.DeleteBatchResponse
+	(*GetDeleteJobRequest)(nil),          // 26: shortener.GetDeleteJobRequest
+	(*DeleteJobItem)(nil),                // 27: shortener.DeleteJobItem
+	(*GetDeleteJobResponse)(nil),         // 28: shortener.GetDeleteJobResponse
+	(*RestoreBatchRequest)(nil),          // 29: shortener.RestoreBatchRequest
+	(*RestoreItem)(nil),                  // 30: shortener.RestoreItem
+	(*RestoreBatchResponse)(nil),         // 31: shortener.RestoreBatchResponse
+	(*GetStatsRequest)(nil),              // 32: shortener.GetStatsRequest
+	(*GetStatsResponse)(nil),             // 33: shortener.GetStatsResponse
+	(*PingRequest)(nil),                  // 34: shortener.PingRequest
+	(*PingResponse)(nil),                 // 35: shortener.PingResponse
+	(*CreateTokenRequest)(nil),           // 36: shortener.CreateTokenRequest
+	(*CreateTokenResponse)(nil),          // 37: shortener.CreateTokenResponse
+	(*timestamppb.Timestamp)(nil),        // 38: google.protobuf.Timestamp
 }
 var file_proto_shortener_proto_depIdxs = []int32{
-	2,  // 0: shortener.CreateShortLinkBatchRequest.links:type_name -> shortener.OriginalLinkBatch
-	4,  // 1: shortener.CreateShortLinkBatchResponse.links:type_name -> shortener.ShortedLinkBatch
-	9,  // 2: shortener.GetAllByUserIDResponse.urls:type_name -> shortener.UserURL
-	0,  // 3: shortener.ShortenerService.CreateShortLink:input_type -> shortener.CreateShortLinkRequest
-	3,  // 4: shortener.ShortenerService.CreateShortLinkBatch:input_type -> shortener.CreateShortLinkBatchRequest
-	6,  // 5: shortener.ShortenerService.GetURL:input_type -> shortener.GetURLRequest
-	8,  // 6: shortener.ShortenerService.GetAllByUserID:input_type -> shortener.GetAllByUserIDRequest
-	11, // 7: shortener.ShortenerService.DeleteBatch:input_type -> shortener.DeleteBatchRequest
-	13, // 8: shortener.ShortenerService.GetStats:input_type -> shortener.GetStatsRequest
-	15, // 9: shortener.ShortenerService.Ping:input_type -> shortener.PingRequest
-	1,  // 10: shortener.ShortenerService.CreateShortLink:output_type -> shortener.CreateShortLinkResponse
-	5,  // 11: shortener.ShortenerService.CreateShortLinkBatch:output_type -> shortener.CreateShortLinkBatchResponse
-	7,  // 12: shortener.ShortenerService.GetURL:output_type -> shortener.GetURLResponse
-	10, // 13: shortener.ShortenerService.GetAllByUserID:output_type -> shortener.GetAllByUserIDResponse
-	12, // 14: shortener.ShortenerService.DeleteBatch:output_type -> shortener.DeleteBatchResponse
-	14, // 15: shortener.ShortenerService.GetStats:output_type -> shortener.GetStatsResponse
-	16, // 16: shortener.ShortenerService.Ping:output_type -> shortener.PingResponse
-	10, // [10:17] is the sub-list for method output_type
-	3,  // [3:10] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	38, // 0: shortener.CreateShortLinkRequest.expires_at:type_name -> google.protobuf.Timestamp
+	38, // 1: shortener.OriginalLinkBatch.expires_at:type_name -> google.protobuf.Timestamp
+	6,  // 2: shortener.CreateShortLinkBatchRequest.links:type_name -> shortener.OriginalLinkBatch
+	0,  // 3: shortener.ShortedLinkBatch.status:type_name -> shortener.BatchItemStatus
+	8,  // 4: shortener.CreateShortLinkBatchResponse.links:type_name -> shortener.ShortedLinkBatch
+	38, // 5: shortener.GetURLResponse.expires_at:type_name -> google.protobuf.Timestamp
+	13, // 6: shortener.GetAllByUserIDResponse.urls:type_name -> shortener.UserURL
+	38, // 7: shortener.LinkDestination.changed_at:type_name -> google.protobuf.Timestamp
+	16, // 8: shortener.UpdateLinkResponse.history:type_name -> shortener.LinkDestination
+	19, // 9: shortener.GetLinkStatsResponse.days:type_name -> shortener.DailyClicks
+	22, // 10: shortener.GetUserStatsResponse.top_urls:type_name -> shortener.LinkClicks
+	2,  // 11: shortener.DeleteJobItem.outcome:type_name -> shortener.DeleteOutcome
+	1,  // 12: shortener.GetDeleteJobResponse.status:type_name -> shortener.DeleteJobStatus
+	38, // 13: shortener.GetDeleteJobResponse.created_at:type_name -> google.protobuf.Timestamp
+	27, // 14: shortener.GetDeleteJobResponse.items:type_name -> shortener.DeleteJobItem
+	3,  // 15: shortener.RestoreItem.outcome:type_name -> shortener.RestoreOutcome
+	30, // 16: shortener.RestoreBatchResponse.items:type_name -> shortener.RestoreItem
+	38, // 17: shortener.CreateTokenResponse.expires_at:type_name -> google.protobuf.Timestamp
+	4,  // 18: shortener.ShortenerService.CreateShortLink:input_type -> shortener.CreateShortLinkRequest
+	7,  // 19: shortener.ShortenerService.CreateShortLinkBatch:input_type -> shortener.CreateShortLinkBatchRequest
+	10, // 20: shortener.ShortenerService.GetURL:input_type -> shortener.GetURLRequest
+	12, // 21: shortener.ShortenerService.GetAllByUserID:input_type -> shortener.GetAllByUserIDRequest
+	15, // 22: shortener.ShortenerService.UpdateLink:input_type -> shortener.UpdateLinkRequest
+	18, // 23: shortener.ShortenerService.GetLinkStats:input_type -> shortener.GetLinkStatsRequest
+	21, // 24: shortener.ShortenerService.GetUserStats:input_type -> shortener.GetUserStatsRequest
+	24, // 25: shortener.ShortenerService.DeleteBatch:input_type -> shortener.DeleteBatchRequest
+	26, // 26: shortener.ShortenerService.GetDeleteJob:input_type -> shortener.GetDeleteJobRequest
+	29, // 27: shortener.ShortenerService.RestoreBatch:input_type -> shortener.RestoreBatchRequest
+	32, // 28: shortener.ShortenerService.GetStats:input_type -> shortener.GetStatsRequest
+	34, // 29: shortener.ShortenerService.Ping:input_type -> shortener.PingRequest
+	36, // 30: shortener.ShortenerService.CreateToken:input_type -> shortener.CreateTokenRequest
+	5,  // 31: shortener.ShortenerService.CreateShortLink:output_type -> shortener.CreateShortLinkResponse
+	9,  // 32: shortener.ShortenerService.CreateShortLinkBatch:output_type -> shortener.CreateShortLinkBatchResponse
+	11, // 33: shortener.ShortenerService.GetURL:output_type -> shortener.GetURLResponse
+	14, // 34: shortener.ShortenerService.GetAllByUserID:output_type -> shortener.GetAllByUserIDResponse
+	17, // 35: shortener.ShortenerService.UpdateLink:output_type -> shortener.UpdateLinkResponse
+	20, // 36: shortener.ShortenerService.GetLinkStats:output_type -> shortener.GetLinkStatsResponse
+	23, // 37: shortener.ShortenerService.GetUserStats:output_type -> shortener.GetUserStatsResponse
+	25, // 38: shortener.ShortenerService.DeleteBatch:output_type -> shortener.DeleteBatchResponse
+	28, // 39: shortener.ShortenerService.GetDeleteJob:output_type -> shortener.GetDeleteJobResponse
+	31, // 40: shortener.ShortenerService.RestoreBatch:output_type -> shortener.RestoreBatchResponse
+	33, // 41: shortener.ShortenerService.GetStats:output_type -> shortener.GetStatsResponse
+	35, // 42: shortener.ShortenerService.Ping:output_type -> shortener.PingResponse
+	37, // 43: shortener.ShortenerService.CreateToken:output_type -> shortener.CreateTokenResponse
+	31, // [31:44] is the sub-list for method output_type
+	18, // [18:31] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
 }
 
 func init() { file_proto_shortener_proto_init() }
@@ -925,18 +2402,20 @@ func file_proto_shortener_proto_init() {
 	if File_proto_shortener_proto != nil {
 		return
 	}
+	file_proto_shortener_proto_msgTypes[8].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_shortener_proto_rawDesc), len(file_proto_shortener_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   17,
+			NumEnums:      4,
+			NumMessages:   34,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_shortener_proto_goTypes,
 		DependencyIndexes: file_proto_shortener_proto_depIdxs,
+		EnumInfos:         file_proto_shortener_proto_enumTypes,
 		MessageInfos:      file_proto_shortener_proto_msgTypes,
 	}.Build()
 	File_proto_shortener_proto = out.File
@@ -23,9 +23,15 @@ const (
 	ShortenerService_CreateShortLinkBatch_FullMethodName = "/shortener.ShortenerService/CreateShortLinkBatch"
 	ShortenerService_GetURL_FullMethodName               = "/shortener.ShortenerService/GetURL"
 	ShortenerService_GetAllByUserID_FullMethodName       = "/shortener.ShortenerService/GetAllByUserID"
+	ShortenerService_UpdateLink_FullMethodName           = "/shortener.ShortenerService/UpdateLink"
+	ShortenerService_GetLinkStats_FullMethodName         = "/shortener.ShortenerService/GetLinkStats"
+	ShortenerService_GetUserStats_FullMethodName         = "/shortener.ShortenerService/GetUserStats"
 	ShortenerService_DeleteBatch_FullMethodName          = "/shortener.ShortenerService/DeleteBatch"
+	ShortenerService_GetDeleteJob_FullMethodName         = "/shortener.ShortenerService/GetDeleteJob"
+	ShortenerService_RestoreBatch_FullMethodName         = "/shortener.ShortenerService/RestoreBatch"
 	ShortenerService_GetStats_FullMethodName             = "/shortener.ShortenerService/GetStats"
 	ShortenerService_Ping_FullMethodName                 = "/shortener.ShortenerService/Ping"
+	ShortenerService_CreateToken_FullMethodName          = "/shortener.ShortenerService/CreateToken"
 )
 
 // ShortenerServiceClient is the client API for ShortenerService service.
@@ -42,12 +48,24 @@ type ShortenerServiceClient interface {
 	GetURL(ctx context.Context, in *GetURLRequest, opts ...grpc.CallOption) (*GetURLResponse, error)
 	// GetAllByUserID retrieves all URLs shortened by a specific user
 	GetAllByUserID(ctx context.Context, in *GetAllByUserIDRequest, opts ...grpc.CallOption) (*GetAllByUserIDResponse, error)
+	// UpdateLink changes the original URL of a short link owned by the user
+	UpdateLink(ctx context.Context, in *UpdateLinkRequest, opts ...grpc.CallOption) (*UpdateLinkResponse, error)
+	// GetLinkStats returns click statistics for a short link owned by the user
+	GetLinkStats(ctx context.Context, in *GetLinkStatsRequest, opts ...grpc.CallOption) (*GetLinkStatsResponse, error)
+	// GetUserStats returns aggregate statistics for all links of the user
+	GetUserStats(ctx context.Context, in *GetUserStatsRequest, opts ...grpc.CallOption) (*GetUserStatsResponse, error)
 	// DeleteBatch marks multiple URLs as deleted
 	DeleteBatch(ctx context.Context, in *DeleteBatchRequest, opts ...grpc.CallOption) (*DeleteBatchResponse, error)
+	// GetDeleteJob returns the status of a delete request created by DeleteBatch
+	GetDeleteJob(ctx context.Context, in *GetDeleteJobRequest, opts ...grpc.CallOption) (*GetDeleteJobResponse, error)
+	// RestoreBatch restores URLs deleted within the restore window
+	RestoreBatch(ctx context.Context, in *RestoreBatchRequest, opts ...grpc.CallOption) (*RestoreBatchResponse, error)
 	// GetStats returns service statistics (only for trusted subnets)
 	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
 	// Ping checks service health
 	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
+	// CreateToken issues a JWT access token for the user of the current auth cookie or token
+	CreateToken(ctx context.Context, in *CreateTokenRequest, opts ...grpc.CallOption) (*CreateTokenResponse, error)
 }
 
 type shortenerServiceClient struct {
@@ -98,6 +116,36 @@ func (c *shortenerServiceClient) GetAllByUserID(ctx context.Context, in *GetAllB
 	return out, nil
 }
 
+func (c *shortenerServiceClient) UpdateLink(ctx context.Context, in *UpdateLinkRequest, opts ...grpc.CallOption) (*UpdateLinkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateLinkResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_UpdateLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) GetLinkStats(ctx context.Context, in *GetLinkStatsRequest, opts ...grpc.CallOption) (*GetLinkStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLinkStatsResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_GetLinkStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) GetUserStats(ctx context.Context, in *GetUserStatsRequest, opts ...grpc.CallOption) (*GetUserStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserStatsResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_GetUserStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *shortenerServiceClient) DeleteBatch(ctx context.Context, in *DeleteBatchRequest, opts ...grpc.CallOption) (*DeleteBatchResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DeleteBatchResponse)
@@ -108,6 +156,26 @@ func (c *shortenerServiceClient) DeleteBatch(ctx context.Context, in *DeleteBatc
 	return out, nil
 }
 
+func (c *shortenerServiceClient) GetDeleteJob(ctx context.Context, in *GetDeleteJobRequest, opts ...grpc.CallOption) (*GetDeleteJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDeleteJobResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_GetDeleteJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerServiceClient) RestoreBatch(ctx context.Context, in *RestoreBatchRequest, opts ...grpc.CallOption) (*RestoreBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreBatchResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_RestoreBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *shortenerServiceClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetStatsResponse)
@@ -128,6 +196,16 @@ func (c *shortenerServiceClient) Ping(ctx context.Context, in *PingRequest, opts
 	return out, nil
 }
 
+func (c *shortenerServiceClient) CreateToken(ctx context.Context, in *CreateTokenRequest, opts ...grpc.CallOption) (*CreateTokenResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateTokenResponse)
+	err := c.cc.Invoke(ctx, ShortenerService_CreateToken_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ShortenerServiceServer is the server API for ShortenerService service.
 // All implementations must embed UnimplementedShortenerServiceServer
 // for forward compatibility.
@@ -142,12 +220,24 @@ type ShortenerServiceServer interface {
 	GetURL(context.Context, *GetURLRequest) (*GetURLResponse, error)
 	// GetAllByUserID retrieves all URLs shortened by a specific user
 	GetAllByUserID(context.Context, *GetAllByUserIDRequest) (*GetAllByUserIDResponse, error)
+	// UpdateLink changes the original URL of a short link owned by the user
+	UpdateLink(context.Context, *UpdateLinkRequest) (*UpdateLinkResponse, error)
+	// GetLinkStats returns click statistics for a short link owned by the user
+	GetLinkStats(context.Context, *GetLinkStatsRequest) (*GetLinkStatsResponse, error)
+	// GetUserStats returns aggregate statistics for all links of the user
+	GetUserStats(context.Context, *GetUserStatsRequest) (*GetUserStatsResponse, error)
 	// DeleteBatch marks multiple URLs as deleted
 	DeleteBatch(context.Context, *DeleteBatchRequest) (*DeleteBatchResponse, error)
+	// GetDeleteJob returns the status of a delete request created by DeleteBatch
+	GetDeleteJob(context.Context, *GetDeleteJobRequest) (*GetDeleteJobResponse, error)
+	// RestoreBatch restores URLs deleted within the restore window
+	RestoreBatch(context.Context, *RestoreBatchRequest) (*RestoreBatchResponse, error)
 	// GetStats returns service statistics (only for trusted subnets)
 	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
 	// Ping checks service health
 	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	// CreateToken issues a JWT access token for the user of the current auth cookie or token
+	CreateToken(context.Context, *CreateTokenRequest) (*CreateTokenResponse, error)
 	mustEmbedUnimplementedShortenerServiceServer()
 }
 
@@ -170,15 +260,33 @@ func (UnimplementedShortenerServiceServer) GetURL(context.Context, *GetURLReques
 func (UnimplementedShortenerServiceServer) GetAllByUserID(context.Context, *GetAllByUserIDRequest) (*GetAllByUserIDResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAllByUserID not implemented")
 }
+func (UnimplementedShortenerServiceServer) UpdateLink(context.Context, *UpdateLinkRequest) (*UpdateLinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateLink not implemented")
+}
+func (UnimplementedShortenerServiceServer) GetLinkStats(context.Context, *GetLinkStatsRequest) (*GetLinkStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLinkStats not implemented")
+}
+func (UnimplementedShortenerServiceServer) GetUserStats(context.Context, *GetUserStatsRequest) (*GetUserStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserStats not implemented")
+}
 func (UnimplementedShortenerServiceServer) DeleteBatch(context.Context, *DeleteBatchRequest) (*DeleteBatchResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteBatch not implemented")
 }
+func (UnimplementedShortenerServiceServer) GetDeleteJob(context.Context, *GetDeleteJobRequest) (*GetDeleteJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeleteJob not implemented")
+}
+func (UnimplementedShortenerServiceServer) RestoreBatch(context.Context, *RestoreBatchRequest) (*RestoreBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreBatch not implemented")
+}
 func (UnimplementedShortenerServiceServer) GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
 }
 func (UnimplementedShortenerServiceServer) Ping(context.Context, *PingRequest) (*PingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
 }
+func (UnimplementedShortenerServiceServer) CreateToken(context.Context, *CreateTokenRequest) (*CreateTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateToken not implemented")
+}
 func (UnimplementedShortenerServiceServer) mustEmbedUnimplementedShortenerServiceServer() {}
 func (UnimplementedShortenerServiceServer) testEmbeddedByValue()                          {}
 
@@ -272,6 +380,60 @@ func _ShortenerService_GetAllByUserID_Handler(srv interface{}, ctx context.Conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShortenerService_UpdateLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).UpdateLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_UpdateLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).UpdateLink(ctx, req.(*UpdateLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_GetLinkStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLinkStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).GetLinkStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_GetLinkStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).GetLinkStats(ctx, req.(*GetLinkStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_GetUserStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).GetUserStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_GetUserStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).GetUserStats(ctx, req.(*GetUserStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ShortenerService_DeleteBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteBatchRequest)
 	if err := dec(in); err != nil {
@@ -290,6 +452,42 @@ func _ShortenerService_DeleteBatch_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShortenerService_GetDeleteJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeleteJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).GetDeleteJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_GetDeleteJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).GetDeleteJob(ctx, req.(*GetDeleteJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ShortenerService_RestoreBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).RestoreBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_RestoreBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).RestoreBatch(ctx, req.(*RestoreBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ShortenerService_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetStatsRequest)
 	if err := dec(in); err != nil {
@@ -326,6 +524,24 @@ func _ShortenerService_Ping_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ShortenerService_CreateToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServiceServer).CreateToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ShortenerService_CreateToken_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServiceServer).CreateToken(ctx, req.(*CreateTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ShortenerService_ServiceDesc is the grpc.ServiceDesc for ShortenerService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -349,10 +565,30 @@ var ShortenerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAllByUserID",
 			Handler:    _ShortenerService_GetAllByUserID_Handler,
 		},
+		{
+			MethodName: "UpdateLink",
+			Handler:    _ShortenerService_UpdateLink_Handler,
+		},
+		{
+			MethodName: "GetLinkStats",
+			Handler:    _ShortenerService_GetLinkStats_Handler,
+		},
+		{
+			MethodName: "GetUserStats",
+			Handler:    _ShortenerService_GetUserStats_Handler,
+		},
 		{
 			MethodName: "DeleteBatch",
 			Handler:    _ShortenerService_DeleteBatch_Handler,
 		},
+		{
+			MethodName: "GetDeleteJob",
+			Handler:    _ShortenerService_GetDeleteJob_Handler,
+		},
+		{
+			MethodName: "RestoreBatch",
+			Handler:    _ShortenerService_RestoreBatch_Handler,
+		},
 		{
 			MethodName: "GetStats",
 			Handler:    _ShortenerService_GetStats_Handler,
@@ -361,6 +597,10 @@ var ShortenerService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Ping",
 			Handler:    _ShortenerService_Ping_Handler,
 		},
+		{
+			MethodName: "CreateToken",
+			Handler:    _ShortenerService_CreateToken_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/shortener.proto",